@@ -38,6 +38,7 @@ package adbc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -139,6 +140,18 @@ func (e Error) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Msg)
 }
 
+// As lets errors.As find the details of the error that implement error,
+// such as QueryIDErrorDetail, so that callers don't have to search
+// Details themselves.
+func (e Error) As(target any) bool {
+	for _, detail := range e.Details {
+		if err, ok := detail.(error); ok && errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 // Status represents an error code for operations that may fail
 type Status uint8
 
@@ -801,6 +814,35 @@ type StatementExecuteSchema interface {
 	ExecuteSchema(context.Context) (*arrow.Schema, error)
 }
 
+// StatementCancel is a Statement that also supports cancelling an
+// in-progress operation.
+//
+// Since ADBC API revision 1.1.0.
+type StatementCancel interface {
+	// Cancel attempts to cancel any in-progress operation on this
+	// statement, including reading from a RecordReader it returned.
+	//
+	// Cancel is the only method that may be called concurrently with
+	// other methods on the statement. It is a no-op if nothing is in
+	// progress. A cancelled operation should fail with StatusCancelled.
+	Cancel() error
+}
+
+// ConnectionCancel is a Connection that also supports cancelling an
+// in-progress operation.
+//
+// Since ADBC API revision 1.1.0.
+type ConnectionCancel interface {
+	// Cancel attempts to cancel any in-progress operation on this
+	// connection, such as GetObjects or ReadPartition. It does not
+	// affect statements created from the connection.
+	//
+	// Cancel is the only method that may be called concurrently with
+	// other methods on the connection. It is a no-op if nothing is in
+	// progress. A cancelled operation should fail with StatusCancelled.
+	Cancel() error
+}
+
 // GetSetOptions is a PostInitOptions that also supports getting and setting option values of different types.
 //
 // GetOption functions should return an error with StatusNotFound for unsupported options.
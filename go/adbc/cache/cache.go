@@ -0,0 +1,504 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Options configures a Cache.
+type Options struct {
+	// Dir is the directory results are stored in, which is created if
+	// needed. If empty, results are stored in memory.
+	Dir string
+	// MaxBytes is the maximum total size of the stored results. The
+	// least recently used results are evicted beyond it. Zero means no
+	// limit.
+	MaxBytes int64
+	// MaxEntryBytes is the maximum size of a single result; larger
+	// results are not stored. Zero means the same as MaxBytes.
+	MaxEntryBytes int64
+	// TTL is how long results are served after they were stored. Zero
+	// means no limit.
+	TTL time.Duration
+	// KeyOptions are the keys of the statement options that change the
+	// results of queries, like the timezone of a session. Their values,
+	// as set on the statement or else as reported by the connection,
+	// are part of the key of results.
+	KeyOptions []string
+	// Allocator allocates the records of cached results. If nil,
+	// memory.DefaultAllocator is used.
+	Allocator memory.Allocator
+}
+
+// Stats are metrics about a Cache.
+type Stats struct {
+	// Entries is the number of stored results.
+	Entries int
+	// Bytes is the total size of the stored results.
+	Bytes int64
+
+	// Hits is the number of queries served from the cache.
+	Hits int64
+	// Misses is the number of queries run because their results were
+	// not stored, or had expired.
+	Misses int64
+	// Bypassed is the number of queries that were passed through
+	// without looking up the cache.
+	Bypassed int64
+	// Stored is the number of results stored.
+	Stored int64
+	// TooLarge is the number of results not stored because they
+	// exceeded MaxEntryBytes.
+	TooLarge int64
+	// Evicted is the number of results evicted to stay within
+	// MaxBytes.
+	Evicted int64
+	// Expired is the number of results dropped because they exceeded
+	// the TTL.
+	Expired int64
+}
+
+// key identifies the results of a query.
+type key [sha256.Size]byte
+
+// entry is a stored result.
+type entry struct {
+	key          key
+	query        string
+	rowsAffected int64
+	size         int64
+	storedAt     time.Time
+	elem         *list.Element
+
+	// data holds the result if it is stored in memory, and path is the
+	// file holding it otherwise
+	data []byte
+	path string
+	// refs counts the reference of the cache and those of the open
+	// readers; the file of the entry is removed once they are all gone
+	refs int
+}
+
+// Cache stores the results of queries. It is safe for concurrent use.
+type Cache struct {
+	opts     Options
+	maxEntry int64
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[key]*entry
+	lru     *list.List // most recently used first
+	size    int64
+	closed  bool
+	stats   Stats
+}
+
+// New creates a cache.
+func New(opts Options) (*Cache, error) {
+	if opts.Dir != "" {
+		if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+			return nil, adbc.Error{
+				Msg:  "[cache] failed to create the cache directory: " + err.Error(),
+				Code: adbc.StatusIO,
+			}
+		}
+	}
+	if opts.Allocator == nil {
+		opts.Allocator = memory.DefaultAllocator
+	}
+
+	c := &Cache{
+		opts:     opts,
+		maxEntry: opts.MaxEntryBytes,
+		now:      time.Now,
+		entries:  make(map[key]*entry),
+		lru:      list.New(),
+	}
+	if c.maxEntry <= 0 {
+		c.maxEntry = opts.MaxBytes
+	}
+	return c, nil
+}
+
+// Wrap returns a connection whose queries are served from the cache.
+// Closing it closes cnxn.
+func (c *Cache) Wrap(cnxn adbc.Connection) *Conn {
+	return &Conn{Connection: cnxn, cache: c}
+}
+
+// Stats returns the current metrics of the cache.
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.size
+	return stats
+}
+
+// Invalidate drops the stored results of a query, for any parameters and
+// options. It returns the number of results dropped.
+func (c *Cache) Invalidate(query string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, e := range c.entries {
+		if e.query == query {
+			c.remove(e)
+			n++
+		}
+	}
+	return n
+}
+
+// InvalidateAll drops all the stored results.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		c.remove(e)
+	}
+}
+
+// Close drops all the stored results and stops storing new ones. Queries
+// through the connections of the cache are passed through afterwards.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return adbc.Error{
+			Msg:  "[cache] cache is already closed",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	c.closed = true
+	for _, e := range c.entries {
+		c.remove(e)
+	}
+	return nil
+}
+
+// bypass counts a query passed through.
+func (c *Cache) bypass() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Bypassed++
+}
+
+// get returns a reader of the stored result for k, or nil if there is
+// none. It returns ok = false if the cache is closed.
+func (c *Cache) get(k key) (rdr array.RecordReader, rowsAffected int64, ok bool) {
+	c.mu.Lock()
+	if c.closed {
+		c.stats.Bypassed++
+		c.mu.Unlock()
+		return nil, -1, false
+	}
+	e := c.entries[k]
+	if e != nil && c.opts.TTL > 0 && c.now().Sub(e.storedAt) >= c.opts.TTL {
+		c.stats.Expired++
+		c.remove(e)
+		e = nil
+	}
+	if e == nil {
+		c.stats.Misses++
+		c.mu.Unlock()
+		return nil, -1, true
+	}
+	c.lru.MoveToFront(e.elem)
+	e.refs++
+	c.mu.Unlock()
+
+	rdr, err := c.open(e)
+	if err != nil {
+		// the result can't be read back, so the query runs again
+		c.mu.Lock()
+		c.stats.Misses++
+		if c.entries[k] == e {
+			c.remove(e)
+		}
+		c.unref(e)
+		c.mu.Unlock()
+		return nil, -1, true
+	}
+
+	c.mu.Lock()
+	c.stats.Hits++
+	c.mu.Unlock()
+	return rdr, e.rowsAffected, true
+}
+
+func (c *Cache) open(e *entry) (array.RecordReader, error) {
+	var src io.Reader = bytes.NewReader(e.data)
+	var file *os.File
+	if e.path != "" {
+		var err error
+		if file, err = os.Open(e.path); err != nil {
+			return nil, err
+		}
+		src = file
+	}
+
+	rdr, err := ipc.NewReader(src, ipc.WithAllocator(c.opts.Allocator))
+	if err != nil {
+		if file != nil {
+			_ = file.Close()
+		}
+		return nil, err
+	}
+	r := &cachedReader{Reader: rdr, done: func() {
+		if file != nil {
+			_ = file.Close()
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.unref(e)
+	}}
+	r.refs.Store(1)
+	return r, nil
+}
+
+// put stores a result, replacing any previous one with the same key.
+func (c *Cache) put(e *entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.refs = 1
+	if c.closed {
+		c.unref(e)
+		return
+	}
+	if old := c.entries[e.key]; old != nil {
+		c.remove(old)
+	}
+	e.storedAt = c.now()
+	e.elem = c.lru.PushFront(e)
+	c.entries[e.key] = e
+	c.size += e.size
+	c.stats.Stored++
+
+	for c.opts.MaxBytes > 0 && c.size > c.opts.MaxBytes && c.lru.Len() > 1 {
+		c.stats.Evicted++
+		c.remove(c.lru.Back().Value.(*entry))
+	}
+}
+
+// remove drops an entry from the cache. c.mu must be held.
+func (c *Cache) remove(e *entry) {
+	delete(c.entries, e.key)
+	c.lru.Remove(e.elem)
+	c.size -= e.size
+	c.unref(e)
+}
+
+// unref drops a reference to an entry. c.mu must be held.
+func (c *Cache) unref(e *entry) {
+	e.refs--
+	if e.refs == 0 && e.path != "" {
+		_ = os.Remove(e.path)
+	}
+}
+
+// cachedReader reads a stored result.
+type cachedReader struct {
+	*ipc.Reader
+
+	refs atomic.Int64
+	done func()
+}
+
+func (r *cachedReader) Retain() {
+	r.refs.Add(1)
+}
+
+func (r *cachedReader) Release() {
+	if r.refs.Add(-1) == 0 {
+		r.Reader.Release()
+		r.done()
+	}
+}
+
+// sink is where a result is written while it is read from the database.
+type sink struct {
+	buf  bytes.Buffer
+	file *os.File
+	size int64
+}
+
+func (c *Cache) newSink() (*sink, error) {
+	s := &sink{}
+	if c.opts.Dir != "" {
+		var err error
+		if s.file, err = os.CreateTemp(c.opts.Dir, "result-*.arrows"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *sink) Write(p []byte) (int, error) {
+	s.size += int64(len(p))
+	if s.file != nil {
+		return s.file.Write(p)
+	}
+	return s.buf.Write(p)
+}
+
+// entry finishes writing the result, and returns the entry holding it.
+func (s *sink) entry() (*entry, error) {
+	e := &entry{size: s.size, data: s.buf.Bytes()}
+	if s.file != nil {
+		e.path = s.file.Name()
+		if err := s.file.Close(); err != nil {
+			_ = os.Remove(e.path)
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// discard drops the result.
+func (s *sink) discard() {
+	if s.file != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name())
+	}
+	s.buf = bytes.Buffer{}
+}
+
+// recordingReader stores the records it reads from a database as they
+// are read, once they have all been read.
+type recordingReader struct {
+	array.RecordReader
+	refCount atomic.Int64
+
+	cache        *Cache
+	key          key
+	query        string
+	rowsAffected int64
+
+	sink *sink
+	w    *ipc.Writer
+}
+
+// record returns rdr, storing its records as they are read.
+func (c *Cache) record(k key, query string, rdr array.RecordReader, rowsAffected int64) array.RecordReader {
+	s, err := c.newSink()
+	if err != nil {
+		return rdr
+	}
+	r := &recordingReader{
+		RecordReader: rdr,
+		cache:        c,
+		key:          k,
+		query:        query,
+		rowsAffected: rowsAffected,
+		sink:         s,
+		w:            ipc.NewWriter(s, ipc.WithSchema(rdr.Schema()), ipc.WithAllocator(c.opts.Allocator)),
+	}
+	r.refCount.Store(1)
+	return r
+}
+
+func (r *recordingReader) Next() bool {
+	if !r.RecordReader.Next() {
+		if r.w != nil {
+			if r.RecordReader.Err() == nil {
+				r.store()
+			} else {
+				r.abandon()
+			}
+		}
+		return false
+	}
+
+	if r.w != nil {
+		err := r.w.Write(r.RecordReader.Record())
+		if err == nil && r.cache.maxEntry > 0 && r.sink.size > r.cache.maxEntry {
+			err = errTooLarge
+			r.cache.mu.Lock()
+			r.cache.stats.TooLarge++
+			r.cache.mu.Unlock()
+		}
+		if err != nil {
+			r.abandon()
+		}
+	}
+	return true
+}
+
+var errTooLarge = errors.New("result too large")
+
+func (r *recordingReader) store() {
+	err := r.w.Close()
+	r.w = nil
+	if err != nil {
+		r.sink.discard()
+		return
+	}
+	if r.cache.maxEntry > 0 && r.sink.size > r.cache.maxEntry {
+		r.cache.mu.Lock()
+		r.cache.stats.TooLarge++
+		r.cache.mu.Unlock()
+		r.sink.discard()
+		return
+	}
+
+	e, err := r.sink.entry()
+	if err != nil {
+		return
+	}
+	e.key, e.query, e.rowsAffected = r.key, r.query, r.rowsAffected
+	r.cache.put(e)
+}
+
+func (r *recordingReader) abandon() {
+	_ = r.w.Close()
+	r.w = nil
+	r.sink.discard()
+}
+
+func (r *recordingReader) Retain() {
+	r.refCount.Add(1)
+	r.RecordReader.Retain()
+}
+
+// Release releases the reader. Results released before they were read
+// to the end are not stored.
+func (r *recordingReader) Release() {
+	if r.refCount.Add(-1) == 0 && r.w != nil {
+		r.abandon()
+	}
+	r.RecordReader.Release()
+}
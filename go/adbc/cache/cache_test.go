@@ -0,0 +1,322 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cache
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockConnection struct {
+	adbc.Connection
+	adbc.GetSetOptions
+
+	mem      memory.Allocator
+	options  map[string]string
+	executed int
+}
+
+func (c *mockConnection) NewStatement() (adbc.Statement, error) {
+	return &mockStatement{cnxn: c, options: make(map[string]string)}, nil
+}
+
+func (c *mockConnection) GetOption(key string) (string, error) {
+	if val, ok := c.options[key]; ok {
+		return val, nil
+	}
+	return "", adbc.Error{Code: adbc.StatusNotFound}
+}
+
+func (c *mockConnection) SetOption(key, value string) error {
+	c.options[key] = value
+	return nil
+}
+
+// mockStatement returns the query, the parameters and the options it ran
+// with as rows.
+type mockStatement struct {
+	adbc.Statement
+
+	cnxn    *mockConnection
+	query   string
+	bound   arrow.Record
+	options map[string]string
+}
+
+func (s *mockStatement) SetSqlQuery(query string) error {
+	s.query = query
+	return nil
+}
+
+func (s *mockStatement) SetOption(key, value string) error {
+	s.options[key] = value
+	return nil
+}
+
+func (s *mockStatement) Bind(_ context.Context, values arrow.Record) error {
+	s.bound = values
+	return nil
+}
+
+func (s *mockStatement) BindStream(context.Context, array.RecordReader) error {
+	return nil
+}
+
+func (s *mockStatement) Close() error {
+	return nil
+}
+
+var resultSchema = arrow.NewSchema([]arrow.Field{{Name: "run", Type: arrow.BinaryTypes.String}}, nil)
+
+func (s *mockStatement) ExecuteQuery(context.Context) (array.RecordReader, int64, error) {
+	s.cnxn.executed++
+	bldr := array.NewRecordBuilder(s.cnxn.mem, resultSchema)
+	defer bldr.Release()
+	run := bldr.Field(0).(*array.StringBuilder)
+	run.Append(s.query)
+	if s.bound != nil {
+		run.Append(s.bound.Column(0).ValueStr(0))
+	}
+	run.Append(s.cnxn.options[adbc.OptionKeyCurrentCatalog] + "." + s.options["tz"])
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	rdr, err := array.NewRecordReader(resultSchema, []arrow.Record{rec, rec})
+	return rdr, -1, err
+}
+
+func newTestCache(t *testing.T, opts Options) (*Cache, *mockConnection, *memory.CheckedAllocator) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	opts.Allocator = mem
+	c, err := New(opts)
+	require.NoError(t, err)
+	cnxn := &mockConnection{mem: mem, options: map[string]string{adbc.OptionKeyCurrentCatalog: "db"}}
+	t.Cleanup(func() {
+		_ = c.Close()
+		mem.AssertSize(t, 0)
+	})
+	return c, cnxn, mem
+}
+
+// query runs a query to the end and returns its first column.
+func query(t *testing.T, stmt adbc.Statement, query string) string {
+	require.NoError(t, stmt.SetSqlQuery(query))
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	var values []string
+	for rdr.Next() {
+		col := rdr.Record().Column(0)
+		for i := range col.Len() {
+			values = append(values, col.ValueStr(i))
+		}
+	}
+	require.NoError(t, rdr.Err())
+	return strings.Join(values, ",")
+}
+
+func TestHit(t *testing.T) {
+	for _, dir := range []string{"", t.TempDir()} {
+		c, cnxn, _ := newTestCache(t, Options{Dir: dir})
+		stmt, err := c.Wrap(cnxn).NewStatement()
+		require.NoError(t, err)
+		defer stmt.Close()
+
+		assert.Equal(t, "q,db.,q,db.", query(t, stmt, "q"))
+		assert.Equal(t, "q,db.,q,db.", query(t, stmt, "q"))
+		assert.Equal(t, 1, cnxn.executed)
+		stats := c.Stats()
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(1), stats.Misses)
+		assert.Equal(t, 1, stats.Entries)
+		assert.Positive(t, stats.Bytes)
+
+		if dir != "" {
+			files, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, files, 1)
+			c.InvalidateAll()
+			files, err = os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, files)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	c, cnxn, mem := newTestCache(t, Options{KeyOptions: []string{"tz"}})
+	wrapped := c.Wrap(cnxn)
+	stmt, err := wrapped.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	query(t, stmt, "q")
+	assert.Equal(t, "other,db.,other,db.", query(t, stmt, "other"))
+
+	require.NoError(t, wrapped.SetOption(adbc.OptionKeyCurrentCatalog, "db2"))
+	assert.Equal(t, "q,db2.,q,db2.", query(t, stmt, "q"))
+
+	require.NoError(t, stmt.SetOption("tz", "UTC"))
+	assert.Equal(t, "q,db2.UTC,q,db2.UTC", query(t, stmt, "q"))
+	require.NoError(t, stmt.SetOption("unrelated", "x"))
+	query(t, stmt, "q")
+	assert.Equal(t, 4, cnxn.executed)
+
+	for _, param := range []int64{1, 2, 1} {
+		bldr := array.NewRecordBuilder(mem, arrow.NewSchema([]arrow.Field{{Name: "p", Type: arrow.PrimitiveTypes.Int64}}, nil))
+		bldr.Field(0).(*array.Int64Builder).Append(param)
+		rec := bldr.NewRecord()
+		bldr.Release()
+		require.NoError(t, stmt.Bind(context.Background(), rec))
+		rec.Release()
+		query(t, stmt, "q")
+	}
+	assert.Equal(t, 6, cnxn.executed)
+}
+
+func TestBypass(t *testing.T) {
+	c, cnxn, _ := newTestCache(t, Options{})
+	stmt, err := c.Wrap(cnxn).NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	require.NoError(t, stmt.SetOption(OptionKeyBypass, adbc.OptionValueEnabled))
+	query(t, stmt, "q")
+	query(t, stmt, "q")
+	require.NoError(t, stmt.SetOption(OptionKeyBypass, adbc.OptionValueDisabled))
+	query(t, stmt, "q")
+	require.NoError(t, stmt.BindStream(context.Background(), nil))
+	query(t, stmt, "q")
+	assert.Equal(t, 4, cnxn.executed)
+	assert.Equal(t, int64(3), c.Stats().Bypassed)
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, stmt.SetOption(OptionKeyBypass, "maybe"), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestPartialRead(t *testing.T) {
+	c, cnxn, _ := newTestCache(t, Options{})
+	stmt, err := c.Wrap(cnxn).NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	require.NoError(t, stmt.SetSqlQuery("q"))
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	require.True(t, rdr.Next())
+	rdr.Release()
+	assert.Equal(t, 0, c.Stats().Entries)
+
+	query(t, stmt, "q")
+	assert.Equal(t, 2, cnxn.executed)
+}
+
+func TestRetainedRead(t *testing.T) {
+	c, cnxn, _ := newTestCache(t, Options{})
+	stmt, err := c.Wrap(cnxn).NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	require.NoError(t, stmt.SetSqlQuery("q"))
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	// releasing one of two references doesn't abandon the result
+	rdr.Retain()
+	rdr.Release()
+	for rdr.Next() {
+	}
+	require.NoError(t, rdr.Err())
+	rdr.Release()
+	assert.Equal(t, 1, c.Stats().Entries)
+
+	query(t, stmt, "q")
+	assert.Equal(t, 1, cnxn.executed)
+}
+
+func TestLimits(t *testing.T) {
+	c, cnxn, _ := newTestCache(t, Options{TTL: time.Minute})
+	clock := time.Unix(0, 0)
+	c.now = func() time.Time { return clock }
+	stmt, err := c.Wrap(cnxn).NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	query(t, stmt, "q")
+	size := c.Stats().Bytes
+	clock = clock.Add(time.Minute)
+	query(t, stmt, "q")
+	assert.Equal(t, 2, cnxn.executed)
+	assert.Equal(t, int64(1), c.Stats().Expired)
+
+	// room for two results
+	c.opts.MaxBytes = 2*size + size/2
+	query(t, stmt, "a")
+	query(t, stmt, "q")
+	query(t, stmt, "b")
+	stats := c.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, int64(1), stats.Evicted)
+	query(t, stmt, "q")
+	query(t, stmt, "a")
+	assert.Equal(t, 5, cnxn.executed)
+
+	c.maxEntry = size - 1
+	query(t, stmt, "c")
+	assert.Equal(t, int64(1), c.Stats().TooLarge)
+}
+
+func TestInvalidate(t *testing.T) {
+	c, cnxn, _ := newTestCache(t, Options{})
+	wrapped := c.Wrap(cnxn)
+	stmt, err := wrapped.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	query(t, stmt, "q")
+	require.NoError(t, wrapped.SetOption(adbc.OptionKeyCurrentCatalog, "db2"))
+	query(t, stmt, "q")
+	query(t, stmt, "other")
+	assert.Equal(t, 2, c.Invalidate("q"))
+	assert.Equal(t, 1, c.Stats().Entries)
+
+	// readers of dropped results stay valid
+	require.NoError(t, stmt.SetSqlQuery("other"))
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	require.NoError(t, err)
+	defer rdr.Release()
+	c.InvalidateAll()
+	require.True(t, rdr.Next())
+	assert.Equal(t, "other", rdr.Record().Column(0).ValueStr(0))
+
+	require.NoError(t, c.Close())
+	query(t, stmt, "other")
+	assert.Equal(t, 0, c.Stats().Entries)
+	assert.Equal(t, 4, cnxn.executed)
+}
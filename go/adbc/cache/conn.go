@@ -0,0 +1,405 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"slices"
+	"strconv"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// OptionKeyBypass is a statement option. When enabled, the queries of the
+// statement are passed through without looking up or storing results.
+const OptionKeyBypass = "adbc.cache.bypass"
+
+// Conn is a connection whose queries are served from a Cache.
+type Conn struct {
+	adbc.Connection
+
+	cache *Cache
+}
+
+// Unwrap returns the underlying connection, to reach the extensions of
+// the driver.
+func (c *Conn) Unwrap() adbc.Connection {
+	return c.Connection
+}
+
+func (c *Conn) NewStatement() (adbc.Statement, error) {
+	stmt, err := c.Connection.NewStatement()
+	if err != nil {
+		return nil, err
+	}
+	return &Statement{Statement: stmt, cnxn: c, options: make(map[string]string)}, nil
+}
+
+// getOption returns an option of the connection, or "" if the driver
+// doesn't report it.
+func (c *Conn) getOption(key string) string {
+	if opts, ok := c.Connection.(adbc.GetSetOptions); ok {
+		if val, err := opts.GetOption(key); err == nil {
+			return val
+		}
+	}
+	return ""
+}
+
+// GetOption implements adbc.GetSetOptions.
+func (c *Conn) GetOption(key string) (string, error) {
+	opts, err := c.options()
+	if err != nil {
+		return "", err
+	}
+	return opts.GetOption(key)
+}
+
+// GetOptionBytes implements adbc.GetSetOptions.
+func (c *Conn) GetOptionBytes(key string) ([]byte, error) {
+	opts, err := c.options()
+	if err != nil {
+		return nil, err
+	}
+	return opts.GetOptionBytes(key)
+}
+
+// GetOptionInt implements adbc.GetSetOptions.
+func (c *Conn) GetOptionInt(key string) (int64, error) {
+	opts, err := c.options()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionInt(key)
+}
+
+// GetOptionDouble implements adbc.GetSetOptions.
+func (c *Conn) GetOptionDouble(key string) (float64, error) {
+	opts, err := c.options()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionDouble(key)
+}
+
+// SetOption implements adbc.PostInitOptions.
+func (c *Conn) SetOption(key, value string) error {
+	if opts, ok := c.Connection.(adbc.PostInitOptions); ok {
+		return opts.SetOption(key, value)
+	}
+	return errNotSupported("SetOption")
+}
+
+// SetOptionBytes implements adbc.GetSetOptions.
+func (c *Conn) SetOptionBytes(key string, value []byte) error {
+	opts, err := c.options()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionBytes(key, value)
+}
+
+// SetOptionInt implements adbc.GetSetOptions.
+func (c *Conn) SetOptionInt(key string, value int64) error {
+	opts, err := c.options()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionInt(key, value)
+}
+
+// SetOptionDouble implements adbc.GetSetOptions.
+func (c *Conn) SetOptionDouble(key string, value float64) error {
+	opts, err := c.options()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionDouble(key, value)
+}
+
+func (c *Conn) options() (adbc.GetSetOptions, error) {
+	if opts, ok := c.Connection.(adbc.GetSetOptions); ok {
+		return opts, nil
+	}
+	return nil, errNotSupported("GetSetOptions")
+}
+
+func errNotSupported(what string) error {
+	return adbc.Error{
+		Msg:  "[cache] the driver doesn't support " + what,
+		Code: adbc.StatusNotImplemented,
+	}
+}
+
+// Statement is a statement whose queries are served from a Cache.
+type Statement struct {
+	adbc.Statement
+
+	cnxn  *Conn
+	query string
+	// bound is the record of parameters, retained for the key
+	bound arrow.Record
+	// queries pass through during bulk ingestion, with Substrait plans,
+	// with a stream of parameters, or when bypass is set
+	ingest    bool
+	substrait bool
+	stream    bool
+	bypass    bool
+	// options holds the values of the key options set on the statement
+	options map[string]string
+}
+
+func (s *Statement) Close() error {
+	s.setBound(nil)
+	return s.Statement.Close()
+}
+
+func (s *Statement) setBound(rec arrow.Record) {
+	if s.bound != nil {
+		s.bound.Release()
+	}
+	if rec != nil {
+		rec.Retain()
+	}
+	s.bound = rec
+}
+
+func (s *Statement) SetSqlQuery(query string) error {
+	if err := s.Statement.SetSqlQuery(query); err != nil {
+		return err
+	}
+	s.query, s.ingest, s.substrait = query, false, false
+	return nil
+}
+
+func (s *Statement) SetSubstraitPlan(plan []byte) error {
+	if err := s.Statement.SetSubstraitPlan(plan); err != nil {
+		return err
+	}
+	s.query, s.substrait = "", true
+	return nil
+}
+
+func (s *Statement) Bind(ctx context.Context, values arrow.Record) error {
+	if err := s.Statement.Bind(ctx, values); err != nil {
+		return err
+	}
+	s.setBound(values)
+	s.stream = false
+	return nil
+}
+
+// BindStream binds a stream of parameters. Streams can only be read once,
+// so the queries of the statement are passed through until parameters are
+// bound with Bind.
+func (s *Statement) BindStream(ctx context.Context, stream array.RecordReader) error {
+	if err := s.Statement.BindStream(ctx, stream); err != nil {
+		return err
+	}
+	s.setBound(nil)
+	s.stream = true
+	return nil
+}
+
+func (s *Statement) SetOption(key, value string) error {
+	if key == OptionKeyBypass {
+		switch value {
+		case adbc.OptionValueEnabled:
+			s.bypass = true
+		case adbc.OptionValueDisabled:
+			s.bypass = false
+		default:
+			return adbc.Error{
+				Msg:  "[cache] invalid value " + strconv.Quote(value) + " for option " + key,
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
+		return nil
+	}
+
+	if err := s.Statement.SetOption(key, value); err != nil {
+		return err
+	}
+	s.recordOption(key, value)
+	if key == adbc.OptionKeyIngestTargetTable {
+		s.ingest = value != ""
+	}
+	return nil
+}
+
+func (s *Statement) recordOption(key, value string) {
+	if slices.Contains(s.cnxn.cache.opts.KeyOptions, key) {
+		s.options[key] = value
+	}
+}
+
+// GetOption implements adbc.GetSetOptions.
+func (s *Statement) GetOption(key string) (string, error) {
+	if key == OptionKeyBypass {
+		if s.bypass {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
+	}
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return "", err
+	}
+	return opts.GetOption(key)
+}
+
+// GetOptionBytes implements adbc.GetSetOptions.
+func (s *Statement) GetOptionBytes(key string) ([]byte, error) {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return nil, err
+	}
+	return opts.GetOptionBytes(key)
+}
+
+// GetOptionInt implements adbc.GetSetOptions.
+func (s *Statement) GetOptionInt(key string) (int64, error) {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionInt(key)
+}
+
+// GetOptionDouble implements adbc.GetSetOptions.
+func (s *Statement) GetOptionDouble(key string) (float64, error) {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionDouble(key)
+}
+
+// SetOptionBytes implements adbc.GetSetOptions.
+func (s *Statement) SetOptionBytes(key string, value []byte) error {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return err
+	}
+	if err := opts.SetOptionBytes(key, value); err != nil {
+		return err
+	}
+	s.recordOption(key, string(value))
+	return nil
+}
+
+// SetOptionInt implements adbc.GetSetOptions.
+func (s *Statement) SetOptionInt(key string, value int64) error {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return err
+	}
+	if err := opts.SetOptionInt(key, value); err != nil {
+		return err
+	}
+	s.recordOption(key, strconv.FormatInt(value, 10))
+	return nil
+}
+
+// SetOptionDouble implements adbc.GetSetOptions.
+func (s *Statement) SetOptionDouble(key string, value float64) error {
+	opts, err := s.getSetOptions()
+	if err != nil {
+		return err
+	}
+	if err := opts.SetOptionDouble(key, value); err != nil {
+		return err
+	}
+	s.recordOption(key, strconv.FormatFloat(value, 'g', -1, 64))
+	return nil
+}
+
+func (s *Statement) getSetOptions() (adbc.GetSetOptions, error) {
+	if opts, ok := s.Statement.(adbc.GetSetOptions); ok {
+		return opts, nil
+	}
+	return nil, errNotSupported("GetSetOptions")
+}
+
+// ExecuteQuery returns the stored results of the query if there are any,
+// or else runs it and stores its results as they are read.
+func (s *Statement) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	if s.bypass || s.ingest || s.substrait || s.stream || s.query == "" {
+		s.cnxn.cache.bypass()
+		return s.Statement.ExecuteQuery(ctx)
+	}
+
+	k, err := s.key()
+	if err != nil {
+		s.cnxn.cache.bypass()
+		return s.Statement.ExecuteQuery(ctx)
+	}
+	rdr, rowsAffected, ok := s.cnxn.cache.get(k)
+	if rdr != nil {
+		return rdr, rowsAffected, nil
+	}
+
+	rdr, rowsAffected, err = s.Statement.ExecuteQuery(ctx)
+	if err != nil || !ok {
+		return rdr, rowsAffected, err
+	}
+	return s.cnxn.cache.record(k, s.query, rdr, rowsAffected), rowsAffected, nil
+}
+
+// key hashes what the results of the query depend on.
+func (s *Statement) key() (key, error) {
+	h := sha256.New()
+	writeString(h, s.query)
+	writeString(h, s.cnxn.getOption(adbc.OptionKeyCurrentCatalog))
+	writeString(h, s.cnxn.getOption(adbc.OptionKeyCurrentDbSchema))
+	for _, opt := range s.cnxn.cache.opts.KeyOptions {
+		val, ok := s.options[opt]
+		if !ok {
+			val = s.cnxn.getOption(opt)
+		}
+		writeString(h, opt)
+		writeString(h, val)
+	}
+
+	if s.bound != nil {
+		w := ipc.NewWriter(h, ipc.WithSchema(s.bound.Schema()), ipc.WithAllocator(s.cnxn.cache.opts.Allocator))
+		if err := w.Write(s.bound); err != nil {
+			return key{}, err
+		}
+		if err := w.Close(); err != nil {
+			return key{}, err
+		}
+	}
+
+	var k key
+	h.Sum(k[:0])
+	return k, nil
+}
+
+// writeString writes a length-prefixed string, so that consecutive
+// strings can't be confused.
+func writeString(h hash.Hash, s string) {
+	_ = binary.Write(h, binary.LittleEndian, uint64(len(s)))
+	_, _ = h.Write([]byte(s))
+}
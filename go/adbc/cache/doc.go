@@ -0,0 +1,52 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package cache caches the results of queries run through any
+// adbc.Connection, so that applications re-running the same read-only
+// queries, like dashboards, don't pay for them every time.
+//
+//	c, err := cache.New(cache.Options{MaxBytes: 1 << 30, TTL: 5 * time.Minute})
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Close()
+//
+//	cnxn := c.Wrap(rawCnxn)
+//	stmt, err := cnxn.NewStatement()
+//	...
+//	// served from the cache if the same query ran before
+//	rdr, _, err := stmt.ExecuteQuery(ctx)
+//
+// Results of ExecuteQuery are keyed on the query text, the bound
+// parameters, the current catalog and schema of the connection, and the
+// values of the options listed in Options.KeyOptions. They are stored as
+// Arrow IPC streams, in memory or in files, as the caller reads them, and
+// only once the caller has read them all without error. Cached results
+// expire after Options.TTL, and the least recently used ones are evicted
+// beyond Options.MaxBytes.
+//
+// The cache doesn't know which queries write data or which tables a query
+// reads: only run queries whose results may be stale for up to the TTL
+// through a cached connection, or invalidate the cache explicitly. Other
+// statements, bulk ingestion, Substrait plans and queries with a stream of
+// parameters are passed through. Setting OptionKeyBypass on a statement
+// passes its queries through too.
+//
+// A cache should only be shared by connections to the same database.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+package cache
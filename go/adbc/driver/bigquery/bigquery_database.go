@@ -77,6 +77,7 @@ func (d *databaseImpl) Open(ctx context.Context) (adbc.Connection, error) {
 		WithCurrentNamespacer(conn).
 		WithTableTypeLister(conn).
 		WithDbObjectsEnumerator(conn).
+		WithStatisticsEnumerator(conn).
 		Connection(), nil
 }
 
@@ -0,0 +1,669 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	storage "cloud.google.com/go/bigquery/storage/apiv1"
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AppendRows requests are limited to 10 MB, leave some room for the
+	// rest of the request.
+	maxAppendRowsBytes = 9 * 1024 * 1024
+	// the length of the end-of-stream marker written by ipc.Writer.Close
+	ipcEOSLength = 8
+
+	// how often to retry opening a write stream on a table that was just
+	// created but is not yet visible to the Storage Write API
+	maxCreateWriteStreamAttempts = 5
+
+	// how long a staging table for a replace outlives a failed cleanup
+	stagingTableExpiration = 24 * time.Hour
+)
+
+// storageWriteUnavailableError reports that the Storage Write API refused
+// Arrow data before anything was written, so the data can still be loaded
+// another way. Arrow rows are only accepted for allowlisted projects.
+type storageWriteUnavailableError struct {
+	err error
+	// the first record from the stream, which was not written. It is
+	// retained and must be released by the receiver of the error.
+	pending arrow.Record
+}
+
+func (e *storageWriteUnavailableError) Error() string {
+	return fmt.Sprintf("Storage Write API unavailable: %s", e.err)
+}
+
+func (e *storageWriteUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// isStorageWriteUnavailable returns true for errors that mean this project
+// can't use Arrow with the Storage Write API at all, as opposed to a
+// problem with the data. Invalid arguments and failed preconditions are
+// reported as they are, since a load job would most likely fail the same
+// way.
+func isStorageWriteUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unimplemented, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
+
+// toBigQueryType maps an Arrow type to the type of a BigQuery column.
+// Lists are handled by the caller since they map to REPEATED fields.
+func toBigQueryType(dt arrow.DataType) bigquery.FieldType {
+	switch dt.ID() {
+	case arrow.EXTENSION:
+		return toBigQueryType(dt.(arrow.ExtensionType).StorageType())
+	case arrow.DICTIONARY:
+		return toBigQueryType(dt.(*arrow.DictionaryType).ValueType)
+	case arrow.RUN_END_ENCODED:
+		return toBigQueryType(dt.(*arrow.RunEndEncodedType).Encoded())
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32:
+		// UINT64 is left out: values above math.MaxInt64 do not fit in
+		// INTEGER, and the data is written as-is
+		return bigquery.IntegerFieldType
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return bigquery.FloatFieldType
+	case arrow.DECIMAL128:
+		dec := dt.(arrow.DecimalType)
+		// NUMERIC has at most 29 integer digits and 9 fractional digits
+		if dec.GetScale() >= 0 && dec.GetScale() <= 9 && dec.GetPrecision()-dec.GetScale() <= 29 {
+			return bigquery.NumericFieldType
+		}
+		return bigquery.BigNumericFieldType
+	case arrow.DECIMAL256:
+		return bigquery.BigNumericFieldType
+	case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
+		return bigquery.StringFieldType
+	case arrow.BINARY, arrow.LARGE_BINARY, arrow.BINARY_VIEW, arrow.FIXED_SIZE_BINARY:
+		return bigquery.BytesFieldType
+	case arrow.BOOL:
+		return bigquery.BooleanFieldType
+	case arrow.TIME32, arrow.TIME64:
+		return bigquery.TimeFieldType
+	case arrow.DATE32, arrow.DATE64:
+		return bigquery.DateFieldType
+	case arrow.TIMESTAMP:
+		if dt.(*arrow.TimestampType).TimeZone == "" {
+			return bigquery.DateTimeFieldType
+		}
+		return bigquery.TimestampFieldType
+	case arrow.STRUCT:
+		return bigquery.RecordFieldType
+	}
+
+	return ""
+}
+
+// toBigQueryField converts an Arrow field into the schema of a BigQuery
+// column, recursing into lists and structs.
+func toBigQueryField(f arrow.Field) (*bigquery.FieldSchema, error) {
+	field := &bigquery.FieldSchema{
+		Name:     f.Name,
+		Required: !f.Nullable,
+	}
+
+	dt := f.Type
+	if ext, ok := dt.(arrow.ExtensionType); ok {
+		dt = ext.StorageType()
+	}
+
+	if list, ok := dt.(arrow.ListLikeType); ok && dt.ID() != arrow.MAP {
+		elem := list.ElemField()
+		if _, nested := elem.Type.(arrow.ListLikeType); nested {
+			return nil, adbc.Error{
+				Code: adbc.StatusNotImplemented,
+				Msg:  fmt.Sprintf("[BigQuery] cannot ingest field %s: BigQuery does not support nested arrays, arrow type: %s", f.Name, f.Type),
+			}
+		}
+
+		inner, err := toBigQueryField(elem)
+		if err != nil {
+			return nil, err
+		}
+		inner.Name = f.Name
+		inner.Repeated = true
+		// REPEATED fields cannot also be REQUIRED
+		inner.Required = false
+		return inner, nil
+	}
+
+	field.Type = toBigQueryType(dt)
+	if field.Type == "" {
+		return nil, adbc.Error{
+			Code: adbc.StatusNotImplemented,
+			Msg:  fmt.Sprintf("[BigQuery] unimplemented type conversion for field %s, arrow type: %s", f.Name, f.Type),
+		}
+	}
+
+	switch field.Type {
+	case bigquery.RecordFieldType:
+		for _, child := range dt.(*arrow.StructType).Fields() {
+			childSchema, err := toBigQueryField(child)
+			if err != nil {
+				return nil, err
+			}
+			field.Schema = append(field.Schema, childSchema)
+		}
+	case bigquery.NumericFieldType, bigquery.BigNumericFieldType:
+		if dec, ok := dt.(arrow.DecimalType); ok {
+			field.Precision = int64(dec.GetPrecision())
+			field.Scale = int64(dec.GetScale())
+		}
+	}
+	return field, nil
+}
+
+func toBigQuerySchema(schema *arrow.Schema) (bigquery.Schema, error) {
+	out := make(bigquery.Schema, 0, schema.NumFields())
+	for _, f := range schema.Fields() {
+		field, err := toBigQueryField(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, field)
+	}
+	return out, nil
+}
+
+// initIngest prepares the target table according to the ingest mode and
+// reports whether the table was created. It is not used for replace,
+// which writes to a staging table instead.
+func (st *statement) initIngest(ctx context.Context, table *bigquery.Table, schema *arrow.Schema) (bool, error) {
+	if st.ingestMode == adbc.OptionValueIngestModeAppend {
+		return false, nil
+	}
+
+	bqSchema, err := toBigQuerySchema(schema)
+	if err != nil {
+		return false, err
+	}
+
+	err = table.Create(ctx, &bigquery.TableMetadata{Schema: bqSchema})
+	if err != nil {
+		if st.ingestMode == adbc.OptionValueIngestModeCreateAppend && googleapiErrorCode(err) == http.StatusConflict {
+			return false, nil
+		}
+		return false, apiErrToAdbcErr(err, "failed to create table")
+	}
+	return true, nil
+}
+
+func (st *statement) ingestTable() (*bigquery.Table, error) {
+	project := st.ingestCatalog
+	if project == "" {
+		project = st.cnxn.catalog
+	}
+	dataset := st.ingestDBSchema
+	if dataset == "" {
+		dataset = st.cnxn.dbSchema
+	}
+	if dataset == "" {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  fmt.Sprintf("[BigQuery] no dataset for bulk ingestion, set %s or %s", adbc.OptionValueIngestTargetDBSchema, OptionStringDatasetID),
+		}
+	}
+	return st.cnxn.client.DatasetInProject(project, dataset).Table(st.targetTable), nil
+}
+
+// createStagingTable creates an empty table next to table for a replace
+// to write to. The staging table expires on its own in case it can't be
+// dropped afterwards.
+func (st *statement) createStagingTable(ctx context.Context, table *bigquery.Table, schema *arrow.Schema) (*bigquery.Table, error) {
+	bqSchema, err := toBigQuerySchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s_adbc_staging_%s", table.TableID, strings.ReplaceAll(uuid.NewString(), "-", ""))
+	staging := st.cnxn.client.DatasetInProject(table.ProjectID, table.DatasetID).Table(name)
+	err = staging.Create(ctx, &bigquery.TableMetadata{
+		Schema:         bqSchema,
+		ExpirationTime: time.Now().Add(stagingTableExpiration),
+	})
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "failed to create staging table for replace")
+	}
+	return staging, nil
+}
+
+// replaceTable overwrites table, schema included, with the contents of
+// staging. The copy job truncates the target atomically, so the target
+// keeps its old contents if anything fails before this point.
+func replaceTable(ctx context.Context, table, staging *bigquery.Table) error {
+	copier := table.CopierFrom(staging)
+	copier.CreateDisposition = bigquery.CreateIfNeeded
+	copier.WriteDisposition = bigquery.WriteTruncate
+
+	job, err := copier.Run(ctx)
+	if err != nil {
+		return apiErrToAdbcErr(err, "failed to start copy job for replace")
+	}
+	jobStatus, err := job.Wait(ctx)
+	if err == nil {
+		err = jobStatus.Err()
+	}
+	if err != nil {
+		return jobErrToAdbcErr(err, job, "copy job for replace failed")
+	}
+	return nil
+}
+
+// executeIngest writes the bound data to the target table. It uses the
+// Storage Write API and falls back to a Parquet load job if the project
+// can't write Arrow data that way.
+func (st *statement) executeIngest(ctx context.Context) (int64, error) {
+	// neither the Storage Write API nor load jobs can join a transaction
+	if st.cnxn.sessionID != "" {
+		return -1, adbc.Error{
+			Msg:  "bulk ingestion is not supported while autocommit is disabled",
+			Code: adbc.StatusNotImplemented,
+		}
+	}
+
+	rdr, err := st.getBoundParameterReader()
+	if err != nil {
+		return -1, err
+	}
+	if rdr == nil {
+		return -1, adbc.Error{
+			Msg:  "must call Bind before bulk ingestion",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	defer rdr.Release()
+	defer st.State.ClearBound()
+
+	table, err := st.ingestTable()
+	if err != nil {
+		return -1, err
+	}
+
+	// a replace writes everything to a staging table first, so that the
+	// target is left alone if the write fails
+	dest := table
+	created := true
+	if st.ingestMode == adbc.OptionValueIngestModeReplace {
+		dest, err = st.createStagingTable(ctx, table, rdr.Schema())
+		if err != nil {
+			return -1, err
+		}
+		defer func() {
+			_ = dest.Delete(context.WithoutCancel(ctx))
+		}()
+	} else {
+		created, err = st.initIngest(ctx, table, rdr.Schema())
+		if err != nil {
+			return -1, err
+		}
+	}
+
+	wc, err := st.cnxn.storageWriteClient(ctx)
+	if err != nil {
+		return -1, apiErrToAdbcErr(err, "failed to create Storage Write API client")
+	}
+
+	nrows, err := writeArrowStream(ctx, wc, dest, rdr, st.alloc, created)
+	var unavailable *storageWriteUnavailableError
+	if errors.As(err, &unavailable) {
+		nrows, err = loadParquet(ctx, dest, unavailable.pending, rdr, st.alloc)
+		if err != nil {
+			return -1, err
+		}
+	} else if err != nil {
+		return -1, apiErrToAdbcErr(err, "failed to write to table")
+	}
+
+	if dest != table {
+		if err := replaceTable(ctx, table, dest); err != nil {
+			return -1, err
+		}
+	}
+	return nrows, nil
+}
+
+func tableResourceName(table *bigquery.Table) string {
+	return fmt.Sprintf("projects/%s/datasets/%s/tables/%s", table.ProjectID, table.DatasetID, table.TableID)
+}
+
+// ipcSerializer produces the separately serialized Arrow schema and
+// record batch messages that AppendRows expects.
+type ipcSerializer struct {
+	buf    bytes.Buffer
+	w      *ipc.Writer
+	schema []byte
+	first  bool
+}
+
+func newIPCSerializer(schema *arrow.Schema, mem memory.Allocator) (*ipcSerializer, error) {
+	// ipc.Writer only writes the schema along with the first batch, so
+	// serialize it on its own to find where it ends.
+	var schemaBuf bytes.Buffer
+	w := ipc.NewWriter(&schemaBuf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	s := &ipcSerializer{
+		schema: schemaBuf.Bytes()[:schemaBuf.Len()-ipcEOSLength],
+		first:  true,
+	}
+	s.w = ipc.NewWriter(&s.buf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	return s, nil
+}
+
+func (s *ipcSerializer) serialize(rec arrow.Record) ([]byte, error) {
+	s.buf.Reset()
+	if err := s.w.Write(rec); err != nil {
+		return nil, err
+	}
+
+	out := s.buf.Bytes()
+	if s.first {
+		out = out[len(s.schema):]
+		s.first = false
+	}
+	return bytes.Clone(out), nil
+}
+
+func (s *ipcSerializer) Close() error {
+	return s.w.Close()
+}
+
+// createWriteStream opens a pending write stream on the table. A table
+// that was just created may briefly not be visible to the Storage Write
+// API, so NotFound is retried in that case.
+func createWriteStream(ctx context.Context, wc *storage.BigQueryWriteClient, parent string, retryNotFound bool) (*storagepb.WriteStream, error) {
+	backoff := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		ws, err := wc.CreateWriteStream(ctx, &storagepb.CreateWriteStreamRequest{
+			Parent:      parent,
+			WriteStream: &storagepb.WriteStream{Type: storagepb.WriteStream_PENDING},
+		})
+		if err == nil || !retryNotFound || status.Code(err) != codes.NotFound || attempt == maxCreateWriteStreamAttempts {
+			return ws, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// writeArrowStream appends every record from rdr to the table through a
+// pending write stream, which is committed atomically once all of the
+// data has been appended.
+//
+// If Arrow data is refused before anything was written, a
+// *storageWriteUnavailableError holding the unwritten first record is
+// returned.
+func writeArrowStream(ctx context.Context, wc *storage.BigQueryWriteClient, table *bigquery.Table, rdr array.RecordReader, mem memory.Allocator, created bool) (int64, error) {
+	parent := tableResourceName(table)
+	if !rdr.Next() {
+		if err := rdr.Err(); err != nil {
+			return -1, err
+		}
+		return 0, nil
+	}
+	first := rdr.Record()
+	first.Retain()
+	defer func() {
+		if first != nil {
+			first.Release()
+		}
+	}()
+
+	unavailable := func(err error) error {
+		rec := first
+		first = nil
+		return &storageWriteUnavailableError{err: err, pending: rec}
+	}
+
+	ws, err := createWriteStream(ctx, wc, parent, created)
+	if err != nil {
+		if status.Code(err) == codes.Unimplemented || status.Code(err) == codes.PermissionDenied {
+			return -1, unavailable(err)
+		}
+		return -1, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := wc.AppendRows(ctx)
+	if err != nil {
+		return -1, err
+	}
+
+	serializer, err := newIPCSerializer(rdr.Schema(), mem)
+	if err != nil {
+		return -1, err
+	}
+	defer serializer.Close()
+
+	appender := &rowAppender{stream: stream, serializer: serializer, writeStream: ws.GetName()}
+	if err := appender.append(first); err != nil {
+		if isStorageWriteUnavailable(err) {
+			return -1, unavailable(err)
+		}
+		return -1, err
+	}
+	nrows := first.NumRows()
+	first.Release()
+	first = nil
+
+	for rdr.Next() {
+		rec := rdr.Record()
+		if err := appender.append(rec); err != nil {
+			return -1, err
+		}
+		nrows += rec.NumRows()
+	}
+	if err := rdr.Err(); err != nil {
+		return -1, err
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return -1, err
+	}
+
+	if _, err := wc.FinalizeWriteStream(ctx, &storagepb.FinalizeWriteStreamRequest{Name: ws.GetName()}); err != nil {
+		return -1, err
+	}
+
+	resp, err := wc.BatchCommitWriteStreams(ctx, &storagepb.BatchCommitWriteStreamsRequest{
+		Parent:       parent,
+		WriteStreams: []string{ws.GetName()},
+	})
+	if err != nil {
+		return -1, err
+	}
+	if len(resp.GetStreamErrors()) > 0 {
+		streamErr := resp.GetStreamErrors()[0]
+		return -1, fmt.Errorf("failed to commit write stream %s: %s", streamErr.GetEntity(), streamErr.GetErrorMessage())
+	}
+	return nrows, nil
+}
+
+// rowAppender sends records over an AppendRows stream, waiting for each
+// one to be acknowledged.
+type rowAppender struct {
+	stream      storagepb.BigQueryWrite_AppendRowsClient
+	serializer  *ipcSerializer
+	writeStream string
+	sentSchema  bool
+}
+
+func (a *rowAppender) append(rec arrow.Record) error {
+	data, err := a.serializer.serialize(rec)
+	if err != nil {
+		return err
+	}
+
+	if len(data) > maxAppendRowsBytes && rec.NumRows() > 1 {
+		// too large for a single request, split it up
+		half := rec.NumRows() / 2
+		left := rec.NewSlice(0, half)
+		defer left.Release()
+		right := rec.NewSlice(half, rec.NumRows())
+		defer right.Release()
+
+		if err := a.append(left); err != nil {
+			return err
+		}
+		return a.append(right)
+	}
+
+	arrowRows := &storagepb.AppendRowsRequest_ArrowData{
+		Rows: &storagepb.ArrowRecordBatch{SerializedRecordBatch: data},
+	}
+	req := &storagepb.AppendRowsRequest{
+		Rows: &storagepb.AppendRowsRequest_ArrowRows{ArrowRows: arrowRows},
+	}
+	if !a.sentSchema {
+		// only the first request on the connection needs to identify
+		// the stream and the schema of the data
+		req.WriteStream = a.writeStream
+		arrowRows.WriterSchema = &storagepb.ArrowSchema{SerializedSchema: a.serializer.schema}
+	}
+
+	if err := a.stream.Send(req); err != nil {
+		if err == io.EOF {
+			// the server closed the stream, the reason is in Recv
+			_, err = a.stream.Recv()
+		}
+		return err
+	}
+
+	resp, err := a.stream.Recv()
+	if err != nil {
+		return err
+	}
+	if respErr := resp.GetError(); respErr != nil {
+		return status.ErrorProto(respErr)
+	}
+	if rowErrs := resp.GetRowErrors(); len(rowErrs) > 0 {
+		return fmt.Errorf("row %d: %s", rowErrs[0].GetIndex(), rowErrs[0].GetMessage())
+	}
+	a.sentSchema = true
+	return nil
+}
+
+// loadParquet writes first and the rest of rdr to the table with a load
+// job, converting the data to Parquet on the fly.
+func loadParquet(ctx context.Context, table *bigquery.Table, first arrow.Record, rdr array.RecordReader, mem memory.Allocator) (int64, error) {
+	defer first.Release()
+
+	pr, pw := io.Pipe()
+	var nrows int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(writeParquet(pw, first, rdr, mem, &nrows))
+	}()
+
+	src := bigquery.NewReaderSource(pr)
+	src.SourceFormat = bigquery.Parquet
+	src.ParquetOptions = &bigquery.ParquetOptions{EnableListInference: true}
+
+	loader := table.LoaderFrom(src)
+	loader.CreateDisposition = bigquery.CreateNever
+	loader.WriteDisposition = bigquery.WriteAppend
+
+	job, err := loader.Run(ctx)
+	// unblock the writer if the upload stopped early
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	<-done
+	if err != nil {
+		return -1, apiErrToAdbcErr(err, "failed to start load job")
+	}
+
+	jobStatus, err := job.Wait(ctx)
+	if err == nil {
+		err = jobStatus.Err()
+	}
+	if err != nil {
+		return -1, jobErrToAdbcErr(err, job, "load job failed")
+	}
+	return nrows, nil
+}
+
+func writeParquet(w io.Writer, first arrow.Record, rdr array.RecordReader, mem memory.Allocator, nrows *int64) (err error) {
+	parquetProps := parquet.NewWriterProperties(
+		parquet.WithAllocator(mem),
+		parquet.WithCompression(compress.Codecs.Snappy),
+	)
+	// BigQuery stores timestamps with microsecond precision
+	arrowProps := pqarrow.NewArrowWriterProperties(
+		pqarrow.WithAllocator(mem),
+		pqarrow.WithCoerceTimestamps(arrow.Microsecond),
+		pqarrow.WithTruncatedTimestamps(true),
+	)
+
+	pqWriter, err := pqarrow.NewFileWriter(rdr.Schema(), w, parquetProps, arrowProps)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, pqWriter.Close())
+	}()
+
+	if err := pqWriter.WriteBuffered(first); err != nil {
+		return err
+	}
+	*nrows = first.NumRows()
+
+	for rdr.Next() {
+		rec := rdr.Record()
+		if err := pqWriter.WriteBuffered(rec); err != nil {
+			return err
+		}
+		*nrows += rec.NumRows()
+	}
+	return rdr.Err()
+}
@@ -0,0 +1,298 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	storage "cloud.google.com/go/bigquery/storage/apiv1"
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeWriteServer is a stand-in for the Storage Write API that decodes
+// and keeps everything appended to it.
+type fakeWriteServer struct {
+	storagepb.UnimplementedBigQueryWriteServer
+
+	mem memory.Allocator
+	// if set, returned from the first AppendRows call
+	appendErr error
+
+	mu        sync.Mutex
+	parent    string
+	records   []arrow.Record
+	committed []string
+}
+
+func (s *fakeWriteServer) CreateWriteStream(_ context.Context, req *storagepb.CreateWriteStreamRequest) (*storagepb.WriteStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.parent = req.GetParent()
+	return &storagepb.WriteStream{
+		Name: req.GetParent() + "/streams/stream0",
+		Type: req.GetWriteStream().GetType(),
+	}, nil
+}
+
+func (s *fakeWriteServer) AppendRows(stream storagepb.BigQueryWrite_AppendRowsServer) error {
+	if s.appendErr != nil {
+		return s.appendErr
+	}
+
+	var schema []byte
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		rows := req.GetArrowRows()
+		if rows == nil {
+			return status.Error(codes.InvalidArgument, "expected arrow rows")
+		}
+		if schema == nil {
+			if req.GetWriteStream() == "" || rows.GetWriterSchema() == nil {
+				return status.Error(codes.InvalidArgument, "first request must have stream and schema")
+			}
+			schema = rows.GetWriterSchema().GetSerializedSchema()
+		}
+
+		// a schema message followed by a batch message is a valid IPC
+		// stream, even without an end-of-stream marker
+		msg := append(bytes.Clone(schema), rows.GetRows().GetSerializedRecordBatch()...)
+		rdr, err := ipc.NewReader(bytes.NewReader(msg), ipc.WithAllocator(s.mem))
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		for rdr.Next() {
+			rec := rdr.Record()
+			rec.Retain()
+			s.mu.Lock()
+			s.records = append(s.records, rec)
+			s.mu.Unlock()
+		}
+		rdr.Release()
+
+		err = stream.Send(&storagepb.AppendRowsResponse{
+			Response: &storagepb.AppendRowsResponse_AppendResult_{
+				AppendResult: &storagepb.AppendRowsResponse_AppendResult{},
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *fakeWriteServer) FinalizeWriteStream(context.Context, *storagepb.FinalizeWriteStreamRequest) (*storagepb.FinalizeWriteStreamResponse, error) {
+	return &storagepb.FinalizeWriteStreamResponse{}, nil
+}
+
+func (s *fakeWriteServer) BatchCommitWriteStreams(_ context.Context, req *storagepb.BatchCommitWriteStreamsRequest) (*storagepb.BatchCommitWriteStreamsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, req.GetWriteStreams()...)
+	return &storagepb.BatchCommitWriteStreamsResponse{CommitTime: timestamppb.Now()}, nil
+}
+
+func (s *fakeWriteServer) release() {
+	for _, rec := range s.records {
+		rec.Release()
+	}
+}
+
+func startFakeWriteServer(t *testing.T, srv *fakeWriteServer) *storage.BigQueryWriteClient {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	storagepb.RegisterBigQueryWriteServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	wc, err := storage.NewBigQueryWriteClient(context.Background(),
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = wc.Close() })
+	return wc
+}
+
+func makeIngestRecords(t *testing.T, mem memory.Allocator) array.RecordReader {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+
+	first, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"id": 1, "name": "a"}, {"id": 2, "name": null}]`))
+	require.NoError(t, err)
+	defer first.Release()
+	second, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"id": 3, "name": "c"}]`))
+	require.NoError(t, err)
+	defer second.Release()
+
+	rdr, err := array.NewRecordReader(schema, []arrow.Record{first, second})
+	require.NoError(t, err)
+	return rdr
+}
+
+func TestWriteArrowStream(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	srv := &fakeWriteServer{mem: mem}
+	defer srv.release()
+	wc := startFakeWriteServer(t, srv)
+
+	rdr := makeIngestRecords(t, mem)
+	defer rdr.Release()
+
+	table := &bigquery.Table{ProjectID: "project", DatasetID: "dataset", TableID: "table"}
+	nrows, err := writeArrowStream(context.Background(), wc, table, rdr, mem, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, nrows)
+
+	assert.Equal(t, "projects/project/datasets/dataset/tables/table", srv.parent)
+	assert.Equal(t, []string{"projects/project/datasets/dataset/tables/table/streams/stream0"}, srv.committed)
+	require.Len(t, srv.records, 2)
+	assert.EqualValues(t, 2, srv.records[0].NumRows())
+	assert.EqualValues(t, 1, srv.records[1].NumRows())
+	assert.Equal(t, `["a" (null)]`, srv.records[0].Column(1).String())
+}
+
+func TestWriteArrowStreamUnavailable(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	srv := &fakeWriteServer{mem: mem, appendErr: status.Error(codes.PermissionDenied, "arrow not allowlisted")}
+	wc := startFakeWriteServer(t, srv)
+
+	rdr := makeIngestRecords(t, mem)
+	defer rdr.Release()
+
+	table := &bigquery.Table{ProjectID: "project", DatasetID: "dataset", TableID: "table"}
+	_, err := writeArrowStream(context.Background(), wc, table, rdr, mem, false)
+
+	// nothing is committed and the first record is handed back so that
+	// it can be loaded another way
+	var unavailable *storageWriteUnavailableError
+	require.True(t, errors.As(err, &unavailable))
+	defer unavailable.pending.Release()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.EqualValues(t, 2, unavailable.pending.NumRows())
+	assert.Empty(t, srv.committed)
+
+	require.True(t, rdr.Next())
+	assert.EqualValues(t, 1, rdr.Record().NumRows())
+}
+
+func TestWriteArrowStreamInvalidData(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	srv := &fakeWriteServer{mem: mem, appendErr: status.Error(codes.InvalidArgument, "bad row")}
+	wc := startFakeWriteServer(t, srv)
+
+	rdr := makeIngestRecords(t, mem)
+	defer rdr.Release()
+
+	table := &bigquery.Table{ProjectID: "project", DatasetID: "dataset", TableID: "table"}
+	_, err := writeArrowStream(context.Background(), wc, table, rdr, mem, false)
+
+	// problems with the data are reported instead of falling back to a
+	// load job
+	var unavailable *storageWriteUnavailableError
+	assert.False(t, errors.As(err, &unavailable))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Empty(t, srv.committed)
+}
+
+func TestToBigQuerySchema(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "f", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.LargeString, Nullable: true},
+		{Name: "b", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		{Name: "n", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}, Nullable: true},
+		{Name: "bn", Type: &arrow.Decimal128Type{Precision: 38, Scale: 20}, Nullable: true},
+		{Name: "d", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+		{Name: "t", Type: arrow.FixedWidthTypes.Time64us, Nullable: true},
+		{Name: "dt", Type: &arrow.TimestampType{Unit: arrow.Microsecond}, Nullable: true},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "l", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+		{Name: "r", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.FixedWidthTypes.Boolean, Nullable: true}), Nullable: true},
+	}, nil)
+
+	bqSchema, err := toBigQuerySchema(schema)
+	require.NoError(t, err)
+
+	expected := bigquery.Schema{
+		{Name: "i", Type: bigquery.IntegerFieldType, Required: true},
+		{Name: "f", Type: bigquery.FloatFieldType},
+		{Name: "s", Type: bigquery.StringFieldType},
+		{Name: "b", Type: bigquery.BytesFieldType},
+		{Name: "n", Type: bigquery.NumericFieldType, Precision: 10, Scale: 2},
+		{Name: "bn", Type: bigquery.BigNumericFieldType, Precision: 38, Scale: 20},
+		{Name: "d", Type: bigquery.DateFieldType},
+		{Name: "t", Type: bigquery.TimeFieldType},
+		{Name: "dt", Type: bigquery.DateTimeFieldType},
+		{Name: "ts", Type: bigquery.TimestampFieldType},
+		{Name: "l", Type: bigquery.IntegerFieldType, Repeated: true},
+		{Name: "r", Type: bigquery.RecordFieldType, Schema: bigquery.Schema{
+			{Name: "x", Type: bigquery.BooleanFieldType},
+		}},
+	}
+	assert.Equal(t, expected, bqSchema)
+
+	_, err = toBigQuerySchema(arrow.NewSchema([]arrow.Field{
+		{Name: "m", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)},
+	}, nil))
+	assert.ErrorContains(t, err, "unimplemented type conversion for field m")
+
+	_, err = toBigQuerySchema(arrow.NewSchema([]arrow.Field{
+		{Name: "u", Type: arrow.PrimitiveTypes.Uint64},
+	}, nil))
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+	assert.ErrorContains(t, err, "unimplemented type conversion for field u")
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -33,11 +34,13 @@ import (
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	storage "cloud.google.com/go/bigquery/storage/apiv1"
 	"github.com/apache/arrow-adbc/go/adbc"
 	"github.com/apache/arrow-adbc/go/adbc/driver/internal"
 	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"golang.org/x/oauth2"
+	bq "google.golang.org/api/bigquery/v2"
 	"google.golang.org/api/impersonate"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
@@ -68,6 +71,18 @@ type connectionImpl struct {
 	prefetchConcurrency    int
 
 	client *bigquery.Client
+	// clientOptions are the options client was created with, used to
+	// create other clients on demand
+	clientOptions []option.ClientOption
+	writeClient   *storage.BigQueryWriteClient
+	readClient    *storage.BigQueryReadClient
+	// restService is the REST API underlying client, for the parts of the
+	// API that client does not expose
+	restService *bq.Service
+
+	// sessionID is the BigQuery session holding the open transaction
+	// while autocommit is disabled. Every statement joins it.
+	sessionID string
 }
 
 func (c *connectionImpl) GetCatalogs(ctx context.Context, catalogFilter *string) ([]string, error) {
@@ -293,41 +308,165 @@ func (c *connectionImpl) ListTableTypes(ctx context.Context) ([]string, error) {
 }
 
 // SetAutocommit implements driverbase.AutocommitSetter.
+//
+// BigQuery only supports multi-statement transactions within a session,
+// so disabling autocommit creates a session and begins a transaction in
+// it. Enabling autocommit again commits the transaction and ends the
+// session.
 func (c *connectionImpl) SetAutocommit(enabled bool) error {
+	ctx := context.Background()
 	if enabled {
-		return nil
+		if c.sessionID == "" {
+			return nil
+		}
+		if err := c.runSessionQuery(ctx, "COMMIT TRANSACTION"); err != nil {
+			// the session is ended anyway, which rolls back the
+			// transaction, so the connection is back to autocommit
+			_ = c.endSession(ctx)
+			return err
+		}
+		return c.endSession(ctx)
 	}
-	return adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "SetAutocommit to `false` is not yet implemented",
+
+	if c.sessionID != "" {
+		return nil
 	}
+	return c.runSessionQuery(ctx, "BEGIN TRANSACTION")
 }
 
 // Commit commits any pending transactions on this connection, it should
 // only be used if autocommit is disabled.
 //
 // Behavior is undefined if this is mixed with SQL transaction statements.
-func (c *connectionImpl) Commit(_ context.Context) error {
-	return adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "Commit not yet implemented for BigQuery driver",
+func (c *connectionImpl) Commit(ctx context.Context) error {
+	if err := c.runSessionQuery(ctx, "COMMIT TRANSACTION"); err != nil {
+		return err
 	}
+	return c.runSessionQuery(ctx, "BEGIN TRANSACTION")
 }
 
 // Rollback rolls back any pending transactions. Only used if autocommit
 // is disabled.
 //
 // Behavior is undefined if this is mixed with SQL transaction statements.
-func (c *connectionImpl) Rollback(_ context.Context) error {
-	return adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "Rollback not yet implemented for BigQuery driver",
+func (c *connectionImpl) Rollback(ctx context.Context) error {
+	if err := c.runSessionQuery(ctx, "ROLLBACK TRANSACTION"); err != nil {
+		return err
+	}
+	return c.runSessionQuery(ctx, "BEGIN TRANSACTION")
+}
+
+// sessionProperties returns the connection properties that make a job
+// join the connection's session, if there is one.
+func (c *connectionImpl) sessionProperties() []*bigquery.ConnectionProperty {
+	if c.sessionID == "" {
+		return nil
+	}
+	return []*bigquery.ConnectionProperty{{Key: "session_id", Value: c.sessionID}}
+}
+
+// runSessionQuery runs sql in the connection's session and waits for it
+// to finish, creating the session if there isn't one yet.
+func (c *connectionImpl) runSessionQuery(ctx context.Context, sql string) error {
+	query := c.client.Query(sql)
+	if c.sessionID == "" {
+		query.CreateSession = true
+	} else {
+		query.ConnectionProperties = c.sessionProperties()
+	}
+
+	job, err := query.Run(ctx)
+	if err != nil {
+		return apiErrToAdbcErr(err, sql)
+	}
+	status, err := job.Wait(ctx)
+	if err == nil {
+		err = status.Err()
+	}
+	if err != nil {
+		return jobErrToAdbcErr(err, job, sql)
+	}
+
+	if c.sessionID == "" {
+		if status.Statistics == nil || status.Statistics.SessionInfo == nil {
+			return adbc.Error{
+				Code: adbc.StatusInternal,
+				Msg:  "[BigQuery] query did not create a session",
+			}
+		}
+		c.sessionID = status.Statistics.SessionInfo.SessionID
+	}
+	return nil
+}
+
+// endSession terminates the connection's session, which rolls back any
+// transaction still open in it.
+func (c *connectionImpl) endSession(ctx context.Context) error {
+	if c.sessionID == "" {
+		return nil
 	}
+	err := c.runSessionQuery(ctx, "CALL BQ.ABORT_SESSION()")
+	c.sessionID = ""
+	return err
 }
 
 // Close closes this connection and releases any associated resources.
 func (c *connectionImpl) Close() error {
-	return c.client.Close()
+	// the clients are closed even if the session could not be ended
+	errs := []error{c.endSession(context.Background())}
+	if c.writeClient != nil {
+		errs = append(errs, c.writeClient.Close())
+	}
+	if c.readClient != nil {
+		errs = append(errs, c.readClient.Close())
+	}
+	errs = append(errs, c.client.Close())
+	return errors.Join(errs...)
+}
+
+// storageWriteClient returns a client for the Storage Write API, creating
+// it the first time it is needed.
+func (c *connectionImpl) storageWriteClient(ctx context.Context) (*storage.BigQueryWriteClient, error) {
+	if c.writeClient != nil {
+		return c.writeClient, nil
+	}
+
+	wc, err := storage.NewBigQueryWriteClient(ctx, c.clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+	c.writeClient = wc
+	return wc, nil
+}
+
+// storageReadClient returns a client for the Storage Read API, creating
+// it the first time it is needed.
+func (c *connectionImpl) storageReadClient(ctx context.Context) (*storage.BigQueryReadClient, error) {
+	if c.readClient != nil {
+		return c.readClient, nil
+	}
+
+	rc, err := storage.NewBigQueryReadClient(ctx, c.clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+	c.readClient = rc
+	return rc, nil
+}
+
+// bigqueryService returns a REST API client, creating it the first time
+// it is needed.
+func (c *connectionImpl) bigqueryService(ctx context.Context) (*bq.Service, error) {
+	if c.restService != nil {
+		return c.restService, nil
+	}
+
+	svc, err := bq.NewService(ctx, c.clientOptions...)
+	if err != nil {
+		return nil, err
+	}
+	c.restService = svc
+	return svc, nil
 }
 
 // Metadata methods
@@ -442,11 +581,13 @@ func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, db
 // NewStatement initializes a new statement object tied to this connection
 func (c *connectionImpl) NewStatement() (adbc.Statement, error) {
 	return &statement{
+		StatementImplBase:      driverbase.NewStatementImplBase(&c.ConnectionImplBase, c.ErrorHelper),
 		alloc:                  c.Alloc,
 		cnxn:                   c,
 		parameterMode:          OptionValueQueryParameterModePositional,
 		resultRecordBufferSize: c.resultRecordBufferSize,
 		prefetchConcurrency:    c.prefetchConcurrency,
+		ingestMode:             adbc.OptionValueIngestModeCreate,
 		queryConfig: bigquery.QueryConfig{
 			DefaultProjectID: c.catalog,
 			DefaultDatasetID: c.dbSchema,
@@ -634,6 +775,7 @@ func (c *connectionImpl) newClient(ctx context.Context) error {
 	}
 
 	c.client = client
+	c.clientOptions = authOptions
 	return nil
 }
 
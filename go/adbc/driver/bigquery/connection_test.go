@@ -0,0 +1,201 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+// fakeJobServer is a stand-in for the BigQuery REST API that completes
+// every query job immediately, unless running is set, and records the
+// query and the session it ran in.
+type fakeJobServer struct {
+	mu      sync.Mutex
+	jobs    map[string]*bq.Job
+	queries []string
+	// the session each query ran in, "new" if it created one
+	sessions []string
+	// jobs are reported as running until this is cleared
+	running bool
+	// queries with this text fail
+	fail string
+}
+
+func (s *fakeJobServer) setRunning(running bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = running
+}
+
+func (s *fakeJobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var job *bq.Job
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/jobs"):
+		job = &bq.Job{}
+		if err := json.NewDecoder(r.Body).Decode(job); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		query := job.Configuration.Query
+		session := ""
+		for _, p := range query.ConnectionProperties {
+			if p.Key == "session_id" {
+				session = p.Value
+			}
+		}
+		job.Statistics = &bq.JobStatistics{Query: &bq.JobStatistics2{}}
+		if query.CreateSession {
+			session = "new"
+			job.Statistics.SessionInfo = &bq.SessionInfo{SessionId: "session0"}
+		}
+		if query.DestinationTable == nil {
+			query.DestinationTable = &bq.TableReference{ProjectId: "project", DatasetId: "_anon", TableId: "results"}
+		}
+		job.Status = &bq.JobStatus{State: "DONE"}
+		if query.Query == s.fail {
+			job.Status.ErrorResult = &bq.ErrorProto{Reason: "invalidQuery", Message: "query failed"}
+		}
+		s.queries = append(s.queries, query.Query)
+		s.sessions = append(s.sessions, session)
+		s.jobs[job.JobReference.JobId] = job
+	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/queries/"):
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		job = s.jobs[id]
+		if job == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&bq.GetQueryResultsResponse{
+			JobComplete:  !s.running,
+			JobReference: job.JobReference,
+			Schema:       &bq.TableSchema{},
+		})
+		return
+	case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/jobs/"):
+		job = s.jobs[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]]
+	}
+
+	if job == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if s.running {
+		running := *job
+		running.Status = &bq.JobStatus{State: "RUNNING"}
+		job = &running
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+func TestSessionTransactions(t *testing.T) {
+	srv := &fakeJobServer{jobs: make(map[string]*bq.Job)}
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project",
+		option.WithEndpoint(httpSrv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	c := &connectionImpl{client: client}
+	st := &statement{cnxn: c}
+
+	// autocommit: statements don't join a session
+	assert.Nil(t, st.query().ConnectionProperties)
+
+	require.NoError(t, c.SetAutocommit(false))
+	assert.Equal(t, "session0", c.sessionID)
+	require.NoError(t, c.SetAutocommit(false))
+
+	// statements join the session, even if they asked for a new one
+	st.queryConfig.CreateSession = true
+	query := st.query()
+	assert.False(t, query.CreateSession)
+	require.Len(t, query.ConnectionProperties, 1)
+	assert.Equal(t, "session0", query.ConnectionProperties[0].Value)
+
+	require.NoError(t, c.Commit(ctx))
+	require.NoError(t, c.Rollback(ctx))
+	require.NoError(t, c.SetAutocommit(true))
+	assert.Empty(t, c.sessionID)
+	assert.Nil(t, st.query().ConnectionProperties)
+
+	assert.Equal(t, []string{
+		"BEGIN TRANSACTION",
+		"COMMIT TRANSACTION", "BEGIN TRANSACTION",
+		"ROLLBACK TRANSACTION", "BEGIN TRANSACTION",
+		"COMMIT TRANSACTION", "CALL BQ.ABORT_SESSION()",
+	}, srv.queries)
+	assert.Equal(t, []string{"new", "session0", "session0", "session0", "session0", "session0", "session0"}, srv.sessions)
+}
+
+func TestAutocommitAfterCommitError(t *testing.T) {
+	srv := &fakeJobServer{jobs: make(map[string]*bq.Job), fail: "COMMIT TRANSACTION"}
+	httpSrv := httptest.NewServer(srv)
+	defer httpSrv.Close()
+
+	client, err := bigquery.NewClient(context.Background(), "project",
+		option.WithEndpoint(httpSrv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	c := &connectionImpl{client: client}
+	require.NoError(t, c.SetAutocommit(false))
+	require.Equal(t, "session0", c.sessionID)
+
+	// the session is ended even though the commit failed
+	assert.Error(t, c.SetAutocommit(true))
+	assert.Empty(t, c.sessionID)
+	assert.Equal(t, []string{"BEGIN TRANSACTION", "COMMIT TRANSACTION", "CALL BQ.ABORT_SESSION()"}, srv.queries)
+}
+
+func TestCloseAfterSessionError(t *testing.T) {
+	httpSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "session not found", http.StatusBadRequest)
+	}))
+	defer httpSrv.Close()
+
+	client, err := bigquery.NewClient(context.Background(), "project",
+		option.WithEndpoint(httpSrv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	rc := startFakeReadServer(t, &fakeReadServer{})
+
+	c := &connectionImpl{client: client, readClient: rc, sessionID: "session0"}
+	assert.Error(t, c.Close())
+	// the storage client was closed anyway
+	assert.Error(t, rc.Close())
+}
@@ -19,7 +19,10 @@ package bigquery
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"net/http"
 	"runtime/debug"
 	"strings"
 
@@ -27,6 +30,9 @@ import (
 	"github.com/apache/arrow-adbc/go/adbc"
 	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -138,6 +144,13 @@ func NewDriver(alloc memory.Allocator) adbc.Driver {
 			panic(err)
 		}
 	}
+	info.RegisterOptionNamespace(optionNamespace)
+	if err := info.RegisterOptions(optionSpecs...); err != nil {
+		panic(err)
+	}
+	if err := info.RegisterRetryOptions(); err != nil {
+		panic(err)
+	}
 	return driverbase.NewDriver(&driverImpl{
 		DriverImplBase: driverbase.NewDriverImplBase(info, alloc),
 	})
@@ -148,6 +161,7 @@ func (d *driverImpl) NewDatabase(opts map[string]string) (adbc.Database, error)
 }
 
 func (d *driverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string]string) (adbc.Database, error) {
+	opts = maps.Clone(opts)
 	dbBase, err := driverbase.NewDatabaseImplBase(ctx, &d.DriverImplBase)
 	if err != nil {
 		return nil, err
@@ -156,6 +170,12 @@ func (d *driverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string
 		DatabaseImplBase: dbBase,
 		authType:         OptionValueAuthTypeDefault,
 	}
+	if err := db.ValidateOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := db.SetRetryOptions(opts); err != nil {
+		return nil, err
+	}
 	if err := db.SetOptions(opts); err != nil {
 		return nil, err
 	}
@@ -234,3 +254,136 @@ func tableToString(value *bigquery.Table) string {
 		return fmt.Sprintf("%s.%s.%s", value.ProjectID, value.DatasetID, value.TableID)
 	}
 }
+
+func googleapiErrorCode(err error) int {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return 0
+}
+
+// bigqueryReasonStatuses are the statuses of the error reasons of
+// BigQuery, see https://cloud.google.com/bigquery/docs/error-messages.
+var bigqueryReasonStatuses = map[string]adbc.Status{
+	"accessDenied":      adbc.StatusUnauthorized,
+	"backendError":      adbc.StatusIO,
+	"billingNotEnabled": adbc.StatusUnauthorized,
+	"blocked":           adbc.StatusUnauthorized,
+	"duplicate":         adbc.StatusAlreadyExists,
+	"internalError":     adbc.StatusIO,
+	"invalid":           adbc.StatusInvalidArgument,
+	"invalidQuery":      adbc.StatusInvalidArgument,
+	"jobBackendError":   adbc.StatusIO,
+	"jobInternalError":  adbc.StatusIO,
+	"notFound":          adbc.StatusNotFound,
+	"notImplemented":    adbc.StatusNotImplemented,
+	"quotaExceeded":     adbc.StatusIO,
+	"rateLimitExceeded": adbc.StatusIO,
+	"resourceInUse":     adbc.StatusInvalidState,
+	"responseTooLarge":  adbc.StatusInvalidArgument,
+	"stopped":           adbc.StatusCancelled,
+	"tableUnavailable":  adbc.StatusIO,
+	"timeout":           adbc.StatusTimeout,
+}
+
+// errorReason returns the reason of a BigQuery error, if any.
+func errorReason(err error) string {
+	var jobErr *bigquery.Error
+	if errors.As(err, &jobErr) {
+		return jobErr.Reason
+	}
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && len(apiErr.Errors) > 0 {
+		return apiErr.Errors[0].Reason
+	}
+	return ""
+}
+
+// apiErrToAdbcErr converts an error from the BigQuery REST or gRPC APIs to
+// an adbc.Error, prefixing its message with msg. The vendor code is the
+// HTTP status or the gRPC code of the error, and the SQLSTATE is derived
+// from its status.
+func apiErrToAdbcErr(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+
+	var adbcErr adbc.Error
+	if errors.As(err, &adbcErr) {
+		return adbcErr
+	}
+
+	code := adbc.StatusIO
+	vendorCode := int32(googleapiErrorCode(err))
+	reason := errorReason(err)
+	if reasonCode, ok := bigqueryReasonStatuses[reason]; ok {
+		code = reasonCode
+	} else {
+		switch vendorCode {
+		case http.StatusNotFound:
+			code = adbc.StatusNotFound
+		case http.StatusConflict:
+			code = adbc.StatusAlreadyExists
+		case http.StatusBadRequest:
+			code = adbc.StatusInvalidArgument
+		case http.StatusUnauthorized:
+			code = adbc.StatusUnauthenticated
+		case http.StatusForbidden:
+			code = adbc.StatusUnauthorized
+		case 0:
+			grpcCode := status.Code(err)
+			if grpcCode != codes.Unknown {
+				vendorCode = int32(grpcCode)
+			}
+			switch grpcCode {
+			case codes.NotFound:
+				code = adbc.StatusNotFound
+			case codes.AlreadyExists:
+				code = adbc.StatusAlreadyExists
+			case codes.InvalidArgument:
+				code = adbc.StatusInvalidArgument
+			case codes.Unauthenticated:
+				code = adbc.StatusUnauthenticated
+			case codes.PermissionDenied:
+				code = adbc.StatusUnauthorized
+			case codes.Unimplemented:
+				code = adbc.StatusNotImplemented
+			case codes.Canceled:
+				code = adbc.StatusCancelled
+			case codes.DeadlineExceeded:
+				code = adbc.StatusTimeout
+			}
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		code = adbc.StatusCancelled
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code = adbc.StatusTimeout
+	}
+
+	adbcErr = adbc.Error{
+		Code:       code,
+		Msg:        fmt.Sprintf("[BigQuery] %s: %s", msg, err.Error()),
+		VendorCode: vendorCode,
+		SqlState:   driverbase.SQLStateForStatus(code),
+	}
+	if reason != "" {
+		adbcErr.Details = append(adbcErr.Details, &adbc.TextErrorDetail{Name: "bigquery.reason", Detail: reason})
+	}
+	return adbcErr
+}
+
+// jobErrToAdbcErr is apiErrToAdbcErr for the errors of a job, which is
+// attached to the error as an adbc.JobIDErrorDetail.
+func jobErrToAdbcErr(err error, job *bigquery.Job, msg string) error {
+	err = apiErrToAdbcErr(err, msg)
+	if err == nil || job == nil {
+		return err
+	}
+	return driverbase.WithErrorDetails(err, &adbc.JobIDErrorDetail{
+		ProjectID: job.ProjectID(),
+		Location:  job.Location(),
+		JobID:     job.ID(),
+	})
+}
@@ -0,0 +1,91 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApiErrToAdbcErr(t *testing.T) {
+	var adbcErr adbc.Error
+
+	// the reason takes precedence over the HTTP status
+	err := apiErrToAdbcErr(&googleapi.Error{
+		Code:    http.StatusBadRequest,
+		Message: "Syntax error",
+		Errors:  []googleapi.ErrorItem{{Reason: "invalidQuery"}},
+	}, "Query")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.EqualValues(t, http.StatusBadRequest, adbcErr.VendorCode)
+	assert.Equal(t, "42000", string(adbcErr.SqlState[:]))
+	require.Len(t, adbcErr.Details, 1)
+	assert.Equal(t, "bigquery.reason", adbcErr.Details[0].Key())
+
+	err = apiErrToAdbcErr(&googleapi.Error{Code: http.StatusNotFound}, "GetTable")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotFound, adbcErr.Code)
+	assert.Equal(t, "42S02", string(adbcErr.SqlState[:]))
+
+	// gRPC errors of the Storage API report their code
+	err = apiErrToAdbcErr(status.Error(codes.PermissionDenied, "denied"), "ReadRows")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusUnauthorized, adbcErr.Code)
+	assert.EqualValues(t, codes.PermissionDenied, adbcErr.VendorCode)
+
+	// job errors only have a reason
+	err = apiErrToAdbcErr(&bigquery.Error{Reason: "rateLimitExceeded", Message: "slow down"}, "Wait")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+	assert.Equal(t, "08000", string(adbcErr.SqlState[:]))
+
+	var jobID *adbc.JobIDErrorDetail
+	assert.False(t, errors.As(err, &jobID))
+}
+
+func TestJobProgress(t *testing.T) {
+	status := &bigquery.JobStatus{
+		State: bigquery.Running,
+		Statistics: &bigquery.JobStatistics{
+			TotalBytesProcessed: 4096,
+			Details: &bigquery.QueryStatistics{
+				QueryPlan: []*bigquery.ExplainQueryStage{
+					{Status: "COMPLETE"}, {Status: "RUNNING"}, {Status: "PENDING"}, {Status: "PENDING"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, adbc.QueryProgress{Progress: 0.25, MaxProgress: 1, Rows: -1, BytesProcessed: 4096}, jobProgress(status))
+
+	status.State = bigquery.Done
+	status.Statistics.Details.(*bigquery.QueryStatistics).NumDMLAffectedRows = 12
+	assert.Equal(t, adbc.QueryProgress{Progress: 1, MaxProgress: 1, Rows: 12, BytesProcessed: 4096}, jobProgress(status))
+
+	// jobs that haven't started have no statistics yet
+	assert.Equal(t, adbc.QueryProgress{MaxProgress: 1, Rows: -1, BytesProcessed: -1}, jobProgress(&bigquery.JobStatus{State: bigquery.Pending}))
+}
@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"strconv"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+// optionNamespace is the prefix of the driver-specific options. Keys with
+// this prefix that aren't listed in optionSpecs are rejected.
+const optionNamespace = "adbc.bigquery.sql."
+
+const (
+	levelDatabase  = driverbase.OptionLevelDatabase
+	levelConn      = driverbase.OptionLevelConnection
+	levelStatement = driverbase.OptionLevelStatement
+	// authentication options can be set on the database, and overridden
+	// on the connection
+	levelAuth = levelDatabase | levelConn
+)
+
+var optionSpecs = []driverbase.OptionSpec{
+	{Name: adbc.OptionKeyTelemetryTraceParent, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "W3C trace parent to attach to OpenTelemetry traces."},
+	{Name: adbc.OptionKeyAutoCommit, Levels: levelConn, Type: driverbase.OptionTypeBool,
+		Default:     driverbase.OptionDefault(adbc.OptionValueEnabled),
+		Description: "Disabling autocommit runs the statements of the connection in a session transaction."},
+	{Name: adbc.OptionKeyCurrentCatalog, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyCurrentDbSchema, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestTargetTable, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestMode, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(adbc.OptionValueIngestModeCreate),
+		AllowedValues: []string{
+			adbc.OptionValueIngestModeCreate,
+			adbc.OptionValueIngestModeAppend,
+			adbc.OptionValueIngestModeReplace,
+			adbc.OptionValueIngestModeCreateAppend,
+		}},
+	{Name: adbc.OptionValueIngestTargetCatalog, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTargetDBSchema, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTemporary, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled), Description: "Temporary tables are not supported."},
+	{Name: adbc.OptionKeyIncremental, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default:     driverbase.OptionDefault(adbc.OptionValueDisabled),
+		Description: "Return the partitions of ExecutePartitions once the query job is complete, polling it across calls."},
+
+	{Name: OptionStringAuthType, Levels: levelAuth, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(OptionValueAuthTypeDefault),
+		AllowedValues: []string{
+			OptionValueAuthTypeDefault,
+			OptionValueAuthTypeJSONCredentialFile,
+			OptionValueAuthTypeJSONCredentialString,
+			OptionValueAuthTypeUserAuthentication,
+			OptionValueAuthTypeAppDefaultCredentials,
+		}},
+	{Name: OptionStringAuthCredentials, Levels: levelAuth, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "Path to, or contents of, the JSON credentials, depending on the auth type."},
+	{Name: OptionStringAuthClientID, Levels: levelAuth, Type: driverbase.OptionTypeString},
+	{Name: OptionStringAuthClientSecret, Levels: levelAuth, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: OptionStringAuthRefreshToken, Levels: levelAuth, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: OptionStringImpersonateTargetPrincipal, Levels: levelAuth, Type: driverbase.OptionTypeString,
+		Description: "Service account email to impersonate."},
+	{Name: OptionStringImpersonateDelegates, Levels: levelAuth, Type: driverbase.OptionTypeString,
+		Description: "Comma-separated service account emails in the delegation chain."},
+	{Name: OptionStringImpersonateScopes, Levels: levelAuth, Type: driverbase.OptionTypeString,
+		Description: "Comma-separated OAuth 2.0 scopes."},
+	{Name: OptionStringImpersonateLifetime, Levels: levelAuth, Type: driverbase.OptionTypeDuration},
+	{Name: OptionStringProjectID, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionStringDatasetID, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionStringTableID, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+
+	{Name: OptionStringQueryParameterMode, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(OptionValueQueryParameterModePositional),
+		AllowedValues: []string{
+			OptionValueQueryParameterModeNamed,
+			OptionValueQueryParameterModePositional,
+		}},
+	{Name: OptionStringQueryDestinationTable, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Description: "Table to write the results to, as [[project.]dataset.]table."},
+	{Name: OptionStringQueryDefaultProjectID, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: OptionStringQueryDefaultDatasetID, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: OptionStringQueryCreateDisposition, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		AllowedValues: []string{string(bigquery.CreateIfNeeded), string(bigquery.CreateNever)}},
+	{Name: OptionStringQueryWriteDisposition, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		AllowedValues: []string{string(bigquery.WriteAppend), string(bigquery.WriteTruncate), string(bigquery.WriteEmpty)}},
+	{Name: OptionBoolQueryDisableQueryCache, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionBoolDisableFlattenedResults, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionBoolQueryAllowLargeResults, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionStringQueryPriority, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		AllowedValues: []string{string(bigquery.InteractivePriority), string(bigquery.BatchPriority)}},
+	{Name: OptionIntQueryMaxBillingTier, Levels: levelStatement, Type: driverbase.OptionTypeInt},
+	{Name: OptionIntQueryMaxBytesBilled, Levels: levelStatement, Type: driverbase.OptionTypeInt},
+	{Name: OptionBoolQueryUseLegacySQL, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionBoolQueryDryRun, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionBoolQueryCreateSession, Levels: levelStatement, Type: driverbase.OptionTypeBool},
+	{Name: OptionIntQueryJobTimeout, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Description: "Job timeout in milliseconds."},
+	{Name: OptionIntQueryResultBufferSize, Levels: levelConn | levelStatement, Type: driverbase.OptionTypeInt,
+		Default:     driverbase.OptionDefault(strconv.Itoa(defaultQueryResultBufferSize)),
+		Description: "Number of record batches to buffer per result stream."},
+	{Name: OptionIntQueryPrefetchConcurrency, Levels: levelConn | levelStatement, Type: driverbase.OptionTypeInt,
+		Default: driverbase.OptionDefault(strconv.Itoa(defaultQueryPrefetchConcurrency))},
+}
@@ -0,0 +1,216 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-go/v18/arrow"
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+// parseQueryParameters finds the parameters of a query. In named mode it
+// returns the name of each distinct @name parameter, in positional mode it
+// returns an empty name for each ? parameter. String literals, quoted
+// identifiers, comments and @@system variables are skipped.
+func parseQueryParameters(query string, parameterMode string) []string {
+	named := parameterMode == OptionValueQueryParameterModeNamed
+	names := []string{}
+	seen := make(map[string]bool)
+
+	for i := 0; i < len(query); i++ {
+		switch c := query[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(query, i)
+		case c == '#' || strings.HasPrefix(query[i:], "--"):
+			if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+				i += end
+			} else {
+				i = len(query)
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(query)
+			}
+		case c == '?' && !named:
+			names = append(names, "")
+		case c == '@' && named:
+			if strings.HasPrefix(query[i:], "@@") {
+				// system variable, its name is skipped as an ordinary word
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(query) && isIdentifierByte(query[j]) {
+				j++
+			}
+			if name := query[i+1 : j]; name != "" && !isDigit(name[0]) {
+				// parameter names are case-insensitive
+				if key := strings.ToLower(name); !seen[key] {
+					seen[key] = true
+					names = append(names, name)
+				}
+			}
+			i = j - 1
+		}
+	}
+	return names
+}
+
+// skipQuoted returns the index of the end of the quoted string or
+// identifier starting at start.
+func skipQuoted(query string, start int) int {
+	quote := query[start : start+1]
+	if quote != "`" && strings.HasPrefix(query[start:], strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	for i := start + len(quote); i < len(query); i++ {
+		if query[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(query[i:], quote) {
+			return i + len(quote) - 1
+		}
+	}
+	return len(query)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// dryRunParameterTypes asks BigQuery for the types of the parameters of a
+// query by dry-running it without declaring them. The Go client does not
+// expose the types of undeclared parameters, so this uses the REST API.
+func dryRunParameterTypes(ctx context.Context, svc *bq.Service, client *bigquery.Client, config bigquery.QueryConfig, parameterMode string) ([]*bq.QueryParameter, error) {
+	query := &bq.JobConfigurationQuery{
+		Query:         config.Q,
+		UseLegacySql:  &config.UseLegacySQL,
+		ParameterMode: "POSITIONAL",
+	}
+	if parameterMode == OptionValueQueryParameterModeNamed {
+		query.ParameterMode = "NAMED"
+	}
+	if config.DefaultDatasetID != "" {
+		project := config.DefaultProjectID
+		if project == "" {
+			project = client.Project()
+		}
+		query.DefaultDataset = &bq.DatasetReference{ProjectId: project, DatasetId: config.DefaultDatasetID}
+	}
+
+	job, err := svc.Jobs.Insert(client.Project(), &bq.Job{
+		JobReference: &bq.JobReference{ProjectId: client.Project(), Location: client.Location},
+		Configuration: &bq.JobConfiguration{
+			DryRun: true,
+			Query:  query,
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if job.Statistics == nil || job.Statistics.Query == nil {
+		return nil, nil
+	}
+	return job.Statistics.Query.UndeclaredQueryParameters, nil
+}
+
+// buildParameterSchema builds the parameter schema of a query from its
+// parsed parameter names and the types reported by a dry run. Parameters
+// whose type is unknown are NA.
+func buildParameterSchema(names []string, undeclared []*bq.QueryParameter) *arrow.Schema {
+	types := make([]*bq.QueryParameterType, len(names))
+	if len(undeclared) == len(names) && (len(names) == 0 || names[0] == "") {
+		// positional parameters are reported in order
+		for i, p := range undeclared {
+			types[i] = p.ParameterType
+		}
+	} else {
+		byName := make(map[string]*bq.QueryParameterType, len(undeclared))
+		for _, p := range undeclared {
+			byName[strings.ToLower(p.Name)] = p.ParameterType
+		}
+		for i, name := range names {
+			if name != "" {
+				types[i] = byName[strings.ToLower(name)]
+			}
+		}
+	}
+
+	fields := make([]arrow.Field, len(names))
+	for i, name := range names {
+		fields[i] = arrow.Field{Name: name, Type: parameterTypeToArrow(types[i]), Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// parameterTypeToArrow converts a parameter type to the Arrow type that
+// binds to it.
+func parameterTypeToArrow(t *bq.QueryParameterType) arrow.DataType {
+	if t == nil {
+		return arrow.Null
+	}
+
+	switch t.Type {
+	case "BOOL":
+		return arrow.FixedWidthTypes.Boolean
+	case "INT64":
+		return arrow.PrimitiveTypes.Int64
+	case "FLOAT64":
+		return arrow.PrimitiveTypes.Float64
+	case "NUMERIC":
+		return &arrow.Decimal128Type{Precision: 38, Scale: 9}
+	case "BIGNUMERIC":
+		return &arrow.Decimal256Type{Precision: 76, Scale: 38}
+	case "STRING":
+		return arrow.BinaryTypes.String
+	case "BYTES":
+		return arrow.BinaryTypes.Binary
+	case "DATE":
+		return arrow.FixedWidthTypes.Date32
+	case "TIME":
+		return arrow.FixedWidthTypes.Time64us
+	case "DATETIME":
+		return &arrow.TimestampType{Unit: arrow.Microsecond}
+	case "TIMESTAMP":
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	case "ARRAY":
+		elem := parameterTypeToArrow(t.ArrayType)
+		if elem.ID() == arrow.NULL {
+			return arrow.Null
+		}
+		return arrow.ListOf(elem)
+	case "STRUCT":
+		fields := make([]arrow.Field, len(t.StructTypes))
+		for i, f := range t.StructTypes {
+			fields[i] = arrow.Field{Name: f.Name, Type: parameterTypeToArrow(f.Type), Nullable: true}
+		}
+		return arrow.StructOf(fields...)
+	default:
+		return arrow.Null
+	}
+}
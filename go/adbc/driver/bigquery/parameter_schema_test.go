@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+func TestParseQueryParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		mode     string
+		expected []string
+	}{
+		{"positional", "SELECT ?, ? FROM t WHERE x = ?", OptionValueQueryParameterModePositional, []string{"", "", ""}},
+		{"positional ignores named", "SELECT @a, ?", OptionValueQueryParameterModePositional, []string{""}},
+		{"named", "SELECT @a, @b_2 FROM t WHERE x = @A", OptionValueQueryParameterModeNamed, []string{"a", "b_2"}},
+		{"named ignores positional", "SELECT ?, @a", OptionValueQueryParameterModeNamed, []string{"a"}},
+		{"system variables", "SELECT @@project_id, @p", OptionValueQueryParameterModeNamed, []string{"p"}},
+		{"strings", `SELECT '?', "@a", '''it's ? ''', 'don\'t ?', ?`, OptionValueQueryParameterModePositional, []string{""}},
+		{"quoted identifiers", "SELECT `@a` FROM `p.d.t?` WHERE x = @b", OptionValueQueryParameterModeNamed, []string{"b"}},
+		{"comments", "SELECT ? -- ?\n# @a ?\n, /* ? */ ?", OptionValueQueryParameterModePositional, []string{"", ""}},
+		{"none", "SELECT 1", OptionValueQueryParameterModePositional, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseQueryParameters(tt.query, tt.mode))
+		})
+	}
+}
+
+func TestBuildParameterSchema(t *testing.T) {
+	// named parameters are matched by name, missing types are NA
+	schema := buildParameterSchema([]string{"id", "tags", "other"}, []*bq.QueryParameter{
+		{Name: "TAGS", ParameterType: &bq.QueryParameterType{Type: "ARRAY", ArrayType: &bq.QueryParameterType{Type: "STRING"}}},
+		{Name: "id", ParameterType: &bq.QueryParameterType{Type: "INT64"}},
+	})
+	expected := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "other", Type: arrow.Null, Nullable: true},
+	}, nil)
+	assert.Truef(t, expected.Equal(schema), "expected: %s\ngot: %s", expected, schema)
+
+	// positional parameters are matched in order
+	schema = buildParameterSchema([]string{"", ""}, []*bq.QueryParameter{
+		{ParameterType: &bq.QueryParameterType{Type: "TIMESTAMP"}},
+		{ParameterType: &bq.QueryParameterType{Type: "STRUCT", StructTypes: []*bq.QueryParameterTypeStructTypes{
+			{Name: "x", Type: &bq.QueryParameterType{Type: "BOOL"}},
+		}}},
+	})
+	expected = arrow.NewSchema([]arrow.Field{
+		{Name: "", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "", Type: arrow.StructOf(arrow.Field{Name: "x", Type: arrow.FixedWidthTypes.Boolean, Nullable: true}), Nullable: true},
+	}, nil)
+	assert.Truef(t, expected.Equal(schema), "expected: %s\ngot: %s", expected, schema)
+
+	// without a dry run every type is NA
+	schema = buildParameterSchema([]string{"", ""}, nil)
+	assert.Equal(t, arrow.NULL, schema.Field(0).Type.ID())
+	assert.Equal(t, arrow.NULL, schema.Field(1).Type.ID())
+}
+
+func TestDryRunParameterTypes(t *testing.T) {
+	var inserted bq.Job
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&inserted))
+		resp := inserted
+		resp.Status = &bq.JobStatus{State: "DONE"}
+		resp.Statistics = &bq.JobStatistics{
+			Query: &bq.JobStatistics2{
+				UndeclaredQueryParameters: []*bq.QueryParameter{
+					{Name: "id", ParameterType: &bq.QueryParameterType{Type: "INT64"}},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(&resp))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project", option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+	svc, err := bq.NewService(ctx, option.WithEndpoint(srv.URL+"/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+
+	config := bigquery.QueryConfig{Q: "SELECT * FROM t WHERE id = @id", DefaultDatasetID: "dataset"}
+	undeclared, err := dryRunParameterTypes(ctx, svc, client, config, OptionValueQueryParameterModeNamed)
+	require.NoError(t, err)
+	require.Len(t, undeclared, 1)
+	assert.Equal(t, "INT64", undeclared[0].ParameterType.Type)
+
+	assert.True(t, inserted.Configuration.DryRun)
+	assert.Equal(t, "NAMED", inserted.Configuration.Query.ParameterMode)
+	assert.Equal(t, "project", inserted.Configuration.Query.DefaultDataset.ProjectId)
+	assert.Equal(t, "dataset", inserted.Configuration.Query.DefaultDataset.DatasetId)
+	assert.Empty(t, inserted.Configuration.Query.QueryParameters)
+}
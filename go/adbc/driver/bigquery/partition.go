@@ -0,0 +1,371 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"cloud.google.com/go/bigquery"
+	storage "cloud.google.com/go/bigquery/storage/apiv1"
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/protobuf/proto"
+)
+
+// Partitions are Storage Read API streams. Each partition descriptor is a
+// serialized storagepb.ReadSession holding the session's Arrow schema and
+// exactly one of its streams, so that any connection can read it without
+// any other state.
+
+// createReadSession starts a read session over the whole table, letting
+// the server decide how many streams to split it into.
+func createReadSession(ctx context.Context, rc *storage.BigQueryReadClient, billingProject string, table *bigquery.Table) (*storagepb.ReadSession, error) {
+	return rc.CreateReadSession(ctx, &storagepb.CreateReadSessionRequest{
+		Parent: "projects/" + billingProject,
+		ReadSession: &storagepb.ReadSession{
+			Table:      tableResourceName(table),
+			DataFormat: storagepb.DataFormat_ARROW,
+		},
+	})
+}
+
+// sessionSchema deserializes the Arrow schema of a read session.
+func sessionSchema(session *storagepb.ReadSession, alloc memory.Allocator) (*arrow.Schema, error) {
+	rdr, err := ipc.NewReader(bytes.NewReader(session.GetArrowSchema().GetSerializedSchema()), ipc.WithAllocator(alloc))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+	return rdr.Schema(), nil
+}
+
+// serializePartitions returns one partition descriptor per stream of the
+// session.
+func serializePartitions(session *storagepb.ReadSession) (adbc.Partitions, error) {
+	streams := session.GetStreams()
+	out := adbc.Partitions{
+		NumPartitions: uint64(len(streams)),
+		PartitionIDs:  make([][]byte, len(streams)),
+	}
+
+	partition := proto.Clone(session).(*storagepb.ReadSession)
+	for i, stream := range streams {
+		partition.Streams = []*storagepb.ReadStream{stream}
+		data, err := proto.Marshal(partition)
+		if err != nil {
+			return adbc.Partitions{}, err
+		}
+		out.PartitionIDs[i] = data
+	}
+	return out, nil
+}
+
+func deserializePartition(serializedPartition []byte) (*storagepb.ReadSession, error) {
+	var session storagepb.ReadSession
+	if err := proto.Unmarshal(serializedPartition, &session); err != nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[BigQuery] invalid partition descriptor: %s", err.Error()),
+		}
+	}
+	if len(session.GetStreams()) != 1 || session.GetArrowSchema() == nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "[BigQuery] invalid partition descriptor: expected one Arrow stream",
+		}
+	}
+	return &session, nil
+}
+
+// readStreamReader presents the schema of a read session followed by the
+// record batches of one of its streams as a single IPC stream.
+type readStreamReader struct {
+	stream storagepb.BigQueryRead_ReadRowsClient
+	buf    bytes.Reader
+}
+
+func (r *readStreamReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		resp, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf.Reset(resp.GetArrowRecordBatch().GetSerializedRecordBatch())
+	}
+	return r.buf.Read(p)
+}
+
+// readPartition reads every record of the single stream in session.
+// Opening the stream is retried according to retry.
+func readPartition(ctx context.Context, rc *storage.BigQueryReadClient, session *storagepb.ReadSession, alloc memory.Allocator, resultRecordBufferSize int, retry driverbase.RetryPolicy) (*reader, error) {
+	ctx, cancelFn := context.WithCancel(ctx)
+	// errors of a server stream only surface when receiving, so the
+	// first response is received before the stream is handed over
+	var first []byte
+	stream, err := driverbase.RetryValue(ctx, retry, true, func(ctx context.Context) (storagepb.BigQueryRead_ReadRowsClient, error) {
+		stream, err := rc.ReadRows(ctx, &storagepb.ReadRowsRequest{ReadStream: session.GetStreams()[0].GetName()})
+		if err != nil {
+			return nil, apiErrToAdbcErr(err, "ReadRows")
+		}
+		resp, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, apiErrToAdbcErr(err, "ReadRows")
+		}
+		first = resp.GetArrowRecordBatch().GetSerializedRecordBatch()
+		return stream, nil
+	})
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	src := &readStreamReader{stream: stream}
+	src.buf.Reset(append(slices.Clone(session.GetArrowSchema().GetSerializedSchema()), first...))
+	rdr, err := ipc.NewReader(src, ipc.WithAllocator(alloc))
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	ch := make(chan arrow.Record, resultRecordBufferSize)
+	bigqueryRdr := &reader{
+		refCount: 1,
+		chs:      []chan arrow.Record{ch},
+		cancelFn: cancelFn,
+		schema:   rdr.Schema(),
+	}
+
+	go func() {
+		defer close(ch)
+		defer rdr.Release()
+		for rdr.Next() && ctx.Err() == nil {
+			rec := rdr.Record()
+			rec.Retain()
+			ch <- rec
+		}
+
+		if err := rdr.Err(); err != nil && !errors.Is(err, io.EOF) {
+			bigqueryRdr.err = apiErrToAdbcErr(err, "ReadRows")
+		} else {
+			bigqueryRdr.err = checkContext(ctx, nil)
+		}
+	}()
+	return bigqueryRdr, nil
+}
+
+// ExecutePartitions executes the current statement and gets the results
+// as a partitioned result set.
+//
+// It returns the Schema of the result set, the collection of partition
+// descriptors and the number of rows affected, if known. If unknown,
+// the number of rows affected will be -1.
+//
+// The results of the query are split into Storage Read API streams, one
+// per partition. Statements without a result set, such as DDL or DML,
+// return no partitions.
+//
+// If adbc.OptionKeyIncremental is enabled, the first call starts the
+// query job and returns at once, and each later call checks its status
+// once without waiting for it. While the job runs, a call returns no
+// schema and no partitions, and its progress is reported by
+// adbc.OptionKeyProgress. Once the job is complete, its partitions are
+// returned with the schema, and the next call returns the schema and no
+// partitions to signal completion.
+func (st *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	done, err := st.State.BeginExecute("ExecutePartitions")
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	if st.incrementalState != nil {
+		return st.executeIncremental(ctx)
+	}
+	if err := st.checkPlainQuery("ExecutePartitions"); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+
+	job, err := st.query().Run(ctx)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, apiErrToAdbcErr(err, "ExecutePartitions")
+	}
+	st.setJob(ctx, job)
+
+	status, err := job.Wait(ctx)
+	if err == nil {
+		err = status.Err()
+	}
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, jobErrToAdbcErr(err, job, "ExecutePartitions")
+	}
+
+	schema, partitions, err := st.jobPartitions(ctx, job)
+	return schema, partitions, -1, err
+}
+
+// checkPlainQuery returns an error if the statement isn't a query without
+// parameters, which is all that method supports.
+func (st *statement) checkPlainQuery(method string) error {
+	if st.targetTable != "" {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  fmt.Sprintf("%s is not supported for bulk ingestion", method),
+		}
+	}
+	if st.queryConfig.Q == "" {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "cannot execute without a query",
+		}
+	}
+	if st.State.HasBound() {
+		return adbc.Error{
+			Code: adbc.StatusNotImplemented,
+			Msg:  fmt.Sprintf("%s with bound parameters not yet implemented for BigQuery driver", method),
+		}
+	}
+	return nil
+}
+
+// executeIncremental starts the query job on the first call, and checks
+// whether it is complete on later calls. Once it is complete, the
+// partitions of its result are returned.
+func (st *statement) executeIncremental(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	state := st.incrementalState
+	if state.complete {
+		schema := state.schema
+		// Reset the statement for reuse
+		st.incrementalState = &incrementalState{}
+		return schema, adbc.Partitions{}, -1, nil
+	}
+
+	if state.job == nil {
+		if err := st.checkPlainQuery("ExecutePartitions"); err != nil {
+			return nil, adbc.Partitions{}, -1, err
+		}
+		job, err := st.query().Run(ctx)
+		if err != nil {
+			return nil, adbc.Partitions{}, -1, apiErrToAdbcErr(err, "ExecutePartitions")
+		}
+		// the job outlives this call, and so does the polling of its
+		// progress
+		st.setJob(context.WithoutCancel(ctx), job)
+		state.job = job
+		// the job is still running, its partitions are returned by a
+		// later call
+		return nil, adbc.Partitions{}, -1, nil
+	}
+
+	job := state.job
+	status, err := job.Status(ctx)
+	if err == nil {
+		if !status.Done() {
+			return nil, adbc.Partitions{}, -1, nil
+		}
+		err = status.Err()
+	}
+	if err != nil {
+		st.incrementalState = &incrementalState{}
+		return nil, adbc.Partitions{}, -1, jobErrToAdbcErr(err, job, "ExecutePartitions")
+	}
+
+	schema, partitions, err := st.jobPartitions(ctx, job)
+	if err != nil {
+		st.incrementalState = &incrementalState{}
+		return nil, adbc.Partitions{}, -1, err
+	}
+	state.schema = schema
+	state.complete = true
+	// Special case: returning no partitions already implies completion
+	if partitions.NumPartitions == 0 {
+		st.incrementalState = &incrementalState{}
+	}
+	return schema, partitions, -1, nil
+}
+
+// jobPartitions creates a read session over the destination table of a
+// completed query job, and returns its schema and partitions.
+func (st *statement) jobPartitions(ctx context.Context, job *bigquery.Job) (*arrow.Schema, adbc.Partitions, error) {
+	config, err := job.Config()
+	if err != nil {
+		return nil, adbc.Partitions{}, apiErrToAdbcErr(err, "ExecutePartitions")
+	}
+	queryConfig, ok := config.(*bigquery.QueryConfig)
+	if !ok || queryConfig.Dst == nil {
+		return nil, adbc.Partitions{}, nil
+	}
+
+	rc, err := st.cnxn.storageReadClient(ctx)
+	if err != nil {
+		return nil, adbc.Partitions{}, apiErrToAdbcErr(err, "ExecutePartitions")
+	}
+	session, err := createReadSession(ctx, rc, st.cnxn.client.Project(), queryConfig.Dst)
+	if err != nil {
+		return nil, adbc.Partitions{}, apiErrToAdbcErr(err, "CreateReadSession")
+	}
+
+	schema, err := sessionSchema(session, st.cnxn.Alloc)
+	if err != nil {
+		return nil, adbc.Partitions{}, adbc.Error{
+			Code: adbc.StatusInternal,
+			Msg:  fmt.Sprintf("[BigQuery] could not deserialize read session schema: %s", err.Error()),
+		}
+	}
+
+	partitions, err := serializePartitions(session)
+	if err != nil {
+		return nil, adbc.Partitions{}, adbc.Error{
+			Code: adbc.StatusInternal,
+			Msg:  fmt.Sprintf("[BigQuery] could not serialize partition: %s", err.Error()),
+		}
+	}
+	return schema, partitions, nil
+}
+
+// ReadPartition reads one stream of a read session created by
+// ExecutePartitions. The partition does not have to come from this
+// connection.
+func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
+	session, err := deserializePartition(serializedPartition)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := c.storageReadClient(ctx)
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "ReadPartition")
+	}
+
+	rdr, err := readPartition(ctx, rc, session, c.Alloc, c.resultRecordBufferSize, c.RetryPolicy)
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "ReadPartition")
+	}
+	return rdr, nil
+}
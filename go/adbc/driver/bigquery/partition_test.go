@@ -0,0 +1,328 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	storage "cloud.google.com/go/bigquery/storage/apiv1"
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeReadServer is a stand-in for the Storage Read API that serves one
+// stream per record it was given.
+type fakeReadServer struct {
+	storagepb.UnimplementedBigQueryReadServer
+
+	schema  []byte
+	batches map[string][]byte
+	parent  string
+	table   string
+	// failures is the number of ReadRows calls that fail before the
+	// stream is served
+	failures int
+}
+
+func newFakeReadServer(t *testing.T, mem memory.Allocator, recs ...arrow.Record) *fakeReadServer {
+	srv := &fakeReadServer{batches: make(map[string][]byte)}
+	for i, rec := range recs {
+		ser, err := newIPCSerializer(rec.Schema(), mem)
+		require.NoError(t, err)
+		batch, err := ser.serialize(rec)
+		require.NoError(t, err)
+		srv.schema = ser.schema
+		srv.batches[fmt.Sprintf("stream%d", i)] = batch
+	}
+	return srv
+}
+
+func (s *fakeReadServer) CreateReadSession(_ context.Context, req *storagepb.CreateReadSessionRequest) (*storagepb.ReadSession, error) {
+	s.parent = req.GetParent()
+	s.table = req.GetReadSession().GetTable()
+	if req.GetReadSession().GetDataFormat() != storagepb.DataFormat_ARROW {
+		return nil, status.Error(codes.InvalidArgument, "expected arrow")
+	}
+
+	session := &storagepb.ReadSession{
+		Name:       "session",
+		Table:      s.table,
+		DataFormat: storagepb.DataFormat_ARROW,
+		Schema: &storagepb.ReadSession_ArrowSchema{
+			ArrowSchema: &storagepb.ArrowSchema{SerializedSchema: s.schema},
+		},
+	}
+	for i := range s.batches {
+		session.Streams = append(session.Streams, &storagepb.ReadStream{Name: i})
+	}
+	return session, nil
+}
+
+func (s *fakeReadServer) ReadRows(req *storagepb.ReadRowsRequest, stream storagepb.BigQueryRead_ReadRowsServer) error {
+	batch, ok := s.batches[req.GetReadStream()]
+	if !ok {
+		return status.Error(codes.NotFound, "no such stream")
+	}
+	if s.failures > 0 {
+		s.failures--
+		return status.Error(codes.Internal, "transient failure")
+	}
+	return stream.Send(&storagepb.ReadRowsResponse{
+		Rows: &storagepb.ReadRowsResponse_ArrowRecordBatch{
+			ArrowRecordBatch: &storagepb.ArrowRecordBatch{SerializedRecordBatch: batch},
+		},
+	})
+}
+
+func startFakeReadServer(t *testing.T, srv *fakeReadServer) *storage.BigQueryReadClient {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	storagepb.RegisterBigQueryReadServer(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	rc, err := storage.NewBigQueryReadClient(context.Background(),
+		option.WithEndpoint(lis.Addr().String()),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = rc.Close() })
+	return rc
+}
+
+func TestPartitions(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ints", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	first, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"ints": 1}, {"ints": null}]`))
+	require.NoError(t, err)
+	defer first.Release()
+	second, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"ints": 3}]`))
+	require.NoError(t, err)
+	defer second.Release()
+
+	srv := newFakeReadServer(t, mem, first, second)
+	rc := startFakeReadServer(t, srv)
+
+	ctx := context.Background()
+	table := &bigquery.Table{ProjectID: "project", DatasetID: "_anon", TableID: "results"}
+	session, err := createReadSession(ctx, rc, "billing", table)
+	require.NoError(t, err)
+	assert.Equal(t, "projects/billing", srv.parent)
+	assert.Equal(t, "projects/project/datasets/_anon/tables/results", srv.table)
+
+	sc, err := sessionSchema(session, mem)
+	require.NoError(t, err)
+	assert.Truef(t, schema.Equal(sc), "expected: %s\ngot: %s", schema, sc)
+
+	partitions, err := serializePartitions(session)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, partitions.NumPartitions)
+	require.Len(t, partitions.PartitionIDs, 2)
+
+	// every partition can be read on its own
+	totalRows := int64(0)
+	for _, id := range partitions.PartitionIDs {
+		partition, err := deserializePartition(id)
+		require.NoError(t, err)
+		require.Len(t, partition.GetStreams(), 1)
+
+		rdr, err := readPartition(ctx, rc, partition, mem, 1, driverbase.DefaultRetryPolicy())
+		require.NoError(t, err)
+		assert.Truef(t, schema.Equal(rdr.Schema()), "expected: %s\ngot: %s", schema, rdr.Schema())
+		for rdr.Next() {
+			totalRows += rdr.Record().NumRows()
+		}
+		assert.NoError(t, rdr.Err())
+		rdr.Release()
+	}
+	assert.EqualValues(t, 3, totalRows)
+}
+
+func TestReadPartitionRetry(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ints", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"ints": 1}, {"ints": 2}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	srv := newFakeReadServer(t, mem, rec)
+	rc := startFakeReadServer(t, srv)
+
+	ctx := context.Background()
+	table := &bigquery.Table{ProjectID: "project", DatasetID: "_anon", TableID: "results"}
+	session, err := createReadSession(ctx, rc, "billing", table)
+	require.NoError(t, err)
+
+	// by default, a failure is returned
+	srv.failures = 1
+	_, err = readPartition(ctx, rc, session, mem, 1, driverbase.DefaultRetryPolicy())
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+
+	// but the stream is opened again if the policy allows it
+	retry := driverbase.DefaultRetryPolicy()
+	retry.MaxAttempts = 2
+	retry.InitialBackoff = time.Millisecond
+	srv.failures = 1
+	rdr, err := readPartition(ctx, rc, session, mem, 1, retry)
+	require.NoError(t, err)
+	defer rdr.Release()
+	totalRows := int64(0)
+	for rdr.Next() {
+		totalRows += rdr.Record().NumRows()
+	}
+	assert.NoError(t, rdr.Err())
+	assert.EqualValues(t, 2, totalRows)
+}
+
+func TestDeserializePartitionInvalid(t *testing.T) {
+	var adbcErr adbc.Error
+
+	_, err := deserializePartition([]byte("not a partition"))
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+
+	// descriptors from serializePartitions round-trip
+	partition, err := serializePartitions(&storagepb.ReadSession{
+		Schema: &storagepb.ReadSession_ArrowSchema{ArrowSchema: &storagepb.ArrowSchema{}},
+		Streams: []*storagepb.ReadStream{
+			{Name: "stream0"},
+		},
+	})
+	require.NoError(t, err)
+	_, err = deserializePartition(partition.PartitionIDs[0])
+	assert.NoError(t, err)
+
+	// but a descriptor must have exactly one stream
+	_, err = deserializePartition(nil)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestExecutePartitionsIncremental(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ints", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	first, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"ints": 1}, {"ints": null}]`))
+	require.NoError(t, err)
+	defer first.Release()
+	second, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"ints": 3}]`))
+	require.NoError(t, err)
+	defer second.Release()
+	rc := startFakeReadServer(t, newFakeReadServer(t, mem, first, second))
+
+	jobSrv := &fakeJobServer{jobs: make(map[string]*bq.Job), running: true}
+	httpSrv := httptest.NewServer(jobSrv)
+	defer httpSrv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project",
+		option.WithEndpoint(httpSrv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	drv := driverbase.NewDriverImplBase(driverbase.DefaultDriverInfo("BigQuery"), mem)
+	db, err := driverbase.NewDatabaseImplBase(ctx, &drv)
+	require.NoError(t, err)
+	c := &connectionImpl{ConnectionImplBase: driverbase.NewConnectionImplBase(&db), client: client, readClient: rc}
+	stmt, err := c.NewStatement()
+	require.NoError(t, err)
+	st := stmt.(*statement)
+
+	require.NoError(t, st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueEnabled))
+	val, err := st.GetOption(adbc.OptionKeyIncremental)
+	require.NoError(t, err)
+	assert.Equal(t, adbc.OptionValueEnabled, val)
+	require.NoError(t, st.SetSqlQuery("SELECT ints FROM t"))
+
+	// the call returns at once, leaving the job running
+	sc, partitions, _, err := st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, sc)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+
+	// and so do the calls while it is running
+	sc, partitions, _, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, sc)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+
+	// incremental execution can't be toggled while the query is running
+	var adbcErr adbc.Error
+	err = st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueDisabled)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+
+	// the next call checks the same job
+	jobSrv.setRunning(false)
+	sc, partitions, _, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Truef(t, schema.Equal(sc), "expected: %s\ngot: %s", schema, sc)
+	require.EqualValues(t, 2, partitions.NumPartitions)
+	assert.Equal(t, []string{"SELECT ints FROM t"}, jobSrv.queries)
+
+	totalRows := int64(0)
+	for _, id := range partitions.PartitionIDs {
+		rdr, err := c.ReadPartition(ctx, id)
+		require.NoError(t, err)
+		for rdr.Next() {
+			totalRows += rdr.Record().NumRows()
+		}
+		assert.NoError(t, rdr.Err())
+		rdr.Release()
+	}
+	assert.EqualValues(t, 3, totalRows)
+
+	// and then the query is complete
+	sc, partitions, _, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Truef(t, schema.Equal(sc), "expected: %s\ngot: %s", schema, sc)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+
+	require.NoError(t, st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueDisabled))
+	val, err = st.GetOption(adbc.OptionKeyIncremental)
+	require.NoError(t, err)
+	assert.Equal(t, adbc.OptionValueDisabled, val)
+}
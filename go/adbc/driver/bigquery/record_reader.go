@@ -55,18 +55,28 @@ func checkContext(ctx context.Context, maybeErr error) error {
 	return ctx.Err()
 }
 
-func runQuery(ctx context.Context, query *bigquery.Query, executeUpdate bool) (bigquery.ArrowIterator, int64, error) {
+// runQuery starts the query and, unless executeUpdate is set, returns an
+// iterator over its results. If onJob is not nil, it is called with the
+// job as soon as it has been created so that it can be cancelled.
+func runQuery(ctx context.Context, query *bigquery.Query, executeUpdate bool, onJob func(context.Context, *bigquery.Job)) (bigquery.ArrowIterator, int64, error) {
 	job, err := query.Run(ctx)
 	if err != nil {
-		return nil, -1, err
+		return nil, -1, apiErrToAdbcErr(err, "Run")
+	}
+	if onJob != nil {
+		onJob(ctx, job)
 	}
 	if executeUpdate {
 		return nil, 0, nil
 	}
+	return readJob(ctx, job)
+}
 
+// readJob waits for a query job and returns an iterator over its result.
+func readJob(ctx context.Context, job *bigquery.Job) (bigquery.ArrowIterator, int64, error) {
 	iter, err := job.Read(ctx)
 	if err != nil {
-		return nil, -1, err
+		return nil, -1, jobErrToAdbcErr(err, job, "Read")
 	}
 
 	var arrowIterator bigquery.ArrowIterator
@@ -74,7 +84,7 @@ func runQuery(ctx context.Context, query *bigquery.Query, executeUpdate bool) (b
 	// iterator should be empty (#2173)
 	if iter.TotalRows > 0 {
 		if arrowIterator, err = iter.ArrowIterator(); err != nil {
-			return nil, -1, err
+			return nil, -1, jobErrToAdbcErr(err, job, "ArrowIterator")
 		}
 	} else {
 		arrowIterator = emptyArrowIterator{iter.Schema}
@@ -105,15 +115,25 @@ func getQueryParameter(values arrow.Record, row int, parameterMode string) ([]bi
 	return parameters, nil
 }
 
-func runPlainQuery(ctx context.Context, query *bigquery.Query, alloc memory.Allocator, resultRecordBufferSize int) (bigqueryRdr *reader, totalRows int64, err error) {
-	arrowIterator, totalRows, err := runQuery(ctx, query, false)
+func runPlainQuery(ctx context.Context, query *bigquery.Query, alloc memory.Allocator, resultRecordBufferSize int, onJob func(context.Context, *bigquery.Job)) (bigqueryRdr *reader, totalRows int64, err error) {
+	arrowIterator, totalRows, err := runQuery(ctx, query, false, onJob)
 	if err != nil {
 		return nil, -1, err
 	}
-	rdr, err := ipcReaderFromArrowIterator(arrowIterator, alloc)
+	bigqueryRdr, err = newIteratorReader(ctx, arrowIterator, alloc, resultRecordBufferSize)
 	if err != nil {
 		return nil, -1, err
 	}
+	return bigqueryRdr, totalRows, nil
+}
+
+// newIteratorReader returns a reader over the records of arrowIterator,
+// which are read in the background.
+func newIteratorReader(ctx context.Context, arrowIterator bigquery.ArrowIterator, alloc memory.Allocator, resultRecordBufferSize int) (bigqueryRdr *reader, err error) {
+	rdr, err := ipcReaderFromArrowIterator(arrowIterator, alloc)
+	if err != nil {
+		return nil, err
+	}
 
 	chs := make([]chan arrow.Record, 1)
 	ctx, cancelFn := context.WithCancel(ctx)
@@ -144,13 +164,15 @@ func runPlainQuery(ctx context.Context, query *bigquery.Query, alloc memory.Allo
 			ch <- rec
 		}
 
-		err = checkContext(ctx, rdr.Err())
+		// set before ch is closed, so that Err reports it once Next
+		// returns false
+		bigqueryRdr.err = checkContext(ctx, rdr.Err())
 		defer close(ch)
 	}()
-	return bigqueryRdr, totalRows, nil
+	return bigqueryRdr, nil
 }
 
-func queryRecordWithSchemaCallback(ctx context.Context, group *errgroup.Group, query *bigquery.Query, rec arrow.Record, ch chan arrow.Record, parameterMode string, alloc memory.Allocator, rdrSchema func(schema *arrow.Schema)) (int64, error) {
+func queryRecordWithSchemaCallback(ctx context.Context, group *errgroup.Group, query *bigquery.Query, rec arrow.Record, ch chan arrow.Record, parameterMode string, alloc memory.Allocator, rdrSchema func(schema *arrow.Schema), onJob func(context.Context, *bigquery.Job)) (int64, error) {
 	totalRows := int64(-1)
 	for i := 0; i < int(rec.NumRows()); i++ {
 		parameters, err := getQueryParameter(rec, i, parameterMode)
@@ -161,7 +183,7 @@ func queryRecordWithSchemaCallback(ctx context.Context, group *errgroup.Group, q
 			query.Parameters = parameters
 		}
 
-		arrowIterator, rows, err := runQuery(ctx, query, false)
+		arrowIterator, rows, err := runQuery(ctx, query, false, onJob)
 		if err != nil {
 			return -1, err
 		}
@@ -186,9 +208,9 @@ func queryRecordWithSchemaCallback(ctx context.Context, group *errgroup.Group, q
 
 // kicks off a goroutine for each endpoint and returns a reader which
 // gathers all of the records as they come in.
-func newRecordReader(ctx context.Context, query *bigquery.Query, boundParameters array.RecordReader, parameterMode string, alloc memory.Allocator, resultRecordBufferSize, prefetchConcurrency int) (bigqueryRdr *reader, totalRows int64, err error) {
+func newRecordReader(ctx context.Context, query *bigquery.Query, boundParameters array.RecordReader, parameterMode string, alloc memory.Allocator, resultRecordBufferSize, prefetchConcurrency int, onJob func(context.Context, *bigquery.Job)) (bigqueryRdr *reader, totalRows int64, err error) {
 	if boundParameters == nil {
-		return runPlainQuery(ctx, query, alloc, resultRecordBufferSize)
+		return runPlainQuery(ctx, query, alloc, resultRecordBufferSize, onJob)
 	}
 	defer boundParameters.Release()
 
@@ -226,7 +248,7 @@ func newRecordReader(ctx context.Context, query *bigquery.Query, boundParameters
 		// we don't need to call rec.Retain() here and call call rec.Release() in queryRecordWithSchemaCallback
 		batchRows, err := queryRecordWithSchemaCallback(ctx, group, query, rec, ch, parameterMode, alloc, func(schema *arrow.Schema) {
 			bigqueryRdr.schema = schema
-		})
+		}, onJob)
 		if err != nil {
 			return nil, -1, err
 		}
@@ -21,13 +21,16 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/bigquery"
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	bq "google.golang.org/api/bigquery/v2"
 )
 
 // todos for bigqueryConfig
@@ -41,16 +44,134 @@ import (
 // - SchemaUpdateOptions
 // - ConnectionProperties
 
+// incrementalState is the query job run by ExecutePartitions when
+// adbc.OptionKeyIncremental is enabled.
+type incrementalState struct {
+	job      *bigquery.Job
+	schema   *arrow.Schema
+	complete bool
+}
+
 type statement struct {
+	driverbase.StatementImplBase
 	alloc memory.Allocator
 	cnxn  *connectionImpl
 
 	queryConfig            bigquery.QueryConfig
 	parameterMode          string
-	paramBinding           arrow.Record
-	streamBinding          array.RecordReader
 	resultRecordBufferSize int
 	prefetchConcurrency    int
+
+	// paramSchema is the schema of the query's parameters, found by Prepare
+	paramSchema *arrow.Schema
+
+	// bulk ingestion target, if targetTable is set then binding data and
+	// executing the statement writes the data to that table
+	targetTable    string
+	ingestMode     string
+	ingestCatalog  string
+	ingestDBSchema string
+
+	// jobMu guards the most recently started query job, which Cancel
+	// asks BigQuery to stop.
+	jobMu sync.Mutex
+	job   *bigquery.Job
+
+	// incrementalState is nil unless incremental execution is enabled
+	incrementalState *incrementalState
+}
+
+func (st *statement) Base() *driverbase.StatementImplBase {
+	return &st.StatementImplBase
+}
+
+// setJob records the query job that was just started, and polls its
+// statistics to report its progress.
+func (st *statement) setJob(ctx context.Context, job *bigquery.Job) {
+	st.jobMu.Lock()
+	st.job = job
+	st.jobMu.Unlock()
+
+	st.Progress.Start()
+	client := st.cnxn.client
+	st.Progress.Poll(ctx, driverbase.ProgressPollInterval, func(ctx context.Context) (adbc.QueryProgress, bool, error) {
+		// a Job caches its status, so the job isn't shared with the
+		// goroutine that waits for it
+		polled, err := client.JobFromProject(ctx, job.ProjectID(), job.ID(), job.Location())
+		if err != nil {
+			return adbc.QueryProgress{}, false, err
+		}
+		status := polled.LastStatus()
+		progress := jobProgress(status)
+		if progress.Rows < 0 {
+			progress.Rows = st.Progress.Progress().Rows
+		}
+		return progress, status.Done(), nil
+	})
+}
+
+// jobProgress converts the status of a query job into its progress: the
+// fraction of the stages of its query plan that are complete.
+func jobProgress(status *bigquery.JobStatus) adbc.QueryProgress {
+	progress := adbc.QueryProgress{MaxProgress: 1, Rows: -1, BytesProcessed: -1}
+	if stats := status.Statistics; stats != nil {
+		progress.BytesProcessed = stats.TotalBytesProcessed
+		if query, ok := stats.Details.(*bigquery.QueryStatistics); ok {
+			completed := 0
+			for _, stage := range query.QueryPlan {
+				if stage.Status == "COMPLETE" {
+					completed++
+				}
+			}
+			if len(query.QueryPlan) > 0 {
+				progress.Progress = float64(completed) / float64(len(query.QueryPlan))
+			}
+			if query.NumDMLAffectedRows > 0 {
+				progress.Rows = query.NumDMLAffectedRows
+			}
+		}
+	}
+	if status.Done() {
+		progress.Progress = progress.MaxProgress
+	}
+	return progress
+}
+
+// clearIncrementalQuery returns an error if an incremental query is in
+// progress, since toggling incremental execution would abandon it.
+func (st *statement) clearIncrementalQuery() error {
+	if st.incrementalState != nil && st.incrementalState.job != nil && !st.incrementalState.complete {
+		return adbc.Error{
+			Code: adbc.StatusInvalidState,
+			Msg:  "[BigQuery] Cannot disable incremental execution while a query is in progress, finish execution first",
+		}
+	}
+	return nil
+}
+
+// Cancel stops any in-progress operation on this statement and requests
+// cancellation of the last query job it started.
+func (st *statement) Cancel() error {
+	if err := st.StatementImplBase.Cancel(); err != nil {
+		return err
+	}
+
+	st.jobMu.Lock()
+	job := st.job
+	st.job = nil
+	st.jobMu.Unlock()
+
+	if job == nil {
+		return nil
+	}
+
+	if err := job.Cancel(context.Background()); err != nil {
+		return adbc.Error{
+			Code: adbc.StatusIO,
+			Msg:  fmt.Sprintf("[BigQuery] failed to cancel job %s: %s", job.ID(), err.Error()),
+		}
+	}
+	return nil
 }
 
 func (st *statement) GetOptionBytes(key string) ([]byte, error) {
@@ -61,6 +182,9 @@ func (st *statement) GetOptionBytes(key string) ([]byte, error) {
 }
 
 func (st *statement) GetOptionDouble(key string) (float64, error) {
+	if value, ok := st.Progress.GetOptionDouble(key); ok {
+		return value, nil
+	}
 	return 0, adbc.Error{
 		Msg:  fmt.Sprintf("[BigQuery] Unknown statement option '%s'", key),
 		Code: adbc.StatusNotFound,
@@ -86,13 +210,10 @@ func (st *statement) SetOptionDouble(key string, value float64) error {
 //
 // A statement instance should not be used after Close is called.
 func (st *statement) Close() error {
-	if st.cnxn == nil {
-		return adbc.Error{
-			Msg:  "statement already closed",
-			Code: adbc.StatusInvalidState}
+	if err := st.State.Close(); err != nil {
+		return err
 	}
 
-	st.clearParameters()
 	st.cnxn = nil
 	return nil
 }
@@ -132,6 +253,21 @@ func (st *statement) GetOption(key string) (string, error) {
 		return strconv.FormatBool(st.queryConfig.DryRun), nil
 	case OptionBoolQueryCreateSession:
 		return strconv.FormatBool(st.queryConfig.CreateSession), nil
+	case adbc.OptionKeyIngestTargetTable:
+		return st.targetTable, nil
+	case adbc.OptionKeyIngestMode:
+		return st.ingestMode, nil
+	case adbc.OptionValueIngestTargetCatalog:
+		return st.ingestCatalog, nil
+	case adbc.OptionValueIngestTargetDBSchema:
+		return st.ingestDBSchema, nil
+	case adbc.OptionValueIngestTemporary:
+		return adbc.OptionValueDisabled, nil
+	case adbc.OptionKeyIncremental:
+		if st.incrementalState != nil {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
 	default:
 		val, err := st.cnxn.GetOption(key)
 		if err == nil {
@@ -163,11 +299,66 @@ func (st *statement) GetOptionInt(key string) (int64, error) {
 }
 
 func (st *statement) SetOption(key string, v string) error {
+	if err := st.ValidateOption(key, v); err != nil {
+		return err
+	}
+
 	switch key {
+	case adbc.OptionKeyIngestTargetTable:
+		if err := st.State.SetQuery(); err != nil {
+			return err
+		}
+		st.queryConfig.Q = ""
+		st.targetTable = v
+	case adbc.OptionKeyIngestMode:
+		switch v {
+		case adbc.OptionValueIngestModeCreate, adbc.OptionValueIngestModeAppend,
+			adbc.OptionValueIngestModeReplace, adbc.OptionValueIngestModeCreateAppend:
+			st.ingestMode = v
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[BigQuery] invalid statement option %s=%s", key, v),
+			}
+		}
+	case adbc.OptionValueIngestTargetCatalog:
+		st.ingestCatalog = v
+	case adbc.OptionValueIngestTargetDBSchema:
+		st.ingestDBSchema = v
+	case adbc.OptionValueIngestTemporary:
+		switch v {
+		case adbc.OptionValueEnabled:
+			return adbc.Error{
+				Code: adbc.StatusNotImplemented,
+				Msg:  "[BigQuery] bulk ingestion into temporary tables is not supported",
+			}
+		case adbc.OptionValueDisabled:
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[BigQuery] invalid statement option %s=%s", key, v),
+			}
+		}
+	case adbc.OptionKeyIncremental:
+		if err := st.clearIncrementalQuery(); err != nil {
+			return err
+		}
+		switch v {
+		case adbc.OptionValueEnabled:
+			st.incrementalState = &incrementalState{}
+		case adbc.OptionValueDisabled:
+			st.incrementalState = nil
+		default:
+			return adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[BigQuery] invalid statement option %s=%s", key, v),
+			}
+		}
 	case OptionStringQueryParameterMode:
 		switch v {
 		case OptionValueQueryParameterModeNamed, OptionValueQueryParameterModePositional:
 			st.parameterMode = v
+			st.paramSchema = nil
 		default:
 			return adbc.Error{
 				Code: adbc.StatusInvalidArgument,
@@ -259,6 +450,10 @@ func (st *statement) SetOption(key string, v string) error {
 }
 
 func (st *statement) SetOptionInt(key string, value int64) error {
+	if err := st.ValidateOption(key, strconv.FormatInt(value, 10)); err != nil {
+		return err
+	}
+
 	switch key {
 	case OptionIntQueryMaxBillingTier:
 		st.queryConfig.MaxBillingTier = int(value)
@@ -287,7 +482,12 @@ func (st *statement) SetOptionInt(key string, value int64) error {
 // For queries expected to be executed repeatedly, Prepare should be
 // called before execution.
 func (st *statement) SetSqlQuery(query string) error {
+	if err := st.State.SetQuery(); err != nil {
+		return err
+	}
 	st.queryConfig.Q = query
+	st.targetTable = ""
+	st.paramSchema = nil
 	return nil
 }
 
@@ -297,36 +497,67 @@ func (st *statement) SetSqlQuery(query string) error {
 //
 // This invalidates any prior result sets on this statement.
 func (st *statement) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
-	if st.queryConfig.Q == "" {
-		return nil, -1, adbc.Error{
-			Msg:  "cannot execute without a query",
-			Code: adbc.StatusInvalidState,
-		}
+	done, err := st.State.BeginExecute("ExecuteQuery")
+	if err != nil {
+		return nil, -1, err
+	}
+	defer done()
+
+	// the context is used by the reader, so it lives until the reader is
+	// released
+	ctx, cancel := st.WithCancel(ctx)
+	if st.targetTable != "" {
+		defer cancel()
+		nrows, err := st.executeIngest(ctx)
+		return nil, nrows, err
 	}
 
 	rdr, err := st.getBoundParameterReader()
 	if err != nil {
+		cancel()
 		return nil, -1, err
 	}
 
-	return newRecordReader(ctx, st.query(), rdr, st.parameterMode, st.cnxn.Alloc, st.resultRecordBufferSize, st.prefetchConcurrency)
+	reader, totalRows, err := newRecordReader(ctx, st.query(), rdr, st.parameterMode, st.cnxn.Alloc, st.resultRecordBufferSize, st.prefetchConcurrency, st.setJob)
+	if err != nil {
+		cancel()
+		return nil, -1, err
+	}
+	if rdr == nil {
+		// without parameters, the job completed before the results are read
+		st.Progress.Finish(totalRows, -1)
+	}
+	return driverbase.CancelOnRelease(st.State.TrackReader(reader), cancel), totalRows, nil
 }
 
 // ExecuteUpdate executes a statement that does not generate a result
 // set. It returns the number of rows affected if known, otherwise -1.
 func (st *statement) ExecuteUpdate(ctx context.Context) (int64, error) {
+	done, err := st.State.BeginExecute("ExecuteUpdate")
+	if err != nil {
+		return -1, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	if st.targetTable != "" {
+		return st.executeIngest(ctx)
+	}
+
 	boundParameters, err := st.getBoundParameterReader()
 	if err != nil {
 		return -1, err
 	}
 
 	if boundParameters == nil {
-		_, totalRows, err := runQuery(ctx, st.query(), true)
+		_, totalRows, err := runQuery(ctx, st.query(), true, st.setJob)
 		if err != nil {
 			return -1, err
 		}
 		return totalRows, nil
 	} else {
+		defer boundParameters.Release()
 		totalRows := int64(0)
 		for boundParameters.Next() {
 			values := boundParameters.Record()
@@ -339,7 +570,7 @@ func (st *statement) ExecuteUpdate(ctx context.Context) (int64, error) {
 					st.queryConfig.Parameters = parameters
 				}
 
-				_, currentRows, err := runQuery(ctx, st.query(), true)
+				_, currentRows, err := runQuery(ctx, st.query(), true, st.setJob)
 				if err != nil {
 					return -1, err
 				}
@@ -351,23 +582,108 @@ func (st *statement) ExecuteUpdate(ctx context.Context) (int64, error) {
 }
 
 // ExecuteSchema gets the schema of the result set of a query without executing it.
+//
+// The query is run as a dry-run job, which validates it and reports the
+// result schema without scanning any data. If parameters are bound, they
+// are sent as typed NULLs since only their types affect the schema.
 func (st *statement) ExecuteSchema(ctx context.Context) (*arrow.Schema, error) {
-	return nil, adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "ExecuteSchema not yet implemented for BigQuery driver",
+	done, err := st.State.BeginExecute("ExecuteSchema")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	if st.targetTable != "" {
+		return nil, adbc.Error{
+			Msg:  "cannot get the schema of a bulk ingestion",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+
+	query := st.query()
+	parameters, err := st.dryRunParameters()
+	if err != nil {
+		return nil, err
+	}
+	if parameters != nil {
+		query.Parameters = parameters
+	}
+	return dryRunSchema(ctx, query)
+}
+
+// dryRunParameters returns query parameters with the types of the bound
+// parameters, or nil if none are bound. A bound stream is not consumed.
+func (st *statement) dryRunParameters() ([]bigquery.QueryParameter, error) {
+	var schema *arrow.Schema
+	if bound, stream := st.State.Bound(); bound != nil {
+		schema = bound.Schema()
+	} else if stream != nil {
+		schema = stream.Schema()
+	} else {
+		return nil, nil
+	}
+
+	cols := make([]arrow.Array, schema.NumFields())
+	for i, f := range schema.Fields() {
+		cols[i] = array.MakeArrayOfNull(st.cnxn.Alloc, f.Type, 1)
+		defer cols[i].Release()
+	}
+	values := array.NewRecord(schema, cols, 1)
+	defer values.Release()
+	return getQueryParameter(values, 0, st.parameterMode)
+}
+
+// dryRunSchema runs query as a dry-run job and converts the schema of its
+// result.
+func dryRunSchema(ctx context.Context, query *bigquery.Query) (*arrow.Schema, error) {
+	query.DryRun = true
+	job, err := query.Run(ctx)
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "ExecuteSchema")
+	}
+
+	var stats *bigquery.QueryStatistics
+	if status := job.LastStatus(); status != nil && status.Statistics != nil {
+		stats, _ = status.Statistics.Details.(*bigquery.QueryStatistics)
 	}
+	if stats == nil {
+		return nil, adbc.Error{
+			Msg:  "[BigQuery] dry run did not return query statistics",
+			Code: adbc.StatusInternal,
+		}
+	}
+
+	fields := make([]arrow.Field, len(stats.Schema))
+	for i, schema := range stats.Schema {
+		f, err := buildField(schema, 0)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = f
+	}
+	return arrow.NewSchema(fields, nil), nil
 }
 
 // Prepare turns this statement into a prepared statement to be executed
 // multiple times. This invalidates any prior result sets.
-func (st *statement) Prepare(_ context.Context) error {
-	if st.queryConfig.Q == "" {
-		return adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "cannot prepare statement with no query",
+func (st *statement) Prepare(ctx context.Context) error {
+	if err := st.State.Prepare(); err != nil {
+		return err
+	}
+
+	// bigquery doesn't provide a "Prepare" api, but the parameters of the
+	// query are found here for GetParameterSchema. Their types come from
+	// a dry run, if BigQuery can infer them; otherwise they are left as NA.
+	names := parseQueryParameters(st.queryConfig.Q, st.parameterMode)
+	var undeclared []*bq.QueryParameter
+	if len(names) > 0 {
+		if svc, err := st.cnxn.bigqueryService(ctx); err == nil {
+			undeclared, _ = dryRunParameterTypes(ctx, svc, st.cnxn.client, st.queryConfig, st.parameterMode)
 		}
 	}
-	// bigquery doesn't provide a "Prepare" api, this is a no-op
+	st.paramSchema = buildParameterSchema(names, undeclared)
 	return nil
 }
 
@@ -390,6 +706,11 @@ func (st *statement) SetSubstraitPlan(plan []byte) error {
 func (st *statement) query() *bigquery.Query {
 	query := st.cnxn.client.Query("")
 	query.QueryConfig = st.queryConfig
+	if props := st.cnxn.sessionProperties(); props != nil {
+		// join the connection's transaction
+		query.CreateSession = false
+		query.ConnectionProperties = props
+	}
 	return query
 }
 
@@ -662,57 +983,17 @@ func arrowValueToQueryParameterValue(field arrow.Field, value arrow.Array, i int
 }
 
 func (st *statement) getBoundParameterReader() (array.RecordReader, error) {
-	if st.paramBinding != nil {
-		rdr, err := array.NewRecordReader(st.paramBinding.Schema(), []arrow.Record{st.paramBinding})
-		if err != nil {
-			return nil, err
-		}
-		st.streamBinding = rdr
-		return st.streamBinding, nil
-	} else if st.streamBinding != nil {
-		return st.streamBinding, nil
-	} else {
-		return nil, nil
-	}
-}
-
-func (st *statement) clearParameters() {
-	if st.paramBinding != nil {
-		st.paramBinding.Release()
-		st.paramBinding = nil
-	}
-	if st.streamBinding != nil {
-		st.streamBinding.Release()
-		st.streamBinding = nil
+	// a bound record is kept for the next execution, while a bound stream
+	// is consumed
+	bound, stream := st.State.Bound()
+	if bound != nil {
+		return array.NewRecordReader(bound.Schema(), []arrow.Record{bound})
 	}
-}
-
-// SetParameters takes a record batch to send as the parameter bindings when
-// executing. It should match the schema from ParameterSchema.
-//
-// This will call Retain on the record to ensure it doesn't get released out
-// from under the statement. Release will be called on a previous binding
-// record or reader if it existed, and will be called upon calling Close on the
-// PreparedStatement.
-func (st *statement) SetParameters(binding arrow.Record) {
-	st.clearParameters()
-	st.paramBinding = binding
-	if st.paramBinding != nil {
-		st.paramBinding.Retain()
+	if stream != nil {
+		_, stream = st.State.TakeBound()
+		return stream, nil
 	}
-}
-
-// SetRecordReader takes a RecordReader to send as the parameter bindings when
-// executing. It should match the schema from ParameterSchema.
-//
-// This will call Retain on the reader to ensure it doesn't get released out
-// from under the statement. Release will be called on a previous binding
-// record or reader if it existed, and will be called upon calling Close on the
-// PreparedStatement.
-func (st *statement) SetRecordReader(binding array.RecordReader) {
-	st.clearParameters()
-	st.streamBinding = binding
-	st.streamBinding.Retain()
+	return nil, nil
 }
 
 // Bind uses an arrow record batch to bind parameters to the query.
@@ -722,8 +1003,7 @@ func (st *statement) SetRecordReader(binding array.RecordReader) {
 // but it may not do this until the statement is closed or another
 // record is bound.
 func (st *statement) Bind(_ context.Context, values arrow.Record) error {
-	st.SetParameters(values)
-	return nil
+	return st.State.Bind(values)
 }
 
 // BindStream uses a record batch stream to bind parameters for this
@@ -732,8 +1012,7 @@ func (st *statement) Bind(_ context.Context, values arrow.Record) error {
 // The driver will call Release on the record reader, but may not do this
 // until Close is called.
 func (st *statement) BindStream(_ context.Context, stream array.RecordReader) error {
-	st.SetRecordReader(stream)
-	return nil
+	return st.State.BindStream(stream)
 }
 
 // GetParameterSchema returns an Arrow schema representation of
@@ -753,27 +1032,14 @@ func (st *statement) BindStream(_ context.Context, stream array.RecordReader) er
 //
 // This should return an error with StatusNotImplemented if the schema
 // cannot be determined.
-func (st *statement) GetParameterSchema() (*arrow.Schema, error) {
-	return nil, adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "GetParameterSchema not yet implemented for BigQuery driver",
-	}
-}
-
-// ExecutePartitions executes the current statement and gets the results
-// as a partitioned result set.
-//
-// It returns the Schema of the result set, the collection of partition
-// descriptors and the number of rows affected, if known. If unknown,
-// the number of rows affected will be -1.
 //
-// If the driver does not support partitioned results, this will return
-// an error with a StatusNotImplemented code.
-func (st *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
-	return nil, adbc.Partitions{}, -1, adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "ExecutePartitions not yet implemented for BigQuery driver",
+// Named parameters (@name) or positional parameters (?) are found
+// according to OptionStringQueryParameterMode.
+func (st *statement) GetParameterSchema() (*arrow.Schema, error) {
+	if err := st.State.RequirePrepared("GetParameterSchema"); err != nil {
+		return nil, err
 	}
+	return st.paramSchema, nil
 }
 
 var _ adbc.GetSetOptions = (*statement)(nil)
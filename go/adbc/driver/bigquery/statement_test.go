@@ -0,0 +1,88 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+func TestDryRunSchema(t *testing.T) {
+	var inserted bq.Job
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&inserted))
+
+		// a dry run comes back done, with only statistics
+		resp := inserted
+		resp.Status = &bq.JobStatus{State: "DONE"}
+		resp.Statistics = &bq.JobStatistics{
+			Query: &bq.JobStatistics2{
+				Schema: &bq.TableSchema{Fields: []*bq.TableFieldSchema{
+					{Name: "id", Type: "INTEGER", Mode: "REQUIRED"},
+					{Name: "name", Type: "STRING", Mode: "NULLABLE"},
+				}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(&resp))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project",
+		option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	query := client.Query("SELECT id, name FROM t WHERE id = ?")
+	query.Parameters = []bigquery.QueryParameter{{
+		Value: &bigquery.QueryParameterValue{
+			Type:  bigquery.StandardSQLDataType{TypeKind: "INT64"},
+			Value: "NULL",
+		},
+	}}
+
+	schema, err := dryRunSchema(ctx, query)
+	require.NoError(t, err)
+
+	assert.True(t, inserted.Configuration.DryRun)
+	require.Len(t, inserted.Configuration.Query.QueryParameters, 1)
+	assert.Equal(t, "INT64", inserted.Configuration.Query.QueryParameters[0].ParameterType.Type)
+
+	require.Equal(t, 2, schema.NumFields())
+	assert.Equal(t, "id", schema.Field(0).Name)
+	assert.Equal(t, arrow.INT64, schema.Field(0).Type.ID())
+	assert.False(t, schema.Field(0).Nullable)
+	assert.Equal(t, "name", schema.Field(1).Name)
+	assert.Equal(t, arrow.STRING, schema.Field(1).Type.ID())
+	assert.True(t, schema.Field(1).Nullable)
+}
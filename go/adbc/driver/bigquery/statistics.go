@@ -0,0 +1,115 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	// StatisticTableBytesKey is the key of the logical size of a table in
+	// bytes, as reported by the table metadata.
+	StatisticTableBytesKey = 1024
+	// StatisticTableBytesName is the name of StatisticTableBytesKey.
+	StatisticTableBytesName = "adbc.bigquery.statistic.table_bytes"
+)
+
+// EnumerateStatistics implements driverbase.StatisticsEnumerator.
+//
+// Row counts and sizes of tables come from the table metadata. They
+// don't include rows in the streaming buffer, so they are always reported
+// as approximate. Views and external tables have no statistics.
+func (c *connectionImpl) EnumerateStatistics(ctx context.Context, catalogFilter, dbSchemaFilter, tableFilter *string, approximate bool) ([]driverbase.StatisticsEntry, error) {
+	tablePattern, err := internal.PatternToRegexp(tableFilter)
+	if err != nil {
+		return nil, err
+	}
+	if tablePattern == nil {
+		tablePattern = internal.AcceptAll
+	}
+
+	projects, err := c.GetCatalogs(ctx, catalogFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]driverbase.StatisticsEntry, 0)
+	for _, project := range projects {
+		datasets, err := c.GetDBSchemasForCatalog(ctx, project, dbSchemaFilter)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dataset := range datasets {
+			it := c.client.DatasetInProject(project, dataset).Tables(ctx)
+			for {
+				table, err := it.Next()
+				if err == iterator.Done {
+					break
+				}
+				if err != nil {
+					return nil, err
+				}
+				if !tablePattern.MatchString(table.TableID) {
+					continue
+				}
+
+				// the basic view leaves out the storage statistics
+				md, err := table.Metadata(ctx, bigquery.WithMetadataView(bigquery.StorageStatsMetadataView))
+				if err != nil {
+					return nil, err
+				}
+				if md.Type != bigquery.RegularTable {
+					continue
+				}
+
+				entries = append(entries,
+					driverbase.StatisticsEntry{
+						CatalogName:   project,
+						DbSchemaName:  dataset,
+						TableName:     table.TableID,
+						Name:          adbc.StatisticRowCountName,
+						Value:         int64(md.NumRows),
+						IsApproximate: true,
+					},
+					driverbase.StatisticsEntry{
+						CatalogName:   project,
+						DbSchemaName:  dataset,
+						TableName:     table.TableID,
+						Name:          StatisticTableBytesName,
+						Value:         md.NumBytes,
+						IsApproximate: true,
+					})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// StatisticNames implements driverbase.StatisticsEnumerator.
+func (c *connectionImpl) StatisticNames() []driverbase.StatisticName {
+	return []driverbase.StatisticName{
+		{Name: StatisticTableBytesName, Key: StatisticTableBytesKey},
+	}
+}
@@ -0,0 +1,107 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bq "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+func TestGetStatistics(t *testing.T) {
+	mux := http.NewServeMux()
+	reply := func(path string, v any) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(v)
+		})
+	}
+	reply("GET /projects/project/datasets", &bq.DatasetList{Datasets: []*bq.DatasetListDatasets{
+		{DatasetReference: &bq.DatasetReference{ProjectId: "project", DatasetId: "dataset"}},
+	}})
+	reply("GET /projects/project/datasets/dataset/tables", &bq.TableList{Tables: []*bq.TableListTables{
+		{TableReference: &bq.TableReference{ProjectId: "project", DatasetId: "dataset", TableId: "table"}},
+		{TableReference: &bq.TableReference{ProjectId: "project", DatasetId: "dataset", TableId: "view"}},
+		{TableReference: &bq.TableReference{ProjectId: "project", DatasetId: "dataset", TableId: "misc"}},
+	}})
+	reply("GET /projects/project/datasets/dataset/tables/table", &bq.Table{
+		TableReference: &bq.TableReference{ProjectId: "project", DatasetId: "dataset", TableId: "table"},
+		Type:           "TABLE",
+		NumRows:        42,
+		NumBytes:       1024,
+	})
+	reply("GET /projects/project/datasets/dataset/tables/view", &bq.Table{
+		TableReference: &bq.TableReference{ProjectId: "project", DatasetId: "dataset", TableId: "view"},
+		Type:           "VIEW",
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project",
+		option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	c := &connectionImpl{client: client}
+	c.Alloc = mem
+	cnxn := driverbase.NewConnectionBuilder(c).WithStatisticsEnumerator(c).Connection()
+	rdr, err := cnxn.GetStatistics(ctx, nil, nil, driverbase.Nullable("%e%"), true)
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	require.True(t, rdr.Next())
+	rec := rdr.Record()
+	assert.Equal(t, `["project"]`, rec.Column(0).String())
+
+	dbSchemas := rec.Column(1).(*array.List).ListValues().(*array.Struct)
+	assert.Equal(t, `["dataset"]`, dbSchemas.Field(0).String())
+
+	statistics := dbSchemas.Field(1).(*array.List).ListValues().(*array.Struct)
+	assert.Equal(t, `["table" "table"]`, statistics.Field(0).String())
+	assert.Equal(t, `[6 1024]`, statistics.Field(2).String())
+	assert.Equal(t, `[true true]`, statistics.Field(4).String())
+
+	values := statistics.Field(3).(*array.DenseUnion)
+	ints := values.Field(0).(*array.Int64)
+	assert.EqualValues(t, 42, ints.Value(int(values.ValueOffset(0))))
+	assert.EqualValues(t, 1024, ints.Value(int(values.ValueOffset(1))))
+	assert.False(t, rdr.Next())
+
+	names, err := cnxn.GetStatisticNames(ctx)
+	require.NoError(t, err)
+	defer names.Release()
+	require.True(t, names.Next())
+	assert.Equal(t, `["adbc.bigquery.statistic.table_bytes"]`, names.Record().Column(0).String())
+	assert.True(t, adbc.GetStatisticNamesSchema.Equal(names.Schema()))
+}
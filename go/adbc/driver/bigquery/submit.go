@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// jobHandle is the handle of a query job submitted by SubmitQuery. Jobs
+// and their results outlive the connection that created them, so the
+// handle can be attached from any connection with access to the project.
+type jobHandle struct {
+	ProjectID string `json:"project_id"`
+	Location  string `json:"location,omitempty"`
+	JobID     string `json:"job_id"`
+}
+
+func deserializeJobHandle(handle []byte) (*jobHandle, error) {
+	var h jobHandle
+	if err := json.Unmarshal(handle, &h); err != nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[BigQuery] invalid query handle: %s", err.Error()),
+		}
+	}
+	if h.ProjectID == "" || h.JobID == "" {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "[BigQuery] invalid query handle: missing project or job ID",
+		}
+	}
+	return &h, nil
+}
+
+// SubmitQuery starts the query job without waiting for it, and returns a
+// handle to the job which AttachQuery reads the result of.
+func (st *statement) SubmitQuery(ctx context.Context) ([]byte, error) {
+	done, err := st.State.BeginExecute("SubmitQuery")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	if err := st.checkPlainQuery("SubmitQuery"); err != nil {
+		return nil, err
+	}
+
+	job, err := st.query().Run(ctx)
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "SubmitQuery")
+	}
+
+	handle, err := json.Marshal(jobHandle{ProjectID: job.ProjectID(), Location: job.Location(), JobID: job.ID()})
+	if err != nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInternal,
+			Msg:  fmt.Sprintf("[BigQuery] could not serialize query handle: %s", err.Error()),
+		}
+	}
+	return handle, nil
+}
+
+// AttachQuery waits for the query job of a handle returned by SubmitQuery,
+// and reads its result.
+func (c *connectionImpl) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	h, err := deserializeJobHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.client.JobFromProject(ctx, h.ProjectID, h.JobID, h.Location)
+	if err != nil {
+		return nil, apiErrToAdbcErr(err, "AttachQuery")
+	}
+	arrowIterator, _, err := readJob(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	rdr, err := newIteratorReader(ctx, arrowIterator, c.Alloc, c.resultRecordBufferSize)
+	if err != nil {
+		return nil, err
+	}
+	return rdr, nil
+}
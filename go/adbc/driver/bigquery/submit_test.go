@@ -0,0 +1,90 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+
+	bq "google.golang.org/api/bigquery/v2"
+)
+
+func TestSubmitAndAttachQuery(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	jobSrv := &fakeJobServer{jobs: make(map[string]*bq.Job), running: true}
+	httpSrv := httptest.NewServer(jobSrv)
+	defer httpSrv.Close()
+
+	ctx := context.Background()
+	client, err := bigquery.NewClient(ctx, "project",
+		option.WithEndpoint(httpSrv.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+
+	drv := driverbase.NewDriverImplBase(driverbase.DefaultDriverInfo("BigQuery"), mem)
+	db, err := driverbase.NewDatabaseImplBase(ctx, &drv)
+	require.NoError(t, err)
+	c := &connectionImpl{ConnectionImplBase: driverbase.NewConnectionImplBase(&db), client: client, resultRecordBufferSize: 1}
+	stmt, err := c.NewStatement()
+	require.NoError(t, err)
+	st := stmt.(*statement)
+	require.NoError(t, st.SetSqlQuery("SELECT ints FROM t"))
+
+	// the job is still running when it is submitted
+	handle, err := st.SubmitQuery(ctx)
+	require.NoError(t, err)
+	var h jobHandle
+	require.NoError(t, json.Unmarshal(handle, &h))
+	assert.Equal(t, "project", h.ProjectID)
+	assert.NotEmpty(t, h.JobID)
+
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = c.AttachQuery(short, handle)
+	cancel()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+
+	jobSrv.setRunning(false)
+	rdr, err := c.AttachQuery(ctx, handle)
+	require.NoError(t, err)
+	defer rdr.Release()
+	// the fake job has no rows
+	assert.False(t, rdr.Next())
+	require.NoError(t, rdr.Err())
+	assert.Equal(t, []string{"SELECT ints FROM t"}, jobSrv.queries)
+
+	for _, invalid := range []string{"", "{}", `{"project_id": "project"}`} {
+		_, err = c.AttachQuery(ctx, []byte(invalid))
+		require.ErrorAs(t, err, &adbcErr)
+		assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	}
+}
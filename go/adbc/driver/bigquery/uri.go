@@ -0,0 +1,65 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/profile"
+)
+
+func init() {
+	profile.RegisterURIParser("bigquery", parseURI)
+}
+
+// parseURI converts a URI of the form
+// bigquery://project/dataset?auth_type=json_credential_file&auth_credentials=path
+// into database options. Parameters are named like the options, with
+// or without the adbc.bigquery.sql. prefix.
+func parseURI(uri *url.URL) (map[string]string, error) {
+	opts := map[string]string{}
+	if project := uri.Host; project != "" {
+		opts[OptionStringProjectID] = project
+	}
+	if dataset := strings.Trim(uri.Path, "/"); dataset != "" {
+		if strings.Contains(dataset, "/") {
+			return nil, adbc.Error{
+				Msg:  "[BigQuery] URI path must be /dataset, got " + uri.Path,
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
+		opts[OptionStringDatasetID] = dataset
+	}
+
+	err := profile.QueryOptions(opts, uri.Query(), nil, func(key string) string {
+		if strings.HasPrefix(key, "adbc.") {
+			return key
+		}
+		return optionNamespace + key
+	})
+	if err != nil {
+		return nil, err
+	}
+	// allow auth_type=json_credential_file for the full option value
+	if authType, ok := opts[OptionStringAuthType]; ok && !strings.HasPrefix(authType, OptionStringAuthType+".") {
+		opts[OptionStringAuthType] = OptionStringAuthType + "." + authType
+	}
+	return opts, nil
+}
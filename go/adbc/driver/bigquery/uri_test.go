@@ -0,0 +1,42 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bigquery
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	opts, err := profile.ParseURI("bigquery://my-project/my_dataset?auth_type=json_credential_file&" +
+		"auth_credentials=%2Fkeys%2Fsa.json&adbc.bigquery.sql.query.priority=BATCH")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		OptionStringProjectID:       "my-project",
+		OptionStringDatasetID:       "my_dataset",
+		OptionStringAuthType:        OptionValueAuthTypeJSONCredentialFile,
+		OptionStringAuthCredentials: "/keys/sa.json",
+		OptionStringQueryPriority:   "BATCH",
+	}, opts)
+
+	_, err = profile.ParseURI("bigquery://my-project/a/b")
+	assert.ErrorContains(t, err, "URI path must be /dataset")
+}
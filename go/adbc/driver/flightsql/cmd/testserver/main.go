@@ -28,6 +28,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,8 +59,51 @@ type ExampleServer struct {
 	mu            sync.Mutex
 	pollingStatus map[string]int
 	headers       []RecordedHeader
+	// tables created by bulk ingestion, by name
+	tables map[string]*ingestedTable
 }
 
+// ingestedTable holds the data of a table created by bulk ingestion.
+// Catalogs, schemas and temporary tables are not modeled; tables are
+// identified only by name.
+type ingestedTable struct {
+	schema  *arrow.Schema
+	records []arrow.Record
+}
+
+func (t *ingestedTable) release() {
+	for _, rec := range t.records {
+		rec.Release()
+	}
+}
+
+// concat combines all the ingested records into a single record.
+func (t *ingestedTable) concat(mem memory.Allocator) (arrow.Record, error) {
+	cols := make([]arrow.Array, t.schema.NumFields())
+	nrows := int64(0)
+	for _, rec := range t.records {
+		nrows += rec.NumRows()
+	}
+	for i := range cols {
+		chunks := make([]arrow.Array, len(t.records))
+		for j, rec := range t.records {
+			chunks[j] = rec.Column(i)
+		}
+		col, err := array.Concatenate(chunks, mem)
+		if err != nil {
+			return nil, err
+		}
+		defer col.Release()
+		cols[i] = col
+	}
+	return array.NewRecord(t.schema, cols, nrows), nil
+}
+
+var (
+	selectTableQuery = regexp.MustCompile(`(?i)^SELECT \* FROM "([^"]+)"`)
+	dropTableQuery   = regexp.MustCompile(`(?i)^DROP TABLE IF EXISTS "?([^"]+)"?$`)
+)
+
 var recordedHeadersSchema = arrow.NewSchema([]arrow.Field{
 	{Name: "method", Type: arrow.BinaryTypes.String, Nullable: false},
 	{Name: "header", Type: arrow.BinaryTypes.String, Nullable: false},
@@ -158,7 +202,7 @@ func (srv *ExampleServer) GetFlightInfoPreparedStatement(ctx context.Context, cm
 
 func (srv *ExampleServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
 	srv.recordHeaders(ctx, "GetFlightInfoStatement")
-	ticket, err := flightsql.CreateStatementQueryTicket(desc.Cmd)
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
 	if err != nil {
 		return nil, err
 	}
@@ -393,6 +437,12 @@ func (srv *ExampleServer) DoGetPreparedStatement(ctx context.Context, cmd flight
 }
 
 func (srv *ExampleServer) DoGetStatement(ctx context.Context, cmd flightsql.StatementQueryTicket) (schema *arrow.Schema, out <-chan flight.StreamChunk, err error) {
+	// Queries of the form SELECT * FROM "table" read back ingested data
+	// (in insertion order; anything after the table name is ignored)
+	if m := selectTableQuery.FindStringSubmatch(string(cmd.GetStatementHandle())); m != nil {
+		return srv.doGetTable(m[1])
+	}
+
 	schema = arrow.NewSchema([]arrow.Field{{Name: "ints", Type: arrow.PrimitiveTypes.Int32, Nullable: true}}, nil)
 	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(`[{"ints": 5}]`))
 
@@ -409,6 +459,88 @@ func (srv *ExampleServer) DoGetStatement(ctx context.Context, cmd flightsql.Stat
 	return
 }
 
+func (srv *ExampleServer) doGetTable(name string) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	table, ok := srv.tables[name]
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "table not found: %s", name)
+	}
+
+	ch := make(chan flight.StreamChunk, 1)
+	defer close(ch)
+	if len(table.records) > 0 {
+		rec, err := table.concat(srv.Alloc)
+		if err != nil {
+			return nil, nil, status.Error(codes.Internal, err.Error())
+		}
+		ch <- flight.StreamChunk{Data: rec}
+	}
+	return table.schema, ch, nil
+}
+
+func (srv *ExampleServer) DoPutCommandStatementUpdate(ctx context.Context, cmd flightsql.StatementUpdate) (int64, error) {
+	srv.recordHeaders(ctx, "DoPutCommandStatementUpdate")
+	m := dropTableQuery.FindStringSubmatch(cmd.GetQuery())
+	if m == nil {
+		return 0, status.Errorf(codes.Unimplemented, "DoPutCommandStatementUpdate not implemented: %s", cmd.GetQuery())
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if table, ok := srv.tables[m[1]]; ok {
+		table.release()
+		delete(srv.tables, m[1])
+	}
+	return 0, nil
+}
+
+func (srv *ExampleServer) DoPutCommandStatementIngest(ctx context.Context, cmd flightsql.StatementIngest, reader flight.MessageReader) (int64, error) {
+	srv.recordHeaders(ctx, "DoPutCommandStatementIngest")
+	name := cmd.GetTable()
+	opts := cmd.GetTableDefinitionOptions()
+
+	// Read everything first, so a failed ingestion leaves no trace
+	incoming := &ingestedTable{schema: reader.Schema()}
+	nrows := int64(0)
+	for reader.Next() {
+		rec := reader.Record()
+		rec.Retain()
+		incoming.records = append(incoming.records, rec)
+		nrows += rec.NumRows()
+	}
+	if err := reader.Err(); err != nil {
+		incoming.release()
+		return 0, err
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	table, exists := srv.tables[name]
+	switch {
+	case !exists && opts.GetIfNotExist() == flightsql.TableDefinitionOptionsTableNotExistOptionCreate:
+		srv.tables[name] = incoming
+	case !exists:
+		incoming.release()
+		return 0, status.Errorf(codes.NotFound, "table not found: %s", name)
+	case opts.GetIfExists() == flightsql.TableDefinitionOptionsTableExistsOptionReplace:
+		table.release()
+		srv.tables[name] = incoming
+	case opts.GetIfExists() == flightsql.TableDefinitionOptionsTableExistsOptionAppend:
+		if !table.schema.Equal(incoming.schema) {
+			incoming.release()
+			return 0, status.Errorf(codes.InvalidArgument, "schema does not match table %s: %s", name, incoming.schema)
+		}
+		table.records = append(table.records, incoming.records...)
+	default:
+		incoming.release()
+		return 0, status.Errorf(codes.AlreadyExists, "table already exists: %s", name)
+	}
+	return nrows, nil
+}
+
 func (srv *ExampleServer) DoPutPreparedStatementQuery(ctx context.Context, cmd flightsql.PreparedStatementQuery, reader flight.MessageReader, writer flight.MetadataWriter) ([]byte, error) {
 	srv.recordHeaders(ctx, "DoPutPreparedStatementQuery")
 	switch string(cmd.GetPreparedStatementHandle()) {
@@ -546,11 +678,17 @@ func main() {
 
 	flag.Parse()
 
-	srv := &ExampleServer{pollingStatus: make(map[string]int)}
+	srv := &ExampleServer{
+		pollingStatus: make(map[string]int),
+		tables:        make(map[string]*ingestedTable),
+	}
 	srv.Alloc = memory.DefaultAllocator
 	if err := srv.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerTransaction, int32(flightsql.SqlTransactionTransaction)); err != nil {
 		log.Fatal(err)
 	}
+	if err := srv.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerBulkIngestion, true); err != nil {
+		log.Fatal(err)
+	}
 
 	server := flight.NewServerWithMiddleware(nil)
 	server.RegisterFlightService(flightsql.NewFlightServer(srv))
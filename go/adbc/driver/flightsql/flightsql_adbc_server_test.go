@@ -193,6 +193,10 @@ func TestExecuteSchema(t *testing.T) {
 	suite.Run(t, &ExecuteSchemaTests{})
 }
 
+func TestIngest(t *testing.T) {
+	suite.Run(t, &IngestTests{})
+}
+
 func TestIncrementalPoll(t *testing.T) {
 	suite.Run(t, &IncrementalPollTests{})
 }
@@ -217,6 +221,10 @@ func TestSessionOptions(t *testing.T) {
 	suite.Run(t, &SessionOptionTests{})
 }
 
+func TestCancel(t *testing.T) {
+	suite.Run(t, &CancelTests{})
+}
+
 func TestGetObjects(t *testing.T) {
 	suite.Run(t, &GetObjectsTests{})
 }
@@ -669,6 +677,8 @@ func (srv *ErrorDetailsTestServer) GetFlightInfoStatement(ctx context.Context, q
 		return &flight.FlightInfo{Endpoint: []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: tkt}}}}, nil
 	} else if query.GetQuery() == "vendorcode" {
 		return nil, status.Errorf(codes.ResourceExhausted, "Resource exhausted")
+	} else if query.GetQuery() == "notfound" {
+		return nil, status.Errorf(codes.NotFound, "Function not found")
 	} else if query.GetQuery() == "binaryheader" {
 		if err := grpc.SendHeader(ctx, metadata.Pairs("x-header-bin", string([]byte{0, 110}))); err != nil {
 			return nil, err
@@ -796,7 +806,8 @@ func (ts *ErrorDetailsTests) TestDoGet() {
 	var adbcErr adbc.Error
 	ts.ErrorAs(err, &adbcErr, "Error was: %#v", err)
 
-	ts.Equal(1, len(adbcErr.Details))
+	// the status details, then the ticket of the endpoint
+	ts.Equal(2, len(adbcErr.Details))
 
 	wrapper := adbcErr.Details[0]
 	ts.Equal("grpc-status-details-bin", wrapper.Key())
@@ -808,6 +819,11 @@ func (ts *ErrorDetailsTests) TestDoGet() {
 	message := wrappers.Int32Value{}
 	ts.NoError(any.UnmarshalTo(&message))
 	ts.Equal(int32(42), message.Value)
+
+	var ticket *adbc.FlightTicketErrorDetail
+	ts.ErrorAs(reader.Err(), &ticket)
+	ts.Equal(adbc.ErrorDetailKeyFlightTicket, ticket.Key())
+	ts.NotEmpty(ticket.Ticket)
 }
 
 func (ts *ErrorDetailsTests) TestVendorCode() {
@@ -824,6 +840,22 @@ func (ts *ErrorDetailsTests) TestVendorCode() {
 	ts.Equal(int32(codes.ResourceExhausted), adbcErr.VendorCode)
 }
 
+func (ts *ErrorDetailsTests) TestNotFoundSQLState() {
+	stmt, err := ts.cnxn.NewStatement()
+	ts.NoError(err)
+	defer validation.CheckedClose(ts.T(), stmt)
+
+	ts.NoError(stmt.SetSqlQuery("notfound"))
+
+	_, _, err = stmt.ExecuteQuery(context.Background())
+	var adbcErr adbc.Error
+	ts.ErrorAs(err, &adbcErr)
+
+	// the server didn't say what was not found, so it isn't a missing table
+	ts.Equal(adbc.StatusNotFound, adbcErr.Code)
+	ts.Equal([5]byte{}, adbcErr.SqlState)
+}
+
 // ---- ExecuteSchema Tests --------------------
 
 type ExecuteSchemaTestServer struct {
@@ -918,6 +950,192 @@ func (ts *ExecuteSchemaTests) TestQuery() {
 	ts.True(expectedSchema.Equal(schema), schema.String())
 }
 
+// ---- Ingest Tests --------------------
+
+type IngestTestServer struct {
+	flightsql.BaseServer
+
+	mu    sync.Mutex
+	cmd   flightsql.StatementIngest
+	nrows int64
+}
+
+func (srv *IngestTestServer) DoPutCommandStatementIngest(ctx context.Context, cmd flightsql.StatementIngest, reader flight.MessageReader) (int64, error) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.cmd = cmd
+	srv.nrows = 0
+	for reader.Next() {
+		srv.nrows += reader.Record().NumRows()
+	}
+	if cmd.GetTable() == "missing" {
+		return 0, status.Error(codes.NotFound, "table not found")
+	}
+	return srv.nrows, reader.Err()
+}
+
+func (srv *IngestTestServer) BeginTransaction(context.Context, flightsql.ActionBeginTransactionRequest) ([]byte, error) {
+	return []byte("txn"), nil
+}
+
+func (srv *IngestTestServer) EndTransaction(context.Context, flightsql.ActionEndTransactionRequest) error {
+	return nil
+}
+
+func (srv *IngestTestServer) last() (flightsql.StatementIngest, int64) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.cmd, srv.nrows
+}
+
+type IngestTests struct {
+	ServerBasedTests
+
+	srv *IngestTestServer
+}
+
+func (suite *IngestTests) SetupSuite() {
+	suite.srv = &IngestTestServer{}
+	suite.srv.Alloc = memory.DefaultAllocator
+	suite.Require().NoError(suite.srv.RegisterSqlInfo(flightsql.SqlInfoFlightSqlServerTransaction, int32(flightsql.SqlTransactionTransaction)))
+	suite.DoSetupSuite(suite.srv, nil, nil)
+}
+
+func (suite *IngestTests) makeReader() array.RecordReader {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ints", Type: arrow.PrimitiveTypes.Int64, Nullable: true}}, nil)
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(`[{"ints": 1}, {"ints": null}, {"ints": 3}]`))
+	suite.Require().NoError(err)
+	defer rec.Release()
+	rdr, err := array.NewRecordReader(schema, []arrow.Record{rec})
+	suite.Require().NoError(err)
+	return rdr
+}
+
+func (suite *IngestTests) TestIngestStream() {
+	rdr := suite.makeReader()
+	defer rdr.Release()
+
+	n, err := adbc.IngestStream(context.Background(), suite.cnxn, rdr, "target", adbc.OptionValueIngestModeCreateAppend, adbc.IngestStreamOptions{
+		Catalog:   "catalog",
+		DBSchema:  "schema",
+		Temporary: true,
+	})
+	suite.Require().NoError(err)
+	suite.EqualValues(3, n)
+
+	cmd, nrows := suite.srv.last()
+	suite.EqualValues(3, nrows)
+	suite.Equal("target", cmd.GetTable())
+	suite.Equal("catalog", cmd.GetCatalog())
+	suite.Equal("schema", cmd.GetSchema())
+	suite.True(cmd.GetTemporary())
+	suite.Empty(cmd.GetTransactionId())
+}
+
+func (suite *IngestTests) TestIngestModes() {
+	tests := []struct {
+		mode       string
+		ifNotExist flightsql.TableDefinitionOptionsTableNotExistOption
+		ifExists   flightsql.TableDefinitionOptionsTableExistsOption
+	}{
+		{adbc.OptionValueIngestModeCreate, flightsql.TableDefinitionOptionsTableNotExistOptionCreate, flightsql.TableDefinitionOptionsTableExistsOptionFail},
+		{adbc.OptionValueIngestModeAppend, flightsql.TableDefinitionOptionsTableNotExistOptionFail, flightsql.TableDefinitionOptionsTableExistsOptionAppend},
+		{adbc.OptionValueIngestModeReplace, flightsql.TableDefinitionOptionsTableNotExistOptionCreate, flightsql.TableDefinitionOptionsTableExistsOptionReplace},
+		{adbc.OptionValueIngestModeCreateAppend, flightsql.TableDefinitionOptionsTableNotExistOptionCreate, flightsql.TableDefinitionOptionsTableExistsOptionAppend},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.mode, func() {
+			stmt, err := suite.cnxn.NewStatement()
+			suite.Require().NoError(err)
+			defer validation.CheckedClose(suite.T(), stmt)
+
+			rdr := suite.makeReader()
+			defer rdr.Release()
+
+			suite.Require().NoError(stmt.SetOption(adbc.OptionKeyIngestTargetTable, "target"))
+			suite.Require().NoError(stmt.SetOption(adbc.OptionKeyIngestMode, tt.mode))
+			suite.Require().NoError(stmt.BindStream(context.Background(), rdr))
+			n, err := stmt.ExecuteUpdate(context.Background())
+			suite.Require().NoError(err)
+			suite.EqualValues(3, n)
+
+			cmd, _ := suite.srv.last()
+			suite.Equal(tt.ifNotExist, cmd.GetTableDefinitionOptions().GetIfNotExist())
+			suite.Equal(tt.ifExists, cmd.GetTableDefinitionOptions().GetIfExists())
+			suite.False(cmd.GetTemporary())
+
+			val, err := stmt.(adbc.GetSetOptions).GetOption(adbc.OptionKeyIngestMode)
+			suite.Require().NoError(err)
+			suite.Equal(tt.mode, val)
+		})
+	}
+}
+
+func (suite *IngestTests) TestIngestTransaction() {
+	suite.Require().NoError(suite.cnxn.(adbc.PostInitOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueDisabled))
+	defer func() {
+		suite.NoError(suite.cnxn.(adbc.PostInitOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueEnabled))
+	}()
+
+	rdr := suite.makeReader()
+	defer rdr.Release()
+
+	_, err := adbc.IngestStream(context.Background(), suite.cnxn, rdr, "target", adbc.OptionValueIngestModeAppend, adbc.IngestStreamOptions{})
+	suite.Require().NoError(err)
+
+	cmd, _ := suite.srv.last()
+	suite.Equal([]byte("txn"), cmd.GetTransactionId())
+}
+
+func (suite *IngestTests) TestIngestErrors() {
+	stmt, err := suite.cnxn.NewStatement()
+	suite.Require().NoError(err)
+	defer validation.CheckedClose(suite.T(), stmt)
+
+	var adbcErr adbc.Error
+	suite.Require().NoError(stmt.SetOption(adbc.OptionKeyIngestTargetTable, "missing"))
+	_, err = stmt.ExecuteUpdate(context.Background())
+	suite.ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusInvalidState, adbcErr.Code)
+
+	suite.ErrorAs(stmt.SetOption(adbc.OptionKeyIngestMode, "overwrite"), &adbcErr)
+	suite.Equal(adbc.StatusInvalidArgument, adbcErr.Code)
+
+	rdr := suite.makeReader()
+	defer rdr.Release()
+	suite.Require().NoError(stmt.SetOption(adbc.OptionKeyIngestMode, adbc.OptionValueIngestModeAppend))
+	suite.Require().NoError(stmt.BindStream(context.Background(), rdr))
+	_, err = stmt.ExecuteUpdate(context.Background())
+	suite.ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusNotFound, adbcErr.Code)
+	suite.Equal([5]byte{'4', '2', 'S', '0', '2'}, adbcErr.SqlState)
+}
+
+func (suite *IngestTests) TestBindWithoutPrepare() {
+	stmt, err := suite.cnxn.NewStatement()
+	suite.Require().NoError(err)
+	defer validation.CheckedClose(suite.T(), stmt)
+
+	// without a target table or a prepared statement, the bound data
+	// can't be used by the query
+	rdr := suite.makeReader()
+	defer rdr.Release()
+	suite.Require().NoError(stmt.SetSqlQuery("SELECT ?"))
+	suite.Require().NoError(stmt.BindStream(context.Background(), rdr))
+
+	var adbcErr adbc.Error
+	_, _, err = stmt.ExecuteQuery(context.Background())
+	suite.Require().ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusInvalidState, adbcErr.Code)
+	_, err = stmt.ExecuteUpdate(context.Background())
+	suite.Require().ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusInvalidState, adbcErr.Code)
+	_, err = stmt.(adbc.StatementExecuteSchema).ExecuteSchema(context.Background())
+	suite.Require().ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusInvalidState, adbcErr.Code)
+}
+
 // ---- IncrementalPoll Tests --------------------
 
 type IncrementalQuery struct {
@@ -1985,6 +2203,34 @@ func (suite *DataTypeTests) TestMapIntInt() {
 	suite.DoTestCase("map[int]int", SchemaMapIntInt)
 }
 
+func (suite *DataTypeTests) TestSubmitAndAttachQuery() {
+	stmt, err := suite.cnxn.NewStatement()
+	suite.Require().NoError(err)
+	suite.Require().NoError(stmt.SetSqlQuery("list[int]"))
+	handle, err := stmt.(adbc.StatementSubmitQuery).SubmitQuery(context.Background())
+	suite.Require().NoError(err)
+	validation.CheckedClose(suite.T(), stmt)
+
+	// the results can be read from another connection
+	cnxn, err := suite.db.Open(context.Background())
+	suite.Require().NoError(err)
+	defer validation.CheckedClose(suite.T(), cnxn)
+
+	reader, err := cnxn.(adbc.ConnectionAttachQuery).AttachQuery(context.Background(), handle)
+	suite.Require().NoError(err)
+	defer reader.Release()
+	suite.Equal(SchemaListInt, reader.Schema())
+	suite.Require().True(reader.Next())
+	suite.EqualValues(1, reader.Record().NumRows())
+	suite.False(reader.Next())
+	suite.NoError(reader.Err())
+
+	var adbcErr adbc.Error
+	_, err = cnxn.(adbc.ConnectionAttachQuery).AttachQuery(context.Background(), []byte("not a FlightInfo"))
+	suite.ErrorAs(err, &adbcErr)
+	suite.Equal(adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
 // ---- Multi Table Tests --------------------
 
 type MultiTableTestServer struct {
@@ -2072,6 +2318,108 @@ func (suite *MultiTableTests) TestGetTableSchema() {
 	suite.Equal(expectedSchema, actualSchema)
 }
 
+// ---- Cancel Tests --------------------
+
+type CancelTestServer struct {
+	flightsql.BaseServer
+
+	mu        sync.Mutex
+	cancelled int
+}
+
+func (server *CancelTestServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	tkt, err := flightsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
+	if err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: tkt}}},
+		FlightDescriptor: desc,
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+func (server *CancelTestServer) DoGetStatement(ctx context.Context, tkt flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	sc := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int32, Nullable: true}}, nil)
+	rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, sc, strings.NewReader(`[{"a": 5}]`))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan flight.StreamChunk, 1)
+	ch <- flight.StreamChunk{Data: rec}
+	close(ch)
+	return sc, ch, nil
+}
+
+func (server *CancelTestServer) CancelFlightInfo(ctx context.Context, req *flight.CancelFlightInfoRequest) (flight.CancelFlightInfoResult, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.cancelled++
+	return flight.CancelFlightInfoResult{Status: flight.CancelStatusCancelled}, nil
+}
+
+func (server *CancelTestServer) takeCancelled() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	n := server.cancelled
+	server.cancelled = 0
+	return n
+}
+
+type CancelTests struct {
+	ServerBasedTests
+	srv *CancelTestServer
+}
+
+func (suite *CancelTests) SetupSuite() {
+	suite.srv = &CancelTestServer{}
+	suite.DoSetupSuite(suite.srv, nil, nil)
+}
+
+func (suite *CancelTests) TestCancelRunningQuery() {
+	stmt, err := suite.cnxn.NewStatement()
+	suite.Require().NoError(err)
+	defer validation.CheckedClose(suite.T(), stmt)
+	suite.Require().NoError(stmt.SetSqlQuery("SELECT 1"))
+
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	suite.Require().NoError(err)
+	defer rdr.Release()
+
+	suite.Require().NoError(stmt.(adbc.StatementCancel).Cancel())
+	suite.Equal(1, suite.srv.takeCancelled())
+
+	// the query is only cancelled once
+	suite.Require().NoError(stmt.(adbc.StatementCancel).Cancel())
+	suite.Zero(suite.srv.takeCancelled())
+}
+
+func (suite *CancelTests) TestCancelFinishedQuery() {
+	stmt, err := suite.cnxn.NewStatement()
+	suite.Require().NoError(err)
+	defer validation.CheckedClose(suite.T(), stmt)
+	suite.Require().NoError(stmt.SetSqlQuery("SELECT 1"))
+
+	// a query whose results were read completely isn't cancelled
+	rdr, _, err := stmt.ExecuteQuery(context.Background())
+	suite.Require().NoError(err)
+	for rdr.Next() {
+	}
+	suite.Require().NoError(rdr.Err())
+	suite.Require().NoError(stmt.(adbc.StatementCancel).Cancel())
+	suite.Zero(suite.srv.takeCancelled())
+	rdr.Release()
+
+	// nor is one whose reader was released
+	rdr, _, err = stmt.ExecuteQuery(context.Background())
+	suite.Require().NoError(err)
+	rdr.Release()
+	suite.Require().NoError(stmt.(adbc.StatementCancel).Cancel())
+	suite.Zero(suite.srv.takeCancelled())
+}
+
 // ---- Session Option Tests --------------------
 
 type SessionOptionTestServer struct {
@@ -383,6 +383,71 @@ func TestADBCFlightSQLWithHeader(t *testing.T) {
 	suite.Run(t, &StatementTests{Quirks: q})
 }
 
+// Run the bulk ingestion tests against cmd/testserver, if it is running
+
+type TestServerQuirks struct {
+	FlightSQLQuirks
+
+	uri string
+}
+
+func (s *TestServerQuirks) SetupDriver(t *testing.T) adbc.Driver {
+	s.mem = memory.NewCheckedAllocator(memory.DefaultAllocator)
+	return driver.NewDriver(s.mem)
+}
+
+func (s *TestServerQuirks) TearDownDriver(t *testing.T, _ adbc.Driver) {
+	s.mem.AssertSize(t, 0)
+}
+
+func (s *TestServerQuirks) DatabaseOptions() map[string]string {
+	return map[string]string{
+		adbc.OptionKeyURI: s.uri,
+	}
+}
+
+func (s *TestServerQuirks) SupportsBulkIngest(string) bool { return true }
+
+// The test server reports an existing table with AlreadyExists, not an
+// internal error
+func (s *TestServerQuirks) SupportsErrorIngestIncompatibleSchema() bool { return false }
+
+// TestServerIngestTests runs only the bulk ingestion tests from
+// validation.StatementTests, since the test server can't run the rest.
+type TestServerIngestTests struct {
+	suite.Suite
+
+	tests validation.StatementTests
+}
+
+func (s *TestServerIngestTests) run(test func()) {
+	s.tests.SetT(s.T())
+	s.tests.SetupTest()
+	defer s.tests.TearDownTest()
+	test()
+}
+
+func (s *TestServerIngestTests) TestSqlIngestInts()   { s.run(s.tests.TestSqlIngestInts) }
+func (s *TestServerIngestTests) TestSqlIngestAppend() { s.run(s.tests.TestSqlIngestAppend) }
+func (s *TestServerIngestTests) TestSqlIngestReplace() {
+	s.run(s.tests.TestSqlIngestReplace)
+}
+func (s *TestServerIngestTests) TestSqlIngestCreateAppend() {
+	s.run(s.tests.TestSqlIngestCreateAppend)
+}
+func (s *TestServerIngestTests) TestSqlIngestErrors() { s.run(s.tests.TestSqlIngestErrors) }
+
+func TestADBCFlightSQLTestServer(t *testing.T) {
+	// e.g. go run ./driver/flightsql/cmd/testserver -port 41414
+	uri := os.Getenv("ADBC_TEST_FLIGHTSQL_URI")
+	if uri == "" {
+		t.Skip("ADBC_TEST_FLIGHTSQL_URI not set")
+	}
+
+	q := &TestServerQuirks{uri: uri}
+	suite.Run(t, &TestServerIngestTests{tests: validation.StatementTests{Quirks: q}})
+}
+
 // Driver-specific tests
 
 type DefaultDialOptionsTests struct {
@@ -701,7 +701,7 @@ func (c *connectionImpl) PrepareDriverInfo(ctx context.Context, infoCodes []adbc
 // Helper function to read and validate a metadata stream
 func (c *connectionImpl) readInfo(ctx context.Context, expectedSchema *arrow.Schema, info *flight.FlightInfo, opts ...grpc.CallOption) (array.RecordReader, error) {
 	// use a default queueSize for the reader
-	rdr, err := newRecordReader(ctx, c.db.Alloc, c.cl, info, c.clientCache, 5, opts...)
+	rdr, err := newRecordReader(ctx, c.db.Alloc, c.cl, info, c.clientCache, 5, c.RetryPolicy, opts...)
 	if err != nil {
 		return nil, adbcFromFlightStatus(err, "DoGet")
 	}
@@ -723,9 +723,12 @@ func (c *connectionImpl) GetObjectsCatalogs(ctx context.Context, catalog *string
 	)
 	ctx = metadata.NewOutgoingContext(ctx, c.hdrs)
 	// To avoid an N+1 query problem, we assume result sets here will fit in memory and build up a single response.
-	info, err := c.cl.GetCatalogs(ctx, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+	info, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.FlightInfo, error) {
+		info, err := c.cl.GetCatalogs(ctx, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+		return info, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetCatalogs)")
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetCatalogs)")
+		return nil, err
 	}
 
 	if info.TotalRecords > 0 {
@@ -766,9 +769,12 @@ func (c *connectionImpl) GetObjectsDbSchemas(ctx context.Context, depth adbc.Obj
 	result = make(map[string][]string)
 	var header, trailer metadata.MD
 	// Pre-populate the map of which schemas are in which catalogs
-	info, err := c.cl.GetDBSchemas(ctx, &flightsql.GetDBSchemasOpts{DbSchemaFilterPattern: dbSchema}, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+	info, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.FlightInfo, error) {
+		info, err := c.cl.GetDBSchemas(ctx, &flightsql.GetDBSchemasOpts{DbSchemaFilterPattern: dbSchema}, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+		return info, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetDBSchemas)")
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetDBSchemas)")
+		return nil, err
 	}
 
 	header = metadata.MD{}
@@ -810,14 +816,17 @@ func (c *connectionImpl) GetObjectsTables(ctx context.Context, depth adbc.Object
 	// Pre-populate the map of which schemas are in which catalogs
 	includeSchema := depth == adbc.ObjectDepthAll || depth == adbc.ObjectDepthColumns
 	var header, trailer metadata.MD
-	info, err := c.cl.GetTables(ctx, &flightsql.GetTablesOpts{
-		DbSchemaFilterPattern:  dbSchema,
-		TableNameFilterPattern: tableName,
-		TableTypes:             tableType,
-		IncludeSchema:          includeSchema,
-	}, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+	info, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.FlightInfo, error) {
+		info, err := c.cl.GetTables(ctx, &flightsql.GetTablesOpts{
+			DbSchemaFilterPattern:  dbSchema,
+			TableNameFilterPattern: tableName,
+			TableTypes:             tableType,
+			IncludeSchema:          includeSchema,
+		}, grpc.Header(&header), grpc.Trailer(&trailer), c.timeouts)
+		return info, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetTables)")
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetObjects(GetTables)")
+		return nil, err
 	}
 
 	expectedSchema := schema_ref.Tables
@@ -891,16 +900,25 @@ func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, db
 
 	ctx = metadata.NewOutgoingContext(ctx, c.hdrs)
 	var header, trailer metadata.MD
-	info, err := c.cl.GetTables(ctx, opts, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+	info, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.FlightInfo, error) {
+		info, err := c.cl.GetTables(ctx, opts, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+		return info, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(GetTables)")
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(GetTables)")
+		return nil, err
 	}
 
 	header = metadata.MD{}
 	trailer = metadata.MD{}
-	rdr, err := doGet(ctx, c.cl, info.Endpoint[0], c.clientCache, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+	rdr, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.Reader, error) {
+		rdr, err := doGet(ctx, c.cl, info.Endpoint[0], c.clientCache, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err != nil {
+			return nil, withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(DoGet)"), info.Endpoint[0])
+		}
+		return rdr, nil
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(DoGet)")
+		return nil, err
 	}
 	defer rdr.Release()
 
@@ -912,7 +930,7 @@ func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, db
 				Code: adbc.StatusNotFound,
 			}
 		}
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(DoGet)")
+		return nil, withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableSchema(DoGet)"), info.Endpoint[0])
 	}
 
 	numRows := rec.NumRows()
@@ -963,12 +981,15 @@ func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, db
 func (c *connectionImpl) GetTableTypes(ctx context.Context) (array.RecordReader, error) {
 	ctx = metadata.NewOutgoingContext(ctx, c.hdrs)
 	var header, trailer metadata.MD
-	info, err := c.cl.GetTableTypes(ctx, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+	info, err := driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (*flight.FlightInfo, error) {
+		info, err := c.cl.GetTableTypes(ctx, c.timeouts, grpc.Header(&header), grpc.Trailer(&trailer))
+		return info, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableTypes")
+	})
 	if err != nil {
-		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "GetTableTypes")
+		return nil, err
 	}
 
-	return newRecordReader(ctx, c.db.Alloc, c.cl, info, c.clientCache, 5)
+	return newRecordReader(ctx, c.db.Alloc, c.cl, info, c.clientCache, 5, c.RetryPolicy)
 }
 
 // Commit commits any pending transactions on this connection, it should
@@ -1020,12 +1041,14 @@ func (c *connectionImpl) Rollback(ctx context.Context) error {
 // NewStatement initializes a new statement object tied to this connection
 func (c *connectionImpl) NewStatement() (adbc.Statement, error) {
 	return &statement{
-		alloc:       c.db.Alloc,
-		clientCache: c.clientCache,
-		hdrs:        c.hdrs.Copy(),
-		queueSize:   5,
-		timeouts:    c.timeouts,
-		cnxn:        c,
+		StatementImplBase: driverbase.NewStatementImplBase(&c.ConnectionImplBase, c.ErrorHelper),
+		alloc:             c.db.Alloc,
+		clientCache:       c.clientCache,
+		hdrs:              c.hdrs.Copy(),
+		queueSize:         5,
+		timeouts:          c.timeouts,
+		cnxn:              c,
+		ingestMode:        adbc.OptionValueIngestModeCreate,
 	}, nil
 }
 
@@ -1077,6 +1100,14 @@ func (c *connectionImpl) executeSubstraitUpdate(ctx context.Context, plan flight
 	return c.cl.ExecuteSubstraitUpdate(ctx, plan, opts...)
 }
 
+func (c *connectionImpl) executeIngest(ctx context.Context, rdr array.RecordReader, req *flightsql.ExecuteIngestOpts, opts ...grpc.CallOption) (n int64, err error) {
+	if c.txn != nil {
+		req.TransactionId = c.txn.ID()
+	}
+
+	return c.cl.ExecuteIngest(ctx, rdr, req, opts...)
+}
+
 func (c *connectionImpl) poll(ctx context.Context, query string, retryDescriptor *flight.FlightDescriptor, opts ...grpc.CallOption) (*flight.PollInfo, error) {
 	if c.txn != nil {
 		return c.txn.ExecutePoll(ctx, query, retryDescriptor, opts...)
@@ -1158,11 +1189,28 @@ func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition
 	}
 
 	ctx = metadata.NewOutgoingContext(ctx, c.hdrs)
-	rdr, err = doGet(ctx, c.cl, info.Endpoint[0], c.clientCache, c.timeouts)
-	if err != nil {
-		return nil, adbcFromFlightStatus(err, "ReadPartition(DoGet)")
+	return driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (array.RecordReader, error) {
+		rdr, err := doGet(ctx, c.cl, info.Endpoint[0], c.clientCache, c.timeouts)
+		if err != nil {
+			return nil, withTicket(adbcFromFlightStatus(err, "ReadPartition(DoGet)"), info.Endpoint[0])
+		}
+		return rdr, nil
+	})
+}
+
+// AttachQuery reads the results of a serialized FlightInfo returned by
+// SubmitQuery.
+func (c *connectionImpl) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	var info flight.FlightInfo
+	if err := proto.Unmarshal(handle, &info); err != nil {
+		return nil, adbc.Error{
+			Msg:  err.Error(),
+			Code: adbc.StatusInvalidArgument,
+		}
 	}
-	return rdr, nil
+
+	ctx = metadata.NewOutgoingContext(ctx, c.hdrs)
+	return newRecordReader(ctx, c.db.Alloc, c.cl, &info, c.clientCache, 5, c.RetryPolicy, c.timeouts)
 }
 
 var (
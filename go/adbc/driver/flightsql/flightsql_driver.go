@@ -108,6 +108,13 @@ type Driver interface {
 // NewDriver creates a new Flight SQL driver using the given Arrow allocator.
 func NewDriver(alloc memory.Allocator) Driver {
 	info := driverbase.DefaultDriverInfo("Flight SQL")
+	info.RegisterOptionNamespace(optionNamespace)
+	if err := info.RegisterOptions(optionSpecs...); err != nil {
+		panic(err)
+	}
+	if err := info.RegisterRetryOptions(); err != nil {
+		panic(err)
+	}
 	return &driverImpl{DriverImplBase: driverbase.NewDriverImplBase(info, alloc)}
 }
 
@@ -122,6 +129,9 @@ func (d *driverImpl) NewDatabaseWithOptions(opts map[string]string, userDialOpts
 
 func (d *driverImpl) NewDatabaseWithOptionsContext(ctx context.Context, opts map[string]string, userDialOpts ...grpc.DialOption) (adbc.Database, error) {
 	opts = maps.Clone(opts)
+	if err := d.DriverInfo.ValidateOptions(driverbase.OptionLevelDatabase, opts); err != nil {
+		return nil, err
+	}
 	uri, ok := opts[adbc.OptionKeyURI]
 	if !ok {
 		return nil, adbc.Error{
@@ -154,6 +164,10 @@ func (d *driverImpl) NewDatabaseWithOptionsContext(ctx context.Context, opts map
 
 	db.options = make(map[string]string)
 
+	if err := db.SetRetryOptions(opts); err != nil {
+		return nil, err
+	}
+
 	if err := db.SetOptions(opts); err != nil {
 		return nil, err
 	}
@@ -28,6 +28,7 @@ import (
 	"unsafe"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/flight"
@@ -35,7 +36,9 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/bluele/gcache"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -155,6 +158,7 @@ type incrementalState struct {
 }
 
 type statement struct {
+	driverbase.StatementImplBase
 	alloc       memory.Allocator
 	cnxn        *connectionImpl
 	clientCache gcache.Cache
@@ -168,6 +172,46 @@ type statement struct {
 	progress         float64
 	// may seem redundant, but incrementalState isn't locked
 	lastInfo atomic.Pointer[flight.FlightInfo]
+	// the FlightInfo of the query started by ExecuteQuery, for Cancel.
+	// It is cleared once its reader is exhausted or released.
+	activeInfo atomic.Pointer[flight.FlightInfo]
+
+	// bulk ingestion state; data bound without a prepared statement
+	// is held in State until the ingestion is executed
+	targetTable     string
+	ingestMode      string
+	ingestCatalog   *string
+	ingestDBSchema  *string
+	ingestTemporary bool
+}
+
+func (s *statement) Base() *driverbase.StatementImplBase {
+	return &s.StatementImplBase
+}
+
+// Cancel stops any in-progress operation on this statement. If a query
+// has been submitted to the server, it is also asked to cancel it via
+// CancelFlightInfo.
+func (s *statement) Cancel() error {
+	if err := s.StatementImplBase.Cancel(); err != nil {
+		return err
+	}
+
+	info := s.activeInfo.Swap(nil)
+	if info == nil {
+		// no query is being read
+		return nil
+	}
+
+	ctx := metadata.NewOutgoingContext(context.Background(), s.hdrs)
+	var header, trailer metadata.MD
+	_, err := s.cnxn.cl.CancelFlightInfo(ctx, &flight.CancelFlightInfoRequest{Info: info}, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
+	if status.Code(err) == codes.Unimplemented {
+		// the server can't cancel the query, but the client side
+		// has already stopped reading from it
+		return nil
+	}
+	return adbcFromFlightStatusWithDetails(err, header, trailer, "Cancel")
 }
 
 func (s *statement) closePreparedStatement() error {
@@ -203,18 +247,14 @@ func (s *statement) poll(ctx context.Context, opts ...grpc.CallOption) (*flight.
 //
 // A statement instance should not be used after Close is called.
 func (s *statement) Close() (err error) {
+	if err = s.State.Close(); err != nil {
+		return err
+	}
 	if s.prepared != nil {
 		err = s.closePreparedStatement()
 		s.prepared = nil
 	}
 
-	if s.cnxn == nil {
-		return adbc.Error{
-			Msg:  "[Flight SQL Statement] cannot close already closed statement",
-			Code: adbc.StatusInvalidState,
-		}
-	}
-
 	s.clientCache = nil
 	s.cnxn = nil
 
@@ -236,6 +276,25 @@ func (s *statement) GetOption(key string) (string, error) {
 			return adbc.OptionValueEnabled, nil
 		}
 		return adbc.OptionValueDisabled, nil
+	case adbc.OptionKeyIngestTargetTable:
+		return s.targetTable, nil
+	case adbc.OptionKeyIngestMode:
+		return s.ingestMode, nil
+	case adbc.OptionValueIngestTargetCatalog:
+		if s.ingestCatalog != nil {
+			return *s.ingestCatalog, nil
+		}
+		return "", nil
+	case adbc.OptionValueIngestTargetDBSchema:
+		if s.ingestDBSchema != nil {
+			return *s.ingestDBSchema, nil
+		}
+		return "", nil
+	case adbc.OptionValueIngestTemporary:
+		if s.ingestTemporary {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
 	}
 
 	if strings.HasPrefix(key, OptionRPCCallHeaderPrefix) {
@@ -311,8 +370,23 @@ func (s *statement) GetOptionDouble(key string) (float64, error) {
 	}
 }
 
+// validateQueueSize is the OptionSpec.Validate of OptionStatementQueueSize.
+func validateQueueSize(key, value string) error {
+	if size, err := strconv.Atoi(value); err != nil || size <= 0 {
+		return adbc.Error{
+			Msg:  fmt.Sprintf("Invalid value for statement option '%s': '%s' is not a positive integer", key, value),
+			Code: adbc.StatusInvalidArgument,
+		}
+	}
+	return nil
+}
+
 // SetOption sets a string option on this statement
 func (s *statement) SetOption(key string, val string) error {
+	if err := s.ValidateOption(key, val); err != nil {
+		return err
+	}
+
 	if strings.HasPrefix(key, OptionRPCCallHeaderPrefix) {
 		name := strings.TrimPrefix(key, OptionRPCCallHeaderPrefix)
 		if val == "" {
@@ -360,6 +434,53 @@ func (s *statement) SetOption(key string, val string) error {
 				Code: adbc.StatusInvalidArgument,
 			}
 		}
+	case adbc.OptionKeyIngestTargetTable:
+		if err := s.State.SetQuery(); err != nil {
+			return err
+		}
+		if s.prepared != nil {
+			if err := s.closePreparedStatement(); err != nil {
+				return err
+			}
+			s.prepared = nil
+		}
+		s.query.setSqlQuery("")
+		s.targetTable = val
+	case adbc.OptionKeyIngestMode:
+		switch val {
+		case adbc.OptionValueIngestModeCreate, adbc.OptionValueIngestModeAppend,
+			adbc.OptionValueIngestModeReplace, adbc.OptionValueIngestModeCreateAppend:
+			s.ingestMode = val
+		default:
+			return adbc.Error{
+				Msg:  fmt.Sprintf("[Flight SQL] Invalid statement option value %s=%s", key, val),
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
+	case adbc.OptionValueIngestTargetCatalog:
+		if val == "" {
+			s.ingestCatalog = nil
+		} else {
+			s.ingestCatalog = &val
+		}
+	case adbc.OptionValueIngestTargetDBSchema:
+		if val == "" {
+			s.ingestDBSchema = nil
+		} else {
+			s.ingestDBSchema = &val
+		}
+	case adbc.OptionValueIngestTemporary:
+		switch val {
+		case adbc.OptionValueEnabled:
+			s.ingestTemporary = true
+		case adbc.OptionValueDisabled:
+			s.ingestTemporary = false
+		default:
+			return adbc.Error{
+				Msg:  fmt.Sprintf("[Flight SQL] Invalid statement option value %s=%s", key, val),
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
 	default:
 		return adbc.Error{
 			Msg:  "[Flight SQL] Unknown statement option '" + key + "'",
@@ -377,6 +498,10 @@ func (s *statement) SetOptionBytes(key string, value []byte) error {
 }
 
 func (s *statement) SetOptionInt(key string, value int64) error {
+	if err := s.ValidateOption(key, strconv.FormatInt(value, 10)); err != nil {
+		return err
+	}
+
 	switch key {
 	case OptionStatementQueueSize:
 		if value <= 0 {
@@ -392,6 +517,10 @@ func (s *statement) SetOptionInt(key string, value int64) error {
 }
 
 func (s *statement) SetOptionDouble(key string, value float64) error {
+	if err := s.ValidateOption(key, strconv.FormatFloat(value, 'g', -1, 64)); err != nil {
+		return err
+	}
+
 	switch key {
 	case OptionTimeoutFetch:
 		fallthrough
@@ -412,6 +541,9 @@ func (s *statement) SetOptionDouble(key string, value float64) error {
 // For queries expected to be executed repeatedly, Prepare should be
 // called before execution.
 func (s *statement) SetSqlQuery(query string) error {
+	if err := s.State.SetQuery(); err != nil {
+		return err
+	}
 	if s.prepared != nil {
 		if err := s.closePreparedStatement(); err != nil {
 			return err
@@ -422,6 +554,7 @@ func (s *statement) SetSqlQuery(query string) error {
 		return err
 	}
 	s.query.setSqlQuery(query)
+	s.targetTable = ""
 	return nil
 }
 
@@ -431,11 +564,29 @@ func (s *statement) SetSqlQuery(query string) error {
 //
 // This invalidates any prior result sets on this statement.
 func (s *statement) ExecuteQuery(ctx context.Context) (rdr array.RecordReader, nrec int64, err error) {
+	done, err := s.State.BeginExecute("ExecuteQuery")
+	if err != nil {
+		return nil, -1, err
+	}
+	defer done()
+	if err := s.checkUnpreparedBind("ExecuteQuery"); err != nil {
+		return nil, -1, err
+	}
+
 	if err := s.clearIncrementalQuery(); err != nil {
 		return nil, -1, err
 	}
 
+	// the context is used by the reader, so it lives until the reader is
+	// released
+	ctx, cancel := s.WithCancel(ctx)
 	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
+	if s.targetTable != "" {
+		defer cancel()
+		nrec, err = s.executeIngest(ctx)
+		return nil, nrec, err
+	}
+
 	var info *flight.FlightInfo
 	var header, trailer metadata.MD
 	opts := append([]grpc.CallOption{}, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
@@ -446,44 +597,150 @@ func (s *statement) ExecuteQuery(ctx context.Context) (rdr array.RecordReader, n
 	}
 
 	if err != nil {
+		cancel()
 		return nil, -1, adbcFromFlightStatusWithDetails(err, header, trailer, "ExecuteQuery")
 	}
 
+	s.activeInfo.Store(info)
+	// another query may have been started since
+	finished := func() { s.activeInfo.CompareAndSwap(info, nil) }
 	nrec = info.TotalRecords
-	rdr, err = newRecordReader(ctx, s.alloc, s.cnxn.cl, info, s.clientCache, s.queueSize, s.timeouts)
-	return
+	rdr, err = newRecordReader(ctx, s.alloc, s.cnxn.cl, info, s.clientCache, s.queueSize, s.cnxn.RetryPolicy, s.timeouts)
+	if err != nil {
+		finished()
+		cancel()
+		return nil, -1, err
+	}
+	rdr = &activeQueryReader{RecordReader: rdr, finished: finished}
+	return driverbase.CancelOnRelease(s.State.TrackReader(rdr), func() {
+		finished()
+		cancel()
+	}), nrec, nil
+}
+
+// activeQueryReader reports when the reader of a query is exhausted, so
+// that Cancel stops trying to cancel the query.
+type activeQueryReader struct {
+	array.RecordReader
+	finished func()
+}
+
+func (r *activeQueryReader) Next() bool {
+	if r.RecordReader.Next() {
+		return true
+	}
+	r.finished()
+	return false
+}
+
+// SubmitQuery executes the query and returns the serialized FlightInfo
+// as the handle, without reading the results. Whether the tickets of the
+// FlightInfo can be read later, or from another connection, depends on
+// the server.
+func (s *statement) SubmitQuery(ctx context.Context) ([]byte, error) {
+	done, err := s.State.BeginExecute("SubmitQuery")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err := s.checkUnpreparedBind("SubmitQuery"); err != nil {
+		return nil, err
+	}
+
+	if err := s.clearIncrementalQuery(); err != nil {
+		return nil, err
+	}
+	if s.targetTable != "" {
+		return nil, adbc.Error{
+			Msg:  "[Flight SQL] cannot submit a query for bulk ingestion",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+
+	ctx, cancel := s.WithCancel(ctx)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
+	var (
+		info            *flight.FlightInfo
+		header, trailer metadata.MD
+	)
+	opts := append([]grpc.CallOption{}, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
+	if s.prepared != nil {
+		info, err = s.prepared.Execute(ctx, opts...)
+	} else {
+		info, err = s.query.execute(ctx, s.cnxn, opts...)
+	}
+	if err != nil {
+		return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "SubmitQuery")
+	}
+
+	handle, err := proto.Marshal(info)
+	if err != nil {
+		return nil, adbc.Error{
+			Msg:  err.Error(),
+			Code: adbc.StatusInternal,
+		}
+	}
+	return handle, nil
 }
 
 // ExecuteUpdate executes a statement that does not generate a result
 // set. It returns the number of rows affected if known, otherwise -1.
 func (s *statement) ExecuteUpdate(ctx context.Context) (n int64, err error) {
+	done, err := s.State.BeginExecute("ExecuteUpdate")
+	if err != nil {
+		return -1, err
+	}
+	defer done()
+	if err := s.checkUnpreparedBind("ExecuteUpdate"); err != nil {
+		return -1, err
+	}
+
 	if err := s.clearIncrementalQuery(); err != nil {
 		return -1, err
 	}
 
+	ctx, cancel := s.WithCancel(ctx)
+	defer cancel()
 	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
+	if s.targetTable != "" {
+		return s.executeIngest(ctx)
+	}
+
 	var header, trailer metadata.MD
 	opts := append([]grpc.CallOption{}, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
 	if s.prepared != nil {
 		n, err = s.prepared.ExecuteUpdate(ctx, opts...)
-	} else {
-		n, err = s.query.executeUpdate(ctx, s.cnxn, opts...)
-	}
-
-	if err != nil {
-		err = adbcFromFlightStatusWithDetails(err, header, trailer, "ExecuteQuery")
+		if err != nil {
+			err = adbcFromFlightStatusWithDetails(err, header, trailer, "ExecuteQuery")
+		}
+		return
 	}
 
-	return
+	// updates are only retried if the retry policy allows non-idempotent
+	// operations
+	return driverbase.RetryValue(ctx, s.cnxn.RetryPolicy, false, func(ctx context.Context) (int64, error) {
+		n, err := s.query.executeUpdate(ctx, s.cnxn, opts...)
+		if err != nil {
+			return n, adbcFromFlightStatusWithDetails(err, header, trailer, "ExecuteQuery")
+		}
+		return n, nil
+	})
 }
 
 // Prepare turns this statement into a prepared statement to be executed
 // multiple times. This invalidates any prior result sets.
 func (s *statement) Prepare(ctx context.Context) error {
+	if err := s.State.Prepare(); err != nil {
+		return err
+	}
+
 	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
 	var header, trailer metadata.MD
 	prep, err := s.query.prepare(ctx, s.cnxn, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
 	if err != nil {
+		// the query is still set, just not prepared
+		_ = s.State.SetQuery()
 		return adbcFromFlightStatusWithDetails(err, header, trailer, "Prepare")
 	}
 	s.prepared = prep
@@ -500,6 +757,9 @@ func (s *statement) Prepare(ctx context.Context) error {
 // using any of the Execute methods. If the query is expected to be
 // executed repeatedly, Prepare should be called first on the statement.
 func (s *statement) SetSubstraitPlan(plan []byte) error {
+	if err := s.State.SetQuery(); err != nil {
+		return err
+	}
 	if s.prepared != nil {
 		if err := s.closePreparedStatement(); err != nil {
 			return err
@@ -511,6 +771,7 @@ func (s *statement) SetSubstraitPlan(plan []byte) error {
 	}
 
 	s.query.setSubstraitPlan(plan)
+	s.targetTable = ""
 	return nil
 }
 
@@ -520,18 +781,22 @@ func (s *statement) SetSubstraitPlan(plan []byte) error {
 // The driver will call release on the passed in Record when it is done,
 // but it may not do this until the statement is closed or another
 // record is bound.
-func (s *statement) Bind(_ context.Context, values arrow.Record) error {
-	// TODO: handle bulk insert situation
+func (s *statement) Bind(ctx context.Context, values arrow.Record) error {
+	if s.prepared != nil && s.targetTable == "" {
+		// calls retain
+		s.prepared.SetParameters(values)
+		return nil
+	}
 
-	if s.prepared == nil {
+	rdr, err := array.NewRecordReader(values.Schema(), []arrow.Record{values})
+	if err != nil {
 		return adbc.Error{
-			Msg:  "[Flight SQL Statement] must call Prepare before calling Bind",
-			Code: adbc.StatusInvalidState}
+			Msg:  fmt.Sprintf("[Flight SQL Statement] could not bind record: %s", err.Error()),
+			Code: adbc.StatusInvalidArgument,
+		}
 	}
-
-	// calls retain
-	s.prepared.SetParameters(values)
-	return nil
+	defer rdr.Release()
+	return s.BindStream(ctx, rdr)
 }
 
 // BindStream uses a record batch stream to bind parameters for this
@@ -540,15 +805,84 @@ func (s *statement) Bind(_ context.Context, values arrow.Record) error {
 // The driver will call Release on the record reader, but may not do this
 // until Close is called.
 func (s *statement) BindStream(_ context.Context, stream array.RecordReader) error {
-	if s.prepared == nil {
-		return adbc.Error{
-			Msg:  "[Flight SQL Statement] must call Prepare before calling Bind",
-			Code: adbc.StatusInvalidState}
+	if s.prepared != nil && s.targetTable == "" {
+		// calls retain
+		s.prepared.SetRecordReader(stream)
+		return nil
 	}
 
-	// calls retain
-	s.prepared.SetRecordReader(stream)
-	return nil
+	// Without a prepared statement the data can only be meant for bulk
+	// ingestion, which may be configured after binding (as IngestStream
+	// does), so hold on to it until execution.
+	return s.State.BindStream(stream)
+}
+
+// checkUnpreparedBind returns an error if data was bound to a statement
+// that is neither prepared nor a bulk ingestion, which method would
+// otherwise run without parameters.
+func (s *statement) checkUnpreparedBind(method string) error {
+	if s.prepared != nil || s.targetTable != "" || !s.State.HasBound() {
+		return nil
+	}
+	return adbc.Error{
+		Msg:  fmt.Sprintf("[Flight SQL Statement] must call Prepare before %s with bound parameters", method),
+		Code: adbc.StatusInvalidState,
+	}
+}
+
+// ingestTableDefinitionOptions maps an ADBC ingest mode to the Flight SQL
+// behavior for when the target table does or does not exist.
+func ingestTableDefinitionOptions(mode string) *flightsql.TableDefinitionOptions {
+	switch mode {
+	case adbc.OptionValueIngestModeAppend:
+		return &flightsql.TableDefinitionOptions{
+			IfNotExist: flightsql.TableDefinitionOptionsTableNotExistOptionFail,
+			IfExists:   flightsql.TableDefinitionOptionsTableExistsOptionAppend,
+		}
+	case adbc.OptionValueIngestModeReplace:
+		return &flightsql.TableDefinitionOptions{
+			IfNotExist: flightsql.TableDefinitionOptionsTableNotExistOptionCreate,
+			IfExists:   flightsql.TableDefinitionOptionsTableExistsOptionReplace,
+		}
+	case adbc.OptionValueIngestModeCreateAppend:
+		return &flightsql.TableDefinitionOptions{
+			IfNotExist: flightsql.TableDefinitionOptionsTableNotExistOptionCreate,
+			IfExists:   flightsql.TableDefinitionOptionsTableExistsOptionAppend,
+		}
+	default:
+		return &flightsql.TableDefinitionOptions{
+			IfNotExist: flightsql.TableDefinitionOptionsTableNotExistOptionCreate,
+			IfExists:   flightsql.TableDefinitionOptionsTableExistsOptionFail,
+		}
+	}
+}
+
+// executeIngest streams the bound data to the server with DoPut and a
+// CommandStatementIngest, returning the number of rows ingested if known.
+func (s *statement) executeIngest(ctx context.Context) (int64, error) {
+	_, stream := s.State.TakeBound()
+	if stream == nil {
+		return -1, adbc.Error{
+			Msg:  "[Flight SQL Statement] must call Bind before bulk ingestion",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	defer stream.Release()
+
+	req := &flightsql.ExecuteIngestOpts{
+		TableDefinitionOptions: ingestTableDefinitionOptions(s.ingestMode),
+		Table:                  s.targetTable,
+		Catalog:                s.ingestCatalog,
+		Schema:                 s.ingestDBSchema,
+		Temporary:              s.ingestTemporary,
+	}
+
+	var header, trailer metadata.MD
+	n, err := s.cnxn.executeIngest(ctx, stream, req, grpc.Header(&header), grpc.Trailer(&trailer), s.timeouts)
+	if err != nil {
+		return -1, adbcFromTableStatus(err, header, trailer, "ExecuteIngest")
+	}
+	return n, nil
 }
 
 // GetParameterSchema returns an Arrow schema representation of
@@ -594,6 +928,17 @@ func (s *statement) GetParameterSchema() (*arrow.Schema, error) {
 // If the driver does not support partitioned results, this will return
 // an error with a StatusNotImplemented code.
 func (s *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	done, err := s.State.BeginExecute("ExecutePartitions")
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer done()
+	if err := s.checkUnpreparedBind("ExecutePartitions"); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+
+	ctx, cancel := s.WithCancel(ctx)
+	defer cancel()
 	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
 
 	var (
@@ -601,7 +946,6 @@ func (s *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.
 		poll *flight.PollInfo
 		out  adbc.Partitions
 		sc   *arrow.Schema
-		err  error
 	)
 
 	var header, trailer metadata.MD
@@ -715,6 +1059,15 @@ func (s *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.
 
 // ExecuteSchema gets the schema of the result set of a query without executing it.
 func (s *statement) ExecuteSchema(ctx context.Context) (schema *arrow.Schema, err error) {
+	done, err := s.State.BeginExecute("ExecuteSchema")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if err := s.checkUnpreparedBind("ExecuteSchema"); err != nil {
+		return nil, err
+	}
+
 	ctx = metadata.NewOutgoingContext(ctx, s.hdrs)
 
 	if s.prepared != nil {
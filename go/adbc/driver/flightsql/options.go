@@ -0,0 +1,119 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package flightsql
+
+import (
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+// optionNamespace is the prefix of the driver-specific options. Keys with
+// this prefix that aren't listed in optionSpecs are rejected.
+const optionNamespace = "adbc.flight.sql."
+
+const (
+	levelDatabase  = driverbase.OptionLevelDatabase
+	levelConn      = driverbase.OptionLevelConnection
+	levelStatement = driverbase.OptionLevelStatement
+)
+
+var optionSpecs = []driverbase.OptionSpec{
+	{Name: adbc.OptionKeyURI, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Description: "Location of the Flight SQL service, e.g. grpc+tls://host:port. Required."},
+	{Name: adbc.OptionKeyUsername, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyPassword, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: adbc.OptionKeyTelemetryTraceParent, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "W3C trace parent to attach to OpenTelemetry traces."},
+	{Name: adbc.OptionKeyAutoCommit, Levels: levelConn, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueEnabled)},
+	{Name: adbc.OptionKeyCurrentCatalog, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyCurrentDbSchema, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIncremental, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: adbc.OptionKeyIngestTargetTable, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestMode, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(adbc.OptionValueIngestModeCreate),
+		AllowedValues: []string{
+			adbc.OptionValueIngestModeCreate,
+			adbc.OptionValueIngestModeAppend,
+			adbc.OptionValueIngestModeReplace,
+			adbc.OptionValueIngestModeCreateAppend,
+		}},
+	{Name: adbc.OptionValueIngestTargetCatalog, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTargetDBSchema, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTemporary, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: OptionStatementQueueSize, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default: driverbase.OptionDefault("5"), Validate: validateQueueSize,
+		Description: "Number of record batches to buffer per endpoint."},
+
+	{Name: OptionAuthority, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionMTLSCertChain, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Description: "PEM-encoded client certificate chain for mutual TLS."},
+	{Name: OptionMTLSPrivateKey, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "PEM-encoded client private key for mutual TLS."},
+	{Name: OptionSSLOverrideHostname, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionSSLSkipVerify, Levels: levelDatabase, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: OptionSSLRootCerts, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Description: "PEM-encoded root certificates to trust."},
+	{Name: OptionWithBlock, Levels: levelDatabase, Type: driverbase.OptionTypeBool,
+		Description: "Deprecated and ignored."},
+	{Name: OptionWithMaxMsgSize, Levels: levelDatabase, Type: driverbase.OptionTypeInt,
+		Default:     driverbase.OptionDefault("16777216"),
+		Description: "Maximum size in bytes of a gRPC message received."},
+	{Name: OptionAuthorizationHeader, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "Value of the authorization header. Cannot be combined with username and password."},
+	{Name: OptionTimeoutConnect, Levels: levelDatabase, Type: driverbase.OptionTypeDouble,
+		Default: driverbase.OptionDefault("20"), Validate: validateTimeout,
+		Description: "Connection timeout in seconds."},
+	{Name: OptionTimeoutFetch, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeDouble,
+		Validate: validateTimeout, Description: "Timeout in seconds for fetching results."},
+	{Name: OptionTimeoutQuery, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeDouble,
+		Validate: validateTimeout, Description: "Timeout in seconds for executing queries."},
+	{Name: OptionTimeoutUpdate, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeDouble,
+		Validate: validateTimeout, Description: "Timeout in seconds for executing updates."},
+	{Name: OptionRPCCallHeaderPrefix, Prefix: true, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeString,
+		Description: "Header to send with every RPC call, named by the rest of the key."},
+	{Name: OptionCookieMiddleware, Levels: levelDatabase, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: OptionSessionOptionPrefix, Prefix: true, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "Server session option, named by the rest of the key."},
+	{Name: OptionBoolSessionOptionPrefix, Prefix: true, Levels: levelConn, Type: driverbase.OptionTypeBool,
+		Description: "Boolean server session option, named by the rest of the key."},
+	{Name: OptionStringListSessionOptionPrefix, Prefix: true, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "String list server session option as a JSON array, named by the rest of the key."},
+	{Name: OptionEraseSessionOptionPrefix, Prefix: true, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "Erases the server session option named by the rest of the key."},
+	{Name: OptionStatementSubstraitVersion, Levels: levelStatement, Type: driverbase.OptionTypeString},
+
+	{Name: OptionKeyOauthFlow, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		AllowedValues: []string{ClientCredentials, TokenExchange}},
+	{Name: OptionKeyTokenURI, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyScope, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyClientId, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyClientSecret, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: OptionKeySubjectToken, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: OptionKeySubjectTokenType, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyActorToken, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: OptionKeyActorTokenType, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyReqTokenType, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyExchangeScope, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyExchangeAud, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeyExchangeResource, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+}
@@ -23,6 +23,7 @@ import (
 	"sync/atomic"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-adbc/go/adbc/utils"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
@@ -48,7 +49,9 @@ type reader struct {
 
 // kicks off a goroutine for each endpoint and returns a reader which
 // gathers all of the records as they come in.
-func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.Client, info *flight.FlightInfo, clCache gcache.Cache, bufferSize int, opts ...grpc.CallOption) (rdr array.RecordReader, err error) {
+// Opening the stream of an endpoint is retried according to retry; once
+// records have been read from it, errors are not retried.
+func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.Client, info *flight.FlightInfo, clCache gcache.Cache, bufferSize int, retry driverbase.RetryPolicy, opts ...grpc.CallOption) (rdr array.RecordReader, err error) {
 	endpoints := info.Endpoint
 	var header, trailer metadata.MD
 	opts = append(append([]grpc.CallOption{}, opts...), grpc.Header(&header), grpc.Trailer(&trailer))
@@ -92,9 +95,15 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.
 		}
 	} else {
 		firstEndpoint := endpoints[0]
-		rdr, err := doGet(ctx, cl, firstEndpoint, clCache, opts...)
+		rdr, err := driverbase.RetryValue(ctx, retry, true, func(ctx context.Context) (*flight.Reader, error) {
+			rdr, err := doGet(ctx, cl, firstEndpoint, clCache, opts...)
+			if err != nil {
+				return nil, withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint 0: remote: %s", firstEndpoint.Location), firstEndpoint)
+			}
+			return rdr, nil
+		})
 		if err != nil {
-			return nil, adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint 0: remote: %s", firstEndpoint.Location)
+			return nil, err
 		}
 		schema = rdr.Schema()
 		group.Go(func() error {
@@ -109,7 +118,7 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.
 				ch <- rec
 			}
 			if err := checkContext(rdr.Err(), ctx); err != nil {
-				return adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint 0: remote: %s", firstEndpoint.Location)
+				return withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint 0: remote: %s", firstEndpoint.Location), firstEndpoint)
 			}
 			return nil
 		})
@@ -140,9 +149,15 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.
 				defer close(chs[endpointIndex])
 			}
 
-			rdr, err := doGet(ctx, cl, endpoint, clCache, opts...)
+			rdr, err := driverbase.RetryValue(ctx, retry, true, func(ctx context.Context) (*flight.Reader, error) {
+				rdr, err := doGet(ctx, cl, endpoint, clCache, opts...)
+				if err != nil {
+					return nil, withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint %d: %s", endpointIndex, endpoint.Location), endpoint)
+				}
+				return rdr, nil
+			})
 			if err != nil {
-				return adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint %d: %s", endpointIndex, endpoint.Location)
+				return err
 			}
 			defer rdr.Release()
 
@@ -158,7 +173,7 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, cl *flightsql.
 			}
 
 			if err := checkContext(rdr.Err(), ctx); err != nil {
-				return adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint %d: %s", endpointIndex, endpoint.Location)
+				return withTicket(adbcFromFlightStatusWithDetails(err, header, trailer, "DoGet: endpoint %d: %s", endpointIndex, endpoint.Location), endpoint)
 			}
 			return nil
 		})
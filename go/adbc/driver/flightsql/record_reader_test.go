@@ -25,6 +25,7 @@ import (
 	"testing"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/flight"
@@ -160,7 +161,7 @@ func (suite *RecordReaderTests) TestFallbackFailedConnection() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -190,7 +191,7 @@ func (suite *RecordReaderTests) TestFallbackFailedDoGet() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -204,7 +205,7 @@ func (suite *RecordReaderTests) TestFallbackFailedDoGet() {
 
 	// Not enough retries
 	suite.service.failureCount = 4
-	reader, err = newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err = newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 	suite.False(reader.Next())
@@ -223,7 +224,7 @@ func (suite *RecordReaderTests) TestFallbackFailed() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -236,7 +237,7 @@ func (suite *RecordReaderTests) TestNoEndpoints() {
 		Schema: flight.SerializeSchema(orderingSchema(), suite.alloc),
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -248,7 +249,7 @@ func (suite *RecordReaderTests) TestNoEndpoints() {
 func (suite *RecordReaderTests) TestNoEndpointsNoSchema() {
 	info := flight.FlightInfo{}
 
-	_, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	_, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.ErrorContains(err, "Server returned FlightInfo with no schema and no endpoints, cannot read stream")
 }
 
@@ -257,7 +258,7 @@ func (suite *RecordReaderTests) TestNoEndpointsInvalidSchema() {
 		Schema: []byte("f"),
 	}
 
-	_, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	_, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.ErrorContains(err, "Server returned FlightInfo with invalid schema and no endpoints, cannot read stream")
 }
 
@@ -272,7 +273,7 @@ func (suite *RecordReaderTests) TestNoSchema() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -305,7 +306,7 @@ func (suite *RecordReaderTests) TestSchemaEndpointMismatch() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -339,7 +340,7 @@ func (suite *RecordReaderTests) TestOrdering() {
 		},
 	}
 
-	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3)
+	reader, err := newRecordReader(context.Background(), suite.alloc, suite.cl, &info, suite.clCache, 3, driverbase.DefaultRetryPolicy())
 	suite.NoError(err)
 	defer reader.Release()
 
@@ -233,3 +233,9 @@ func streamTimeoutInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *gr
 
 	return streamer(ctx, desc, cc, method, opts...)
 }
+
+// validateTimeout is the OptionSpec.Validate of the timeout options.
+func validateTimeout(key, value string) error {
+	var t timeoutOption
+	return t.setTimeoutString(key, value)
+}
@@ -25,6 +25,8 @@ import (
 	"strings"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/flight"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -133,15 +135,42 @@ func adbcFromFlightStatusWithDetails(err error, header, trailer metadata.MD, con
 		}
 	}
 
+	var sqlState [5]byte
+	if adbcCode != adbc.StatusNotFound {
+		// NotFound may be about any object, not just a table, so it gets
+		// no SQLSTATE unless the request was about a table (see
+		// adbcFromTableStatus)
+		sqlState = driverbase.SQLStateForStatus(adbcCode)
+	}
+
 	return adbc.Error{
 		// People don't read error messages, so backload the context and frontload the server error
 		Msg:        fmt.Sprintf("[FlightSQL] %s (%s; %s)", grpcStatus.Message(), grpcStatus.Code(), fmt.Sprintf(context, args...)),
 		Code:       adbcCode,
 		VendorCode: int32(grpcStatus.Code()),
+		SqlState:   sqlState,
 		Details:    details,
 	}
 }
 
+// adbcFromTableStatus converts the error of a request about a single
+// table, such that NotFound means the table does not exist.
+func adbcFromTableStatus(err error, header, trailer metadata.MD, context string, args ...any) error {
+	err = adbcFromFlightStatusWithDetails(err, header, trailer, context, args...)
+	var adbcErr adbc.Error
+	if errors.As(err, &adbcErr) && adbcErr.Code == adbc.StatusNotFound && adbcErr.SqlState == [5]byte{} {
+		adbcErr.SqlState = driverbase.SQLStateForStatus(adbc.StatusNotFound)
+		return adbcErr
+	}
+	return err
+}
+
+// withTicket attaches the ticket of endpoint to err, so that the stream
+// that could not be read can be identified.
+func withTicket(err error, endpoint *flight.FlightEndpoint) error {
+	return driverbase.WithErrorDetails(err, &adbc.FlightTicketErrorDetail{Ticket: endpoint.GetTicket().GetTicket()})
+}
+
 func checkContext(maybeErr error, ctx context.Context) error {
 	if maybeErr != nil && !errors.Is(maybeErr, io.EOF) {
 		return maybeErr
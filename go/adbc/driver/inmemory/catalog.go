@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// tables maps the names of tables to their data. Tables are immutable:
+// changes replace the record of a table with a new one, so readers of the
+// previous record are not affected.
+type tables map[string]arrow.Record
+
+// clone returns a copy of t holding its own references to the records.
+func (t tables) clone() tables {
+	clone := maps.Clone(t)
+	for _, rec := range clone {
+		rec.Retain()
+	}
+	return clone
+}
+
+func (t tables) release() {
+	for name, rec := range t {
+		rec.Release()
+		delete(t, name)
+	}
+}
+
+// catalog holds the tables of a database, shared by its connections.
+type catalog struct {
+	name string
+	// refs counts the databases and connections using the catalog. It is
+	// guarded by the mutex of the driver.
+	refs int
+
+	mu     sync.Mutex
+	tables tables
+}
+
+func newCatalog(name string) *catalog {
+	return &catalog{name: name, refs: 1, tables: make(tables)}
+}
+
+// snapshot returns the current tables.
+func (c *catalog) snapshot() tables {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tables.clone()
+}
+
+func (c *catalog) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tables.release()
+}
+
+// ident is an identifier in a query or an option.
+type ident struct {
+	name string
+	// quoted identifiers match names exactly, others ignore case
+	quoted bool
+}
+
+func (id ident) matches(name string) bool {
+	if id.quoted {
+		return id.name == name
+	}
+	return strings.EqualFold(id.name, name)
+}
+
+// lookup finds the table named by id. An exact match is preferred over
+// one ignoring case, which must be unique.
+func (id ident) lookup(t tables) (string, bool) {
+	if _, ok := t[id.name]; ok {
+		return id.name, true
+	}
+	if id.quoted {
+		return "", false
+	}
+	found, n := "", 0
+	for name := range t {
+		if id.matches(name) {
+			found = name
+			n++
+		}
+	}
+	return found, n == 1
+}
+
+// tableRef is the possibly qualified name of a table.
+type tableRef struct {
+	catalog  *ident
+	dbSchema *ident
+	table    ident
+}
+
+// check returns an error unless the qualifiers of ref name the only
+// catalog and schema.
+func (ref tableRef) check() error {
+	if ref.catalog != nil && !ref.catalog.matches(DefaultCatalog) {
+		return errorf(adbc.StatusNotFound, "3D000", "catalog %q not found", ref.catalog.name)
+	}
+	if ref.dbSchema != nil && !ref.dbSchema.matches(DefaultDBSchema) {
+		return errorf(adbc.StatusNotFound, "3F000", "schema %q not found", ref.dbSchema.name)
+	}
+	return nil
+}
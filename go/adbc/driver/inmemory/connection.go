@@ -0,0 +1,403 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+const tableTypeTable = "TABLE"
+
+type connectionImpl struct {
+	driverbase.ConnectionImplBase
+	driver  *driverImpl
+	catalog *catalog
+
+	mu sync.Mutex
+	// tx is the transaction of the connection while autocommit is
+	// disabled
+	tx *transaction
+}
+
+// transaction is a snapshot of the tables of the catalog, which the
+// connection reads and writes until it is committed or rolled back.
+type transaction struct {
+	tables tables
+	// changed are the names of the tables written in the transaction,
+	// which are copied to the catalog on commit
+	changed map[string]struct{}
+}
+
+func (c *connectionImpl) begin() {
+	c.tx = &transaction{tables: c.catalog.snapshot(), changed: make(map[string]struct{})}
+}
+
+// readTables returns the tables the connection sees, which the caller
+// must release.
+func (c *connectionImpl) readTables() tables {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tx != nil {
+		return c.tx.tables.clone()
+	}
+	return c.catalog.snapshot()
+}
+
+// writeTable replaces the table with the given name by the result of fn,
+// which is called with the current table, or nil if there is none. fn
+// returns a table owned by the caller, or nil to drop the table.
+func (c *connectionImpl) writeTable(name ident, fn func(old arrow.Record) (arrow.Record, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := c.catalog.tables
+	if c.tx != nil {
+		t = c.tx.tables
+	} else {
+		c.catalog.mu.Lock()
+		defer c.catalog.mu.Unlock()
+	}
+
+	key, ok := name.lookup(t)
+	if !ok {
+		key = name.name
+	}
+	old := t[key]
+	rec, err := fn(old)
+	if err != nil {
+		return err
+	}
+
+	if old != nil {
+		old.Release()
+	}
+	if rec == nil {
+		delete(t, key)
+	} else {
+		t[key] = rec
+	}
+	if c.tx != nil {
+		c.tx.changed[key] = struct{}{}
+	}
+	return nil
+}
+
+// SetAutocommit implements driverbase.AutocommitSetter.
+func (c *connectionImpl) SetAutocommit(enabled bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case enabled && c.tx != nil:
+		c.commit()
+		c.tx.tables.release()
+		c.tx = nil
+	case !enabled && c.tx == nil:
+		c.begin()
+	}
+	return nil
+}
+
+// commit copies the tables changed in the transaction to the catalog.
+// Concurrent transactions writing the same table overwrite each other.
+func (c *connectionImpl) commit() {
+	c.catalog.mu.Lock()
+	defer c.catalog.mu.Unlock()
+	for name := range c.tx.changed {
+		if old, ok := c.catalog.tables[name]; ok {
+			old.Release()
+			delete(c.catalog.tables, name)
+		}
+		if rec, ok := c.tx.tables[name]; ok {
+			rec.Retain()
+			c.catalog.tables[name] = rec
+		}
+	}
+}
+
+func (c *connectionImpl) Commit(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commit()
+	c.tx.tables.release()
+	c.begin()
+	return nil
+}
+
+func (c *connectionImpl) Rollback(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tx.tables.release()
+	c.begin()
+	return nil
+}
+
+func (c *connectionImpl) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tx != nil {
+		c.tx.tables.release()
+		c.tx = nil
+	}
+	c.driver.releaseCatalog(c.catalog)
+	return nil
+}
+
+// GetCurrentCatalog implements driverbase.CurrentNamespacer.
+func (c *connectionImpl) GetCurrentCatalog() (string, error) {
+	return DefaultCatalog, nil
+}
+
+// GetCurrentDbSchema implements driverbase.CurrentNamespacer.
+func (c *connectionImpl) GetCurrentDbSchema() (string, error) {
+	return DefaultDBSchema, nil
+}
+
+// SetCurrentCatalog implements driverbase.CurrentNamespacer.
+func (c *connectionImpl) SetCurrentCatalog(value string) error {
+	if value != DefaultCatalog {
+		return c.ErrorHelper.Errorf(adbc.StatusNotFound, "catalog %q not found: the only catalog is %q", value, DefaultCatalog)
+	}
+	return nil
+}
+
+// SetCurrentDbSchema implements driverbase.CurrentNamespacer.
+func (c *connectionImpl) SetCurrentDbSchema(value string) error {
+	if value != DefaultDBSchema {
+		return c.ErrorHelper.Errorf(adbc.StatusNotFound, "schema %q not found: the only schema is %q", value, DefaultDBSchema)
+	}
+	return nil
+}
+
+func filterName(name string, filter *string) ([]string, error) {
+	pattern, err := internal.PatternToRegexp(filter)
+	if err != nil {
+		return nil, err
+	}
+	if pattern != nil && !pattern.MatchString(name) {
+		return []string{}, nil
+	}
+	return []string{name}, nil
+}
+
+// GetCatalogs implements driverbase.DbObjectsEnumerator.
+func (c *connectionImpl) GetCatalogs(ctx context.Context, catalogFilter *string) ([]string, error) {
+	return filterName(DefaultCatalog, catalogFilter)
+}
+
+// GetDBSchemasForCatalog implements driverbase.DbObjectsEnumerator.
+func (c *connectionImpl) GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) ([]string, error) {
+	if catalog != DefaultCatalog {
+		return []string{}, nil
+	}
+	return filterName(DefaultDBSchema, schemaFilter)
+}
+
+// GetTablesForDBSchema implements driverbase.DbObjectsEnumerator.
+func (c *connectionImpl) GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]driverbase.TableInfo, error) {
+	if catalog != DefaultCatalog || schema != DefaultDBSchema {
+		return []driverbase.TableInfo{}, nil
+	}
+
+	tablePattern, err := internal.PatternToRegexp(tableFilter)
+	if err != nil {
+		return nil, err
+	}
+	if tablePattern == nil {
+		tablePattern = internal.AcceptAll
+	}
+	columnPattern, err := internal.PatternToRegexp(columnFilter)
+	if err != nil {
+		return nil, err
+	}
+	if columnPattern == nil {
+		columnPattern = internal.AcceptAll
+	}
+
+	t := c.readTables()
+	defer t.release()
+
+	result := make([]driverbase.TableInfo, 0, len(t))
+	for _, name := range slices.Sorted(maps.Keys(t)) {
+		if !tablePattern.MatchString(name) {
+			continue
+		}
+
+		info := driverbase.TableInfo{TableName: name, TableType: tableTypeTable}
+		if includeColumns {
+			info.TableColumns = make([]driverbase.ColumnInfo, 0)
+			for pos, field := range t[name].Schema().Fields() {
+				if !columnPattern.MatchString(field.Name) {
+					continue
+				}
+
+				xdbcIsNullable := "YES"
+				xdbcNullable := int16(1)
+				if !field.Nullable {
+					xdbcIsNullable = "NO"
+					xdbcNullable = 0
+				}
+				info.TableColumns = append(info.TableColumns, driverbase.ColumnInfo{
+					ColumnName:      field.Name,
+					OrdinalPosition: driverbase.Nullable(int32(pos + 1)),
+					XdbcDataType:    driverbase.Nullable(int16(field.Type.ID())),
+					XdbcTypeName:    driverbase.Nullable(field.Type.String()),
+					XdbcNullable:    driverbase.Nullable(xdbcNullable),
+					XdbcSqlDataType: driverbase.Nullable(int16(internal.ToXdbcDataType(field.Type))),
+					XdbcIsNullable:  driverbase.Nullable(xdbcIsNullable),
+				})
+			}
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+// ListTableTypes implements driverbase.TableTypeLister.
+func (c *connectionImpl) ListTableTypes(ctx context.Context) ([]string, error) {
+	return []string{tableTypeTable}, nil
+}
+
+func (c *connectionImpl) GetTableSchema(ctx context.Context, catalog *string, dbSchema *string, tableName string) (*arrow.Schema, error) {
+	ref := tableRef{table: ident{name: tableName}}
+	if catalog != nil {
+		ref.catalog = &ident{name: *catalog}
+	}
+	if dbSchema != nil {
+		ref.dbSchema = &ident{name: *dbSchema}
+	}
+	if err := ref.check(); err != nil {
+		return nil, toADBCError(&c.ErrorHelper, err)
+	}
+
+	t := c.readTables()
+	defer t.release()
+	name, ok := ref.table.lookup(t)
+	if !ok {
+		return nil, toADBCError(&c.ErrorHelper, tableNotFound(tableName))
+	}
+	return t[name].Schema(), nil
+}
+
+// EnumerateStatistics implements driverbase.StatisticsEnumerator.
+//
+// Statistics are computed from the data, so they are always exact: the
+// number of rows of each table, and the number of nulls and the extreme
+// values of each column of numbers or strings.
+func (c *connectionImpl) EnumerateStatistics(ctx context.Context, catalogFilter, dbSchemaFilter, tableFilter *string, approximate bool) ([]driverbase.StatisticsEntry, error) {
+	entries := make([]driverbase.StatisticsEntry, 0)
+	catalogs, err := c.GetCatalogs(ctx, catalogFilter)
+	if err != nil || len(catalogs) == 0 {
+		return entries, err
+	}
+	schemas, err := c.GetDBSchemasForCatalog(ctx, DefaultCatalog, dbSchemaFilter)
+	if err != nil || len(schemas) == 0 {
+		return entries, err
+	}
+	tablePattern, err := internal.PatternToRegexp(tableFilter)
+	if err != nil {
+		return nil, err
+	}
+	if tablePattern == nil {
+		tablePattern = internal.AcceptAll
+	}
+
+	t := c.readTables()
+	defer t.release()
+	for name, rec := range t {
+		if !tablePattern.MatchString(name) {
+			continue
+		}
+		entry := func(column *string, stat string, value any) driverbase.StatisticsEntry {
+			return driverbase.StatisticsEntry{
+				CatalogName:  DefaultCatalog,
+				DbSchemaName: DefaultDBSchema,
+				TableName:    name,
+				ColumnName:   column,
+				Name:         stat,
+				Value:        value,
+			}
+		}
+
+		entries = append(entries, entry(nil, adbc.StatisticRowCountName, rec.NumRows()))
+		for i, field := range rec.Schema().Fields() {
+			column := &field.Name
+			col := rec.Column(i)
+			entries = append(entries, entry(column, adbc.StatisticNullCountName, int64(col.NullN())))
+
+			switch field.Type.ID() {
+			case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
+			default:
+				if class := classOf(field.Type); class != classNumber && class != classBinary {
+					continue
+				}
+			}
+			var minValue, maxValue any
+			for row := range col.Len() {
+				v := valueAt(col, row)
+				if v == nil {
+					continue
+				}
+				if minValue == nil || compareValues(v, minValue) < 0 {
+					minValue = v
+				}
+				if maxValue == nil || compareValues(v, maxValue) > 0 {
+					maxValue = v
+				}
+			}
+			if minValue != nil {
+				entries = append(entries,
+					entry(column, adbc.StatisticMinValueName, minValue),
+					entry(column, adbc.StatisticMaxValueName, maxValue))
+			}
+		}
+	}
+	return entries, nil
+}
+
+// StatisticNames implements driverbase.StatisticsEnumerator.
+func (c *connectionImpl) StatisticNames() []driverbase.StatisticName {
+	return nil
+}
+
+func (c *connectionImpl) NewStatement() (adbc.Statement, error) {
+	return driverbase.NewStatement(&statementImpl{
+		StatementImplBase: driverbase.NewStatementImplBase(&c.ConnectionImplBase, c.ErrorHelper),
+		cnxn:              c,
+		ingestMode:        adbc.OptionValueIngestModeCreate,
+		batchSize:         defaultBatchSize,
+	}), nil
+}
+
+func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
+	rdr, err := ipc.NewReader(bytes.NewReader(serializedPartition), ipc.WithAllocator(c.Alloc))
+	if err != nil {
+		return nil, c.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid partition: %s", err)
+	}
+	return rdr, nil
+}
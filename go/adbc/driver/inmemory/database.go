@@ -0,0 +1,88 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+type databaseImpl struct {
+	driverbase.DatabaseImplBase
+	driver *driverImpl
+	name   string
+
+	mu sync.Mutex
+	// catalog is acquired along with the first connection
+	catalog *catalog
+}
+
+func (d *databaseImpl) GetOption(key string) (string, error) {
+	if key == OptionStringDatabaseName {
+		return d.name, nil
+	}
+	return d.DatabaseImplBase.GetOption(key)
+}
+
+func (d *databaseImpl) SetOption(key, val string) error {
+	if key != OptionStringDatabaseName {
+		return d.DatabaseImplBase.SetOption(key, val)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.catalog != nil {
+		return d.ErrorHelper.Errorf(adbc.StatusInvalidState, "cannot change the database name after opening a connection")
+	}
+	d.name = val
+	return nil
+}
+
+func (d *databaseImpl) Open(ctx context.Context) (adbc.Connection, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.catalog == nil {
+		d.catalog = d.driver.acquireCatalog(d.name)
+	}
+
+	cnxn := &connectionImpl{
+		ConnectionImplBase: driverbase.NewConnectionImplBase(&d.DatabaseImplBase),
+		driver:             d.driver,
+		catalog:            d.driver.retainCatalog(d.catalog),
+	}
+	return driverbase.NewConnectionBuilder(cnxn).
+		WithAutocommitSetter(cnxn).
+		WithCurrentNamespacer(cnxn).
+		WithDbObjectsEnumerator(cnxn).
+		WithTableTypeLister(cnxn).
+		WithStatisticsEnumerator(cnxn).
+		Connection(), nil
+}
+
+func (d *databaseImpl) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.catalog != nil {
+		d.driver.releaseCatalog(d.catalog)
+		d.catalog = nil
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package inmemory implements an ADBC driver that keeps its tables as
+// Arrow records in memory. It needs no server and no cgo, which makes it
+// suitable for hermetic tests of ADBC applications, and it is the
+// reference example of a driver built on driverbase.
+//
+// Tables are created by bulk ingestion and queried with a small SQL
+// dialect:
+//
+//	SELECT <* | expr [AS alias], ...> [FROM table] [WHERE expr]
+//	    [ORDER BY expr [ASC | DESC] [NULLS FIRST | LAST], ...] [LIMIT n]
+//	INSERT INTO table [(column, ...)] VALUES (expr, ...), ...
+//	DELETE FROM table [WHERE expr]
+//	DROP TABLE [IF EXISTS] table
+//
+// Expressions support literals, columns, ? parameters, arithmetic, ||,
+// comparisons, AND, OR, NOT, IS [NOT] NULL, [NOT] LIKE and [NOT] IN.
+// Identifiers may be quoted with double quotes; unquoted identifiers are
+// resolved case-insensitively. There is a single catalog, DefaultCatalog,
+// holding a single schema, DefaultDBSchema.
+//
+// Databases opened with the same OptionStringDatabaseName from the same
+// Driver share their tables, as long as one of them or one of their
+// connections is open. Databases without a name have tables of their own.
+package inmemory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+const (
+	// OptionStringDatabaseName names the tables of a database, so that
+	// other databases of the driver can open them.
+	OptionStringDatabaseName = "adbc.inmemory.database_name"
+	// OptionIntBatchSize is the maximum number of rows per record of the
+	// results of a statement, and per partition of ExecutePartitions.
+	OptionIntBatchSize = "adbc.inmemory.statement.batch_size"
+
+	// DefaultCatalog is the name of the only catalog.
+	DefaultCatalog = "memory"
+	// DefaultDBSchema is the name of the only schema.
+	DefaultDBSchema = "main"
+
+	defaultBatchSize = 65536
+)
+
+type driverImpl struct {
+	driverbase.DriverImplBase
+
+	mu sync.Mutex
+	// catalogs are the named catalogs in use
+	catalogs map[string]*catalog
+}
+
+// NewDriver creates a new in-memory driver using the given Arrow
+// allocator.
+func NewDriver(alloc memory.Allocator) adbc.Driver {
+	info := driverbase.DefaultDriverInfo("In-Memory")
+	info.RegisterOptionNamespace(optionNamespace)
+	if err := info.RegisterOptions(optionSpecs...); err != nil {
+		panic(err)
+	}
+	base := driverbase.NewDriverImplBase(info, alloc)
+	// the driver is its own vendor
+	for vendorCode, driverCode := range map[adbc.InfoCode]adbc.InfoCode{
+		adbc.InfoVendorVersion:      adbc.InfoDriverVersion,
+		adbc.InfoVendorArrowVersion: adbc.InfoDriverArrowVersion,
+	} {
+		if version, ok := info.GetInfoForInfoCode(driverCode); ok {
+			if err := info.RegisterInfoCode(vendorCode, version); err != nil {
+				panic(err)
+			}
+		}
+	}
+	return driverbase.NewDriver(&driverImpl{
+		DriverImplBase: base,
+		catalogs:       make(map[string]*catalog),
+	})
+}
+
+func (d *driverImpl) NewDatabase(opts map[string]string) (adbc.Database, error) {
+	return d.NewDatabaseWithContext(context.Background(), opts)
+}
+
+func (d *driverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string]string) (adbc.Database, error) {
+	opts = maps.Clone(opts)
+	dbBase, err := driverbase.NewDatabaseImplBase(ctx, &d.DriverImplBase)
+	if err != nil {
+		return nil, err
+	}
+	db := &databaseImpl{DatabaseImplBase: dbBase, driver: d}
+	if err := db.ValidateOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := db.SetRetryOptions(opts); err != nil {
+		return nil, err
+	}
+	for key, val := range opts {
+		if err := db.SetOption(key, val); err != nil {
+			return nil, err
+		}
+	}
+	return driverbase.NewDatabase(db), nil
+}
+
+// acquireCatalog returns the catalog with the given name, or a new one if
+// name is empty, with a reference for the caller.
+func (d *driverImpl) acquireCatalog(name string) *catalog {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if name == "" {
+		return newCatalog(name)
+	}
+	cat, ok := d.catalogs[name]
+	if !ok {
+		cat = newCatalog(name)
+		d.catalogs[name] = cat
+		return cat
+	}
+	cat.refs++
+	return cat
+}
+
+// retainCatalog adds a reference to cat.
+func (d *driverImpl) retainCatalog(cat *catalog) *catalog {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cat.refs++
+	return cat
+}
+
+// releaseCatalog drops a reference to cat, and its tables along with the
+// last one.
+func (d *driverImpl) releaseCatalog(cat *catalog) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cat.refs--
+	if cat.refs > 0 {
+		return
+	}
+	if cat.name != "" {
+		delete(d.catalogs, cat.name)
+	}
+	cat.close()
+}
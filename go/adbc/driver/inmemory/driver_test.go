@@ -0,0 +1,391 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory_test
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/inmemory"
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/suite"
+)
+
+const databaseName = "validation"
+
+type InMemoryQuirks struct {
+	mem    *memory.CheckedAllocator
+	driver adbc.Driver
+}
+
+func (q *InMemoryQuirks) SetupDriver(t *testing.T) adbc.Driver {
+	q.driver = inmemory.NewDriver(q.mem)
+	return q.driver
+}
+
+func (q *InMemoryQuirks) TearDownDriver(*testing.T, adbc.Driver) {
+	q.driver = nil
+}
+
+func (q *InMemoryQuirks) DatabaseOptions() map[string]string {
+	return map[string]string{inmemory.OptionStringDatabaseName: databaseName}
+}
+
+func (q *InMemoryQuirks) CreateSampleTable(tableName string, r arrow.Record) (err error) {
+	ctx := context.Background()
+	db, err := q.driver.NewDatabase(q.DatabaseOptions())
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, db.Close()) }()
+
+	cnxn, err := db.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, cnxn.Close()) }()
+
+	stmt, err := cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, stmt.Close()) }()
+
+	if err = stmt.SetOption(adbc.OptionKeyIngestTargetTable, tableName); err != nil {
+		return err
+	}
+	if err = stmt.Bind(ctx, r); err != nil {
+		return err
+	}
+	_, err = stmt.ExecuteUpdate(ctx)
+	return err
+}
+
+func (q *InMemoryQuirks) DropTable(cnxn adbc.Connection, tblname string) (err error) {
+	stmt, err := cnxn.NewStatement()
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.Join(err, stmt.Close()) }()
+
+	if err = stmt.SetSqlQuery(`DROP TABLE IF EXISTS "` + tblname + `"`); err != nil {
+		return err
+	}
+	_, err = stmt.ExecuteUpdate(context.Background())
+	return err
+}
+
+func (q *InMemoryQuirks) SampleTableSchemaMetadata(string, arrow.DataType) arrow.Metadata {
+	return arrow.Metadata{}
+}
+
+func (q *InMemoryQuirks) GetMetadata(code adbc.InfoCode) interface{} {
+	switch code {
+	case adbc.InfoDriverName:
+		return "ADBC In-Memory Driver - Go"
+	// runtime/debug.ReadBuildInfo doesn't currently work for tests
+	// github.com/golang/go/issues/33976
+	case adbc.InfoDriverVersion, adbc.InfoVendorVersion:
+		return "(unknown or development build)"
+	// the version of the Arrow dependency may still be found
+	case adbc.InfoDriverArrowVersion, adbc.InfoVendorArrowVersion:
+		return arrowVersion()
+	case adbc.InfoDriverADBCVersion:
+		return adbc.AdbcVersion1_1_0
+	case adbc.InfoVendorName:
+		return "In-Memory"
+	}
+	return nil
+}
+
+func arrowVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if strings.HasPrefix(dep.Path, "github.com/apache/arrow-go/") {
+				return dep.Version
+			}
+		}
+	}
+	return "(unknown or development build)"
+}
+
+func (q *InMemoryQuirks) Alloc() memory.Allocator                     { return q.mem }
+func (q *InMemoryQuirks) BindParameter(int) string                    { return "?" }
+func (q *InMemoryQuirks) SupportsBulkIngest(string) bool              { return true }
+func (q *InMemoryQuirks) SupportsConcurrentStatements() bool          { return true }
+func (q *InMemoryQuirks) SupportsCurrentCatalogSchema() bool          { return true }
+func (q *InMemoryQuirks) SupportsErrorIngestIncompatibleSchema() bool { return true }
+func (q *InMemoryQuirks) SupportsExecuteSchema() bool                 { return true }
+func (q *InMemoryQuirks) SupportsGetSetOptions() bool                 { return true }
+func (q *InMemoryQuirks) SupportsPartitionedData() bool               { return true }
+func (q *InMemoryQuirks) SupportsStatistics() bool                    { return true }
+func (q *InMemoryQuirks) SupportsTransactions() bool                  { return true }
+func (q *InMemoryQuirks) SupportsGetParameterSchema() bool            { return true }
+func (q *InMemoryQuirks) SupportsDynamicParameterBinding() bool       { return true }
+func (q *InMemoryQuirks) Catalog() string                             { return inmemory.DefaultCatalog }
+func (q *InMemoryQuirks) DBSchema() string                            { return inmemory.DefaultDBSchema }
+
+func TestValidation(t *testing.T) {
+	q := &InMemoryQuirks{mem: memory.NewCheckedAllocator(memory.DefaultAllocator)}
+	suite.Run(t, &validation.DatabaseTests{Quirks: q})
+	suite.Run(t, &validation.ConnectionTests{Quirks: q})
+	suite.Run(t, &validation.StatementTests{Quirks: q})
+	suite.Run(t, &InMemoryTests{Quirks: q})
+	q.mem.AssertSize(t, 0)
+}
+
+type InMemoryTests struct {
+	suite.Suite
+
+	Quirks *InMemoryQuirks
+
+	ctx    context.Context
+	driver adbc.Driver
+	db     adbc.Database
+	cnxn   adbc.Connection
+}
+
+func (s *InMemoryTests) SetupTest() {
+	var err error
+	s.ctx = context.Background()
+	s.driver = s.Quirks.SetupDriver(s.T())
+	s.db, err = s.driver.NewDatabase(s.Quirks.DatabaseOptions())
+	s.Require().NoError(err)
+	s.cnxn, err = s.db.Open(s.ctx)
+	s.Require().NoError(err)
+	s.update(s.cnxn, `DROP TABLE IF EXISTS t`)
+}
+
+func (s *InMemoryTests) TearDownTest() {
+	s.NoError(s.cnxn.Close())
+	s.NoError(s.db.Close())
+	s.Quirks.TearDownDriver(s.T(), s.driver)
+}
+
+func (s *InMemoryTests) createTable(cnxn adbc.Connection, data string) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int32},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(s.Quirks.Alloc(), schema, strings.NewReader(data))
+	s.Require().NoError(err)
+	defer rec.Release()
+
+	stmt, err := cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.SetOption(adbc.OptionKeyIngestTargetTable, "t"))
+	s.Require().NoError(stmt.Bind(s.ctx, rec))
+	_, err = stmt.ExecuteUpdate(s.ctx)
+	s.Require().NoError(err)
+}
+
+func (s *InMemoryTests) update(cnxn adbc.Connection, query string) int64 {
+	stmt, err := cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.SetSqlQuery(query))
+	n, err := stmt.ExecuteUpdate(s.ctx)
+	s.Require().NoError(err)
+	return n
+}
+
+// query returns the results of a query as JSON.
+func (s *InMemoryTests) query(cnxn adbc.Connection, query string) string {
+	stmt, err := cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.SetSqlQuery(query))
+	rdr, _, err := stmt.ExecuteQuery(s.ctx)
+	s.Require().NoError(err)
+	defer rdr.Release()
+
+	var out strings.Builder
+	for rdr.Next() {
+		b, err := rdr.Record().MarshalJSON()
+		s.Require().NoError(err)
+		out.Write(b)
+	}
+	s.Require().NoError(rdr.Err())
+	return out.String()
+}
+
+func (s *InMemoryTests) queryError(query string) adbc.Error {
+	stmt, err := s.cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.SetSqlQuery(query))
+	rdr, _, err := stmt.ExecuteQuery(s.ctx)
+	if rdr != nil {
+		rdr.Release()
+	}
+
+	var adbcErr adbc.Error
+	s.Require().ErrorAs(err, &adbcErr)
+	return adbcErr
+}
+
+func (s *InMemoryTests) TestSelect() {
+	s.createTable(s.cnxn, `[
+		{"id": 1, "name": "alice", "score": 9.5},
+		{"id": 2, "name": "bob", "score": null},
+		{"id": 3, "name": null, "score": 7},
+		{"id": 4, "name": "carol", "score": 8}
+	]`)
+
+	s.JSONEq(`[{"id": 4, "name": "carol"}, {"id": 1, "name": "alice"}]`,
+		s.query(s.cnxn, `SELECT id, name FROM t WHERE score >= 8 ORDER BY score ASC LIMIT 5`))
+	s.JSONEq(`[{"id": 2}, {"id": 4}]`,
+		s.query(s.cnxn, `SELECT "id" FROM main.t WHERE name LIKE '%o%' AND id NOT IN (1, 3)`))
+	s.JSONEq(`[{"double": 30, "label": "id 3"}, {"double": 20, "label": "id 2"}]`,
+		s.query(s.cnxn, `SELECT id * 10 / 1 AS double, 'id ' || id AS label FROM t WHERE id BETWEEN 2 AND 3 ORDER BY double DESC`))
+	s.JSONEq(`[{"id": 3}, {"id": 2}]`,
+		s.query(s.cnxn, `SELECT id FROM t WHERE name IS NULL OR score IS NULL ORDER BY id DESC`))
+}
+
+func (s *InMemoryTests) TestInsertDelete() {
+	s.createTable(s.cnxn, `[{"id": 1, "name": "alice", "score": 9.5}]`)
+
+	s.EqualValues(2, s.update(s.cnxn, `INSERT INTO t (name, id) VALUES ('bob', 2), (NULL, 3)`))
+	s.EqualValues(1, s.update(s.cnxn, `INSERT INTO t VALUES (4, 'dave', 6)`))
+	s.EqualValues(2, s.update(s.cnxn, `DELETE FROM t WHERE id % 2 = 0`))
+	s.JSONEq(`[{"id": 1, "name": "alice", "score": 9.5}, {"id": 3, "name": null, "score": null}]`,
+		s.query(s.cnxn, `SELECT * FROM t`))
+
+	err := s.queryError(`INSERT INTO t (name) VALUES ('eve')`)
+	s.Equal(adbc.StatusIntegrity, err.Code)
+	s.Equal("23502", string(err.SqlState[:]))
+
+	s.EqualValues(2, s.update(s.cnxn, `DELETE FROM t`))
+	s.Equal("", s.query(s.cnxn, `SELECT * FROM t`))
+}
+
+func (s *InMemoryTests) TestParameters() {
+	s.createTable(s.cnxn, `[]`)
+
+	stmt, err := s.cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.SetSqlQuery(`INSERT INTO t (id, name) VALUES (?, ?)`))
+	params, _, err := array.RecordFromJSON(s.Quirks.Alloc(), arrow.NewSchema([]arrow.Field{
+		{Name: "0", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "1", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil), strings.NewReader(`[{"0": 1, "1": "a"}, {"0": 2, "1": null}]`))
+	s.Require().NoError(err)
+	defer params.Release()
+	s.Require().NoError(stmt.Bind(s.ctx, params))
+	n, err := stmt.ExecuteUpdate(s.ctx)
+	s.Require().NoError(err)
+	s.EqualValues(2, n)
+
+	s.JSONEq(`[{"id": 1, "name": "a", "score": null}, {"id": 2, "name": null, "score": null}]`,
+		s.query(s.cnxn, `SELECT * FROM t`))
+}
+
+func (s *InMemoryTests) TestTransactions() {
+	other, err := s.db.Open(s.ctx)
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), other)
+	s.createTable(s.cnxn, `[{"id": 1}]`)
+
+	s.Require().NoError(s.cnxn.(adbc.PostInitOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueDisabled))
+	s.update(s.cnxn, `INSERT INTO t (id) VALUES (2)`)
+	s.JSONEq(`[{"id": 1}, {"id": 2}]`, s.query(s.cnxn, `SELECT id FROM t`))
+	s.JSONEq(`[{"id": 1}]`, s.query(other, `SELECT id FROM t`))
+
+	s.Require().NoError(s.cnxn.Rollback(s.ctx))
+	s.JSONEq(`[{"id": 1}]`, s.query(s.cnxn, `SELECT id FROM t`))
+
+	s.update(s.cnxn, `DELETE FROM t`)
+	s.Require().NoError(s.cnxn.Commit(s.ctx))
+	s.Equal("", s.query(other, `SELECT id FROM t`))
+
+	s.Require().NoError(s.cnxn.(adbc.PostInitOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueEnabled))
+}
+
+func (s *InMemoryTests) TestSeparateDatabases() {
+	s.createTable(s.cnxn, `[{"id": 1}]`)
+
+	shared, err := s.driver.NewDatabase(s.Quirks.DatabaseOptions())
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), shared)
+	cnxn, err := shared.Open(s.ctx)
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), cnxn)
+	s.JSONEq(`[{"id": 1}]`, s.query(cnxn, `SELECT id FROM t`))
+
+	private, err := s.driver.NewDatabase(nil)
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), private)
+	cnxn, err = private.Open(s.ctx)
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), cnxn)
+	_, err = cnxn.GetTableSchema(s.ctx, nil, nil, "t")
+	s.ErrorContains(err, `table "t" not found`)
+}
+
+func (s *InMemoryTests) TestPartitions() {
+	s.createTable(s.cnxn, `[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}]`)
+
+	stmt, err := s.cnxn.NewStatement()
+	s.Require().NoError(err)
+	defer validation.CheckedClose(s.T(), stmt)
+	s.Require().NoError(stmt.(adbc.GetSetOptions).SetOptionInt(inmemory.OptionIntBatchSize, 2))
+	s.Require().NoError(stmt.SetSqlQuery(`SELECT id FROM t`))
+	_, partitions, _, err := stmt.ExecutePartitions(s.ctx)
+	s.Require().NoError(err)
+	s.EqualValues(3, partitions.NumPartitions)
+
+	rdr, err := s.cnxn.ReadPartition(s.ctx, partitions.PartitionIDs[2])
+	s.Require().NoError(err)
+	defer rdr.Release()
+	s.Require().True(rdr.Next())
+	b, err := rdr.Record().MarshalJSON()
+	s.Require().NoError(err)
+	s.JSONEq(`[{"id": 5}]`, string(b))
+}
+
+func (s *InMemoryTests) TestErrors() {
+	s.createTable(s.cnxn, `[{"id": 1}]`)
+
+	for _, tt := range []struct {
+		query    string
+		code     adbc.Status
+		sqlState string
+	}{
+		{`SELECT FROM t`, adbc.StatusInvalidArgument, "42601"},
+		{`SELECT id FROM missing`, adbc.StatusNotFound, "42S02"},
+		{`SELECT missing FROM t`, adbc.StatusNotFound, "42S22"},
+		{`SELECT id FROM other.t`, adbc.StatusNotFound, "3F000"},
+		{`SELECT id / 0 FROM t`, adbc.StatusInvalidData, "22012"},
+		{`SELECT 'a' + 1`, adbc.StatusInvalidArgument, "42883"},
+		{`UPDATE t SET id = 2`, adbc.StatusNotImplemented, "0A000"},
+	} {
+		err := s.queryError(tt.query)
+		s.Equal(tt.code, err.Code, tt.query)
+		s.Equal(tt.sqlState, string(err.SqlState[:]), tt.query)
+	}
+}
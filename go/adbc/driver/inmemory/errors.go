@@ -0,0 +1,67 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+// queryError is an error of a query or of a change to the tables, with
+// the status and SQLSTATE to report it with.
+type queryError struct {
+	code     adbc.Status
+	sqlState string
+	msg      string
+}
+
+func (e *queryError) Error() string {
+	return e.msg
+}
+
+// errorf returns a queryError. The SQLSTATE may be empty, in which case
+// the one of the status is used.
+func errorf(code adbc.Status, sqlState string, format string, args ...any) error {
+	return &queryError{code: code, sqlState: sqlState, msg: fmt.Sprintf(format, args...)}
+}
+
+// toADBCError converts a queryError to an adbc.Error, passing other errors
+// through.
+func toADBCError(helper *driverbase.ErrorHelper, err error) error {
+	var qErr *queryError
+	if !errors.As(err, &qErr) {
+		return err
+	}
+	adbcErr := adbc.Error{
+		Code: qErr.code,
+		Msg:  fmt.Sprintf("[%s] %s", helper.DriverName, qErr.msg),
+	}
+	if qErr.sqlState != "" {
+		adbcErr.SqlState = driverbase.SQLState(qErr.sqlState)
+	} else {
+		adbcErr.SqlState = driverbase.SQLStateForStatus(qErr.code)
+	}
+	return adbcErr
+}
+
+func tableNotFound(name string) error {
+	return errorf(adbc.StatusNotFound, "42S02", "table %q not found", name)
+}
@@ -0,0 +1,121 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ingest writes the bound data to the target table, and returns the
+// number of rows written.
+func (st *statementImpl) ingest(ctx context.Context, bound arrow.Record, stream array.RecordReader) (int64, error) {
+	if bound == nil && stream == nil {
+		return -1, errorf(adbc.StatusInvalidState, "", "must call Bind or BindStream before bulk ingestion")
+	}
+	data, err := readParams(bound, stream)
+	if err != nil {
+		return -1, errorf(adbc.StatusIO, "", "failed to read the data to ingest: %s", err)
+	}
+	defer data.release()
+
+	ref := tableRef{table: ident{name: st.targetTable, quoted: true}}
+	if st.targetCatalog != "" {
+		ref.catalog = &ident{name: st.targetCatalog, quoted: true}
+	}
+	if st.targetDbSchema != "" {
+		ref.dbSchema = &ident{name: st.targetDbSchema, quoted: true}
+	}
+	if err := ref.check(); err != nil {
+		return -1, err
+	}
+
+	var numRows int64
+	for _, rec := range data.batches {
+		numRows += rec.NumRows()
+	}
+
+	err = st.cnxn.writeTable(ref.table, func(old arrow.Record) (arrow.Record, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		mode := st.ingestMode
+		if mode == adbc.OptionValueIngestModeCreateAppend {
+			mode = adbc.OptionValueIngestModeAppend
+			if old == nil {
+				mode = adbc.OptionValueIngestModeCreate
+			}
+		}
+		switch mode {
+		case adbc.OptionValueIngestModeCreate:
+			if old != nil {
+				// clients tell this apart from other failures by the
+				// SQLSTATE
+				return nil, errorf(adbc.StatusInternal, "42S01", "table %q already exists", st.targetTable)
+			}
+		case adbc.OptionValueIngestModeAppend:
+			if old == nil {
+				return nil, tableNotFound(st.targetTable)
+			}
+			if err := checkAppend(old.Schema(), data); err != nil {
+				return nil, err
+			}
+			return concatRecords(st.cnxn.Alloc, old.Schema(), append([]arrow.Record{old}, data.batches...))
+		}
+		return concatRecords(st.cnxn.Alloc, data.schema, data.batches)
+	})
+	if err != nil {
+		return -1, err
+	}
+	return numRows, nil
+}
+
+// checkAppend returns an error unless data can be appended to a table
+// with the given schema.
+func checkAppend(table *arrow.Schema, data *paramRows) error {
+	mismatch := func(format string, args ...any) error {
+		return errorf(adbc.StatusInvalidArgument, "", "cannot append to a table with schema %s: %s",
+			table, fmt.Sprintf(format, args...))
+	}
+	if table.NumFields() != data.schema.NumFields() {
+		return mismatch("the data has %d columns", data.schema.NumFields())
+	}
+	for i, field := range table.Fields() {
+		other := data.schema.Field(i)
+		if field.Name != other.Name {
+			return mismatch("column %d of the data is named %q", i+1, other.Name)
+		}
+		if !arrow.TypeEqual(field.Type, other.Type) {
+			return mismatch("column %q of the data has type %s", other.Name, other.Type)
+		}
+		if field.Nullable {
+			continue
+		}
+		for _, rec := range data.batches {
+			if rec.Column(i).NullN() > 0 {
+				return errorf(adbc.StatusIntegrity, "23502", "null value in column %q violates not-null constraint", field.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"strconv"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+// optionNamespace is the prefix of the driver-specific options. Keys with
+// this prefix that aren't listed in optionSpecs are rejected.
+const optionNamespace = "adbc.inmemory."
+
+const (
+	levelDatabase  = driverbase.OptionLevelDatabase
+	levelConn      = driverbase.OptionLevelConnection
+	levelStatement = driverbase.OptionLevelStatement
+)
+
+var optionSpecs = []driverbase.OptionSpec{
+	{Name: adbc.OptionKeyTelemetryTraceParent, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Description: "W3C trace parent to attach to OpenTelemetry traces."},
+	{Name: adbc.OptionKeyAutoCommit, Levels: levelConn, Type: driverbase.OptionTypeBool,
+		Default:     driverbase.OptionDefault(adbc.OptionValueEnabled),
+		Description: "Disabling autocommit runs the statements of the connection on a snapshot of the tables, until Commit or Rollback."},
+	{Name: adbc.OptionKeyCurrentCatalog, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(DefaultCatalog)},
+	{Name: adbc.OptionKeyCurrentDbSchema, Levels: levelConn, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(DefaultDBSchema)},
+	{Name: adbc.OptionKeyIngestTargetTable, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestMode, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(adbc.OptionValueIngestModeCreate),
+		AllowedValues: []string{
+			adbc.OptionValueIngestModeCreate,
+			adbc.OptionValueIngestModeAppend,
+			adbc.OptionValueIngestModeReplace,
+			adbc.OptionValueIngestModeCreateAppend,
+		}},
+	{Name: adbc.OptionValueIngestTargetCatalog, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTargetDBSchema, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionValueIngestTemporary, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled), Description: "Temporary tables are not supported."},
+
+	{Name: OptionStringDatabaseName, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Description: "Name of the tables of the database, shared with the other databases of the driver with the same name."},
+	{Name: OptionIntBatchSize, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default:     driverbase.OptionDefault(strconv.Itoa(defaultBatchSize)),
+		Description: "Maximum number of rows per record of results, and per partition."},
+}
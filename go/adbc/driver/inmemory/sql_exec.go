@@ -0,0 +1,1062 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Expressions are evaluated row by row on Go values: nil, bool, int64,
+// float64, string or []byte. Values of other Arrow types are compared as
+// their string representation.
+
+// valueClass groups the Arrow types whose values can be compared.
+type valueClass int
+
+const (
+	classNull valueClass = iota
+	classBool
+	classNumber
+	classString
+	classBinary
+)
+
+func classOf(dt arrow.DataType) valueClass {
+	switch dt.ID() {
+	case arrow.NULL:
+		return classNull
+	case arrow.BOOL:
+		return classBool
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64,
+		arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64,
+		arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return classNumber
+	case arrow.BINARY, arrow.LARGE_BINARY, arrow.BINARY_VIEW, arrow.FIXED_SIZE_BINARY:
+		return classBinary
+	}
+	return classString
+}
+
+func isFloat(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return true
+	}
+	return false
+}
+
+// valueAt returns the value of arr at row i.
+func valueAt(arr arrow.Array, i int) any {
+	if arr.IsNull(i) {
+		return nil
+	}
+	switch arr := arr.(type) {
+	case *array.Boolean:
+		return arr.Value(i)
+	case *array.Int8:
+		return int64(arr.Value(i))
+	case *array.Int16:
+		return int64(arr.Value(i))
+	case *array.Int32:
+		return int64(arr.Value(i))
+	case *array.Int64:
+		return arr.Value(i)
+	case *array.Uint8:
+		return int64(arr.Value(i))
+	case *array.Uint16:
+		return int64(arr.Value(i))
+	case *array.Uint32:
+		return int64(arr.Value(i))
+	case *array.Uint64:
+		if v := arr.Value(i); v <= math.MaxInt64 {
+			return int64(v)
+		} else {
+			return float64(v)
+		}
+	case *array.Float16:
+		return float64(arr.Value(i).Float32())
+	case *array.Float32:
+		return float64(arr.Value(i))
+	case *array.Float64:
+		return arr.Value(i)
+	case *array.String:
+		return arr.Value(i)
+	case *array.LargeString:
+		return arr.Value(i)
+	case *array.StringView:
+		return arr.Value(i)
+	case *array.Binary:
+		return arr.Value(i)
+	case *array.LargeBinary:
+		return arr.Value(i)
+	case *array.BinaryView:
+		return arr.Value(i)
+	case *array.FixedSizeBinary:
+		return arr.Value(i)
+	}
+	return arr.ValueStr(i)
+}
+
+func literalType(v any) arrow.DataType {
+	switch v.(type) {
+	case bool:
+		return arrow.FixedWidthTypes.Boolean
+	case int64:
+		return arrow.PrimitiveTypes.Int64
+	case float64:
+		return arrow.PrimitiveTypes.Float64
+	case string:
+		return arrow.BinaryTypes.String
+	}
+	return arrow.Null
+}
+
+// compareValues orders two non-null values of the same class.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return cmp.Compare(a, b)
+		case float64:
+			return cmp.Compare(float64(a), b)
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return cmp.Compare(a, float64(b))
+		case float64:
+			return cmp.Compare(a, b)
+		}
+	case string:
+		return strings.Compare(a, b.(string))
+	case []byte:
+		return bytes.Compare(a, b.([]byte))
+	case bool:
+		switch {
+		case a == b.(bool):
+			return 0
+		case a:
+			return 1
+		}
+		return -1
+	}
+	panic(fmt.Sprintf("cannot compare %T and %T", a, b))
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []byte:
+		return string(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// compiled is an expression bound to the columns of a table and to a row
+// of parameters.
+type compiled struct {
+	typ  arrow.DataType
+	eval func(row int) (any, error)
+	// src holds the values of column and parameter references, at srcRow,
+	// or at the evaluated row if srcRow is negative. It is nil for other
+	// expressions, and when only types are compiled.
+	src    arrow.Array
+	srcRow int
+	// field is the field of column references
+	field *arrow.Field
+}
+
+func constant(typ arrow.DataType, v any) *compiled {
+	return &compiled{typ: typ, eval: func(int) (any, error) { return v, nil }, srcRow: -1}
+}
+
+// env is what expressions are compiled against.
+type env struct {
+	// schema and rec are those of the table of the query, if any. rec is
+	// nil if only the types of expressions are needed.
+	schema *arrow.Schema
+	rec    arrow.Record
+	// paramSchema and params are those of the bound parameters, if any,
+	// which are at row paramRow of params. params is nil if only the types
+	// of expressions are needed.
+	paramSchema *arrow.Schema
+	params      arrow.Record
+	paramRow    int
+}
+
+func (e *env) resolveColumn(name ident) (int, error) {
+	if e.schema == nil {
+		return -1, errorf(adbc.StatusNotFound, "42S22", "column %q not found: the query has no FROM clause", name.name)
+	}
+	if name.quoted {
+		if indices := e.schema.FieldIndices(name.name); len(indices) > 0 {
+			if len(indices) > 1 {
+				return -1, errorf(adbc.StatusInvalidArgument, "42702", "column reference %q is ambiguous", name.name)
+			}
+			return indices[0], nil
+		}
+		return -1, errorf(adbc.StatusNotFound, "42S22", "column %q not found", name.name)
+	}
+
+	found := -1
+	for i, field := range e.schema.Fields() {
+		if name.matches(field.Name) {
+			if found >= 0 {
+				return -1, errorf(adbc.StatusInvalidArgument, "42702", "column reference %q is ambiguous", name.name)
+			}
+			found = i
+		}
+	}
+	if found < 0 {
+		return -1, errorf(adbc.StatusNotFound, "42S22", "column %q not found", name.name)
+	}
+	return found, nil
+}
+
+// column compiles a reference to the column of the table at index i.
+func (e *env) column(i int) *compiled {
+	field := e.schema.Field(i)
+	c := &compiled{typ: field.Type, field: &field, srcRow: -1}
+	if e.rec == nil {
+		c.eval = func(int) (any, error) { return nil, nil }
+		return c
+	}
+	col := e.rec.Column(i)
+	c.src = col
+	c.eval = func(row int) (any, error) { return valueAt(col, row), nil }
+	return c
+}
+
+func (e *env) param(index int) (*compiled, error) {
+	if e.paramSchema == nil {
+		return constant(arrow.Null, nil), nil
+	}
+	if index >= e.paramSchema.NumFields() {
+		return nil, errorf(adbc.StatusInvalidArgument, "07001", "parameter %d is not bound", index+1)
+	}
+	typ := e.paramSchema.Field(index).Type
+	if e.params == nil {
+		return constant(typ, nil), nil
+	}
+	col := e.params.Column(index)
+	c := constant(typ, valueAt(col, e.paramRow))
+	c.src, c.srcRow = col, e.paramRow
+	return c, nil
+}
+
+func operatorError(op string, types ...arrow.DataType) error {
+	names := make([]string, len(types))
+	for i, typ := range types {
+		names[i] = typ.String()
+	}
+	return errorf(adbc.StatusInvalidArgument, "42883", "operator %s is not defined for %s", op, strings.Join(names, " and "))
+}
+
+// comparable reports whether values of the two types can be compared.
+func comparable(a, b arrow.DataType) bool {
+	ca, cb := classOf(a), classOf(b)
+	return ca == cb || ca == classNull || cb == classNull
+}
+
+func (e *env) compile(x expr) (*compiled, error) {
+	switch x := x.(type) {
+	case *literal:
+		return constant(literalType(x.value), x.value), nil
+	case *columnRef:
+		i, err := e.resolveColumn(x.name)
+		if err != nil {
+			return nil, err
+		}
+		return e.column(i), nil
+	case *paramRef:
+		return e.param(x.index)
+	case *unaryExpr:
+		return e.compileUnary(x)
+	case *binaryExpr:
+		return e.compileBinary(x)
+	case *isNullExpr:
+		arg, err := e.compile(x.arg)
+		if err != nil {
+			return nil, err
+		}
+		return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+			v, err := arg.eval(row)
+			if err != nil {
+				return nil, err
+			}
+			return (v == nil) != x.not, nil
+		}}, nil
+	case *likeExpr:
+		return e.compileLike(x)
+	case *inExpr:
+		return e.compileIn(x)
+	}
+	return nil, errorf(adbc.StatusInternal, "", "unknown expression %T", x)
+}
+
+func (e *env) compileUnary(x *unaryExpr) (*compiled, error) {
+	arg, err := e.compile(x.arg)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.op == "NOT" {
+		if class := classOf(arg.typ); class != classBool && class != classNull {
+			return nil, operatorError("NOT", arg.typ)
+		}
+		return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+			v, err := arg.eval(row)
+			if v == nil || err != nil {
+				return nil, err
+			}
+			return !v.(bool), nil
+		}}, nil
+	}
+
+	typ, ok := arithmeticType(arg.typ, arg.typ)
+	if !ok {
+		return nil, operatorError(x.op, arg.typ)
+	}
+	return &compiled{typ: typ, srcRow: -1, eval: func(row int) (any, error) {
+		v, err := arg.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case int64:
+			return -v, nil
+		case float64:
+			return -v, nil
+		}
+		return nil, nil
+	}}, nil
+}
+
+// arithmeticType returns the type of arithmetic on values of types a and
+// b: integers are computed as int64, other numbers as float64.
+func arithmeticType(a, b arrow.DataType) (arrow.DataType, bool) {
+	ca, cb := classOf(a), classOf(b)
+	switch {
+	case (ca != classNumber && ca != classNull) || (cb != classNumber && cb != classNull):
+		return nil, false
+	case ca == classNull && cb == classNull:
+		return arrow.Null, true
+	case isFloat(a) || isFloat(b):
+		return arrow.PrimitiveTypes.Float64, true
+	}
+	return arrow.PrimitiveTypes.Int64, true
+}
+
+func (e *env) compileBinary(x *binaryExpr) (*compiled, error) {
+	left, err := e.compile(x.left)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.compile(x.right)
+	if err != nil {
+		return nil, err
+	}
+	operands := func(row int) (a, b any, err error) {
+		if a, err = left.eval(row); err != nil {
+			return nil, nil, err
+		}
+		b, err = right.eval(row)
+		return a, b, err
+	}
+
+	switch x.op {
+	case "AND", "OR":
+		for _, c := range []*compiled{left, right} {
+			if class := classOf(c.typ); class != classBool && class != classNull {
+				return nil, operatorError(x.op, left.typ, right.typ)
+			}
+		}
+		// three-valued logic: the result is null if it depends on a null
+		decisive := x.op == "OR"
+		return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+			a, b, err := operands(row)
+			switch {
+			case err != nil:
+				return nil, err
+			case a == decisive || b == decisive:
+				return decisive, nil
+			case a == nil || b == nil:
+				return nil, nil
+			}
+			return !decisive, nil
+		}}, nil
+
+	case "=", "<>", "<", "<=", ">", ">=":
+		if !comparable(left.typ, right.typ) {
+			return nil, operatorError(x.op, left.typ, right.typ)
+		}
+		test := map[string]func(int) bool{
+			"=":  func(c int) bool { return c == 0 },
+			"<>": func(c int) bool { return c != 0 },
+			"<":  func(c int) bool { return c < 0 },
+			"<=": func(c int) bool { return c <= 0 },
+			">":  func(c int) bool { return c > 0 },
+			">=": func(c int) bool { return c >= 0 },
+		}[x.op]
+		return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+			a, b, err := operands(row)
+			if a == nil || b == nil || err != nil {
+				return nil, err
+			}
+			return test(compareValues(a, b)), nil
+		}}, nil
+
+	case "||":
+		return &compiled{typ: arrow.BinaryTypes.String, srcRow: -1, eval: func(row int) (any, error) {
+			a, b, err := operands(row)
+			if a == nil || b == nil || err != nil {
+				return nil, err
+			}
+			return formatValue(a) + formatValue(b), nil
+		}}, nil
+	}
+
+	typ, ok := arithmeticType(left.typ, right.typ)
+	if !ok {
+		return nil, operatorError(x.op, left.typ, right.typ)
+	}
+	return &compiled{typ: typ, srcRow: -1, eval: func(row int) (any, error) {
+		a, b, err := operands(row)
+		if a == nil || b == nil || err != nil {
+			return nil, err
+		}
+		return arithmetic(x.op, a, b)
+	}}, nil
+}
+
+func arithmetic(op string, a, b any) (any, error) {
+	ai, aInt := a.(int64)
+	bi, bInt := b.(int64)
+	if aInt && bInt {
+		switch op {
+		case "+":
+			return ai + bi, nil
+		case "-":
+			return ai - bi, nil
+		case "*":
+			return ai * bi, nil
+		}
+		if bi == 0 {
+			return nil, errorf(adbc.StatusInvalidData, "22012", "division by zero")
+		}
+		if op == "/" {
+			return ai / bi, nil
+		}
+		return ai % bi, nil
+	}
+
+	af, bf := toFloat(a), toFloat(b)
+	switch op {
+	case "+":
+		return af + bf, nil
+	case "-":
+		return af - bf, nil
+	case "*":
+		return af * bf, nil
+	}
+	if bf == 0 {
+		return nil, errorf(adbc.StatusInvalidData, "22012", "division by zero")
+	}
+	if op == "/" {
+		return af / bf, nil
+	}
+	return math.Mod(af, bf), nil
+}
+
+func toFloat(v any) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+func (e *env) compileLike(x *likeExpr) (*compiled, error) {
+	arg, err := e.compile(x.arg)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := e.compile(x.pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []*compiled{arg, pattern} {
+		if class := classOf(c.typ); class != classString && class != classNull {
+			return nil, operatorError("LIKE", arg.typ, pattern.typ)
+		}
+	}
+
+	// patterns are usually constant, so the last one is kept
+	var (
+		lastPattern string
+		re          *regexp.Regexp
+	)
+	return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+		v, err := arg.eval(row)
+		if v == nil || err != nil {
+			return nil, err
+		}
+		p, err := pattern.eval(row)
+		if p == nil || err != nil {
+			return nil, err
+		}
+		if re == nil || p.(string) != lastPattern {
+			lastPattern = p.(string)
+			re = likeToRegexp(lastPattern)
+		}
+		return re.MatchString(v.(string)) != x.not, nil
+	}}, nil
+}
+
+// likeToRegexp compiles a LIKE pattern, in which % matches any string
+// and _ any character.
+func likeToRegexp(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func (e *env) compileIn(x *inExpr) (*compiled, error) {
+	arg, err := e.compile(x.arg)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*compiled, len(x.list))
+	for i, item := range x.list {
+		if list[i], err = e.compile(item); err != nil {
+			return nil, err
+		}
+		if !comparable(arg.typ, list[i].typ) {
+			return nil, operatorError("IN", arg.typ, list[i].typ)
+		}
+	}
+
+	return &compiled{typ: arrow.FixedWidthTypes.Boolean, srcRow: -1, eval: func(row int) (any, error) {
+		v, err := arg.eval(row)
+		if v == nil || err != nil {
+			return nil, err
+		}
+		sawNull := false
+		for _, item := range list {
+			w, err := item.eval(row)
+			switch {
+			case err != nil:
+				return nil, err
+			case w == nil:
+				sawNull = true
+			case compareValues(v, w) == 0:
+				return !x.not, nil
+			}
+		}
+		if sawNull {
+			return nil, nil
+		}
+		return x.not, nil
+	}}, nil
+}
+
+// compilePredicate compiles the condition of a WHERE clause.
+func (e *env) compilePredicate(x expr) (*compiled, error) {
+	c, err := e.compile(x)
+	if err != nil {
+		return nil, err
+	}
+	if class := classOf(c.typ); class != classBool && class != classNull {
+		return nil, errorf(adbc.StatusInvalidArgument, "42804", "argument of WHERE must be a boolean, not %s", c.typ)
+	}
+	return c, nil
+}
+
+// selectPlan is a SELECT compiled against a table and a row of parameters.
+type selectPlan struct {
+	cols   []*compiled
+	schema *arrow.Schema
+	// aliases are the columns named with AS, which ORDER BY may refer to
+	aliases map[string]*compiled
+}
+
+func (s *selectStmt) plan(e *env) (*selectPlan, error) {
+	plan := &selectPlan{aliases: make(map[string]*compiled)}
+	fields := make([]arrow.Field, 0, len(s.items))
+	for _, item := range s.items {
+		if item.star {
+			if e.schema == nil {
+				return nil, errorf(adbc.StatusInvalidArgument, "42601", "SELECT * requires a FROM clause")
+			}
+			for i := range e.schema.Fields() {
+				col := e.column(i)
+				plan.cols = append(plan.cols, col)
+				fields = append(fields, *col.field)
+			}
+			continue
+		}
+
+		col, err := e.compile(item.expr)
+		if err != nil {
+			return nil, err
+		}
+		field := arrow.Field{Name: item.name, Type: col.typ, Nullable: true}
+		if col.field != nil {
+			field = *col.field
+			field.Name = item.name
+		}
+		if item.aliased {
+			plan.aliases[item.name] = col
+		}
+		plan.cols = append(plan.cols, col)
+		fields = append(fields, field)
+	}
+	plan.schema = arrow.NewSchema(fields, nil)
+	return plan, nil
+}
+
+// compileOrderKey compiles a key of ORDER BY, which may be the alias of
+// a column of the results.
+func (s *selectStmt) compileOrderKey(e *env, plan *selectPlan, x expr) (*compiled, error) {
+	c, err := e.compile(x)
+	if err == nil {
+		return c, nil
+	}
+	if ref, ok := x.(*columnRef); ok {
+		for alias, col := range plan.aliases {
+			if ref.name.matches(alias) {
+				return col, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+// run evaluates the SELECT for the row of parameters of e.
+func (s *selectStmt) run(ctx context.Context, alloc memory.Allocator, e *env) (arrow.Record, error) {
+	plan, err := s.plan(e)
+	if err != nil {
+		return nil, err
+	}
+
+	numRows := 1
+	if e.rec != nil {
+		numRows = int(e.rec.NumRows())
+	}
+	rows := make([]int, 0, numRows)
+	if s.where == nil {
+		for row := range numRows {
+			rows = append(rows, row)
+		}
+	} else {
+		where, err := e.compilePredicate(s.where)
+		if err != nil {
+			return nil, err
+		}
+		for row := range numRows {
+			v, err := where.eval(row)
+			if err != nil {
+				return nil, err
+			}
+			if v == true {
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	if len(s.orderBy) > 0 {
+		if rows, err = s.sort(e, plan, rows); err != nil {
+			return nil, err
+		}
+	}
+	if s.limit >= 0 && int64(len(rows)) > s.limit {
+		rows = rows[:s.limit]
+	}
+
+	cols := make([]arrow.Array, len(plan.cols))
+	defer func() {
+		for _, col := range cols {
+			if col != nil {
+				col.Release()
+			}
+		}
+	}()
+	for i, c := range plan.cols {
+		if cols[i], err = materialize(ctx, alloc, c, rows); err != nil {
+			return nil, err
+		}
+	}
+	return array.NewRecord(plan.schema, cols, int64(len(rows))), nil
+}
+
+func (s *selectStmt) sort(e *env, plan *selectPlan, rows []int) ([]int, error) {
+	keys := make([]*compiled, len(s.orderBy))
+	for i, key := range s.orderBy {
+		var err error
+		if keys[i], err = s.compileOrderKey(e, plan, key.expr); err != nil {
+			return nil, err
+		}
+	}
+
+	type sortRow struct {
+		row  int
+		keys []any
+	}
+	sorted := make([]sortRow, len(rows))
+	for i, row := range rows {
+		sorted[i] = sortRow{row: row, keys: make([]any, len(keys))}
+		for k, key := range keys {
+			v, err := key.eval(row)
+			if err != nil {
+				return nil, err
+			}
+			sorted[i].keys[k] = v
+		}
+	}
+
+	slices.SortStableFunc(sorted, func(a, b sortRow) int {
+		for k, key := range s.orderBy {
+			va, vb := a.keys[k], b.keys[k]
+			var c int
+			switch {
+			case va == nil && vb == nil:
+				continue
+			case va == nil || vb == nil:
+				// nulls are placed regardless of the direction
+				if (va == nil) == key.nullsFirst {
+					return -1
+				}
+				return 1
+			default:
+				c = compareValues(va, vb)
+			}
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	for i, r := range sorted {
+		rows[i] = r.row
+	}
+	return rows, nil
+}
+
+// materialize builds the values of c at the given rows.
+func materialize(ctx context.Context, alloc memory.Allocator, c *compiled, rows []int) (arrow.Array, error) {
+	if c.src != nil {
+		if c.srcRow < 0 {
+			return take(ctx, alloc, c.src, rows)
+		}
+		return take(ctx, alloc, c.src, slices.Repeat([]int{c.srcRow}, len(rows)))
+	}
+
+	bldr := array.NewBuilder(alloc, c.typ)
+	defer bldr.Release()
+	for _, row := range rows {
+		v, err := c.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if err := appendResult(bldr, v); err != nil {
+			return nil, err
+		}
+	}
+	return bldr.NewArray(), nil
+}
+
+// take returns the values of arr at the given rows.
+func take(ctx context.Context, alloc memory.Allocator, arr arrow.Array, rows []int) (arrow.Array, error) {
+	identity := len(rows) == arr.Len()
+	for i, row := range rows {
+		if !identity {
+			break
+		}
+		identity = i == row
+	}
+	if identity {
+		arr.Retain()
+		return arr, nil
+	}
+
+	bldr := array.NewInt64Builder(alloc)
+	defer bldr.Release()
+	bldr.Reserve(len(rows))
+	for _, row := range rows {
+		bldr.UnsafeAppend(int64(row))
+	}
+	indices := bldr.NewArray()
+	defer indices.Release()
+	return compute.TakeArray(compute.WithAllocator(ctx, alloc), arr, indices)
+}
+
+// takeRecord returns the given rows of rec.
+func takeRecord(ctx context.Context, alloc memory.Allocator, rec arrow.Record, rows []int) (arrow.Record, error) {
+	cols := make([]arrow.Array, 0, rec.NumCols())
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for _, col := range rec.Columns() {
+		taken, err := take(ctx, alloc, col, rows)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, taken)
+	}
+	return array.NewRecord(rec.Schema(), cols, int64(len(rows))), nil
+}
+
+// appendResult appends a computed value to a builder of its type.
+func appendResult(bldr array.Builder, v any) error {
+	if v == nil {
+		bldr.AppendNull()
+		return nil
+	}
+	switch bldr := bldr.(type) {
+	case *array.BooleanBuilder:
+		bldr.Append(v.(bool))
+	case *array.Int64Builder:
+		i, ok := v.(int64)
+		if !ok {
+			return errorf(adbc.StatusInvalidData, "22003", "integer out of range: %v", v)
+		}
+		bldr.Append(i)
+	case *array.Float64Builder:
+		bldr.Append(toFloat(v))
+	case *array.StringBuilder:
+		bldr.Append(formatValue(v))
+	default:
+		return errorf(adbc.StatusInternal, "", "cannot append %T to %s", v, bldr.Type())
+	}
+	return nil
+}
+
+// appendValue appends a value to the builder of a column, converting it
+// to the type of the column.
+func appendValue(bldr array.Builder, field arrow.Field, v any) error {
+	if v == nil {
+		if !field.Nullable {
+			return errorf(adbc.StatusIntegrity, "23502", "null value in column %q violates not-null constraint", field.Name)
+		}
+		bldr.AppendNull()
+		return nil
+	}
+
+	var err error
+	switch b := bldr.(type) {
+	case *array.BooleanBuilder:
+		if v, ok := v.(bool); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Int64Builder:
+		if v, ok := v.(int64); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Float64Builder:
+		if _, ok := v.(string); !ok {
+			if _, ok := v.(bool); !ok {
+				b.Append(toFloat(v))
+				return nil
+			}
+		}
+	case *array.StringBuilder:
+		b.Append(formatValue(v))
+		return nil
+	case *array.BinaryBuilder:
+		switch v := v.(type) {
+		case []byte:
+			b.Append(v)
+			return nil
+		case string:
+			b.AppendString(v)
+			return nil
+		}
+		// the string representation of binary values is base64
+		err = b.AppendValueFromString(base64.StdEncoding.EncodeToString([]byte(formatValue(v))))
+	}
+	if err == nil {
+		err = bldr.AppendValueFromString(formatValue(v))
+	}
+	if err != nil {
+		return errorf(adbc.StatusInvalidArgument, "22018", "cannot convert %s to %s for column %q: %s",
+			formatValue(v), field.Type, field.Name, err)
+	}
+	return nil
+}
+
+// apply returns the table old with the rows of the INSERT appended, for
+// each row of parameters.
+func (s *insertStmt) apply(alloc memory.Allocator, old arrow.Record, params *paramRows) (arrow.Record, int64, error) {
+	schema := old.Schema()
+	table := &env{schema: schema}
+	targets := make([]int, 0, schema.NumFields())
+	if s.columns == nil {
+		for i := range schema.NumFields() {
+			targets = append(targets, i)
+		}
+	} else {
+		for _, column := range s.columns {
+			i, err := table.resolveColumn(column)
+			if err != nil {
+				return nil, 0, err
+			}
+			if slices.Contains(targets, i) {
+				return nil, 0, errorf(adbc.StatusInvalidArgument, "42701", "column %q specified more than once", column.name)
+			}
+			targets = append(targets, i)
+		}
+	}
+	for _, row := range s.rows {
+		if len(row) != len(targets) {
+			return nil, 0, errorf(adbc.StatusInvalidArgument, "42601", "INSERT has %d values for %d columns", len(row), len(targets))
+		}
+	}
+
+	bldrs := make([]array.Builder, schema.NumFields())
+	for i, field := range schema.Fields() {
+		bldrs[i] = array.NewBuilder(alloc, field.Type)
+		defer bldrs[i].Release()
+	}
+
+	var numRows int64
+	err := params.each(func(rec arrow.Record, paramRow int) error {
+		e := &env{paramSchema: params.schema, params: rec, paramRow: paramRow}
+		for _, row := range s.rows {
+			values := make([]any, schema.NumFields())
+			for i, x := range row {
+				c, err := e.compile(x)
+				if err != nil {
+					return err
+				}
+				if values[targets[i]], err = c.eval(0); err != nil {
+					return err
+				}
+			}
+			for i, v := range values {
+				if err := appendValue(bldrs[i], schema.Field(i), v); err != nil {
+					return err
+				}
+			}
+			numRows++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	added := make([]arrow.Record, 1, 2)
+	added[0] = old
+	cols := make([]arrow.Array, len(bldrs))
+	for i, bldr := range bldrs {
+		cols[i] = bldr.NewArray()
+		defer cols[i].Release()
+	}
+	rec := array.NewRecord(schema, cols, numRows)
+	defer rec.Release()
+	added = append(added, rec)
+
+	merged, err := concatRecords(alloc, schema, added)
+	if err != nil {
+		return nil, 0, err
+	}
+	return merged, numRows, nil
+}
+
+// apply returns the table old without the rows matching the DELETE, for
+// each row of parameters.
+func (s *deleteStmt) apply(ctx context.Context, alloc memory.Allocator, old arrow.Record, params *paramRows) (arrow.Record, int64, error) {
+	cur := old
+	cur.Retain()
+	var numRows int64
+	err := params.each(func(rec arrow.Record, paramRow int) error {
+		keep := make([]int, 0)
+		if s.where != nil {
+			e := &env{schema: cur.Schema(), rec: cur, paramSchema: params.schema, params: rec, paramRow: paramRow}
+			where, err := e.compilePredicate(s.where)
+			if err != nil {
+				return err
+			}
+			for row := range int(cur.NumRows()) {
+				v, err := where.eval(row)
+				if err != nil {
+					return err
+				}
+				if v != true {
+					keep = append(keep, row)
+				}
+			}
+		}
+		if len(keep) == int(cur.NumRows()) {
+			return nil
+		}
+
+		next, err := takeRecord(ctx, alloc, cur, keep)
+		if err != nil {
+			return err
+		}
+		numRows += cur.NumRows() - next.NumRows()
+		cur.Release()
+		cur = next
+		return nil
+	})
+	if err != nil {
+		cur.Release()
+		return nil, 0, err
+	}
+	return cur, numRows, nil
+}
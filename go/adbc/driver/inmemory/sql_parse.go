@@ -0,0 +1,897 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenQuotedIdent
+	tokenNumber
+	tokenString
+	tokenParam
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	// text is the unquoted text of identifiers and strings
+	text string
+	// pos and end are the offsets of the token in the query
+	pos, end int
+}
+
+func syntaxError(format string, args ...any) error {
+	return errorf(adbc.StatusInvalidArgument, "42601", "syntax error: "+format, args...)
+}
+
+// twoCharOperators are the operators of two characters, which are
+// matched before those of one.
+var twoCharOperators = []string{"<=", ">=", "<>", "!=", "||"}
+
+const oneCharOperators = ",().*+-/%=<>;"
+
+// tokenize splits a query into tokens, ending with a tokenEOF.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	pos := 0
+	for {
+		// skip whitespace and comments
+		for pos < len(query) {
+			switch {
+			case unicode.IsSpace(rune(query[pos])):
+				pos++
+				continue
+			case strings.HasPrefix(query[pos:], "--"):
+				end := strings.IndexByte(query[pos:], '\n')
+				if end < 0 {
+					pos = len(query)
+				} else {
+					pos += end + 1
+				}
+				continue
+			case strings.HasPrefix(query[pos:], "/*"):
+				end := strings.Index(query[pos+2:], "*/")
+				if end < 0 {
+					return nil, syntaxError("unterminated comment")
+				}
+				pos += end + 4
+				continue
+			}
+			break
+		}
+		if pos == len(query) {
+			return append(tokens, token{kind: tokenEOF, pos: pos, end: pos}), nil
+		}
+
+		start := pos
+		c := query[pos]
+		switch {
+		case c == '_' || unicode.IsLetter(rune(c)):
+			for pos < len(query) && (query[pos] == '_' || query[pos] == '$' ||
+				unicode.IsLetter(rune(query[pos])) || unicode.IsDigit(rune(query[pos]))) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: query[start:pos], pos: start, end: pos})
+		case c == '"' || c == '\'':
+			text, end, ok := unquote(query, pos)
+			if !ok {
+				return nil, syntaxError("unterminated quoted string at offset %d", start)
+			}
+			kind := tokenString
+			if c == '"' {
+				kind = tokenQuotedIdent
+			}
+			pos = end
+			tokens = append(tokens, token{kind: kind, text: text, pos: start, end: pos})
+		case unicode.IsDigit(rune(c)) || (c == '.' && pos+1 < len(query) && unicode.IsDigit(rune(query[pos+1]))):
+			pos = scanNumber(query, pos)
+			tokens = append(tokens, token{kind: tokenNumber, text: query[start:pos], pos: start, end: pos})
+		case c == '?':
+			pos++
+			tokens = append(tokens, token{kind: tokenParam, text: "?", pos: start, end: pos})
+		default:
+			op := ""
+			for _, candidate := range twoCharOperators {
+				if strings.HasPrefix(query[pos:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" && strings.IndexByte(oneCharOperators, c) >= 0 {
+				op = query[pos : pos+1]
+			}
+			if op == "" {
+				return nil, syntaxError("unexpected character %q at offset %d", c, pos)
+			}
+			pos += len(op)
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: start, end: pos})
+		}
+	}
+}
+
+// unquote reads the string quoted by query[pos], in which the quote is
+// escaped by doubling it.
+func unquote(query string, pos int) (text string, end int, ok bool) {
+	quote := query[pos]
+	var b strings.Builder
+	for i := pos + 1; i < len(query); i++ {
+		if query[i] != quote {
+			b.WriteByte(query[i])
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, true
+	}
+	return "", 0, false
+}
+
+func scanNumber(query string, pos int) int {
+	digits := func() {
+		for pos < len(query) && unicode.IsDigit(rune(query[pos])) {
+			pos++
+		}
+	}
+	digits()
+	if pos < len(query) && query[pos] == '.' {
+		pos++
+		digits()
+	}
+	if pos < len(query) && (query[pos] == 'e' || query[pos] == 'E') {
+		exp := pos + 1
+		if exp < len(query) && (query[exp] == '+' || query[exp] == '-') {
+			exp++
+		}
+		if exp < len(query) && unicode.IsDigit(rune(query[exp])) {
+			pos = exp
+			digits()
+		}
+	}
+	return pos
+}
+
+// reservedWords can't be used as unquoted aliases.
+var reservedWords = map[string]bool{
+	"AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"DELETE": true, "DESC": true, "DROP": true, "FALSE": true, "FROM": true,
+	"IN": true, "INSERT": true, "INTO": true, "IS": true, "LIKE": true,
+	"LIMIT": true, "NOT": true, "NULL": true, "NULLS": true, "OR": true,
+	"ORDER": true, "SELECT": true, "TABLE": true, "TRUE": true,
+	"VALUES": true, "WHERE": true,
+}
+
+// query is a parsed query.
+type query struct {
+	stmt any
+	// numParams is the number of ? parameters
+	numParams int
+}
+
+type selectStmt struct {
+	items   []selectItem
+	from    *tableRef
+	where   expr
+	orderBy []orderKey
+	// limit is -1 without a LIMIT clause
+	limit int64
+}
+
+type selectItem struct {
+	// star items select all columns, and have no expression
+	star bool
+	expr expr
+	// name is the alias of the item, or its text
+	name    string
+	aliased bool
+}
+
+type orderKey struct {
+	expr       expr
+	desc       bool
+	nullsFirst bool
+}
+
+type insertStmt struct {
+	table tableRef
+	// columns is nil if the query doesn't list them
+	columns []ident
+	rows    [][]expr
+}
+
+type deleteStmt struct {
+	table tableRef
+	where expr
+}
+
+type dropStmt struct {
+	table    tableRef
+	ifExists bool
+}
+
+type parser struct {
+	query  string
+	tokens []token
+	pos    int
+	params int
+}
+
+// parseQuery parses a query of the SQL dialect of the driver.
+func parseQuery(text string) (*query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{query: text, tokens: tokens}
+
+	var stmt any
+	switch {
+	case p.keyword("SELECT"):
+		stmt, err = p.parseSelect()
+	case p.keyword("INSERT"):
+		stmt, err = p.parseInsert()
+	case p.keyword("DELETE"):
+		stmt, err = p.parseDelete()
+	case p.keyword("DROP"):
+		stmt, err = p.parseDrop()
+	default:
+		if p.peek().kind == tokenEOF {
+			return nil, syntaxError("empty query")
+		}
+		return nil, errorf(adbc.StatusNotImplemented, "0A000",
+			"unsupported statement %q: only SELECT, INSERT, DELETE and DROP TABLE are supported", p.peek().text)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.operator(";")
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.unexpected()
+	}
+	return &query{stmt: stmt, numParams: p.params}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether the next token is the given keyword.
+func (p *parser) isKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && strings.EqualFold(tok.text, kw)
+}
+
+// keyword consumes the next token if it is the given keyword.
+func (p *parser) keyword(kw string) bool {
+	if p.isKeyword(kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.keyword(kw) {
+		return p.expected(kw)
+	}
+	return nil
+}
+
+// operator consumes the next token if it is the given operator.
+func (p *parser) operator(op string) bool {
+	tok := p.peek()
+	if tok.kind == tokenOperator && tok.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectOperator(op string) error {
+	if !p.operator(op) {
+		return p.expected(op)
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	if tok.kind == tokenEOF {
+		return syntaxError("unexpected end of query")
+	}
+	return syntaxError("unexpected %q at offset %d", p.query[tok.pos:tok.end], tok.pos)
+}
+
+func (p *parser) expected(what string) error {
+	tok := p.peek()
+	if tok.kind == tokenEOF {
+		return syntaxError("expected %s at end of query", what)
+	}
+	return syntaxError("expected %s at offset %d, got %q", what, tok.pos, p.query[tok.pos:tok.end])
+}
+
+func (p *parser) parseIdent() (ident, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenQuotedIdent:
+		p.pos++
+		return ident{name: tok.text, quoted: true}, nil
+	case tok.kind == tokenIdent && !reservedWords[strings.ToUpper(tok.text)]:
+		p.pos++
+		return ident{name: tok.text}, nil
+	}
+	return ident{}, p.expected("identifier")
+}
+
+func (p *parser) parseTableRef() (tableRef, error) {
+	parts := make([]ident, 0, 3)
+	for {
+		id, err := p.parseIdent()
+		if err != nil {
+			return tableRef{}, err
+		}
+		parts = append(parts, id)
+		if len(parts) == 3 || !p.operator(".") {
+			break
+		}
+	}
+
+	ref := tableRef{table: parts[len(parts)-1]}
+	switch len(parts) {
+	case 2:
+		ref.dbSchema = &parts[0]
+	case 3:
+		ref.catalog, ref.dbSchema = &parts[0], &parts[1]
+	}
+	return ref, nil
+}
+
+func (p *parser) parseSelect() (*selectStmt, error) {
+	stmt := &selectStmt{limit: -1}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if !p.operator(",") {
+			break
+		}
+	}
+
+	if p.keyword("FROM") {
+		ref, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		stmt.from = &ref
+	}
+
+	if p.keyword("WHERE") {
+		where, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.where = where
+	}
+
+	if p.keyword("ORDER") {
+		if err := p.expectKeyword("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			key, err := p.parseOrderKey()
+			if err != nil {
+				return nil, err
+			}
+			stmt.orderBy = append(stmt.orderBy, key)
+			if !p.operator(",") {
+				break
+			}
+		}
+	}
+
+	if p.keyword("LIMIT") {
+		tok := p.next()
+		limit, err := strconv.ParseInt(tok.text, 10, 64)
+		if tok.kind != tokenNumber || err != nil || limit < 0 {
+			p.pos--
+			return nil, p.expected("non-negative integer")
+		}
+		stmt.limit = limit
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseSelectItem() (selectItem, error) {
+	if p.operator("*") {
+		return selectItem{star: true}, nil
+	}
+
+	start := p.peek().pos
+	e, err := p.parseExpr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{expr: e, name: p.query[start:p.tokens[p.pos-1].end]}
+	if col, ok := e.(*columnRef); ok {
+		item.name = col.name.name
+	}
+
+	explicit := p.keyword("AS")
+	if tok := p.peek(); explicit || tok.kind == tokenQuotedIdent ||
+		(tok.kind == tokenIdent && !reservedWords[strings.ToUpper(tok.text)]) {
+		alias, err := p.parseIdent()
+		if err != nil {
+			return selectItem{}, err
+		}
+		item.name, item.aliased = alias.name, true
+	}
+	return item, nil
+}
+
+func (p *parser) parseOrderKey() (orderKey, error) {
+	e, err := p.parseExpr()
+	if err != nil {
+		return orderKey{}, err
+	}
+	key := orderKey{expr: e}
+	if p.keyword("DESC") {
+		key.desc = true
+	} else {
+		p.keyword("ASC")
+	}
+	// like PostgreSQL, nulls are larger than any value by default
+	key.nullsFirst = key.desc
+	if p.keyword("NULLS") {
+		switch {
+		case p.keyword("FIRST"):
+			key.nullsFirst = true
+		case p.keyword("LAST"):
+			key.nullsFirst = false
+		default:
+			return orderKey{}, p.expected("FIRST or LAST")
+		}
+	}
+	return key, nil
+}
+
+func (p *parser) parseInsert() (*insertStmt, error) {
+	if err := p.expectKeyword("INTO"); err != nil {
+		return nil, err
+	}
+	ref, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &insertStmt{table: ref}
+
+	if p.operator("(") {
+		for {
+			col, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			stmt.columns = append(stmt.columns, col)
+			if !p.operator(",") {
+				break
+			}
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expectKeyword("VALUES"); err != nil {
+		return nil, err
+	}
+	for {
+		if err := p.expectOperator("("); err != nil {
+			return nil, err
+		}
+		var row []expr
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, e)
+			if !p.operator(",") {
+				break
+			}
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		stmt.rows = append(stmt.rows, row)
+		if !p.operator(",") {
+			break
+		}
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseDelete() (*deleteStmt, error) {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	ref, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &deleteStmt{table: ref}
+	if p.keyword("WHERE") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseDrop() (*dropStmt, error) {
+	if err := p.expectKeyword("TABLE"); err != nil {
+		return nil, err
+	}
+	stmt := &dropStmt{}
+	if p.keyword("IF") {
+		if err := p.expectKeyword("EXISTS"); err != nil {
+			return nil, err
+		}
+		stmt.ifExists = true
+	}
+	ref, err := p.parseTableRef()
+	if err != nil {
+		return nil, err
+	}
+	stmt.table = ref
+	return stmt, nil
+}
+
+// expr is an expression of a query.
+type expr interface{}
+
+type literal struct {
+	// value is nil, a bool, an int64, a float64 or a string
+	value any
+}
+
+type columnRef struct {
+	name ident
+}
+
+type paramRef struct {
+	// index is the 0-based position of the parameter in the query
+	index int
+}
+
+type unaryExpr struct {
+	op  string
+	arg expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type isNullExpr struct {
+	arg expr
+	not bool
+}
+
+type likeExpr struct {
+	arg, pattern expr
+	not          bool
+}
+
+type inExpr struct {
+	arg  expr
+	list []expr
+	not  bool
+}
+
+// The expression grammar, by increasing precedence: OR, AND, NOT,
+// comparisons and predicates, ||, + and -, *, / and %, unary minus.
+
+func (p *parser) parseExpr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.keyword("NOT") {
+		arg, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: "NOT", arg: arg}, nil
+	}
+	return p.parseComparison()
+}
+
+var comparisonOperators = map[string]string{
+	"=": "=", "<>": "<>", "!=": "<>", "<": "<", "<=": "<=", ">": ">", ">=": ">=",
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind == tokenOperator {
+		if op, ok := comparisonOperators[tok.text]; ok {
+			p.pos++
+			right, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			return &binaryExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	if p.keyword("IS") {
+		not := p.keyword("NOT")
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		return &isNullExpr{arg: left, not: not}, nil
+	}
+
+	not := p.keyword("NOT")
+	switch {
+	case p.keyword("LIKE"):
+		pattern, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &likeExpr{arg: left, pattern: pattern, not: not}, nil
+	case p.keyword("IN"):
+		if err := p.expectOperator("("); err != nil {
+			return nil, err
+		}
+		in := &inExpr{arg: left, not: not}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			in.list = append(in.list, e)
+			if !p.operator(",") {
+				break
+			}
+		}
+		if err := p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+		return in, nil
+	case p.keyword("BETWEEN"):
+		low, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		var between expr = &binaryExpr{op: "AND",
+			left:  &binaryExpr{op: ">=", left: left, right: low},
+			right: &binaryExpr{op: "<=", left: left, right: high}}
+		if not {
+			between = &unaryExpr{op: "NOT", arg: between}
+		}
+		return between, nil
+	case not:
+		return nil, p.expected("LIKE, IN or BETWEEN")
+	}
+	return left, nil
+}
+
+func (p *parser) parseConcat() (expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.operator("||") {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (expr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokenOperator || (op != "+" && op != "-") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseMultiplicative() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek().text
+		if p.peek().kind != tokenOperator || (op != "*" && op != "/" && op != "%") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	switch {
+	case p.operator("-"):
+		arg, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		// fold negative literals into constants
+		if lit, ok := arg.(*literal); ok {
+			switch v := lit.value.(type) {
+			case int64:
+				return &literal{value: -v}, nil
+			case float64:
+				return &literal{value: -v}, nil
+			}
+		}
+		return &unaryExpr{op: "-", arg: arg}, nil
+	case p.operator("+"):
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenNumber:
+		p.pos++
+		return parseNumber(tok.text)
+	case tokenString:
+		p.pos++
+		return &literal{value: tok.text}, nil
+	case tokenParam:
+		p.pos++
+		p.params++
+		return &paramRef{index: p.params - 1}, nil
+	case tokenOperator:
+		if p.operator("(") {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOperator(")"); err != nil {
+				return nil, err
+			}
+			return e, nil
+		}
+	case tokenIdent:
+		switch strings.ToUpper(tok.text) {
+		case "NULL":
+			p.pos++
+			return &literal{}, nil
+		case "TRUE":
+			p.pos++
+			return &literal{value: true}, nil
+		case "FALSE":
+			p.pos++
+			return &literal{value: false}, nil
+		}
+	}
+
+	if tok.kind != tokenIdent && tok.kind != tokenQuotedIdent {
+		return nil, p.unexpected()
+	}
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind == tokenOperator {
+		switch next.text {
+		case "(":
+			return nil, errorf(adbc.StatusNotImplemented, "0A000", "function %s is not supported", name.name)
+		case ".":
+			return nil, errorf(adbc.StatusNotImplemented, "0A000", "qualified column names are not supported")
+		}
+	}
+	return &columnRef{name: name}, nil
+}
+
+func parseNumber(text string) (expr, error) {
+	if !strings.ContainsAny(text, ".eE") {
+		if v, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return &literal{value: v}, nil
+		}
+		// fall back to a float for integers that don't fit
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return nil, syntaxError("invalid number %s", text)
+	}
+	return &literal{value: v}, nil
+}
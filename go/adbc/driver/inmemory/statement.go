@@ -0,0 +1,595 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package inmemory
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+type statementImpl struct {
+	driverbase.StatementImplBase
+	cnxn *connectionImpl
+
+	query string
+	// parsed caches the parsed query, once it is prepared or executed
+	parsed *query
+
+	targetTable    string
+	targetCatalog  string
+	targetDbSchema string
+	ingestMode     string
+	batchSize      int
+}
+
+func (st *statementImpl) Base() *driverbase.StatementImplBase {
+	return &st.StatementImplBase
+}
+
+func (st *statementImpl) Close() error {
+	return st.State.Close()
+}
+
+func (st *statementImpl) GetOption(key string) (string, error) {
+	switch key {
+	case adbc.OptionKeyIngestTargetTable:
+		return st.targetTable, nil
+	case adbc.OptionKeyIngestMode:
+		return st.ingestMode, nil
+	case adbc.OptionValueIngestTargetCatalog:
+		return st.targetCatalog, nil
+	case adbc.OptionValueIngestTargetDBSchema:
+		return st.targetDbSchema, nil
+	case adbc.OptionValueIngestTemporary:
+		return adbc.OptionValueDisabled, nil
+	case OptionIntBatchSize:
+		return strconv.Itoa(st.batchSize), nil
+	}
+	return st.StatementImplBase.GetOption(key)
+}
+
+func (st *statementImpl) GetOptionInt(key string) (int64, error) {
+	if key == OptionIntBatchSize {
+		return int64(st.batchSize), nil
+	}
+	return st.StatementImplBase.GetOptionInt(key)
+}
+
+func (st *statementImpl) SetOption(key string, val string) error {
+	switch key {
+	case adbc.OptionKeyIngestTargetTable:
+		if err := st.State.SetQuery(); err != nil {
+			return err
+		}
+		st.query, st.parsed = "", nil
+		st.targetTable = val
+	case adbc.OptionKeyIngestMode:
+		st.ingestMode = val
+	case adbc.OptionValueIngestTargetCatalog:
+		st.targetCatalog = val
+	case adbc.OptionValueIngestTargetDBSchema:
+		st.targetDbSchema = val
+	case adbc.OptionValueIngestTemporary:
+		if val == adbc.OptionValueEnabled {
+			return st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "temporary tables are not supported")
+		}
+	case OptionIntBatchSize:
+		size, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return st.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "could not parse '%s' as int for option '%s'", val, key)
+		}
+		return st.SetOptionInt(key, size)
+	default:
+		return st.StatementImplBase.SetOption(key, val)
+	}
+	return nil
+}
+
+func (st *statementImpl) SetOptionInt(key string, value int64) error {
+	if key == OptionIntBatchSize {
+		if value <= 0 {
+			return st.ErrorHelper.Errorf(adbc.StatusInvalidArgument, "invalid value %d for option '%s': must be positive", value, key)
+		}
+		st.batchSize = int(value)
+		return nil
+	}
+	return st.StatementImplBase.SetOptionInt(key, value)
+}
+
+func (st *statementImpl) SetSqlQuery(query string) error {
+	if err := st.State.SetQuery(); err != nil {
+		return err
+	}
+	st.query, st.parsed = query, nil
+	st.targetTable = ""
+	return nil
+}
+
+func (st *statementImpl) SetSubstraitPlan(plan []byte) error {
+	return st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "Substrait plans are not supported")
+}
+
+// parse returns the parsed query of the statement.
+func (st *statementImpl) parse() (*query, error) {
+	if st.parsed == nil {
+		q, err := parseQuery(st.query)
+		if err != nil {
+			return nil, toADBCError(&st.ErrorHelper, err)
+		}
+		st.parsed = q
+	}
+	return st.parsed, nil
+}
+
+func (st *statementImpl) Prepare(ctx context.Context) error {
+	if err := st.State.Prepare(); err != nil {
+		return err
+	}
+	if st.targetTable != "" {
+		return nil
+	}
+	if _, err := st.parse(); err != nil {
+		// the query stays set, but isn't prepared
+		_ = st.State.SetQuery()
+		return err
+	}
+	return nil
+}
+
+func (st *statementImpl) Bind(ctx context.Context, values arrow.Record) error {
+	return st.State.Bind(values)
+}
+
+func (st *statementImpl) BindStream(ctx context.Context, stream array.RecordReader) error {
+	return st.State.BindStream(stream)
+}
+
+func (st *statementImpl) GetParameterSchema() (*arrow.Schema, error) {
+	if st.targetTable != "" {
+		return nil, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "GetParameterSchema is not supported for bulk ingestion")
+	}
+	if st.query == "" {
+		return nil, st.ErrorHelper.Errorf(adbc.StatusInvalidState, "cannot get the parameter schema without a query")
+	}
+	q, err := st.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// parameters take the type of the values bound to them
+	fields := make([]arrow.Field, q.numParams)
+	for i := range fields {
+		fields[i] = arrow.Field{Name: strconv.Itoa(i), Type: arrow.Null, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// result is the outcome of running a statement.
+type result struct {
+	schema *arrow.Schema
+	// rec holds the rows of a SELECT; it is nil for other statements
+	rec      arrow.Record
+	affected int64
+}
+
+func (r *result) release() {
+	if r.rec != nil {
+		r.rec.Release()
+	}
+}
+
+// batches splits the rows of the result into records of at most size rows.
+func (r *result) batches(size int) []arrow.Record {
+	if r.rec == nil {
+		return nil
+	}
+	n := r.rec.NumRows()
+	batches := make([]arrow.Record, 0, (n+int64(size)-1)/int64(size))
+	for offset := int64(0); offset < n; offset += int64(size) {
+		batches = append(batches, r.rec.NewSlice(offset, min(offset+int64(size), n)))
+	}
+	return batches
+}
+
+func releaseAll(recs []arrow.Record) {
+	for _, rec := range recs {
+		rec.Release()
+	}
+}
+
+// run runs the statement with the parameters bound to it.
+func (st *statementImpl) run(ctx context.Context) (*result, error) {
+	bound, stream := st.State.TakeBound()
+	if st.targetTable != "" {
+		affected, err := st.ingest(ctx, bound, stream)
+		if err != nil {
+			return nil, toADBCError(&st.ErrorHelper, err)
+		}
+		return &result{schema: arrow.NewSchema(nil, nil), affected: affected}, nil
+	}
+
+	params, err := readParams(bound, stream)
+	if err != nil {
+		return nil, st.ErrorHelper.Errorf(adbc.StatusIO, "failed to read the parameters: %s", err)
+	}
+	defer params.release()
+
+	q, err := st.parse()
+	if err != nil {
+		return nil, err
+	}
+	if err := params.check(q); err != nil {
+		return nil, toADBCError(&st.ErrorHelper, err)
+	}
+
+	res, err := st.runQuery(ctx, q, params)
+	if err != nil {
+		return nil, toADBCError(&st.ErrorHelper, err)
+	}
+	return res, nil
+}
+
+func (st *statementImpl) runQuery(ctx context.Context, q *query, params *paramRows) (*result, error) {
+	alloc := st.cnxn.Alloc
+	empty := arrow.NewSchema(nil, nil)
+
+	switch stmt := q.stmt.(type) {
+	case *selectStmt:
+		e, release, err := st.selectEnv(stmt, params.schema)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		plan, err := stmt.plan(e)
+		if err != nil {
+			return nil, err
+		}
+
+		var parts []arrow.Record
+		defer func() { releaseAll(parts) }()
+		err = params.each(func(rec arrow.Record, row int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			e.params, e.paramRow = rec, row
+			part, err := stmt.run(ctx, alloc, e)
+			if err != nil {
+				return err
+			}
+			parts = append(parts, part)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		rec, err := concatRecords(alloc, plan.schema, parts)
+		if err != nil {
+			return nil, err
+		}
+		return &result{schema: plan.schema, rec: rec, affected: -1}, nil
+
+	case *insertStmt:
+		if err := stmt.table.check(); err != nil {
+			return nil, err
+		}
+		var affected int64
+		err := st.cnxn.writeTable(stmt.table.table, func(old arrow.Record) (rec arrow.Record, err error) {
+			if old == nil {
+				return nil, tableNotFound(stmt.table.table.name)
+			}
+			rec, affected, err = stmt.apply(alloc, old, params)
+			return rec, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &result{schema: empty, affected: affected}, nil
+
+	case *deleteStmt:
+		if err := stmt.table.check(); err != nil {
+			return nil, err
+		}
+		var affected int64
+		err := st.cnxn.writeTable(stmt.table.table, func(old arrow.Record) (rec arrow.Record, err error) {
+			if old == nil {
+				return nil, tableNotFound(stmt.table.table.name)
+			}
+			rec, affected, err = stmt.apply(ctx, alloc, old, params)
+			return rec, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &result{schema: empty, affected: affected}, nil
+
+	case *dropStmt:
+		if err := stmt.table.check(); err != nil {
+			return nil, err
+		}
+		err := st.cnxn.writeTable(stmt.table.table, func(old arrow.Record) (arrow.Record, error) {
+			if old == nil && !stmt.ifExists {
+				return nil, tableNotFound(stmt.table.table.name)
+			}
+			return nil, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &result{schema: empty, affected: 0}, nil
+	}
+	return nil, errorf(adbc.StatusInternal, "", "unknown statement %T", q.stmt)
+}
+
+// selectEnv returns the environment to compile a SELECT in, with the
+// table it reads from, and the function that releases the table.
+func (st *statementImpl) selectEnv(stmt *selectStmt, paramSchema *arrow.Schema) (*env, func(), error) {
+	e := &env{paramSchema: paramSchema}
+	if stmt.from == nil {
+		return e, func() {}, nil
+	}
+	if err := stmt.from.check(); err != nil {
+		return nil, nil, err
+	}
+
+	t := st.cnxn.readTables()
+	name, ok := stmt.from.table.lookup(t)
+	if !ok {
+		t.release()
+		return nil, nil, tableNotFound(stmt.from.table.name)
+	}
+	e.rec = t[name]
+	e.schema = e.rec.Schema()
+	return e, t.release, nil
+}
+
+func (st *statementImpl) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	done, err := st.State.BeginExecute("ExecuteQuery")
+	if err != nil {
+		return nil, -1, err
+	}
+	defer done()
+
+	// the results are materialized, so the context isn't used afterwards
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	res, err := st.run(ctx)
+	if err != nil {
+		return nil, -1, err
+	}
+	defer res.release()
+
+	batches := res.batches(st.batchSize)
+	defer releaseAll(batches)
+	rdr, err := array.NewRecordReader(res.schema, batches)
+	if err != nil {
+		return nil, -1, st.ErrorHelper.Errorf(adbc.StatusInternal, "failed to create the reader: %s", err)
+	}
+	return st.State.TrackReader(rdr), res.affected, nil
+}
+
+func (st *statementImpl) ExecuteUpdate(ctx context.Context) (int64, error) {
+	done, err := st.State.BeginExecute("ExecuteUpdate")
+	if err != nil {
+		return -1, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	res, err := st.run(ctx)
+	if err != nil {
+		return -1, err
+	}
+	res.release()
+	return res.affected, nil
+}
+
+// ExecutePartitions runs the query, and serializes each batch of its
+// results as an Arrow IPC stream, which is read back by ReadPartition.
+func (st *statementImpl) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	done, err := st.State.BeginExecute("ExecutePartitions")
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer done()
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	res, err := st.run(ctx)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer res.release()
+
+	batches := res.batches(st.batchSize)
+	defer releaseAll(batches)
+	partitions := adbc.Partitions{PartitionIDs: make([][]byte, 0, len(batches))}
+	for _, batch := range batches {
+		partition, err := serialize(st.cnxn.Alloc, batch)
+		if err != nil {
+			return nil, adbc.Partitions{}, -1, st.ErrorHelper.Errorf(adbc.StatusInternal, "failed to serialize the results: %s", err)
+		}
+		partitions.PartitionIDs = append(partitions.PartitionIDs, partition)
+	}
+	partitions.NumPartitions = uint64(len(partitions.PartitionIDs))
+	return res.schema, partitions, res.affected, nil
+}
+
+func serialize(alloc memory.Allocator, rec arrow.Record) ([]byte, error) {
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(rec.Schema()), ipc.WithAllocator(alloc))
+	if err := w.Write(rec); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExecuteSchema returns the schema of the results of a SELECT, without
+// running it. Parameters take the types of the values bound to them, and
+// the null type when none are bound.
+func (st *statementImpl) ExecuteSchema(ctx context.Context) (*arrow.Schema, error) {
+	done, err := st.State.BeginExecute("ExecuteSchema")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	if st.targetTable != "" {
+		return nil, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "ExecuteSchema is not supported for bulk ingestion")
+	}
+	q, err := st.parse()
+	if err != nil {
+		return nil, err
+	}
+	stmt, ok := q.stmt.(*selectStmt)
+	if !ok {
+		return arrow.NewSchema(nil, nil), nil
+	}
+
+	var paramSchema *arrow.Schema
+	switch bound, stream := st.State.Bound(); {
+	case bound != nil:
+		paramSchema = bound.Schema()
+	case stream != nil:
+		paramSchema = stream.Schema()
+	}
+	e, release, err := st.selectEnv(stmt, paramSchema)
+	if err != nil {
+		return nil, toADBCError(&st.ErrorHelper, err)
+	}
+	defer release()
+	plan, err := stmt.plan(e)
+	if err != nil {
+		return nil, toADBCError(&st.ErrorHelper, err)
+	}
+	return plan.schema, nil
+}
+
+// paramRows are the bound parameters, each row of which runs the
+// statement once.
+type paramRows struct {
+	// schema is nil if no parameters are bound
+	schema  *arrow.Schema
+	batches []arrow.Record
+}
+
+// readParams takes ownership of the bound parameters.
+func readParams(bound arrow.Record, stream array.RecordReader) (*paramRows, error) {
+	switch {
+	case bound != nil:
+		return &paramRows{schema: bound.Schema(), batches: []arrow.Record{bound}}, nil
+	case stream == nil:
+		return &paramRows{}, nil
+	}
+
+	defer stream.Release()
+	params := &paramRows{schema: stream.Schema()}
+	for stream.Next() {
+		rec := stream.Record()
+		rec.Retain()
+		params.batches = append(params.batches, rec)
+	}
+	if err := stream.Err(); err != nil {
+		params.release()
+		return nil, err
+	}
+	return params, nil
+}
+
+func (p *paramRows) release() {
+	releaseAll(p.batches)
+	p.batches = nil
+}
+
+// check returns an error if the parameters don't fit the query.
+func (p *paramRows) check(q *query) error {
+	switch {
+	case p.schema == nil && q.numParams > 0:
+		return errorf(adbc.StatusInvalidState, "07002", "the query has %d parameters, but none are bound", q.numParams)
+	case p.schema != nil && p.schema.NumFields() != q.numParams:
+		return errorf(adbc.StatusInvalidArgument, "07001", "the query has %d parameters, but %d are bound",
+			q.numParams, p.schema.NumFields())
+	}
+	return nil
+}
+
+// each calls fn with each row of parameters, or once with no parameters
+// if none are bound.
+func (p *paramRows) each(fn func(rec arrow.Record, row int) error) error {
+	if p.schema == nil {
+		return fn(nil, 0)
+	}
+	for _, rec := range p.batches {
+		for row := range int(rec.NumRows()) {
+			if err := fn(rec, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// concatRecords concatenates recs, which have the same columns as
+// schema, into a single record.
+func concatRecords(alloc memory.Allocator, schema *arrow.Schema, recs []arrow.Record) (arrow.Record, error) {
+	if len(recs) == 1 && recs[0].Schema().Equal(schema) {
+		recs[0].Retain()
+		return recs[0], nil
+	}
+
+	var numRows int64
+	for _, rec := range recs {
+		numRows += rec.NumRows()
+	}
+	cols := make([]arrow.Array, 0, schema.NumFields())
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+	for i, field := range schema.Fields() {
+		var col arrow.Array
+		if len(recs) == 0 {
+			bldr := array.NewBuilder(alloc, field.Type)
+			col = bldr.NewArray()
+			bldr.Release()
+		} else {
+			chunks := make([]arrow.Array, len(recs))
+			for j, rec := range recs {
+				chunks[j] = rec.Column(i)
+			}
+			var err error
+			if col, err = array.Concatenate(chunks, alloc); err != nil {
+				return nil, err
+			}
+		}
+		cols = append(cols, col)
+	}
+	return array.NewRecord(schema, cols, numRows), nil
+}
@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// cancelSet tracks the in-flight operations of a connection or statement
+// so that they can all be cancelled from another goroutine.
+type cancelSet struct {
+	mu      sync.Mutex
+	next    uint64
+	pending map[uint64]context.CancelFunc
+}
+
+func newCancelSet() *cancelSet {
+	return &cancelSet{pending: make(map[uint64]context.CancelFunc)}
+}
+
+// withCancel derives a cancellable context from ctx and registers it
+// until the returned function is called, which must happen once the
+// operation is done.
+func (s *cancelSet) withCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s == nil {
+		// the base was not constructed through New*ImplBase, so there
+		// is nothing that could cancel the operation later.
+		return context.WithCancel(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.next
+	s.next++
+	s.pending[id] = cancel
+	return ctx, func() {
+		cancel()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.pending, id)
+	}
+}
+
+// cancelAll cancels every registered context.
+func (s *cancelSet) cancelAll() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[uint64]context.CancelFunc)
+	s.mu.Unlock()
+
+	for _, cancel := range pending {
+		cancel()
+	}
+}
+
+// CancelOnRelease returns rdr as a reader that calls cancel once it is
+// released, for readers that keep using the context of the operation
+// that returned them. If rdr is nil, cancel is called right away.
+func CancelOnRelease(rdr array.RecordReader, cancel context.CancelFunc) array.RecordReader {
+	if rdr == nil {
+		cancel()
+		return nil
+	}
+	r := &cancelOnReleaseReader{RecordReader: rdr, cancel: cancel}
+	r.refs.Store(1)
+	return r
+}
+
+type cancelOnReleaseReader struct {
+	array.RecordReader
+	refs   atomic.Int64
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnReleaseReader) Retain() {
+	r.refs.Add(1)
+}
+
+func (r *cancelOnReleaseReader) Release() {
+	if r.refs.Add(-1) == 0 {
+		r.RecordReader.Release()
+		r.cancel()
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/apache/arrow-adbc/go/adbc"
@@ -50,6 +51,9 @@ const (
 // vendor-specific functionality.
 type ConnectionImpl interface {
 	adbc.Connection
+	adbc.ConnectionAttachQuery
+	adbc.ConnectionCancel
+	adbc.ConnectionGetStatistics
 	adbc.GetSetOptions
 	adbc.OTelTracing
 	Base() *ConnectionImplBase
@@ -100,10 +104,26 @@ type DbObjectsEnumerator interface {
 	GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]TableInfo, error)
 }
 
+// StatisticsEnumerator is an interface that drivers may implement to simplify the
+// implementation of adbc.ConnectionGetStatistics. The driver only lists the statistics
+// it knows about as a flat list, and the driverbase filters them by the search
+// patterns, groups them by catalog and schema, and resolves their names to the
+// standard or custom statistic keys. The filters passed to EnumerateStatistics may be
+// used to narrow the lookup, but need not be applied exactly.
+type StatisticsEnumerator interface {
+	EnumerateStatistics(ctx context.Context, catalogFilter, dbSchemaFilter, tableFilter *string, approximate bool) ([]StatisticsEntry, error)
+	// StatisticNames returns the custom statistics defined by the driver. Their
+	// keys must be at least 1024.
+	StatisticNames() []StatisticName
+}
+
 // Connection is the interface satisfied by the result of the NewConnection constructor,
 // given that an input is provided satisfying the ConnectionImpl interface.
 type Connection interface {
 	adbc.Connection
+	adbc.ConnectionAttachQuery
+	adbc.ConnectionCancel
+	adbc.ConnectionGetStatistics
 	adbc.GetSetOptions
 }
 
@@ -116,11 +136,15 @@ type ConnectionImplBase struct {
 	DriverInfo  *DriverInfo
 	Logger      *slog.Logger
 	Tracer      trace.Tracer
+	// RetryPolicy is the policy of the database when the connection
+	// is opened; it can be changed with the retry options.
+	RetryPolicy RetryPolicy
 
 	Autocommit bool
 	Closed     bool
 
 	traceParent string
+	cancels     *cancelSet
 }
 
 // NewConnectionImplBase instantiates ConnectionImplBase.
@@ -134,9 +158,11 @@ func NewConnectionImplBase(database *DatabaseImplBase) ConnectionImplBase {
 		DriverInfo:  database.DriverInfo,
 		Logger:      database.Logger,
 		Tracer:      database.Tracer,
+		RetryPolicy: database.RetryPolicy,
 		Autocommit:  true,
 		Closed:      false,
 		traceParent: database.traceParent,
+		cancels:     newCancelSet(),
 	}
 }
 
@@ -144,6 +170,22 @@ func (base *ConnectionImplBase) Base() *ConnectionImplBase {
 	return base
 }
 
+// WithCancel returns a copy of ctx that will be cancelled when Cancel is
+// called on the connection, until the returned function is called once
+// the operation is done. The metadata methods provided by driverbase
+// already use it; drivers should use it for any other long-running work,
+// and CancelOnRelease for readers that outlive the call.
+func (base *ConnectionImplBase) WithCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	return base.cancels.withCancel(ctx)
+}
+
+// Cancel cancels every in-progress operation started with a context from
+// WithCancel.
+func (base *ConnectionImplBase) Cancel() error {
+	base.cancels.cancelAll()
+	return nil
+}
+
 func (base *ConnectionImplBase) Commit(ctx context.Context) error {
 	return base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "Commit")
 }
@@ -240,6 +282,18 @@ func (base *ConnectionImplBase) ReadPartition(ctx context.Context, serializedPar
 	return nil, base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "ReadPartition")
 }
 
+func (base *ConnectionImplBase) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	return nil, base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "AttachQuery")
+}
+
+func (base *ConnectionImplBase) GetStatistics(ctx context.Context, catalog, dbSchema, tableName *string, approximate bool) (array.RecordReader, error) {
+	return nil, base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "GetStatistics")
+}
+
+func (base *ConnectionImplBase) GetStatisticNames(ctx context.Context) (array.RecordReader, error) {
+	return nil, base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "GetStatisticNames")
+}
+
 func (base *ConnectionImplBase) GetOption(key string) (string, error) {
 	switch strings.ToLower(key) {
 	case adbc.OptionKeyTelemetryTraceParent:
@@ -286,11 +340,12 @@ func (base *ConnectionImplBase) SetOptionInt(key string, val int64) error {
 type connection struct {
 	ConnectionImpl
 
-	dbObjectsEnumerator DbObjectsEnumerator
-	currentNamespacer   CurrentNamespacer
-	driverInfoPreparer  DriverInfoPreparer
-	tableTypeLister     TableTypeLister
-	autocommitSetter    AutocommitSetter
+	dbObjectsEnumerator  DbObjectsEnumerator
+	currentNamespacer    CurrentNamespacer
+	driverInfoPreparer   DriverInfoPreparer
+	tableTypeLister      TableTypeLister
+	autocommitSetter     AutocommitSetter
+	statisticsEnumerator StatisticsEnumerator
 
 	concurrency int
 }
@@ -351,6 +406,14 @@ func (b *ConnectionBuilder) WithTableTypeLister(helper TableTypeLister) *Connect
 	return b
 }
 
+func (b *ConnectionBuilder) WithStatisticsEnumerator(helper StatisticsEnumerator) *ConnectionBuilder {
+	if b == nil {
+		panic("nil ConnectionBuilder: cannot reuse after calling Connection()")
+	}
+	b.connection.statisticsEnumerator = helper
+	return b
+}
+
 func (b *ConnectionBuilder) Connection() Connection {
 	conn := b.connection
 	b.connection = nil
@@ -393,6 +456,12 @@ func getInitialSpanAttributes(driverInfo *DriverInfo) []attribute.KeyValue {
 
 // GetObjects implements Connection.
 func (cnxn *connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth, catalog *string, dbSchema *string, tableName *string, columnName *string, tableType []string) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	rdr, err := cnxn.getObjects(ctx, depth, catalog, dbSchema, tableName, columnName, tableType)
+	return CancelOnRelease(rdr, cancel), err
+}
+
+func (cnxn *connection) getObjects(ctx context.Context, depth adbc.ObjectDepth, catalog *string, dbSchema *string, tableName *string, columnName *string, tableType []string) (array.RecordReader, error) {
 	helper := cnxn.dbObjectsEnumerator
 
 	// If the dbObjectsEnumerator has not been set, then the driver implementer has elected to provide their own GetObjects implementation
@@ -400,7 +469,10 @@ func (cnxn *connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth,
 		return cnxn.ConnectionImpl.GetObjects(ctx, depth, catalog, dbSchema, tableName, columnName, tableType)
 	}
 
-	catalogs, err := helper.GetCatalogs(ctx, catalog)
+	retry := cnxn.Base().RetryPolicy
+	catalogs, err := RetryValue(ctx, retry, true, func(ctx context.Context) ([]string, error) {
+		return helper.GetCatalogs(ctx, catalog)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +500,9 @@ func (cnxn *connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth,
 	for info := range addCatalogCh {
 		info := info
 		gSchemas.Go(func() error {
-			dbSchemas, err := helper.GetDBSchemasForCatalog(ctxSchemas, ValueOrZero(info.CatalogName), dbSchema)
+			dbSchemas, err := RetryValue(ctxSchemas, retry, true, func(ctx context.Context) ([]string, error) {
+				return helper.GetDBSchemasForCatalog(ctx, ValueOrZero(info.CatalogName), dbSchema)
+			})
 			if err != nil {
 				return err
 			}
@@ -465,7 +539,9 @@ func (cnxn *connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth,
 				catalogDbSchema := catalogDbSchema
 				gTablesInner.Go(func() error {
 					includeColumns := depth == adbc.ObjectDepthColumns
-					tables, err := helper.GetTablesForDBSchema(ctxTablesInner, ValueOrZero(info.CatalogName), ValueOrZero(catalogDbSchema.DbSchemaName), tableName, columnName, includeColumns)
+					tables, err := RetryValue(ctxTablesInner, retry, true, func(ctx context.Context) ([]TableInfo, error) {
+						return helper.GetTablesForDBSchema(ctx, ValueOrZero(info.CatalogName), ValueOrZero(catalogDbSchema.DbSchemaName), tableName, columnName, includeColumns)
+					})
 					if err != nil {
 						return err
 					}
@@ -498,6 +574,10 @@ func (cnxn *connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth,
 }
 
 func (cnxn *connection) GetOption(key string) (string, error) {
+	if isRetryOption(key) {
+		return cnxn.Base().RetryPolicy.get(key), nil
+	}
+
 	switch key {
 	case adbc.OptionKeyAutoCommit:
 		if cnxn.Base().Autocommit {
@@ -525,7 +605,28 @@ func (cnxn *connection) GetOption(key string) (string, error) {
 	return cnxn.ConnectionImpl.GetOption(key)
 }
 
+func (cnxn *connection) GetOptionDouble(key string) (float64, error) {
+	if isRetryOption(key) {
+		return strconv.ParseFloat(cnxn.Base().RetryPolicy.get(key), 64)
+	}
+	return cnxn.ConnectionImpl.GetOptionDouble(key)
+}
+
+func (cnxn *connection) GetOptionInt(key string) (int64, error) {
+	if isRetryOption(key) {
+		return strconv.ParseInt(cnxn.Base().RetryPolicy.get(key), 10, 64)
+	}
+	return cnxn.ConnectionImpl.GetOptionInt(key)
+}
+
 func (cnxn *connection) SetOption(key string, val string) error {
+	if err := cnxn.Base().DriverInfo.ValidateOption(OptionLevelConnection, key, &val); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&cnxn.Base().RetryPolicy, &cnxn.Base().ErrorHelper, OptionLevelConnection, key, val); handled {
+		return err
+	}
+
 	switch key {
 	case adbc.OptionKeyAutoCommit:
 		if cnxn.autocommitSetter != nil {
@@ -560,9 +661,43 @@ func (cnxn *connection) SetOption(key string, val string) error {
 	return cnxn.ConnectionImpl.SetOption(key, val)
 }
 
+func (cnxn *connection) SetOptionBytes(key string, val []byte) error {
+	if err := cnxn.Base().DriverInfo.ValidateOption(OptionLevelConnection, key, nil); err != nil {
+		return err
+	}
+	return cnxn.ConnectionImpl.SetOptionBytes(key, val)
+}
+
+func (cnxn *connection) SetOptionDouble(key string, val float64) error {
+	str := strconv.FormatFloat(val, 'g', -1, 64)
+	if err := cnxn.Base().DriverInfo.ValidateOption(OptionLevelConnection, key, &str); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&cnxn.Base().RetryPolicy, &cnxn.Base().ErrorHelper, OptionLevelConnection, key, str); handled {
+		return err
+	}
+	return cnxn.ConnectionImpl.SetOptionDouble(key, val)
+}
+
+func (cnxn *connection) SetOptionInt(key string, val int64) error {
+	str := strconv.FormatInt(val, 10)
+	if err := cnxn.Base().DriverInfo.ValidateOption(OptionLevelConnection, key, &str); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&cnxn.Base().RetryPolicy, &cnxn.Base().ErrorHelper, OptionLevelConnection, key, str); handled {
+		return err
+	}
+	return cnxn.ConnectionImpl.SetOptionInt(key, val)
+}
+
 func (cnxn *connection) GetInfo(ctx context.Context, infoCodes []adbc.InfoCode) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	defer cancel()
 	if cnxn.driverInfoPreparer != nil {
-		if err := cnxn.driverInfoPreparer.PrepareDriverInfo(ctx, infoCodes); err != nil {
+		err := Retry(ctx, cnxn.Base().RetryPolicy, true, func(ctx context.Context) error {
+			return cnxn.driverInfoPreparer.PrepareDriverInfo(ctx, infoCodes)
+		})
+		if err != nil {
 			return nil, err
 		}
 	}
@@ -571,11 +706,14 @@ func (cnxn *connection) GetInfo(ctx context.Context, infoCodes []adbc.InfoCode)
 }
 
 func (cnxn *connection) GetTableTypes(ctx context.Context) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
 	if cnxn.tableTypeLister == nil {
-		return cnxn.ConnectionImpl.GetTableTypes(ctx)
+		rdr, err := cnxn.ConnectionImpl.GetTableTypes(ctx)
+		return CancelOnRelease(rdr, cancel), err
 	}
+	defer cancel()
 
-	tableTypes, err := cnxn.tableTypeLister.ListTableTypes(ctx)
+	tableTypes, err := RetryValue(ctx, cnxn.Base().RetryPolicy, true, cnxn.tableTypeLister.ListTableTypes)
 	if err != nil {
 		return nil, err
 	}
@@ -589,10 +727,76 @@ func (cnxn *connection) GetTableTypes(ctx context.Context) (array.RecordReader,
 	return array.NewRecordReader(adbc.TableTypesSchema, []arrow.Record{final})
 }
 
+func (cnxn *connection) GetTableSchema(ctx context.Context, catalog *string, dbSchema *string, tableName string) (*arrow.Schema, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	defer cancel()
+	return cnxn.ConnectionImpl.GetTableSchema(ctx, catalog, dbSchema, tableName)
+}
+
+func (cnxn *connection) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	rdr, err := cnxn.ConnectionImpl.ReadPartition(ctx, serializedPartition)
+	return CancelOnRelease(rdr, cancel), err
+}
+
+func (cnxn *connection) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	rdr, err := cnxn.ConnectionImpl.AttachQuery(ctx, handle)
+	return CancelOnRelease(rdr, cancel), err
+}
+
+// GetStatistics implements Connection.
+func (cnxn *connection) GetStatistics(ctx context.Context, catalog, dbSchema, tableName *string, approximate bool) (array.RecordReader, error) {
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	helper := cnxn.statisticsEnumerator
+
+	// If the statisticsEnumerator has not been set, then the driver implementer has elected to provide their own GetStatistics implementation
+	if helper == nil {
+		rdr, err := cnxn.ConnectionImpl.GetStatistics(ctx, catalog, dbSchema, tableName, approximate)
+		return CancelOnRelease(rdr, cancel), err
+	}
+	defer cancel()
+
+	filter, err := newStatisticsFilter(catalog, dbSchema, tableName)
+	if err != nil {
+		return nil, cnxn.Base().ErrorHelper.Errorf(adbc.StatusInvalidArgument, "GetStatistics: %s", err)
+	}
+
+	entries, err := RetryValue(ctx, cnxn.Base().RetryPolicy, true, func(ctx context.Context) ([]StatisticsEntry, error) {
+		return helper.EnumerateStatistics(ctx, catalog, dbSchema, tableName, approximate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs, err := filter.group(entries, helper.StatisticNames())
+	if err != nil {
+		return nil, cnxn.Base().ErrorHelper.Errorf(adbc.StatusInternal, "GetStatistics: %s", err)
+	}
+
+	rdr, err := BuildGetStatisticsRecordReader(cnxn.Base().Alloc, catalogs)
+	if err != nil {
+		return nil, cnxn.Base().ErrorHelper.Errorf(adbc.StatusInternal, "GetStatistics: %s", err)
+	}
+	return rdr, nil
+}
+
+// GetStatisticNames implements Connection.
+func (cnxn *connection) GetStatisticNames(ctx context.Context) (array.RecordReader, error) {
+	if cnxn.statisticsEnumerator == nil {
+		ctx, cancel := cnxn.Base().WithCancel(ctx)
+		rdr, err := cnxn.ConnectionImpl.GetStatisticNames(ctx)
+		return CancelOnRelease(rdr, cancel), err
+	}
+	return BuildGetStatisticNamesRecordReader(cnxn.Base().Alloc, cnxn.statisticsEnumerator.StatisticNames())
+}
+
 func (cnxn *connection) Commit(ctx context.Context) error {
 	if cnxn.Base().Autocommit {
 		return cnxn.Base().ErrorHelper.Errorf(adbc.StatusInvalidState, ConnectionMessageCannotCommit)
 	}
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	defer cancel()
 	return cnxn.ConnectionImpl.Commit(ctx)
 }
 
@@ -600,6 +804,8 @@ func (cnxn *connection) Rollback(ctx context.Context) error {
 	if cnxn.Base().Autocommit {
 		return cnxn.Base().ErrorHelper.Errorf(adbc.StatusInvalidState, ConnectionMessageCannotRollback)
 	}
+	ctx, cancel := cnxn.Base().WithCancel(ctx)
+	defer cancel()
 	return cnxn.ConnectionImpl.Rollback(ctx)
 }
 
@@ -739,8 +945,16 @@ CATALOGLOOP:
 			if err := json.Unmarshal(b, bldr); err != nil {
 				return nil, err
 			}
-		case err := <-errCh:
-			return nil, err
+		case err, ok := <-errCh:
+			if !ok {
+				// a closed channel reports no error, but there may still
+				// be catalogs to read
+				errCh = nil
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
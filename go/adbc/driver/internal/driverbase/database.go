@@ -22,11 +22,13 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -94,6 +96,7 @@ type Database interface {
 	adbc.GetSetOptions
 	adbc.DatabaseLogging
 	adbc.OTelTracingInit
+	adbc.OptionCatalog
 }
 
 // DatabaseImplBase is a struct that provides default implementations of the
@@ -105,6 +108,7 @@ type DatabaseImplBase struct {
 	DriverInfo  *DriverInfo
 	Logger      *slog.Logger
 	Tracer      trace.Tracer
+	RetryPolicy RetryPolicy
 
 	tracerShutdownFunc func(context.Context) error
 	traceParent        string
@@ -121,6 +125,7 @@ func NewDatabaseImplBase(ctx context.Context, driver *DriverImplBase) (DatabaseI
 		DriverInfo:  driver.DriverInfo,
 		Logger:      nilLogger(),
 		Tracer:      nilTracer(),
+		RetryPolicy: DefaultRetryPolicy(),
 	}
 	err := database.InitTracing(ctx, driver.DriverInfo.GetName(), getDriverVersion(driver.DriverInfo))
 	return database, err
@@ -162,8 +167,10 @@ func (base *DatabaseImplBase) SetOptionInt(key string, val int64) error {
 	return base.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s'", DatabaseMessageOptionUnknown, key)
 }
 
+// Close closes the driver's database, then shuts down the tracer, which
+// is skipped by drivers that override Close without calling the base.
 func (base *database) Close() error {
-	return base.Base().Close()
+	return errors.Join(base.DatabaseImpl.Close(), base.Base().Close())
 }
 
 func (base *DatabaseImplBase) Close() (err error) {
@@ -187,6 +194,30 @@ func (base *DatabaseImplBase) SetOptions(options map[string]string) error {
 	return nil
 }
 
+// SetRetryOptions applies the options of the retry policy passed to
+// NewDatabase, and removes them from options. Drivers should call it
+// before applying the other options, as they are not set through the
+// wrapper.
+func (base *DatabaseImplBase) SetRetryOptions(options map[string]string) error {
+	for key, val := range options {
+		handled, err := setRetryOption(&base.RetryPolicy, &base.ErrorHelper, OptionLevelDatabase, key, val)
+		if err != nil {
+			return err
+		}
+		if handled {
+			delete(options, key)
+		}
+	}
+	return nil
+}
+
+// ValidateOptions checks the options passed to NewDatabase against the
+// options registered by the driver. Drivers should call it before
+// applying the options, as they are not set through the wrapper.
+func (base *DatabaseImplBase) ValidateOptions(options map[string]string) error {
+	return base.DriverInfo.ValidateOptions(OptionLevelDatabase, options)
+}
+
 func (d *DatabaseImplBase) GetInitialSpanAttributes() []attribute.KeyValue {
 	return getInitialSpanAttributes(d.DriverInfo)
 }
@@ -220,6 +251,71 @@ func NewDatabase(impl DatabaseImpl) Database {
 	}
 }
 
+func (db *database) GetOption(key string) (string, error) {
+	if isRetryOption(key) {
+		return db.Base().RetryPolicy.get(key), nil
+	}
+	return db.DatabaseImpl.GetOption(key)
+}
+
+func (db *database) GetOptionDouble(key string) (float64, error) {
+	if isRetryOption(key) {
+		return strconv.ParseFloat(db.Base().RetryPolicy.get(key), 64)
+	}
+	return db.DatabaseImpl.GetOptionDouble(key)
+}
+
+func (db *database) GetOptionInt(key string) (int64, error) {
+	if isRetryOption(key) {
+		return strconv.ParseInt(db.Base().RetryPolicy.get(key), 10, 64)
+	}
+	return db.DatabaseImpl.GetOptionInt(key)
+}
+
+func (db *database) SetOption(key string, val string) error {
+	if err := db.Base().DriverInfo.ValidateOption(OptionLevelDatabase, key, &val); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&db.Base().RetryPolicy, &db.Base().ErrorHelper, OptionLevelDatabase, key, val); handled {
+		return err
+	}
+	return db.DatabaseImpl.SetOption(key, val)
+}
+
+func (db *database) SetOptionBytes(key string, val []byte) error {
+	if err := db.Base().DriverInfo.ValidateOption(OptionLevelDatabase, key, nil); err != nil {
+		return err
+	}
+	return db.DatabaseImpl.SetOptionBytes(key, val)
+}
+
+func (db *database) SetOptionDouble(key string, val float64) error {
+	str := strconv.FormatFloat(val, 'g', -1, 64)
+	if err := db.Base().DriverInfo.ValidateOption(OptionLevelDatabase, key, &str); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&db.Base().RetryPolicy, &db.Base().ErrorHelper, OptionLevelDatabase, key, str); handled {
+		return err
+	}
+	return db.DatabaseImpl.SetOptionDouble(key, val)
+}
+
+func (db *database) SetOptionInt(key string, val int64) error {
+	str := strconv.FormatInt(val, 10)
+	if err := db.Base().DriverInfo.ValidateOption(OptionLevelDatabase, key, &str); err != nil {
+		return err
+	}
+	if handled, err := setRetryOption(&db.Base().RetryPolicy, &db.Base().ErrorHelper, OptionLevelDatabase, key, str); handled {
+		return err
+	}
+	return db.DatabaseImpl.SetOptionInt(key, val)
+}
+
+// GetOptionCatalog implements adbc.OptionCatalog.
+func (db *database) GetOptionCatalog(ctx context.Context) (array.RecordReader, error) {
+	return BuildOptionCatalogRecordReader(db.Base().Alloc, db.Base().DriverInfo.Options())
+}
+
 func (db *database) SetLogger(logger *slog.Logger) {
 	if logger != nil {
 		db.Base().Logger = logger
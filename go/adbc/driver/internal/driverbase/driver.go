@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
@@ -66,6 +67,7 @@ type DriverImpl interface {
 // given an input is provided satisfying the DriverImpl interface.
 type Driver interface {
 	adbc.Driver
+	adbc.OptionCatalog
 }
 
 // DriverImplBase is a struct that provides default implementations of the
@@ -117,6 +119,12 @@ func (base *DriverImplBase) Base() *DriverImplBase {
 	return base
 }
 
+// GetOptionCatalog implements adbc.OptionCatalog, listing the options
+// registered with the DriverInfo.
+func (base *DriverImplBase) GetOptionCatalog(ctx context.Context) (array.RecordReader, error) {
+	return BuildOptionCatalogRecordReader(base.Alloc, base.DriverInfo.Options())
+}
+
 type driver struct {
 	DriverImpl
 }
@@ -126,4 +134,8 @@ func NewDriver(impl DriverImpl) Driver {
 	return &driver{DriverImpl: impl}
 }
 
+func (d *driver) GetOptionCatalog(ctx context.Context) (array.RecordReader, error) {
+	return d.Base().GetOptionCatalog(ctx)
+}
+
 var _ DriverImpl = (*DriverImplBase)(nil)
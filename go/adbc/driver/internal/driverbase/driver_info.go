@@ -99,6 +99,9 @@ func DefaultDriverInfo(name string) *DriverInfo {
 type DriverInfo struct {
 	name string
 	info map[adbc.InfoCode]any
+
+	options          []OptionSpec
+	optionNamespaces []string
 }
 
 func (di *DriverInfo) GetName() string { return di.name }
@@ -20,6 +20,7 @@ package driverbase_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -791,3 +792,96 @@ func TestRequiredList(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(`["d", "e", "f"]`), &v))
 	assert.Equal(t, driverbase.RequiredList([]string{"d", "e", "f"}), v)
 }
+
+// closingDatabaseImpl is a database that overrides Close without calling
+// the base.
+type closingDatabaseImpl struct {
+	driverbase.DatabaseImplBase
+
+	closed bool
+}
+
+func (d *closingDatabaseImpl) Close() error {
+	d.closed = true
+	return errors.New("close failed")
+}
+
+func TestDatabaseClose(t *testing.T) {
+	drvBase := driverbase.NewDriverImplBase(driverbase.DefaultDriverInfo("MockDriver"), memory.DefaultAllocator)
+	dbBase, err := driverbase.NewDatabaseImplBase(context.Background(), &drvBase)
+	require.NoError(t, err)
+
+	// the driver's Close is called, and its error returned
+	impl := &closingDatabaseImpl{DatabaseImplBase: dbBase}
+	db := driverbase.NewDatabase(impl)
+	assert.EqualError(t, db.Close(), "close failed")
+	assert.True(t, impl.closed)
+}
+
+func TestCancel(t *testing.T) {
+	drvBase := driverbase.NewDriverImplBase(driverbase.DefaultDriverInfo("MockDriver"), memory.DefaultAllocator)
+	dbBase, err := driverbase.NewDatabaseImplBase(context.Background(), &drvBase)
+	require.NoError(t, err)
+	cnxnBase := driverbase.NewConnectionImplBase(&dbBase)
+	stmtBase := driverbase.NewStatementImplBase(&cnxnBase, cnxnBase.ErrorHelper)
+
+	// nothing in progress
+	require.NoError(t, stmtBase.Cancel())
+	require.NoError(t, cnxnBase.Cancel())
+
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stmtCtx, stmtDone := stmtBase.WithCancel(parent)
+	defer stmtDone()
+	cnxnCtx, cnxnDone := cnxnBase.WithCancel(parent)
+	defer cnxnDone()
+
+	require.NoError(t, stmtBase.Cancel())
+	assert.ErrorIs(t, stmtCtx.Err(), context.Canceled)
+	assert.NoError(t, parent.Err())
+	// statements and connections are cancelled independently
+	assert.NoError(t, cnxnCtx.Err())
+
+	require.NoError(t, cnxnBase.Cancel())
+	assert.ErrorIs(t, cnxnCtx.Err(), context.Canceled)
+
+	// new operations are unaffected by an earlier cancellation
+	ctx, done := stmtBase.WithCancel(parent)
+	assert.NoError(t, ctx.Err())
+	// and their context is released once they are done
+	done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.NoError(t, parent.Err())
+
+	var stmt adbc.Statement = driverbase.NewStatement(&statement{StatementImplBase: stmtBase})
+	_, ok := stmt.(adbc.StatementCancel)
+	assert.True(t, ok)
+}
+
+func TestCancelOnRelease(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	bldr := array.NewRecordBuilder(mem, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.Int64Builder).Append(1)
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	inner, err := array.NewRecordReader(schema, []arrow.Record{rec})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rdr := driverbase.CancelOnRelease(inner, cancel)
+	rdr.Retain()
+	rdr.Release()
+	assert.NoError(t, ctx.Err())
+	require.True(t, rdr.Next())
+	rdr.Release()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	assert.Nil(t, driverbase.CancelOnRelease(nil, cancel))
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
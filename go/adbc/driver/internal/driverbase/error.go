@@ -18,6 +18,7 @@
 package driverbase
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/apache/arrow-adbc/go/adbc"
@@ -35,3 +36,98 @@ func (helper *ErrorHelper) Errorf(code adbc.Status, message string, format ...in
 		Msg:  fmt.Sprintf("[%s] %s", helper.DriverName, msg),
 	}
 }
+
+// SQLState converts a SQLSTATE to the SqlState of an adbc.Error. It
+// returns zeros, i.e. no SQLSTATE, unless state has five characters.
+func SQLState(state string) [5]byte {
+	var sqlState [5]byte
+	if len(state) == len(sqlState) {
+		copy(sqlState[:], state)
+	}
+	return sqlState
+}
+
+// statusSQLStates are the SQLSTATEs reported for errors of backends that
+// don't have SQLSTATEs of their own.
+var statusSQLStates = map[adbc.Status]string{
+	adbc.StatusNotImplemented:  "0A000",
+	adbc.StatusNotFound:        "42S02",
+	adbc.StatusAlreadyExists:   "42S01",
+	adbc.StatusInvalidArgument: "42000",
+	adbc.StatusInvalidState:    "25000",
+	adbc.StatusInvalidData:     "22000",
+	adbc.StatusIntegrity:       "23000",
+	adbc.StatusIO:              "08000",
+	adbc.StatusCancelled:       "HY008",
+	adbc.StatusTimeout:         "HYT00",
+	adbc.StatusUnauthenticated: "28000",
+	adbc.StatusUnauthorized:    "42501",
+}
+
+// SQLStateForStatus returns the SQLSTATE that best describes errors with
+// the given status, for backends that don't report SQLSTATEs. There is
+// none for unknown and internal errors.
+func SQLStateForStatus(code adbc.Status) [5]byte {
+	return SQLState(statusSQLStates[code])
+}
+
+var (
+	sqlStateStatuses = map[string]adbc.Status{
+		"42S02": adbc.StatusNotFound, // base table or view not found
+		"42S22": adbc.StatusNotFound, // column not found
+		"42P01": adbc.StatusNotFound, // undefined table
+		"42704": adbc.StatusNotFound, // undefined object
+		"42S01": adbc.StatusAlreadyExists,
+		"42P07": adbc.StatusAlreadyExists,
+		"42710": adbc.StatusAlreadyExists,
+		"42501": adbc.StatusUnauthorized,
+		"57014": adbc.StatusCancelled,
+		"HY008": adbc.StatusCancelled,
+		"HYT00": adbc.StatusTimeout,
+		"HYT01": adbc.StatusTimeout,
+	}
+	sqlStateClassStatuses = map[string]adbc.Status{
+		"02": adbc.StatusNotFound, // no data
+		"08": adbc.StatusIO,       // connection exception
+		"0A": adbc.StatusNotImplemented,
+		"22": adbc.StatusInvalidData,
+		"23": adbc.StatusIntegrity,
+		"25": adbc.StatusInvalidState,
+		"28": adbc.StatusUnauthenticated,
+		"3D": adbc.StatusNotFound, // invalid catalog name
+		"3F": adbc.StatusNotFound, // invalid schema name
+		"42": adbc.StatusInvalidArgument,
+		"XX": adbc.StatusInternal,
+	}
+)
+
+// StatusForSQLState returns the status of errors with the given
+// SQLSTATE, or fallback if the SQLSTATE doesn't tell.
+func StatusForSQLState(state string, fallback adbc.Status) adbc.Status {
+	if len(state) != 5 {
+		return fallback
+	}
+	if code, ok := sqlStateStatuses[state]; ok {
+		return code
+	}
+	if code, ok := sqlStateClassStatuses[state[:2]]; ok {
+		return code
+	}
+	return fallback
+}
+
+// WithErrorDetails appends details to err, which is wrapped into an
+// adbc.Error with StatusUnknown if it isn't one. Callers can retrieve the
+// details that implement error, such as adbc.QueryIDErrorDetail, with
+// errors.As.
+func WithErrorDetails(err error, details ...adbc.ErrorDetail) error {
+	if err == nil || len(details) == 0 {
+		return err
+	}
+	var adbcErr adbc.Error
+	if !errors.As(err, &adbcErr) {
+		adbcErr = adbc.Error{Code: adbc.StatusUnknown, Msg: err.Error()}
+	}
+	adbcErr.Details = append(adbcErr.Details[:len(adbcErr.Details):len(adbcErr.Details)], details...)
+	return adbcErr
+}
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLState(t *testing.T) {
+	assert.Equal(t, [5]byte{'4', '2', 'S', '0', '2'}, driverbase.SQLState("42S02"))
+	assert.Equal(t, [5]byte{}, driverbase.SQLState(""))
+	assert.Equal(t, [5]byte{}, driverbase.SQLState("4200"))
+
+	assert.Equal(t, driverbase.SQLState("HYT00"), driverbase.SQLStateForStatus(adbc.StatusTimeout))
+	assert.Equal(t, [5]byte{}, driverbase.SQLStateForStatus(adbc.StatusInternal))
+
+	for state, code := range map[string]adbc.Status{
+		"42S02": adbc.StatusNotFound,
+		"42000": adbc.StatusInvalidArgument,
+		"22012": adbc.StatusInvalidData,
+		"23505": adbc.StatusIntegrity,
+		"08001": adbc.StatusIO,
+		"57014": adbc.StatusCancelled,
+		"HY000": adbc.StatusUnknown,
+		"":      adbc.StatusUnknown,
+	} {
+		assert.Equal(t, code, driverbase.StatusForSQLState(state, adbc.StatusUnknown), state)
+	}
+}
+
+func TestWithErrorDetails(t *testing.T) {
+	err := driverbase.WithErrorDetails(adbc.Error{Code: adbc.StatusIO, Msg: "failed"},
+		&adbc.TextErrorDetail{Name: "text", Detail: "value"},
+		&adbc.QueryIDErrorDetail{QueryID: "01b2"})
+	err = fmt.Errorf("wrapped: %w", err)
+
+	// the details can be found through wrapped errors
+	var queryID *adbc.QueryIDErrorDetail
+	require.True(t, errors.As(err, &queryID))
+	assert.Equal(t, "01b2", queryID.QueryID)
+	var jobID *adbc.JobIDErrorDetail
+	assert.False(t, errors.As(err, &jobID))
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+	assert.Len(t, adbcErr.Details, 2)
+
+	// other errors become adbc.Errors
+	err = driverbase.WithErrorDetails(errors.New("plain"), &adbc.JobIDErrorDetail{ProjectID: "p", Location: "US", JobID: "j"})
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusUnknown, adbcErr.Code)
+	require.True(t, errors.As(err, &jobID))
+	assert.Equal(t, "p:US.j", jobID.String())
+
+	assert.NoError(t, driverbase.WithErrorDetails(nil, &adbc.QueryIDErrorDetail{}))
+}
@@ -0,0 +1,55 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"context"
+	"time"
+)
+
+// Backoff between polls of a running query, the same as the Flight SQL
+// driver uses between PollFlightInfo calls.
+const (
+	incrementalInitialBackoff = 100 * time.Millisecond
+	incrementalMaxBackoff     = 5 * time.Second
+)
+
+// PollIncremental calls poll until it reports that the query is done,
+// waiting between calls with a backoff that starts at 100ms and doubles up
+// to 5s. It returns the error of poll, or that of ctx if ctx is done first.
+//
+// It serves AttachQuery to wait for a submitted query: if ctx expires, the
+// query keeps running and can be waited for again by a later call.
+func PollIncremental(ctx context.Context, poll func(context.Context) (done bool, err error)) error {
+	backoff := incrementalInitialBackoff
+	for {
+		done, err := poll(ctx)
+		if err != nil || done {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, incrementalMaxBackoff)
+	}
+}
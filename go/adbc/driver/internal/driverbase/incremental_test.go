@@ -0,0 +1,61 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollIncremental(t *testing.T) {
+	ctx := context.Background()
+
+	polls := 0
+	err := driverbase.PollIncremental(ctx, func(context.Context) (bool, error) {
+		polls++
+		return polls == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, polls)
+
+	// errors stop the polling
+	polls = 0
+	failure := errors.New("failure")
+	err = driverbase.PollIncremental(ctx, func(context.Context) (bool, error) {
+		polls++
+		return false, failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 1, polls)
+
+	// and so does the context, so that the query can be polled again later
+	polls = 0
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = driverbase.PollIncremental(ctx, func(context.Context) (bool, error) {
+		polls++
+		return false, nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, polls)
+}
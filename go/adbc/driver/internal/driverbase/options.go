@@ -0,0 +1,355 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// OptionLevel is a set of the objects an option can be set on.
+type OptionLevel uint8
+
+const (
+	OptionLevelDatabase OptionLevel = 1 << iota
+	OptionLevelConnection
+	OptionLevelStatement
+
+	OptionLevelAll = OptionLevelDatabase | OptionLevelConnection | OptionLevelStatement
+)
+
+// Names lists the levels in the set, as used in adbc.OptionCatalogSchema.
+func (l OptionLevel) Names() []string {
+	var names []string
+	if l&OptionLevelDatabase != 0 {
+		names = append(names, "database")
+	}
+	if l&OptionLevelConnection != 0 {
+		names = append(names, "connection")
+	}
+	if l&OptionLevelStatement != 0 {
+		names = append(names, "statement")
+	}
+	return names
+}
+
+func (l OptionLevel) String() string {
+	return strings.Join(l.Names(), "|")
+}
+
+func (l OptionLevel) unknownMessage() string {
+	switch l {
+	case OptionLevelDatabase:
+		return DatabaseMessageOptionUnknown
+	case OptionLevelConnection:
+		return ConnectionMessageOptionUnknown
+	default:
+		return StatementMessageOptionUnknown
+	}
+}
+
+// OptionType is the type of the value of an option.
+type OptionType uint8
+
+const (
+	OptionTypeString OptionType = iota
+	// OptionTypeBool accepts adbc.OptionValueEnabled and
+	// adbc.OptionValueDisabled, or any value accepted by strconv.ParseBool.
+	OptionTypeBool
+	OptionTypeInt
+	OptionTypeDouble
+	// OptionTypeDuration accepts values parsed by time.ParseDuration.
+	OptionTypeDuration
+	OptionTypeBytes
+)
+
+func (t OptionType) String() string {
+	switch t {
+	case OptionTypeString:
+		return "string"
+	case OptionTypeBool:
+		return "bool"
+	case OptionTypeInt:
+		return "int"
+	case OptionTypeDouble:
+		return "double"
+	case OptionTypeDuration:
+		return "duration"
+	case OptionTypeBytes:
+		return "bytes"
+	}
+	return fmt.Sprintf("OptionType(%d)", uint8(t))
+}
+
+// OptionSpec describes an option accepted by a driver, see
+// DriverInfo.RegisterOptions.
+type OptionSpec struct {
+	// Name is the key of the option.
+	Name string
+	// Prefix indicates that Name is a prefix, and that the option applies
+	// to every key starting with it, like per-call headers.
+	Prefix bool
+	// Levels is the set of objects the option can be set on.
+	Levels OptionLevel
+	Type   OptionType
+	// Default is the value used when the option isn't set, if any.
+	Default *string
+	// AllowedValues lists the accepted values. If empty, any value of
+	// the type is accepted.
+	AllowedValues []string
+	// Secret marks credentials, which should not be logged or displayed.
+	Secret      bool
+	Description string
+	// Validate, if set, replaces the check of values against Type, for
+	// options with constraints the type doesn't capture. An adbc.Error
+	// is returned to the caller as is, other errors are wrapped like
+	// type errors.
+	Validate func(key, value string) error
+}
+
+// OptionDefault returns a pointer to value, for use as OptionSpec.Default.
+func OptionDefault(value string) *string {
+	return &value
+}
+
+func (spec *OptionSpec) matches(key string) bool {
+	if spec.Prefix {
+		return strings.HasPrefix(key, spec.Name)
+	}
+	return key == spec.Name
+}
+
+// checkValue checks that value has the type of the option, and is one of
+// the allowed values, if any.
+func (spec *OptionSpec) checkValue(key, value string) error {
+	if spec.Validate != nil {
+		if err := spec.Validate(key, value); err != nil {
+			return err
+		}
+		return spec.checkAllowed(value)
+	}
+
+	var err error
+	switch spec.Type {
+	case OptionTypeBool:
+		_, err = strconv.ParseBool(value)
+	case OptionTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case OptionTypeDouble:
+		_, err = strconv.ParseFloat(value, 64)
+	case OptionTypeDuration:
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return fmt.Errorf("expected a value of type %s", spec.Type)
+	}
+	return spec.checkAllowed(value)
+}
+
+func (spec *OptionSpec) checkAllowed(value string) error {
+	if len(spec.AllowedValues) > 0 && !slices.Contains(spec.AllowedValues, value) {
+		return fmt.Errorf("expected one of %s", strings.Join(spec.AllowedValues, ", "))
+	}
+	return nil
+}
+
+// RegisterOptions declares options accepted by the driver. Once a driver
+// registers options, SetOption calls on the databases, connections and
+// statements wrapped by driverbase are validated against them: values
+// must have the declared type and be among the allowed values, and
+// unregistered keys within a namespace of the driver are rejected, see
+// RegisterOptionNamespace. Other keys are passed through to the driver.
+//
+// The registered options are listed by GetOptionCatalog.
+func (di *DriverInfo) RegisterOptions(specs ...OptionSpec) error {
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return errors.New("option name must not be empty")
+		}
+		if spec.Levels&OptionLevelAll == 0 {
+			return fmt.Errorf("option %s: no level", spec.Name)
+		}
+		for _, other := range di.options {
+			if other.Name == spec.Name && other.Prefix == spec.Prefix {
+				return fmt.Errorf("option %s: already registered", spec.Name)
+			}
+		}
+		for _, val := range spec.AllowedValues {
+			if err := spec.checkValue(spec.Name, val); err != nil {
+				return fmt.Errorf("option %s: allowed value %q: %w", spec.Name, val, err)
+			}
+		}
+		if spec.Default != nil {
+			if err := spec.checkValue(spec.Name, *spec.Default); err != nil {
+				return fmt.Errorf("option %s: default %q: %w", spec.Name, *spec.Default, err)
+			}
+		}
+		di.options = append(di.options, spec)
+	}
+	return nil
+}
+
+// RegisterOptionNamespace declares that every option with the given prefix,
+// such as "adbc.snowflake.", belongs to the driver, so that keys with this
+// prefix which aren't registered are rejected as typos instead of being
+// passed through.
+func (di *DriverInfo) RegisterOptionNamespace(prefix string) {
+	di.optionNamespaces = append(di.optionNamespaces, prefix)
+}
+
+// Options returns the registered options, sorted by name.
+func (di *DriverInfo) Options() []OptionSpec {
+	specs := slices.Clone(di.options)
+	slices.SortStableFunc(specs, func(a, b OptionSpec) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return specs
+}
+
+// LookupOption finds the registered option for a key. Exact names take
+// precedence over prefixes.
+func (di *DriverInfo) LookupOption(key string) (OptionSpec, bool) {
+	var (
+		found OptionSpec
+		ok    bool
+	)
+	for _, spec := range di.options {
+		if !spec.matches(key) {
+			continue
+		}
+		if !spec.Prefix {
+			return spec, true
+		}
+		if !ok || len(spec.Name) > len(found.Name) {
+			found, ok = spec, true
+		}
+	}
+	return found, ok
+}
+
+// ValidateOption checks an option about to be set on an object of the
+// given level against the registered options. If value is nil, only the
+// key is checked, as for the typed setters like SetOptionInt.
+func (di *DriverInfo) ValidateOption(level OptionLevel, key string, value *string) error {
+	if len(di.options) == 0 {
+		return nil
+	}
+	errorHelper := ErrorHelper{DriverName: di.name}
+
+	spec, ok := di.LookupOption(key)
+	if !ok {
+		for _, prefix := range di.optionNamespaces {
+			if strings.HasPrefix(key, prefix) {
+				return errorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s'", level.unknownMessage(), key)
+			}
+		}
+		return nil
+	}
+
+	if spec.Levels&level == 0 {
+		return errorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s': can only be set on a %s",
+			level.unknownMessage(), key, strings.Join(spec.Levels.Names(), " or "))
+	}
+	if value != nil {
+		if err := spec.checkValue(key, *value); err != nil {
+			if spec.Validate != nil {
+				var adbcErr adbc.Error
+				if errors.As(err, &adbcErr) {
+					return err
+				}
+			}
+			return errorHelper.Errorf(adbc.StatusInvalidArgument, "Invalid value for %s option '%s': '%s': %s", level, key, *value, err)
+		}
+	}
+	return nil
+}
+
+// ValidateOptions calls ValidateOption for each of the options, in order
+// of their keys. Drivers should call it on the options passed to
+// NewDatabase, which don't go through the SetOption of the wrapped
+// database.
+func (di *DriverInfo) ValidateOptions(level OptionLevel, opts map[string]string) error {
+	keys := make([]string, 0, len(opts))
+	for key := range opts {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		val := opts[key]
+		if err := di.ValidateOption(level, key, &val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildOptionCatalogRecordReader constructs a RecordReader with the
+// adbc.OptionCatalogSchema describing the given options.
+func BuildOptionCatalogRecordReader(mem memory.Allocator, specs []OptionSpec) (array.RecordReader, error) {
+	bldr := array.NewRecordBuilder(mem, adbc.OptionCatalogSchema)
+	defer bldr.Release()
+
+	nameBldr := bldr.Field(0).(*array.StringBuilder)
+	prefixBldr := bldr.Field(1).(*array.BooleanBuilder)
+	levelsBldr := bldr.Field(2).(*array.ListBuilder)
+	levelBldr := levelsBldr.ValueBuilder().(*array.StringBuilder)
+	typeBldr := bldr.Field(3).(*array.StringBuilder)
+	defaultBldr := bldr.Field(4).(*array.StringBuilder)
+	allowedBldr := bldr.Field(5).(*array.ListBuilder)
+	allowedValueBldr := allowedBldr.ValueBuilder().(*array.StringBuilder)
+	secretBldr := bldr.Field(6).(*array.BooleanBuilder)
+	descriptionBldr := bldr.Field(7).(*array.StringBuilder)
+
+	for _, spec := range specs {
+		nameBldr.Append(spec.Name)
+		prefixBldr.Append(spec.Prefix)
+		levelsBldr.Append(true)
+		levelBldr.AppendValues(spec.Levels.Names(), nil)
+		typeBldr.Append(spec.Type.String())
+		if spec.Default != nil {
+			defaultBldr.Append(*spec.Default)
+		} else {
+			defaultBldr.AppendNull()
+		}
+		if len(spec.AllowedValues) > 0 {
+			allowedBldr.Append(true)
+			allowedValueBldr.AppendValues(spec.AllowedValues, nil)
+		} else {
+			allowedBldr.AppendNull()
+		}
+		secretBldr.Append(spec.Secret)
+		if spec.Description != "" {
+			descriptionBldr.Append(spec.Description)
+		} else {
+			descriptionBldr.AppendNull()
+		}
+	}
+
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	return array.NewRecordReader(adbc.OptionCatalogSchema, []arrow.Record{rec})
+}
@@ -0,0 +1,203 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testOptionSpecs = []driverbase.OptionSpec{
+	{Name: "test.level", Levels: driverbase.OptionLevelDatabase | driverbase.OptionLevelConnection,
+		Type: driverbase.OptionTypeString, Default: driverbase.OptionDefault("low"),
+		AllowedValues: []string{"low", "high"}, Description: "A level."},
+	{Name: "test.size", Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeInt},
+	{Name: "test.secret", Levels: driverbase.OptionLevelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: "test.header.", Prefix: true, Levels: driverbase.OptionLevelStatement, Type: driverbase.OptionTypeString},
+	{Name: "test.header.flag.", Prefix: true, Levels: driverbase.OptionLevelStatement, Type: driverbase.OptionTypeBool},
+}
+
+func TestRegisterOptions(t *testing.T) {
+	info := driverbase.DefaultDriverInfo("test")
+	require.NoError(t, info.RegisterOptions(testOptionSpecs...))
+
+	assert.ErrorContains(t, info.RegisterOptions(driverbase.OptionSpec{
+		Name: "test.size", Levels: driverbase.OptionLevelDatabase}), "already registered")
+	assert.ErrorContains(t, info.RegisterOptions(driverbase.OptionSpec{Name: "test.none"}), "no level")
+	assert.ErrorContains(t, info.RegisterOptions(driverbase.OptionSpec{
+		Name: "test.bool", Levels: driverbase.OptionLevelDatabase, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault("maybe")}), `default "maybe": expected a value of type bool`)
+	assert.ErrorContains(t, info.RegisterOptions(driverbase.OptionSpec{
+		Name: "test.mode", Levels: driverbase.OptionLevelDatabase, AllowedValues: []string{"a"},
+		Default: driverbase.OptionDefault("b")}), `default "b": expected one of a`)
+
+	var names []string
+	for _, spec := range info.Options() {
+		names = append(names, spec.Name)
+	}
+	assert.Equal(t, []string{"test.header.", "test.header.flag.", "test.level", "test.secret", "test.size"}, names)
+
+	spec, ok := info.LookupOption("test.header.flag.x")
+	require.True(t, ok)
+	assert.Equal(t, driverbase.OptionTypeBool, spec.Type)
+	_, ok = info.LookupOption("test.other")
+	assert.False(t, ok)
+}
+
+func TestValidateOption(t *testing.T) {
+	info := driverbase.DefaultDriverInfo("test")
+	value := func(v string) *string { return &v }
+
+	// nothing is validated until options are registered
+	require.NoError(t, info.ValidateOption(driverbase.OptionLevelDatabase, "test.typo", value("")))
+
+	info.RegisterOptionNamespace("test.")
+	require.NoError(t, info.RegisterOptions(testOptionSpecs...))
+
+	var adbcErr adbc.Error
+	for _, tc := range []struct {
+		level driverbase.OptionLevel
+		key   string
+		value *string
+		code  adbc.Status
+		msg   string
+	}{
+		{driverbase.OptionLevelConnection, "test.level", value("high"), adbc.StatusOK, ""},
+		{driverbase.OptionLevelStatement, "test.header.x", value("v"), adbc.StatusOK, ""},
+		{driverbase.OptionLevelStatement, "test.header.flag.x", value("true"), adbc.StatusOK, ""},
+		{driverbase.OptionLevelDatabase, "other.key", value("v"), adbc.StatusOK, ""},
+		{driverbase.OptionLevelDatabase, "test.size", nil, adbc.StatusOK, ""},
+		{driverbase.OptionLevelDatabase, "test.level", value("medium"), adbc.StatusInvalidArgument,
+			"[test] Invalid value for database option 'test.level': 'medium': expected one of low, high"},
+		{driverbase.OptionLevelStatement, "test.size", value("big"), adbc.StatusInvalidArgument,
+			"[test] Invalid value for statement option 'test.size': 'big': expected a value of type int"},
+		{driverbase.OptionLevelStatement, "test.header.flag.x", value("yes"), adbc.StatusInvalidArgument,
+			"expected a value of type bool"},
+		{driverbase.OptionLevelStatement, "test.level", value("low"), adbc.StatusNotImplemented,
+			"[test] Unknown statement option 'test.level': can only be set on a database or connection"},
+		{driverbase.OptionLevelConnection, "test.typo", value("v"), adbc.StatusNotImplemented,
+			"[test] Unknown connection option 'test.typo'"},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			err := info.ValidateOption(tc.level, tc.key, tc.value)
+			if tc.code == adbc.StatusOK {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorAs(t, err, &adbcErr)
+			assert.Equal(t, tc.code, adbcErr.Code)
+			assert.Contains(t, adbcErr.Msg, tc.msg)
+		})
+	}
+
+	require.NoError(t, info.RegisterOptions(driverbase.OptionSpec{
+		Name: "test.custom", Levels: driverbase.OptionLevelDatabase, Type: driverbase.OptionTypeDouble,
+		Validate: func(key, value string) error {
+			if value != "0.5" {
+				return adbc.Error{Code: adbc.StatusInvalidArgument, Msg: "custom check of " + key}
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, info.ValidateOption(driverbase.OptionLevelDatabase, "test.custom", value("0.5")))
+	assert.EqualError(t, info.ValidateOption(driverbase.OptionLevelDatabase, "test.custom", value("1")),
+		"Invalid Argument: custom check of test.custom")
+
+	err := info.ValidateOptions(driverbase.OptionLevelDatabase, map[string]string{
+		"test.level": "low", "test.size": "1", "test.zzz": "",
+	})
+	assert.ErrorContains(t, err, "Unknown database option 'test.zzz'")
+}
+
+func TestOptionValidationWrappers(t *testing.T) {
+	info := driverbase.DefaultDriverInfo("test")
+	info.RegisterOptionNamespace("test.")
+	require.NoError(t, info.RegisterOptions(testOptionSpecs...))
+	drv := driverbase.NewDriver(&driverImpl{
+		DriverImplBase: driverbase.NewDriverImplBase(info, memory.DefaultAllocator),
+		handler:        slog.NewTextHandler(io.Discard, nil),
+	})
+
+	db, err := drv.NewDatabase(nil)
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, db)
+	dbOpts := db.(adbc.GetSetOptions)
+
+	// unregistered keys outside the namespace are passed to the driver
+	require.NoError(t, dbOpts.SetOption(OptionKeyRecognized, "value"))
+	assert.ErrorContains(t, dbOpts.SetOption("test.level", "medium"), "expected one of low, high")
+	assert.ErrorContains(t, dbOpts.SetOption("test.typo", ""), "Unknown database option 'test.typo'")
+	assert.ErrorContains(t, dbOpts.SetOptionInt("test.level", 1), "expected one of low, high")
+
+	cnxn, err := db.Open(context.Background())
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, cnxn)
+	assert.ErrorContains(t, cnxn.(adbc.GetSetOptions).SetOption("test.secret", "x"), "can only be set on a database")
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+	assert.ErrorContains(t, stmt.SetOption("test.header.flag.x", "yes"), "expected a value of type bool")
+	assert.ErrorContains(t, stmt.(adbc.GetSetOptions).SetOptionDouble("test.size", 1.5), "expected a value of type int")
+}
+
+func TestGetOptionCatalog(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	info := driverbase.DefaultDriverInfo("test")
+	require.NoError(t, info.RegisterOptions(testOptionSpecs...))
+	drv := driverbase.NewDriver(&driverImpl{DriverImplBase: driverbase.NewDriverImplBase(info, mem)})
+
+	rdr, err := drv.GetOptionCatalog(context.Background())
+	require.NoError(t, err)
+	defer rdr.Release()
+	assert.True(t, adbc.OptionCatalogSchema.Equal(rdr.Schema()))
+
+	require.True(t, rdr.Next())
+	rec := rdr.Record()
+	require.EqualValues(t, len(testOptionSpecs), rec.NumRows())
+
+	expected, _, err := array.RecordFromJSON(mem, adbc.OptionCatalogSchema, strings.NewReader(`[
+		{"option_name": "test.header.", "is_prefix": true, "option_levels": ["statement"], "value_type": "string",
+		 "default_value": null, "allowed_values": null, "is_secret": false, "description": null},
+		{"option_name": "test.header.flag.", "is_prefix": true, "option_levels": ["statement"], "value_type": "bool",
+		 "default_value": null, "allowed_values": null, "is_secret": false, "description": null},
+		{"option_name": "test.level", "is_prefix": false, "option_levels": ["database", "connection"], "value_type": "string",
+		 "default_value": "low", "allowed_values": ["low", "high"], "is_secret": false, "description": "A level."},
+		{"option_name": "test.secret", "is_prefix": false, "option_levels": ["database"], "value_type": "string",
+		 "default_value": null, "allowed_values": null, "is_secret": true, "description": null},
+		{"option_name": "test.size", "is_prefix": false, "option_levels": ["database", "connection", "statement"], "value_type": "int",
+		 "default_value": null, "allowed_values": null, "is_secret": false, "description": null}
+	]`))
+	require.NoError(t, err)
+	defer expected.Release()
+	assert.Truef(t, array.RecordEqual(expected, rec), "unexpected catalog: %v", rec)
+	assert.False(t, rdr.Next())
+}
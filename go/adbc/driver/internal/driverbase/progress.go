@@ -0,0 +1,164 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// ProgressPollInterval is how often drivers poll the backend for the
+// progress of a running query.
+const ProgressPollInterval = time.Second
+
+// ProgressReporter tracks the progress of the queries of a statement. It
+// backs adbc.OptionKeyProgress and adbc.OptionKeyMaxProgress and calls the
+// callback set with SetProgressCallback, see adbc.StatementProgress.
+type ProgressReporter struct {
+	mu sync.Mutex
+	// query counts the queries started, so that the progress of a
+	// previous query is no longer reported once another one started
+	query    uint64
+	current  adbc.QueryProgress
+	callback func(adbc.QueryProgress)
+}
+
+// NewProgressReporter returns a reporter with no progress.
+func NewProgressReporter() *ProgressReporter {
+	return &ProgressReporter{current: noProgress()}
+}
+
+func noProgress() adbc.QueryProgress {
+	return adbc.QueryProgress{MaxProgress: 1, Rows: -1, BytesProcessed: -1}
+}
+
+// SetProgressCallback sets the function called when the progress changes,
+// or removes it if callback is nil.
+func (p *ProgressReporter) SetProgressCallback(callback func(adbc.QueryProgress)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callback = callback
+}
+
+// Start resets the progress when a query is started.
+func (p *ProgressReporter) Start() {
+	p.mu.Lock()
+	p.query++
+	query := p.query
+	p.mu.Unlock()
+	p.report(query, noProgress())
+}
+
+// Report records the progress of the running query, calling the callback
+// if it changed. A MaxProgress of 0 is replaced with 1.
+func (p *ProgressReporter) Report(progress adbc.QueryProgress) {
+	p.report(p.currentQuery(), progress)
+}
+
+func (p *ProgressReporter) currentQuery() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.query
+}
+
+// report records the progress of the given query, unless another query
+// started since. It reports whether the query is still the current one.
+func (p *ProgressReporter) report(query uint64, progress adbc.QueryProgress) bool {
+	if progress.MaxProgress <= 0 {
+		progress.MaxProgress = 1
+	}
+
+	p.mu.Lock()
+	if query != p.query {
+		p.mu.Unlock()
+		return false
+	}
+	if progress == p.current {
+		p.mu.Unlock()
+		return true
+	}
+	p.current = progress
+	callback := p.callback
+	p.mu.Unlock()
+
+	if callback != nil {
+		callback(progress)
+	}
+	return true
+}
+
+// Finish reports that the query completed, keeping the rows and bytes
+// already reported unless they are given, i.e. not negative.
+func (p *ProgressReporter) Finish(rows, bytesProcessed int64) {
+	progress := p.Progress()
+	progress.Progress = progress.MaxProgress
+	if rows >= 0 {
+		progress.Rows = rows
+	}
+	if bytesProcessed >= 0 {
+		progress.BytesProcessed = bytesProcessed
+	}
+	p.Report(progress)
+}
+
+// Progress returns the last progress reported.
+func (p *ProgressReporter) Progress() adbc.QueryProgress {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+// GetOptionDouble returns the value of adbc.OptionKeyProgress or
+// adbc.OptionKeyMaxProgress. It reports false for other keys.
+func (p *ProgressReporter) GetOptionDouble(key string) (float64, bool) {
+	switch key {
+	case adbc.OptionKeyProgress:
+		return p.Progress().Progress, true
+	case adbc.OptionKeyMaxProgress:
+		return p.Progress().MaxProgress, true
+	}
+	return 0, false
+}
+
+// Poll calls poll at once and then every interval, reporting the
+// progress it returns, until poll reports that the query is done, poll
+// fails, ctx is done, or another query is started. Progress is best
+// effort, so errors only stop the polling. Poll returns at once; polling
+// happens in the background.
+func (p *ProgressReporter) Poll(ctx context.Context, interval time.Duration, poll func(context.Context) (progress adbc.QueryProgress, done bool, err error)) {
+	query := p.currentQuery()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			progress, done, err := poll(ctx)
+			if err != nil || !p.report(query, progress) || done {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressReporter(t *testing.T) {
+	p := driverbase.NewProgressReporter()
+
+	var mu sync.Mutex
+	var reports []adbc.QueryProgress
+	p.SetProgressCallback(func(progress adbc.QueryProgress) {
+		mu.Lock()
+		defer mu.Unlock()
+		reports = append(reports, progress)
+	})
+
+	value, ok := p.GetOptionDouble(adbc.OptionKeyMaxProgress)
+	require.True(t, ok)
+	assert.Equal(t, 1.0, value)
+	_, ok = p.GetOptionDouble("other")
+	assert.False(t, ok)
+
+	p.Start()
+	p.Report(adbc.QueryProgress{Progress: 0.5, Rows: -1, BytesProcessed: 1024})
+	// unchanged progress isn't reported twice
+	p.Report(adbc.QueryProgress{Progress: 0.5, MaxProgress: 1, Rows: -1, BytesProcessed: 1024})
+	value, _ = p.GetOptionDouble(adbc.OptionKeyProgress)
+	assert.Equal(t, 0.5, value)
+
+	p.Finish(10, -1)
+	assert.Equal(t, adbc.QueryProgress{Progress: 1, MaxProgress: 1, Rows: 10, BytesProcessed: 1024}, p.Progress())
+
+	mu.Lock()
+	assert.Len(t, reports, 2)
+	mu.Unlock()
+
+	p.SetProgressCallback(nil)
+	p.Start()
+	assert.Equal(t, adbc.QueryProgress{MaxProgress: 1, Rows: -1, BytesProcessed: -1}, p.Progress())
+	mu.Lock()
+	assert.Len(t, reports, 2)
+	mu.Unlock()
+}
+
+func TestProgressReporterPoll(t *testing.T) {
+	p := driverbase.NewProgressReporter()
+	p.Start()
+
+	polls := make(chan int, 10)
+	calls := 0
+	p.Poll(context.Background(), time.Millisecond, func(context.Context) (adbc.QueryProgress, bool, error) {
+		calls++
+		polls <- calls
+		return adbc.QueryProgress{Progress: float64(calls) / 4, Rows: -1, BytesProcessed: -1}, calls == 4, nil
+	})
+	for call := range polls {
+		if call == 4 {
+			break
+		}
+	}
+	assert.Eventually(t, func() bool { return p.Progress().Progress == 1 }, time.Second, time.Millisecond)
+
+	// polling stops once another query started
+	stale := make(chan struct{})
+	p.Poll(context.Background(), time.Millisecond, func(context.Context) (adbc.QueryProgress, bool, error) {
+		<-stale
+		return adbc.QueryProgress{Progress: 0.5}, false, nil
+	})
+	p.Start()
+	close(stale)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0.0, p.Progress().Progress)
+}
@@ -0,0 +1,295 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// RetryPolicy describes how operations that fail with a transient error
+// are retried. It is configured with the adbc.OptionKeyRetry* options on
+// a database or a connection.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one. Operations aren't retried if it is less than 2.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is applied to the delay after each retry.
+	BackoffMultiplier float64
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomized to spread out the retries of concurrent clients.
+	Jitter float64
+	// Codes are the error codes that are retried.
+	Codes []adbc.Status
+	// RetryNonIdempotent allows retrying operations that may not be
+	// idempotent, such as updates.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the policy of new databases: operations are
+// not retried until MaxAttempts is raised, and then only when they fail
+// with an I/O error or a timeout.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       1,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        10 * time.Second,
+		BackoffMultiplier: 2,
+		Jitter:            0.2,
+		Codes:             []adbc.Status{adbc.StatusIO, adbc.StatusTimeout},
+	}
+}
+
+var retryStatusNames = map[adbc.Status]string{
+	adbc.StatusUnknown:         "unknown",
+	adbc.StatusNotImplemented:  "not_implemented",
+	adbc.StatusNotFound:        "not_found",
+	adbc.StatusAlreadyExists:   "already_exists",
+	adbc.StatusInvalidArgument: "invalid_argument",
+	adbc.StatusInvalidState:    "invalid_state",
+	adbc.StatusInvalidData:     "invalid_data",
+	adbc.StatusIntegrity:       "integrity",
+	adbc.StatusInternal:        "internal",
+	adbc.StatusIO:              "io",
+	adbc.StatusCancelled:       "cancelled",
+	adbc.StatusTimeout:         "timeout",
+	adbc.StatusUnauthenticated: "unauthenticated",
+	adbc.StatusUnauthorized:    "unauthorized",
+}
+
+func validateRetryOption(key, value string) error {
+	var p RetryPolicy
+	return p.set(key, value)
+}
+
+// retryOptionNamespace is the prefix of the options of RetryPolicy.
+const retryOptionNamespace = "adbc.retry."
+
+var retryOptionSpecs = []OptionSpec{
+	{Name: adbc.OptionKeyRetryMaxAttempts, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeInt,
+		Default: OptionDefault("1"), Validate: validateRetryOption,
+		Description: "Maximum number of attempts of idempotent operations failing with a transient error."},
+	{Name: adbc.OptionKeyRetryInitialBackoff, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeDuration,
+		Default: OptionDefault("100ms"), Validate: validateRetryOption,
+		Description: "Delay before the first retry."},
+	{Name: adbc.OptionKeyRetryMaxBackoff, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeDuration,
+		Default: OptionDefault("10s"), Validate: validateRetryOption,
+		Description: "Maximum delay between two attempts."},
+	{Name: adbc.OptionKeyRetryBackoffMultiplier, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeDouble,
+		Default: OptionDefault("2"), Validate: validateRetryOption,
+		Description: "Factor applied to the delay after each retry."},
+	{Name: adbc.OptionKeyRetryJitter, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeDouble,
+		Default: OptionDefault("0.2"), Validate: validateRetryOption,
+		Description: "Fraction of each delay that is randomized, between 0 and 1."},
+	{Name: adbc.OptionKeyRetryCodes, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeString,
+		Default: OptionDefault("io,timeout"), Validate: validateRetryOption,
+		Description: "Comma-separated error codes that are retried."},
+	{Name: adbc.OptionKeyRetryNonIdempotent, Levels: OptionLevelDatabase | OptionLevelConnection, Type: OptionTypeBool,
+		Default:     OptionDefault(adbc.OptionValueDisabled),
+		Description: "Whether operations that may not be idempotent, such as updates, are retried."},
+}
+
+// RegisterRetryOptions registers the options of RetryPolicy, so that they
+// are validated and listed in the catalog of the driver. The wrapped
+// databases and connections handle the options whether or not they are
+// registered.
+func (di *DriverInfo) RegisterRetryOptions() error {
+	di.RegisterOptionNamespace(retryOptionNamespace)
+	return di.RegisterOptions(retryOptionSpecs...)
+}
+
+func isRetryOption(key string) bool {
+	return slices.ContainsFunc(retryOptionSpecs, func(spec OptionSpec) bool { return spec.Name == key })
+}
+
+// set parses the value of one of the options of the policy.
+func (p *RetryPolicy) set(key, value string) error {
+	switch key {
+	case adbc.OptionKeyRetryMaxAttempts:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return errors.New("expected a positive integer")
+		}
+		p.MaxAttempts = n
+	case adbc.OptionKeyRetryInitialBackoff, adbc.OptionKeyRetryMaxBackoff:
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return errors.New("expected a non-negative duration")
+		}
+		if key == adbc.OptionKeyRetryInitialBackoff {
+			p.InitialBackoff = d
+		} else {
+			p.MaxBackoff = d
+		}
+	case adbc.OptionKeyRetryBackoffMultiplier:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 1 || math.IsInf(f, 0) {
+			return errors.New("expected a number of at least 1")
+		}
+		p.BackoffMultiplier = f
+	case adbc.OptionKeyRetryJitter:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || !(f >= 0 && f <= 1) {
+			return errors.New("expected a number between 0 and 1")
+		}
+		p.Jitter = f
+	case adbc.OptionKeyRetryCodes:
+		var codes []adbc.Status
+	names:
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			for code, codeName := range retryStatusNames {
+				if name == codeName {
+					codes = append(codes, code)
+					continue names
+				}
+			}
+			return fmt.Errorf("unknown error code '%s'", name)
+		}
+		slices.Sort(codes)
+		p.Codes = slices.Compact(codes)
+	case adbc.OptionKeyRetryNonIdempotent:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("expected a value of type bool")
+		}
+		p.RetryNonIdempotent = b
+	}
+	return nil
+}
+
+// get formats the value of one of the options of the policy.
+func (p *RetryPolicy) get(key string) string {
+	switch key {
+	case adbc.OptionKeyRetryMaxAttempts:
+		return strconv.Itoa(p.MaxAttempts)
+	case adbc.OptionKeyRetryInitialBackoff:
+		return p.InitialBackoff.String()
+	case adbc.OptionKeyRetryMaxBackoff:
+		return p.MaxBackoff.String()
+	case adbc.OptionKeyRetryBackoffMultiplier:
+		return strconv.FormatFloat(p.BackoffMultiplier, 'g', -1, 64)
+	case adbc.OptionKeyRetryJitter:
+		return strconv.FormatFloat(p.Jitter, 'g', -1, 64)
+	case adbc.OptionKeyRetryCodes:
+		names := make([]string, len(p.Codes))
+		for i, code := range p.Codes {
+			names[i] = retryStatusNames[code]
+		}
+		return strings.Join(names, ",")
+	case adbc.OptionKeyRetryNonIdempotent:
+		if p.RetryNonIdempotent {
+			return adbc.OptionValueEnabled
+		}
+		return adbc.OptionValueDisabled
+	}
+	return ""
+}
+
+// setRetryOption sets an option of the policy, if key is one. It reports
+// whether the option was handled.
+func setRetryOption(p *RetryPolicy, helper *ErrorHelper, level OptionLevel, key, value string) (bool, error) {
+	if !isRetryOption(key) {
+		return false, nil
+	}
+	if err := p.set(key, value); err != nil {
+		return true, helper.Errorf(adbc.StatusInvalidArgument, "Invalid value for %s option '%s': '%s': %s", level, key, value, err)
+	}
+	return true, nil
+}
+
+// retryable reports whether err is transient according to the policy.
+// Errors that aren't an adbc.Error are retried as I/O errors if they come
+// from the network.
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var adbcErr adbc.Error
+	var netErr net.Error
+	switch {
+	case errors.As(err, &adbcErr):
+		return slices.Contains(p.Codes, adbcErr.Code)
+	case errors.As(err, &netErr):
+		return slices.Contains(p.Codes, adbc.StatusIO)
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting from 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(max(p.BackoffMultiplier, 1), float64(retry-1))
+	if p.MaxBackoff > 0 {
+		delay = min(delay, float64(p.MaxBackoff))
+	}
+	delay -= delay * p.Jitter * rand.Float64()
+	return time.Duration(delay)
+}
+
+// Retry calls op until it succeeds, fails with an error that isn't
+// retryable, or the attempts of the policy are exhausted, waiting between
+// attempts as configured. Operations that aren't idempotent are only
+// retried if the policy allows it. The error of the last attempt is
+// returned; if ctx is done while waiting, the attempts stop.
+func Retry(ctx context.Context, policy RetryPolicy, idempotent bool, op func(context.Context) error) error {
+	_, err := RetryValue(ctx, policy, idempotent, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, op(ctx)
+	})
+	return err
+}
+
+// RetryValue is Retry for operations that return a value.
+func RetryValue[T any](ctx context.Context, policy RetryPolicy, idempotent bool, op func(context.Context) (T, error)) (T, error) {
+	attempts := policy.MaxAttempts
+	if !idempotent && !policy.RetryNonIdempotent {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		value, err := op(ctx)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !policy.retryable(err) {
+			return value, err
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return value, err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryValue(t *testing.T) {
+	ctx := context.Background()
+	policy := driverbase.DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.InitialBackoff = time.Millisecond
+
+	failing := func(code adbc.Status, failures int) (func(context.Context) (int, error), *int) {
+		calls := 0
+		return func(context.Context) (int, error) {
+			calls++
+			if calls <= failures {
+				return 0, adbc.Error{Code: code, Msg: "failed"}
+			}
+			return calls, nil
+		}, &calls
+	}
+
+	// transient errors are retried until the op succeeds
+	op, calls := failing(adbc.StatusIO, 2)
+	value, err := driverbase.RetryValue(ctx, policy, true, op)
+	require.NoError(t, err)
+	assert.Equal(t, 3, value)
+	assert.Equal(t, 3, *calls)
+
+	// or until the attempts are exhausted
+	op, calls = failing(adbc.StatusTimeout, 5)
+	_, err = driverbase.RetryValue(ctx, policy, true, op)
+	assert.ErrorContains(t, err, "failed")
+	assert.Equal(t, 3, *calls)
+
+	// other errors are returned right away
+	op, calls = failing(adbc.StatusInvalidArgument, 1)
+	_, err = driverbase.RetryValue(ctx, policy, true, op)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *calls)
+
+	// operations that aren't idempotent are only retried if allowed
+	op, calls = failing(adbc.StatusIO, 1)
+	_, err = driverbase.RetryValue(ctx, policy, false, op)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *calls)
+
+	policy.RetryNonIdempotent = true
+	op, calls = failing(adbc.StatusIO, 1)
+	_, err = driverbase.RetryValue(ctx, policy, false, op)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *calls)
+
+	// the default policy doesn't retry
+	op, calls = failing(adbc.StatusIO, 1)
+	_, err = driverbase.RetryValue(ctx, driverbase.DefaultRetryPolicy(), true, op)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryCancel(t *testing.T) {
+	policy := driverbase.DefaultRetryPolicy()
+	policy.MaxAttempts = 10
+	policy.InitialBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	start := time.Now()
+	err := driverbase.Retry(ctx, policy, true, func(context.Context) error {
+		calls++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return adbc.Error{Code: adbc.StatusIO, Msg: "failed"}
+	})
+	assert.ErrorContains(t, err, "failed")
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Minute)
+
+	// context errors are never retried
+	calls = 0
+	err = driverbase.Retry(context.Background(), policy, true, func(context.Context) error {
+		calls++
+		return context.DeadlineExceeded
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryOptions(t *testing.T) {
+	info := driverbase.DefaultDriverInfo("test")
+	require.NoError(t, info.RegisterRetryOptions())
+	drv := driverbase.NewDriver(&driverImpl{
+		DriverImplBase: driverbase.NewDriverImplBase(info, memory.DefaultAllocator),
+		handler:        slog.NewTextHandler(io.Discard, nil),
+	})
+
+	db, err := drv.NewDatabase(nil)
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, db)
+	dbOpts := db.(adbc.GetSetOptions)
+
+	value, err := dbOpts.GetOption(adbc.OptionKeyRetryCodes)
+	require.NoError(t, err)
+	assert.Equal(t, "io,timeout", value)
+
+	require.NoError(t, dbOpts.SetOption(adbc.OptionKeyRetryCodes, "timeout, not_found"))
+	require.NoError(t, dbOpts.SetOptionInt(adbc.OptionKeyRetryMaxAttempts, 4))
+	require.NoError(t, dbOpts.SetOption(adbc.OptionKeyRetryInitialBackoff, "5ms"))
+	assert.ErrorContains(t, dbOpts.SetOption(adbc.OptionKeyRetryCodes, "bogus"), "unknown error code 'bogus'")
+	assert.ErrorContains(t, dbOpts.SetOption(adbc.OptionKeyRetryMaxAttempts, "0"), "expected a positive integer")
+	assert.ErrorContains(t, dbOpts.SetOptionDouble(adbc.OptionKeyRetryJitter, 1.5), "expected a number between 0 and 1")
+
+	// connections start with the policy of the database
+	cnxn, err := db.Open(context.Background())
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, cnxn)
+	cnxnOpts := cnxn.(adbc.GetSetOptions)
+
+	value, err = cnxnOpts.GetOption(adbc.OptionKeyRetryCodes)
+	require.NoError(t, err)
+	assert.Equal(t, "not_found,timeout", value)
+	attempts, err := cnxnOpts.GetOptionInt(adbc.OptionKeyRetryMaxAttempts)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, attempts)
+	value, err = cnxnOpts.GetOption(adbc.OptionKeyRetryInitialBackoff)
+	require.NoError(t, err)
+	assert.Equal(t, "5ms", value)
+
+	// but can override it
+	require.NoError(t, cnxnOpts.SetOption(adbc.OptionKeyRetryNonIdempotent, adbc.OptionValueEnabled))
+	value, err = cnxnOpts.GetOption(adbc.OptionKeyRetryNonIdempotent)
+	require.NoError(t, err)
+	assert.Equal(t, adbc.OptionValueEnabled, value)
+	value, err = dbOpts.GetOption(adbc.OptionKeyRetryNonIdempotent)
+	require.NoError(t, err)
+	assert.Equal(t, adbc.OptionValueDisabled, value)
+}
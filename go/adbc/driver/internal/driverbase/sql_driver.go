@@ -0,0 +1,661 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+const defaultSQLBatchSize = 1024
+
+// SQLBackend describes a database reached through its database/sql driver,
+// for NewSQLDriver.
+type SQLBackend struct {
+	// Driver opens connections to the database. The adbc.OptionKeyURI
+	// option of a database is passed to it as the data source name.
+	Driver sqldriver.Driver
+	// Placeholder returns the placeholder of the query parameter with the
+	// given 1-based ordinal, for the metadata queries. If nil, "?" is used.
+	Placeholder func(ordinal int) string
+	// QuoteIdentifier quotes a catalog, schema or table name. If nil, names
+	// are quoted with double quotes as in standard SQL.
+	QuoteIdentifier func(name string) string
+	// ColumnType overrides the Arrow types of result columns, see
+	// ColumnTypesToSchema. It may be nil.
+	ColumnType ColumnTypeMapper
+	// TableTypes are reported by GetTableTypes. If nil, they are read from
+	// INFORMATION_SCHEMA.TABLES.
+	TableTypes []string
+	// BatchSize is the number of rows per record of results. If zero,
+	// 1024 rows are used.
+	BatchSize int
+}
+
+func (b *SQLBackend) quoteIdentifier(name string) string {
+	if b.QuoteIdentifier != nil {
+		return b.QuoteIdentifier(name)
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (b *SQLBackend) batchSize() int {
+	if b.BatchSize > 0 {
+		return b.BatchSize
+	}
+	return defaultSQLBatchSize
+}
+
+// NewSQLDriver returns an ADBC driver for a database/sql driver. Each
+// connection holds one connection of the database/sql driver.
+//
+// Statements run queries and updates once per row of the parameters bound
+// since the last execution, which are converted with ArrowToNamedValues,
+// and read results with NewRowsReader. GetObjects and GetTableTypes use an
+// InformationSchemaEnumerator, and disabling autocommit starts a
+// transaction. Bulk ingestion, partitions and Substrait plans are not
+// supported.
+func NewSQLDriver(info *DriverInfo, alloc memory.Allocator, backend SQLBackend) Driver {
+	return NewDriver(&sqlDriverImpl{
+		DriverImplBase: NewDriverImplBase(info, alloc),
+		backend:        backend,
+	})
+}
+
+type sqlDriverImpl struct {
+	DriverImplBase
+	backend SQLBackend
+}
+
+func (d *sqlDriverImpl) NewDatabase(opts map[string]string) (adbc.Database, error) {
+	return d.NewDatabaseWithContext(context.Background(), opts)
+}
+
+func (d *sqlDriverImpl) NewDatabaseWithContext(ctx context.Context, opts map[string]string) (adbc.Database, error) {
+	opts = maps.Clone(opts)
+	dbBase, err := NewDatabaseImplBase(ctx, &d.DriverImplBase)
+	if err != nil {
+		return nil, err
+	}
+	db := &sqlDatabaseImpl{DatabaseImplBase: dbBase, backend: &d.backend}
+	if err := db.ValidateOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := db.SetRetryOptions(opts); err != nil {
+		return nil, err
+	}
+	for key, val := range opts {
+		if err := db.SetOption(key, val); err != nil {
+			return nil, err
+		}
+	}
+	return NewDatabase(db), nil
+}
+
+type sqlDatabaseImpl struct {
+	DatabaseImplBase
+	backend *SQLBackend
+	uri     string
+
+	mu sync.Mutex
+	// db is opened along with the first connection
+	db *sql.DB
+}
+
+func (d *sqlDatabaseImpl) GetOption(key string) (string, error) {
+	if key == adbc.OptionKeyURI {
+		return d.uri, nil
+	}
+	return d.DatabaseImplBase.GetOption(key)
+}
+
+func (d *sqlDatabaseImpl) SetOption(key, val string) error {
+	if key != adbc.OptionKeyURI {
+		return d.DatabaseImplBase.SetOption(key, val)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.db != nil {
+		return d.ErrorHelper.Errorf(adbc.StatusInvalidState, "cannot change the URI after opening a connection")
+	}
+	d.uri = val
+	return nil
+}
+
+func (d *sqlDatabaseImpl) Open(ctx context.Context) (adbc.Connection, error) {
+	d.mu.Lock()
+	if d.db == nil {
+		connector, err := newSQLConnector(d.backend.Driver, d.uri)
+		if err != nil {
+			d.mu.Unlock()
+			return nil, d.ErrorHelper.sqlError(err)
+		}
+		d.db = sql.OpenDB(connector)
+	}
+	db := d.db
+	d.mu.Unlock()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, d.ErrorHelper.sqlError(err)
+	}
+
+	cnxn := &sqlConnectionImpl{
+		ConnectionImplBase: NewConnectionImplBase(&d.DatabaseImplBase),
+		backend:            d.backend,
+		conn:               conn,
+	}
+	enumerator := &InformationSchemaEnumerator{
+		Queryer:     cnxn,
+		Placeholder: d.backend.Placeholder,
+		TableTypes:  d.backend.TableTypes,
+	}
+	return NewConnectionBuilder(cnxn).
+		WithAutocommitSetter(cnxn).
+		WithDbObjectsEnumerator(enumerator).
+		WithTableTypeLister(enumerator).
+		Connection(), nil
+}
+
+func (d *sqlDatabaseImpl) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.db == nil {
+		return nil
+	}
+	err := d.db.Close()
+	d.db = nil
+	return d.ErrorHelper.sqlError(err)
+}
+
+// newSQLConnector returns a connector opening connections to the data
+// source name dsn.
+func newSQLConnector(drv sqldriver.Driver, dsn string) (sqldriver.Connector, error) {
+	if drv == nil {
+		return nil, errors.New("no database/sql driver")
+	}
+	if drvCtx, ok := drv.(sqldriver.DriverContext); ok {
+		return drvCtx.OpenConnector(dsn)
+	}
+	return dsnConnector{driver: drv, dsn: dsn}, nil
+}
+
+type dsnConnector struct {
+	driver sqldriver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (sqldriver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() sqldriver.Driver {
+	return c.driver
+}
+
+// sqlError converts an error of a database/sql driver to an adbc.Error.
+// The status is found from the SQLSTATE of the error, if it has a
+// SQLState method as the errors of some drivers do.
+func (helper *ErrorHelper) sqlError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var adbcErr adbc.Error
+	if errors.As(err, &adbcErr) {
+		return err
+	}
+
+	code := adbc.StatusInternal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = adbc.StatusCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = adbc.StatusTimeout
+	case errors.Is(err, sqldriver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		code = adbc.StatusIO
+	}
+
+	var sqlState [5]byte
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		code = StatusForSQLState(stateErr.SQLState(), code)
+		sqlState = SQLState(stateErr.SQLState())
+	}
+	return adbc.Error{
+		Code:     code,
+		Msg:      fmt.Sprintf("[%s] %s", helper.DriverName, err),
+		SqlState: sqlState,
+	}
+}
+
+// sqlExecutor runs queries on the connection, or in its transaction.
+type sqlExecutor interface {
+	SQLQueryer
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type sqlConnectionImpl struct {
+	ConnectionImplBase
+	backend *SQLBackend
+	conn    *sql.Conn
+	// tx is the transaction in progress while autocommit is disabled
+	tx *sql.Tx
+}
+
+func (c *sqlConnectionImpl) executor() sqlExecutor {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.conn
+}
+
+// QueryContext implements SQLQueryer, running queries in the transaction
+// if there is one.
+func (c *sqlConnectionImpl) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := c.executor().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, c.ErrorHelper.sqlError(err)
+	}
+	return rows, nil
+}
+
+// SetAutocommit implements AutocommitSetter.
+func (c *sqlConnectionImpl) SetAutocommit(enabled bool) error {
+	if enabled {
+		if c.tx == nil {
+			return nil
+		}
+		err := c.tx.Commit()
+		c.tx = nil
+		return c.ErrorHelper.sqlError(err)
+	}
+	if c.tx != nil {
+		return nil
+	}
+	return c.begin()
+}
+
+func (c *sqlConnectionImpl) begin() error {
+	// the context of a transaction must live until it is committed or
+	// rolled back
+	tx, err := c.conn.BeginTx(context.Background(), nil)
+	if err != nil {
+		return c.ErrorHelper.sqlError(err)
+	}
+	c.tx = tx
+	return nil
+}
+
+func (c *sqlConnectionImpl) Commit(context.Context) error {
+	if c.tx == nil {
+		return c.ErrorHelper.Errorf(adbc.StatusInvalidState, "Commit: no transaction in progress")
+	}
+	if err := c.tx.Commit(); err != nil {
+		c.tx = nil
+		return c.ErrorHelper.sqlError(err)
+	}
+	return c.begin()
+}
+
+func (c *sqlConnectionImpl) Rollback(context.Context) error {
+	if c.tx == nil {
+		return c.ErrorHelper.Errorf(adbc.StatusInvalidState, "Rollback: no transaction in progress")
+	}
+	if err := c.tx.Rollback(); err != nil {
+		c.tx = nil
+		return c.ErrorHelper.sqlError(err)
+	}
+	return c.begin()
+}
+
+func (c *sqlConnectionImpl) GetTableSchema(ctx context.Context, catalog *string, dbSchema *string, tableName string) (*arrow.Schema, error) {
+	var names []string
+	if catalog != nil && *catalog != "" {
+		names = append(names, c.backend.quoteIdentifier(*catalog))
+	}
+	if dbSchema != nil && *dbSchema != "" {
+		names = append(names, c.backend.quoteIdentifier(*dbSchema))
+	}
+	names = append(names, c.backend.quoteIdentifier(tableName))
+
+	rows, err := c.QueryContext(ctx, "SELECT * FROM "+strings.Join(names, ".")+" WHERE 1 = 0")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, c.ErrorHelper.sqlError(err)
+	}
+	return ColumnTypesToSchema(columns, c.backend.ColumnType), nil
+}
+
+func (c *sqlConnectionImpl) NewStatement() (adbc.Statement, error) {
+	return NewStatement(&sqlStatementImpl{
+		StatementImplBase: NewStatementImplBase(&c.ConnectionImplBase, c.ErrorHelper),
+		cnxn:              c,
+	}), nil
+}
+
+func (c *sqlConnectionImpl) Close() error {
+	if c.tx != nil {
+		// uncommitted changes are discarded
+		_ = c.tx.Rollback()
+		c.tx = nil
+	}
+	return c.ErrorHelper.sqlError(c.conn.Close())
+}
+
+type sqlStatementImpl struct {
+	StatementImplBase
+	cnxn     *sqlConnectionImpl
+	query    string
+	prepared *sql.Stmt
+}
+
+func (st *sqlStatementImpl) Base() *StatementImplBase {
+	return &st.StatementImplBase
+}
+
+func (st *sqlStatementImpl) closePrepared() error {
+	if st.prepared == nil {
+		return nil
+	}
+	err := st.prepared.Close()
+	st.prepared = nil
+	return st.ErrorHelper.sqlError(err)
+}
+
+func (st *sqlStatementImpl) Close() error {
+	if err := st.State.Close(); err != nil {
+		return err
+	}
+	return st.closePrepared()
+}
+
+func (st *sqlStatementImpl) SetSqlQuery(query string) error {
+	if err := st.State.SetQuery(); err != nil {
+		return err
+	}
+	st.query = query
+	return st.closePrepared()
+}
+
+func (st *sqlStatementImpl) SetSubstraitPlan([]byte) error {
+	return st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "SetSubstraitPlan")
+}
+
+func (st *sqlStatementImpl) Prepare(ctx context.Context) error {
+	if err := st.State.Prepare(); err != nil {
+		return err
+	}
+	if err := st.closePrepared(); err != nil {
+		return err
+	}
+
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+	prepared, err := st.cnxn.conn.PrepareContext(ctx, st.query)
+	if err != nil {
+		// the query is still set, just not prepared
+		_ = st.State.SetQuery()
+		return st.ErrorHelper.sqlError(err)
+	}
+	st.prepared = prepared
+	return nil
+}
+
+func (st *sqlStatementImpl) Bind(_ context.Context, values arrow.Record) error {
+	return st.State.Bind(values)
+}
+
+func (st *sqlStatementImpl) BindStream(_ context.Context, stream array.RecordReader) error {
+	return st.State.BindStream(stream)
+}
+
+func (st *sqlStatementImpl) GetParameterSchema() (*arrow.Schema, error) {
+	return nil, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "GetParameterSchema")
+}
+
+func (st *sqlStatementImpl) ExecuteSchema(context.Context) (*arrow.Schema, error) {
+	return nil, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "ExecuteSchema")
+}
+
+func (st *sqlStatementImpl) ExecutePartitions(context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	return nil, adbc.Partitions{}, -1, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "ExecutePartitions")
+}
+
+// params takes the bound parameters, which are used by one execution, or
+// returns nil if there are none.
+func (st *sqlStatementImpl) params() *sqlParams {
+	bound, stream := st.State.TakeBound()
+	if bound == nil && stream == nil {
+		return nil
+	}
+	return &sqlParams{rec: bound, stream: stream}
+}
+
+func (st *sqlStatementImpl) queryContext(ctx context.Context, args []any) (*sql.Rows, error) {
+	if st.prepared == nil {
+		return st.cnxn.QueryContext(ctx, st.query, args...)
+	}
+	stmt := st.prepared
+	if st.cnxn.tx != nil {
+		stmt = st.cnxn.tx.StmtContext(ctx, stmt)
+	}
+	rows, err := stmt.QueryContext(ctx, args...)
+	return rows, st.ErrorHelper.sqlError(err)
+}
+
+func (st *sqlStatementImpl) execContext(ctx context.Context, args []any) (sql.Result, error) {
+	if st.prepared == nil {
+		res, err := st.cnxn.executor().ExecContext(ctx, st.query, args...)
+		return res, st.ErrorHelper.sqlError(err)
+	}
+	stmt := st.prepared
+	if st.cnxn.tx != nil {
+		stmt = st.cnxn.tx.StmtContext(ctx, stmt)
+	}
+	res, err := stmt.ExecContext(ctx, args...)
+	return res, st.ErrorHelper.sqlError(err)
+}
+
+// ExecuteQuery runs the query once per row of bound parameters, and
+// returns a reader over the results of all of them, which must have the
+// same columns. The number of rows is not known.
+func (st *sqlStatementImpl) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	done, err := st.State.BeginExecute("ExecuteQuery")
+	if err != nil {
+		return nil, -1, err
+	}
+	defer done()
+
+	params := st.params()
+	var args []any
+	if params != nil {
+		if args, err = params.next(); errors.Is(err, io.EOF) {
+			// no rows of parameters, so no results
+			params.release()
+			rdr, err := array.NewRecordReader(arrow.NewSchema(nil, nil), nil)
+			return rdr, 0, err
+		} else if err != nil {
+			params.release()
+			return nil, -1, err
+		}
+	}
+
+	// the rows are closed once the context is cancelled, so it lives
+	// until the reader is released
+	ctx, cancel := st.WithCancel(ctx)
+	rdr, err := st.newReader(ctx, args, params)
+	if err != nil {
+		cancel()
+		if params != nil {
+			params.release()
+		}
+		return nil, -1, err
+	}
+	return CancelOnRelease(st.State.TrackReader(rdr), cancel), -1, nil
+}
+
+// newReader runs the query with args, and returns a reader over its
+// results followed by those of the remaining rows of params, if not nil.
+func (st *sqlStatementImpl) newReader(ctx context.Context, args []any, params *sqlParams) (*rowsReader, error) {
+	rows, err := st.queryContext(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, st.ErrorHelper.sqlError(err)
+	}
+
+	schema := ColumnTypesToSchema(columns, st.cnxn.backend.ColumnType)
+	batchSize := st.cnxn.backend.batchSize()
+	if params == nil {
+		return newRowsReader(st.cnxn.Alloc, schema, batchSize, rows, nil, nil), nil
+	}
+	return newRowsReader(st.cnxn.Alloc, schema, batchSize, rows, func() (*sql.Rows, error) {
+		args, err := params.next()
+		if err != nil {
+			return nil, err
+		}
+		return st.queryContext(ctx, args)
+	}, params.release), nil
+}
+
+// ExecuteUpdate runs the statement once per row of bound parameters, and
+// returns the total number of rows affected, or -1 if it is not known.
+func (st *sqlStatementImpl) ExecuteUpdate(ctx context.Context) (int64, error) {
+	done, err := st.State.BeginExecute("ExecuteUpdate")
+	if err != nil {
+		return -1, err
+	}
+	defer done()
+	ctx, cancel := st.WithCancel(ctx)
+	defer cancel()
+
+	params := st.params()
+	if params == nil {
+		res, err := st.execContext(ctx, nil)
+		if err != nil {
+			return -1, err
+		}
+		return rowsAffected(res), nil
+	}
+	defer params.release()
+
+	total := int64(0)
+	for {
+		args, err := params.next()
+		if errors.Is(err, io.EOF) {
+			return total, nil
+		} else if err != nil {
+			return -1, err
+		}
+
+		res, err := st.execContext(ctx, args)
+		if err != nil {
+			return -1, err
+		}
+		if n := rowsAffected(res); n < 0 || total < 0 {
+			total = -1
+		} else {
+			total += n
+		}
+	}
+}
+
+func rowsAffected(res sql.Result) int64 {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// sqlParams iterates over the rows of bound parameters, which it owns.
+type sqlParams struct {
+	rec    arrow.Record
+	row    int
+	stream array.RecordReader
+}
+
+// next returns the arguments for the next row of parameters, or io.EOF
+// once there are no more rows.
+func (p *sqlParams) next() ([]any, error) {
+	for p.rec == nil || p.row >= int(p.rec.NumRows()) {
+		if p.rec != nil {
+			p.rec.Release()
+			p.rec = nil
+		}
+		if p.stream == nil || !p.stream.Next() {
+			if p.stream != nil && p.stream.Err() != nil {
+				return nil, p.stream.Err()
+			}
+			return nil, io.EOF
+		}
+		p.rec = p.stream.Record()
+		p.rec.Retain()
+		p.row = 0
+	}
+
+	values, err := ArrowToNamedValues(p.rec, p.row)
+	if err != nil {
+		return nil, err
+	}
+	p.row++
+
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value.Value
+	}
+	return args, nil
+}
+
+func (p *sqlParams) release() {
+	if p.rec != nil {
+		p.rec.Release()
+		p.rec = nil
+	}
+	if p.stream != nil {
+		p.stream.Release()
+		p.stream = nil
+	}
+}
+
+var (
+	_ DriverImpl     = (*sqlDriverImpl)(nil)
+	_ DatabaseImpl   = (*sqlDatabaseImpl)(nil)
+	_ ConnectionImpl = (*sqlConnectionImpl)(nil)
+	_ StatementImpl  = (*sqlStatementImpl)(nil)
+)
@@ -0,0 +1,258 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"modernc.org/sqlite"
+)
+
+func TestArrowToNamedValues(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "i", Type: arrow.PrimitiveTypes.Int32, Nullable: true},
+		{Name: "s", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "b", Type: arrow.BinaryTypes.Binary, Nullable: true},
+		{Name: "ts", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+	}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[
+		{"i": 1, "s": "a", "b": "AQI=", "ts": "2024-01-02T03:04:05Z"},
+		{"i": null, "s": null, "b": null, "ts": null}
+	]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	params, err := driverbase.ArrowToNamedValues(rec, 0)
+	require.NoError(t, err)
+	require.Len(t, params, 4)
+	assert.Equal(t, 1, params[0].Ordinal)
+	assert.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, params[0].Value)
+	assert.Equal(t, sql.NullString{String: "a", Valid: true}, params[1].Value)
+	assert.Equal(t, []byte{1, 2}, params[2].Value)
+	assert.Equal(t, sql.NullTime{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true}, params[3].Value)
+
+	params, err = driverbase.ArrowToNamedValues(rec, 1)
+	require.NoError(t, err)
+	assert.False(t, params[0].Value.(sql.NullInt64).Valid)
+	assert.False(t, params[1].Value.(sql.NullString).Valid)
+	assert.Nil(t, params[2].Value)
+	assert.False(t, params[3].Value.(sql.NullTime).Valid)
+
+	listSchema := arrow.NewSchema([]arrow.Field{{Name: "l", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64)}}, nil)
+	listRec, _, err := array.RecordFromJSON(mem, listSchema, strings.NewReader(`[{"l": [1]}]`))
+	require.NoError(t, err)
+	defer listRec.Release()
+	var adbcErr adbc.Error
+	_, err = driverbase.ArrowToNamedValues(listRec, 0)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
+
+// openSQLite opens a connection of a driver made with NewSQLDriver for an
+// in-memory SQLite database.
+func openSQLite(t *testing.T, mem memory.Allocator) adbc.Connection {
+	drv := driverbase.NewSQLDriver(driverbase.DefaultDriverInfo("SQLite"), mem, driverbase.SQLBackend{
+		Driver:    &sqlite.Driver{},
+		BatchSize: 2,
+	})
+	db, err := drv.NewDatabase(map[string]string{adbc.OptionKeyURI: ":memory:"})
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	cnxn, err := db.Open(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, cnxn.Close()) })
+	return cnxn
+}
+
+func execUpdate(t *testing.T, cnxn adbc.Connection, query string) int64 {
+	t.Helper()
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+	require.NoError(t, stmt.SetSqlQuery(query))
+	n, err := stmt.ExecuteUpdate(context.Background())
+	require.NoError(t, err)
+	return n
+}
+
+func TestSQLDriver(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openSQLite(t, mem)
+	ctx := context.Background()
+
+	execUpdate(t, cnxn, "CREATE TABLE items (id INTEGER NOT NULL, name TEXT, price REAL, data BLOB)")
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	// a prepared insert, run once per row of parameters
+	require.NoError(t, stmt.SetSqlQuery("INSERT INTO items VALUES (?, ?, ?, ?)"))
+	require.NoError(t, stmt.Prepare(ctx))
+	paramSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "price", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "data", Type: arrow.BinaryTypes.Binary, Nullable: true},
+	}, nil)
+	params, _, err := array.RecordFromJSON(mem, paramSchema, strings.NewReader(`[
+		{"id": 1, "name": "one", "price": 1.5, "data": "AQ=="},
+		{"id": 2, "name": null, "price": 2.5, "data": null},
+		{"id": 3, "name": "three", "price": null, "data": "AwM="}
+	]`))
+	require.NoError(t, err)
+	require.NoError(t, stmt.Bind(ctx, params))
+	params.Release()
+	n, err := stmt.ExecuteUpdate(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+
+	// results span records of BatchSize rows
+	require.NoError(t, stmt.SetSqlQuery("SELECT id, name, price, data FROM items ORDER BY id"))
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	require.NoError(t, err)
+	table := tableFromRecordReader(rdr)
+	defer table.Release()
+	expected, err := array.TableFromJSON(mem, table.Schema(), []string{`[
+		{"id": 1, "name": "one", "price": 1.5, "data": "AQ=="},
+		{"id": 2, "name": null, "price": 2.5, "data": null},
+		{"id": 3, "name": "three", "price": null, "data": "AwM="}
+	]`})
+	require.NoError(t, err)
+	defer expected.Release()
+	assert.Truef(t, array.TableEqual(expected, table), "expected: %s\ngot: %s", expected, table)
+	assert.Equal(t, []arrow.DataType{arrow.PrimitiveTypes.Int64, arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64, arrow.BinaryTypes.Binary},
+		[]arrow.DataType{table.Schema().Field(0).Type, table.Schema().Field(1).Type, table.Schema().Field(2).Type, table.Schema().Field(3).Type})
+
+	// a query with parameters returns the results of every row of them
+	require.NoError(t, stmt.SetSqlQuery("SELECT name FROM items WHERE id = ?"))
+	idSchema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	ids, _, err := array.RecordFromJSON(mem, idSchema, strings.NewReader(`[{"id": 3}, {"id": 1}]`))
+	require.NoError(t, err)
+	require.NoError(t, stmt.Bind(ctx, ids))
+	ids.Release()
+	rdr, _, err = stmt.ExecuteQuery(ctx)
+	require.NoError(t, err)
+	var names []string
+	for rdr.Next() {
+		col := rdr.Record().Column(0).(*array.String)
+		for i := 0; i < col.Len(); i++ {
+			names = append(names, col.Value(i))
+		}
+	}
+	require.NoError(t, rdr.Err())
+	rdr.Release()
+	assert.Equal(t, []string{"three", "one"}, names)
+
+	schema, err := cnxn.GetTableSchema(ctx, nil, nil, "items")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name", "price", "data"},
+		[]string{schema.Field(0).Name, schema.Field(1).Name, schema.Field(2).Name, schema.Field(3).Name})
+	assert.Equal(t, arrow.PrimitiveTypes.Int64, schema.Field(0).Type)
+	assert.Equal(t, arrow.BinaryTypes.Binary, schema.Field(3).Type)
+}
+
+func TestSQLDriverTransactions(t *testing.T) {
+	cnxn := openSQLite(t, memory.DefaultAllocator)
+	ctx := context.Background()
+	execUpdate(t, cnxn, "CREATE TABLE t (v INTEGER)")
+
+	require.NoError(t, cnxn.(adbc.GetSetOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueDisabled))
+	assert.EqualValues(t, 1, execUpdate(t, cnxn, "INSERT INTO t VALUES (1)"))
+	require.NoError(t, cnxn.Commit(ctx))
+	assert.EqualValues(t, 1, execUpdate(t, cnxn, "INSERT INTO t VALUES (2)"))
+	require.NoError(t, cnxn.Rollback(ctx))
+	require.NoError(t, cnxn.(adbc.GetSetOptions).SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueEnabled))
+
+	// only the committed row is left
+	assert.EqualValues(t, 1, execUpdate(t, cnxn, "DELETE FROM t"))
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, cnxn.Commit(ctx), &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+}
+
+func TestSQLDriverInformationSchema(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openSQLite(t, mem)
+	ctx := context.Background()
+
+	// SQLite has no INFORMATION_SCHEMA, so emulate one
+	execUpdate(t, cnxn, "ATTACH DATABASE ':memory:' AS information_schema")
+	execUpdate(t, cnxn, "CREATE TABLE information_schema.schemata (catalog_name TEXT, schema_name TEXT)")
+	execUpdate(t, cnxn, "CREATE TABLE information_schema.tables (table_catalog TEXT, table_schema TEXT, table_name TEXT, table_type TEXT)")
+	execUpdate(t, cnxn, `CREATE TABLE information_schema.columns (table_catalog TEXT, table_schema TEXT, table_name TEXT,
+		column_name TEXT, ordinal_position INTEGER, data_type TEXT, is_nullable TEXT, column_default TEXT)`)
+	execUpdate(t, cnxn, "INSERT INTO information_schema.schemata VALUES ('db', 'main'), ('db', 'temp')")
+	execUpdate(t, cnxn, "INSERT INTO information_schema.tables VALUES ('db', 'main', 'items', 'BASE TABLE'), ('db', 'main', 'names', 'VIEW')")
+	execUpdate(t, cnxn, `INSERT INTO information_schema.columns VALUES
+		('db', 'main', 'items', 'name', 2, 'TEXT', 'YES', NULL),
+		('db', 'main', 'items', 'id', 1, 'INTEGER', 'NO', '0'),
+		('db', 'main', 'names', 'name', 1, 'TEXT', 'YES', NULL)`)
+
+	rdr, err := cnxn.GetObjects(ctx, adbc.ObjectDepthAll, nil, driverbase.Nullable("main"), driverbase.Nullable("item%"), nil, nil)
+	require.NoError(t, err)
+	table := tableFromRecordReader(rdr)
+	defer table.Release()
+	expected, err := array.TableFromJSON(mem, adbc.GetObjectsSchema, []string{`[
+		{
+			"catalog_name": "db",
+			"catalog_db_schemas": [
+				{
+					"db_schema_name": "main",
+					"db_schema_tables": [
+						{
+							"table_name": "items",
+							"table_type": "BASE TABLE",
+							"table_columns": [
+								{"column_name": "id", "ordinal_position": 1, "xdbc_type_name": "INTEGER", "xdbc_nullable": 0, "xdbc_is_nullable": "NO", "xdbc_column_def": "0"},
+								{"column_name": "name", "ordinal_position": 2, "xdbc_type_name": "TEXT", "xdbc_nullable": 1, "xdbc_is_nullable": "YES"}
+							]
+						}
+					]
+				}
+			]
+		}
+	]`})
+	require.NoError(t, err)
+	defer expected.Release()
+	assert.Truef(t, array.TableEqual(expected, table), "expected: %s\ngot: %s", expected, table)
+
+	rdr, err = cnxn.GetTableTypes(ctx)
+	require.NoError(t, err)
+	tableTypes := tableFromRecordReader(rdr)
+	defer tableTypes.Release()
+	assert.EqualValues(t, 2, tableTypes.NumRows())
+}
@@ -0,0 +1,215 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+)
+
+// SQLQueryer runs database/sql queries. It is implemented by *sql.DB,
+// *sql.Conn and *sql.Tx.
+type SQLQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// InformationSchemaEnumerator implements DbObjectsEnumerator and
+// TableTypeLister with queries of the INFORMATION_SCHEMA views of the SQL
+// standard, for backends that provide them.
+type InformationSchemaEnumerator struct {
+	// Queryer runs the queries.
+	Queryer SQLQueryer
+	// Placeholder returns the placeholder of the query parameter with the
+	// given 1-based ordinal. If nil, "?" is used.
+	Placeholder func(ordinal int) string
+	// TableTypes are listed by ListTableTypes. If nil, they are the table
+	// types found in INFORMATION_SCHEMA.TABLES.
+	TableTypes []string
+}
+
+// query runs a query with the given conditions, which use the placeholder
+// "?" for their argument, and calls scan for every row.
+func (e *InformationSchemaEnumerator) query(ctx context.Context, query string, conditions []string, args []any, scan func(*sql.Rows) error) error {
+	var sb strings.Builder
+	sb.WriteString(query)
+	for i, cond := range conditions {
+		if i == 0 {
+			sb.WriteString(" WHERE ")
+		} else {
+			sb.WriteString(" AND ")
+		}
+		placeholder := "?"
+		if e.Placeholder != nil {
+			placeholder = e.Placeholder(i + 1)
+		}
+		sb.WriteString(strings.Replace(cond, "?", placeholder, 1))
+	}
+
+	rows, err := e.Queryer.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func patternOrAll(pattern *string) string {
+	if pattern == nil {
+		return "%"
+	}
+	return *pattern
+}
+
+// GetCatalogs implements DbObjectsEnumerator.
+func (e *InformationSchemaEnumerator) GetCatalogs(ctx context.Context, catalogFilter *string) ([]string, error) {
+	catalogs := make([]string, 0)
+	err := e.query(ctx, "SELECT DISTINCT CATALOG_NAME FROM INFORMATION_SCHEMA.SCHEMATA",
+		[]string{"CATALOG_NAME LIKE ?"}, []any{patternOrAll(catalogFilter)},
+		func(rows *sql.Rows) error {
+			var catalog string
+			if err := rows.Scan(&catalog); err != nil {
+				return err
+			}
+			catalogs = append(catalogs, catalog)
+			return nil
+		})
+	return catalogs, err
+}
+
+// GetDBSchemasForCatalog implements DbObjectsEnumerator.
+func (e *InformationSchemaEnumerator) GetDBSchemasForCatalog(ctx context.Context, catalog string, schemaFilter *string) ([]string, error) {
+	schemas := make([]string, 0)
+	err := e.query(ctx, "SELECT SCHEMA_NAME FROM INFORMATION_SCHEMA.SCHEMATA",
+		[]string{"CATALOG_NAME = ?", "SCHEMA_NAME LIKE ?"}, []any{catalog, patternOrAll(schemaFilter)},
+		func(rows *sql.Rows) error {
+			var schema string
+			if err := rows.Scan(&schema); err != nil {
+				return err
+			}
+			schemas = append(schemas, schema)
+			return nil
+		})
+	return schemas, err
+}
+
+// GetTablesForDBSchema implements DbObjectsEnumerator. Constraints are not
+// listed.
+func (e *InformationSchemaEnumerator) GetTablesForDBSchema(ctx context.Context, catalog string, schema string, tableFilter *string, columnFilter *string, includeColumns bool) ([]TableInfo, error) {
+	tables := make([]TableInfo, 0)
+	index := make(map[string]int)
+	err := e.query(ctx, "SELECT TABLE_NAME, TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES",
+		[]string{"TABLE_CATALOG = ?", "TABLE_SCHEMA = ?", "TABLE_NAME LIKE ?"},
+		[]any{catalog, schema, patternOrAll(tableFilter)},
+		func(rows *sql.Rows) error {
+			var table TableInfo
+			if err := rows.Scan(&table.TableName, &table.TableType); err != nil {
+				return err
+			}
+			index[table.TableName] = len(tables)
+			tables = append(tables, table)
+			return nil
+		})
+	if err != nil || !includeColumns {
+		return tables, err
+	}
+
+	for i := range tables {
+		tables[i].TableColumns = make([]ColumnInfo, 0)
+	}
+	err = e.query(ctx, "SELECT TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, DATA_TYPE, IS_NULLABLE, COLUMN_DEFAULT FROM INFORMATION_SCHEMA.COLUMNS",
+		[]string{"TABLE_CATALOG = ?", "TABLE_SCHEMA = ?", "TABLE_NAME LIKE ?", "COLUMN_NAME LIKE ?"},
+		[]any{catalog, schema, patternOrAll(tableFilter), patternOrAll(columnFilter)},
+		func(rows *sql.Rows) error {
+			var (
+				tableName, columnName          string
+				ordinal                        sql.NullInt32
+				dataType, nullable, colDefault sql.NullString
+			)
+			if err := rows.Scan(&tableName, &columnName, &ordinal, &dataType, &nullable, &colDefault); err != nil {
+				return err
+			}
+			i, ok := index[tableName]
+			if !ok {
+				// the table was created after it was listed
+				return nil
+			}
+
+			column := ColumnInfo{ColumnName: columnName}
+			if ordinal.Valid {
+				column.OrdinalPosition = Nullable(ordinal.Int32)
+			}
+			if dataType.Valid {
+				column.XdbcTypeName = Nullable(dataType.String)
+			}
+			if nullable.Valid {
+				column.XdbcIsNullable = Nullable(nullable.String)
+				switch strings.ToUpper(nullable.String) {
+				case "YES":
+					column.XdbcNullable = Nullable(int16(1))
+				case "NO":
+					column.XdbcNullable = Nullable(int16(0))
+				}
+			}
+			if colDefault.Valid {
+				column.XdbcColumnDef = Nullable(colDefault.String)
+			}
+			tables[i].TableColumns = append(tables[i].TableColumns, column)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		slices.SortStableFunc(table.TableColumns, func(a, b ColumnInfo) int {
+			return cmp.Compare(ValueOrZero(a.OrdinalPosition), ValueOrZero(b.OrdinalPosition))
+		})
+	}
+	return tables, nil
+}
+
+// ListTableTypes implements TableTypeLister.
+func (e *InformationSchemaEnumerator) ListTableTypes(ctx context.Context) ([]string, error) {
+	if e.TableTypes != nil {
+		return e.TableTypes, nil
+	}
+
+	tableTypes := make([]string, 0)
+	err := e.query(ctx, "SELECT DISTINCT TABLE_TYPE FROM INFORMATION_SCHEMA.TABLES", nil, nil,
+		func(rows *sql.Rows) error {
+			var tableType string
+			if err := rows.Scan(&tableType); err != nil {
+				return err
+			}
+			tableTypes = append(tableTypes, tableType)
+			return nil
+		})
+	return tableTypes, err
+}
+
+var (
+	_ DbObjectsEnumerator = (*InformationSchemaEnumerator)(nil)
+	_ TableTypeLister     = (*InformationSchemaEnumerator)(nil)
+)
@@ -0,0 +1,536 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ArrowToNamedValues converts a row of bound parameters to the arguments of
+// a database/sql query. The parameters are positional, in the order of the
+// columns. Nulls are converted to invalid sql.Null* values, or to nil for
+// binary and null columns.
+func ArrowToNamedValues(batch arrow.Record, row int) ([]sqldriver.NamedValue, error) {
+	params := make([]sqldriver.NamedValue, batch.NumCols())
+	for i, field := range batch.Schema().Fields() {
+		params[i].Ordinal = i + 1
+		value, err := arrowToValue(field, batch.Column(i), row)
+		if err != nil {
+			return nil, err
+		}
+		params[i].Value = value
+	}
+	return params, nil
+}
+
+func arrowToValue(field arrow.Field, rawColumn arrow.Array, row int) (any, error) {
+	valid := rawColumn.IsValid(row)
+	switch column := rawColumn.(type) {
+	case *array.Null:
+		return nil, nil
+	case *array.Boolean:
+		return sql.NullBool{Bool: column.Value(row), Valid: valid}, nil
+	case *array.Int8:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Int16:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Int32:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Int64:
+		return sql.NullInt64{Int64: column.Value(row), Valid: valid}, nil
+	case *array.Uint8:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Uint16:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Uint32:
+		return sql.NullInt64{Int64: int64(column.Value(row)), Valid: valid}, nil
+	case *array.Uint64:
+		v := column.Value(row)
+		if valid && v > math.MaxInt64 {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("bind parameter '%s' value %d overflows int64", field.Name, v),
+			}
+		}
+		return sql.NullInt64{Int64: int64(v), Valid: valid}, nil
+	case *array.Float32:
+		return sql.NullFloat64{Float64: float64(column.Value(row)), Valid: valid}, nil
+	case *array.Float64:
+		return sql.NullFloat64{Float64: column.Value(row), Valid: valid}, nil
+	case *array.String:
+		return sql.NullString{String: column.Value(row), Valid: valid}, nil
+	case *array.LargeString:
+		return sql.NullString{String: column.Value(row), Valid: valid}, nil
+	case *array.Binary:
+		if !valid {
+			return nil, nil
+		}
+		// the value points into the record, which the caller may release
+		return slices.Clone(column.Value(row)), nil
+	case *array.LargeBinary:
+		if !valid {
+			return nil, nil
+		}
+		return slices.Clone(column.Value(row)), nil
+	case *array.Date32:
+		return sql.NullTime{Time: column.Value(row).ToTime(), Valid: valid}, nil
+	case *array.Date64:
+		return sql.NullTime{Time: column.Value(row).ToTime(), Valid: valid}, nil
+	case *array.Timestamp:
+		unit := column.DataType().(*arrow.TimestampType).Unit
+		return sql.NullTime{Time: column.Value(row).ToTime(unit), Valid: valid}, nil
+	default:
+		return nil, adbc.Error{
+			Code: adbc.StatusNotImplemented,
+			Msg:  fmt.Sprintf("unsupported bind parameter '%s' of type %s", field.Name, field.Type),
+		}
+	}
+}
+
+// ColumnTypeMapper picks the Arrow type of a result column. It returns
+// false to use the default mapping of ColumnTypesToSchema. The type must be
+// one that the default mapping can produce, or large string or binary.
+type ColumnTypeMapper func(column *sql.ColumnType) (arrow.DataType, bool)
+
+var scanTypes = map[reflect.Type]arrow.DataType{
+	reflect.TypeOf(false):             arrow.FixedWidthTypes.Boolean,
+	reflect.TypeOf(sql.NullBool{}):    arrow.FixedWidthTypes.Boolean,
+	reflect.TypeOf(int8(0)):           arrow.PrimitiveTypes.Int8,
+	reflect.TypeOf(int16(0)):          arrow.PrimitiveTypes.Int16,
+	reflect.TypeOf(sql.NullInt16{}):   arrow.PrimitiveTypes.Int16,
+	reflect.TypeOf(int32(0)):          arrow.PrimitiveTypes.Int32,
+	reflect.TypeOf(sql.NullInt32{}):   arrow.PrimitiveTypes.Int32,
+	reflect.TypeOf(int(0)):            arrow.PrimitiveTypes.Int64,
+	reflect.TypeOf(int64(0)):          arrow.PrimitiveTypes.Int64,
+	reflect.TypeOf(sql.NullInt64{}):   arrow.PrimitiveTypes.Int64,
+	reflect.TypeOf(uint8(0)):          arrow.PrimitiveTypes.Uint8,
+	reflect.TypeOf(sql.NullByte{}):    arrow.PrimitiveTypes.Uint8,
+	reflect.TypeOf(uint16(0)):         arrow.PrimitiveTypes.Uint16,
+	reflect.TypeOf(uint32(0)):         arrow.PrimitiveTypes.Uint32,
+	reflect.TypeOf(uint(0)):           arrow.PrimitiveTypes.Uint64,
+	reflect.TypeOf(uint64(0)):         arrow.PrimitiveTypes.Uint64,
+	reflect.TypeOf(float32(0)):        arrow.PrimitiveTypes.Float32,
+	reflect.TypeOf(float64(0)):        arrow.PrimitiveTypes.Float64,
+	reflect.TypeOf(sql.NullFloat64{}): arrow.PrimitiveTypes.Float64,
+	reflect.TypeOf(""):                arrow.BinaryTypes.String,
+	reflect.TypeOf(sql.NullString{}):  arrow.BinaryTypes.String,
+	reflect.TypeOf([]byte(nil)):       arrow.BinaryTypes.Binary,
+	reflect.TypeOf(sql.RawBytes(nil)): arrow.BinaryTypes.Binary,
+	reflect.TypeOf(time.Time{}):       arrow.FixedWidthTypes.Timestamp_us,
+	reflect.TypeOf(sql.NullTime{}):    arrow.FixedWidthTypes.Timestamp_us,
+}
+
+// databaseTypes maps the database type names of columns whose scan type
+// isn't known, with any length or precision removed.
+var databaseTypes = map[string]arrow.DataType{
+	"BOOL":             arrow.FixedWidthTypes.Boolean,
+	"BOOLEAN":          arrow.FixedWidthTypes.Boolean,
+	"TINYINT":          arrow.PrimitiveTypes.Int8,
+	"SMALLINT":         arrow.PrimitiveTypes.Int16,
+	"INT":              arrow.PrimitiveTypes.Int64,
+	"INTEGER":          arrow.PrimitiveTypes.Int64,
+	"BIGINT":           arrow.PrimitiveTypes.Int64,
+	"REAL":             arrow.PrimitiveTypes.Float64,
+	"FLOAT":            arrow.PrimitiveTypes.Float64,
+	"DOUBLE":           arrow.PrimitiveTypes.Float64,
+	"DOUBLE PRECISION": arrow.PrimitiveTypes.Float64,
+	"BINARY":           arrow.BinaryTypes.Binary,
+	"VARBINARY":        arrow.BinaryTypes.Binary,
+	"BLOB":             arrow.BinaryTypes.Binary,
+	"BYTEA":            arrow.BinaryTypes.Binary,
+	"DATE":             arrow.FixedWidthTypes.Date32,
+	"DATETIME":         arrow.FixedWidthTypes.Timestamp_us,
+	"TIMESTAMP":        arrow.FixedWidthTypes.Timestamp_us,
+}
+
+// ColumnTypesToSchema returns the Arrow schema of a result with the given
+// columns. Columns are mapped by their scan type, then by their database
+// type name, and are strings if neither is known. If mapper is not nil, it
+// is tried first.
+func ColumnTypesToSchema(columns []*sql.ColumnType, mapper ColumnTypeMapper) *arrow.Schema {
+	fields := make([]arrow.Field, len(columns))
+	for i, column := range columns {
+		fields[i] = arrow.Field{Name: column.Name(), Type: columnTypeToArrow(column, mapper), Nullable: true}
+		if nullable, ok := column.Nullable(); ok {
+			fields[i].Nullable = nullable
+		}
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+func columnTypeToArrow(column *sql.ColumnType, mapper ColumnTypeMapper) arrow.DataType {
+	if mapper != nil {
+		if dt, ok := mapper(column); ok {
+			return dt
+		}
+	}
+	if scanType := column.ScanType(); scanType != nil {
+		if dt, ok := scanTypes[scanType]; ok {
+			return dt
+		}
+	}
+	name, _, _ := strings.Cut(strings.ToUpper(column.DatabaseTypeName()), "(")
+	if dt, ok := databaseTypes[strings.TrimSpace(name)]; ok {
+		return dt
+	}
+	return arrow.BinaryTypes.String
+}
+
+// NewRowsReader returns a reader over the rows of a database/sql query,
+// with the schema given by ColumnTypesToSchema. It reads the rows in
+// records of up to batchSize rows, and closes them when it is released.
+func NewRowsReader(alloc memory.Allocator, rows *sql.Rows, batchSize int, mapper ColumnTypeMapper) (array.RecordReader, error) {
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	schema := ColumnTypesToSchema(columns, mapper)
+	return newRowsReader(alloc, schema, batchSize, rows, nil, nil), nil
+}
+
+// rowsReader reads the rows of one or more database/sql queries with the
+// same result columns, such as a query executed once per row of bound
+// parameters.
+type rowsReader struct {
+	refCount  atomic.Int64
+	alloc     memory.Allocator
+	schema    *arrow.Schema
+	batchSize int
+
+	rows *sql.Rows
+	// next returns the rows of the next query, or io.EOF once there are
+	// no more queries. It is nil if there is a single query.
+	next func() (*sql.Rows, error)
+	// release, if not nil, is called when the reader is released.
+	release func()
+	rec     arrow.Record
+	err     error
+}
+
+func newRowsReader(alloc memory.Allocator, schema *arrow.Schema, batchSize int, rows *sql.Rows, next func() (*sql.Rows, error), release func()) *rowsReader {
+	r := &rowsReader{alloc: alloc, schema: schema, batchSize: max(batchSize, 1), rows: rows, next: next, release: release}
+	r.refCount.Add(1)
+	return r
+}
+
+func (r *rowsReader) Retain() {
+	r.refCount.Add(1)
+}
+
+func (r *rowsReader) Release() {
+	if r.refCount.Add(-1) == 0 {
+		if r.rec != nil {
+			r.rec.Release()
+			r.rec = nil
+		}
+		if r.rows != nil {
+			r.rows.Close()
+			r.rows = nil
+		}
+		if r.release != nil {
+			r.release()
+		}
+	}
+}
+
+func (r *rowsReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+func (r *rowsReader) Record() arrow.Record {
+	return r.rec
+}
+
+func (r *rowsReader) Err() error {
+	return r.err
+}
+
+func (r *rowsReader) Next() bool {
+	if r.rec != nil {
+		r.rec.Release()
+		r.rec = nil
+	}
+	if r.err != nil {
+		return false
+	}
+
+	bldr := array.NewRecordBuilder(r.alloc, r.schema)
+	defer bldr.Release()
+
+	values := make([]any, len(r.schema.Fields()))
+	dest := make([]any, len(values))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	nrows := 0
+	for nrows < r.batchSize && r.advance() {
+		if err := r.rows.Scan(dest...); err != nil {
+			r.err = err
+			return false
+		}
+		for i, value := range values {
+			if err := appendValue(bldr.Field(i), value); err != nil {
+				r.err = adbc.Error{
+					Code: adbc.StatusInvalidData,
+					Msg:  fmt.Sprintf("column '%s': %s", r.schema.Field(i).Name, err),
+				}
+				return false
+			}
+		}
+		nrows++
+	}
+	if r.err != nil || nrows == 0 {
+		return false
+	}
+	r.rec = bldr.NewRecord()
+	return true
+}
+
+// advance moves to the next row, moving on to the rows of the next query
+// once those of the current one are exhausted.
+func (r *rowsReader) advance() bool {
+	for r.rows != nil {
+		if r.rows.Next() {
+			return true
+		}
+		err := r.rows.Err()
+		r.rows.Close()
+		r.rows = nil
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if r.next == nil {
+			return false
+		}
+
+		r.rows, err = r.next()
+		if errors.Is(err, io.EOF) {
+			return false
+		} else if err != nil {
+			r.err = err
+			return false
+		}
+	}
+	return false
+}
+
+// appendValue appends a value scanned from a database/sql row, which is
+// one of the driver.Value types, to b.
+func appendValue(b array.Builder, value any) error {
+	if value == nil {
+		b.AppendNull()
+		return nil
+	}
+
+	switch b := b.(type) {
+	case *array.BooleanBuilder:
+		switch v := value.(type) {
+		case bool:
+			b.Append(v)
+		case int64:
+			b.Append(v != 0)
+		default:
+			s, err := valueToString(value)
+			if err != nil {
+				return err
+			}
+			parsed, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			b.Append(parsed)
+		}
+	case *array.Int8Builder:
+		return appendInt(b, value)
+	case *array.Int16Builder:
+		return appendInt(b, value)
+	case *array.Int32Builder:
+		return appendInt(b, value)
+	case *array.Int64Builder:
+		return appendInt(b, value)
+	case *array.Uint8Builder:
+		return appendInt(b, value)
+	case *array.Uint16Builder:
+		return appendInt(b, value)
+	case *array.Uint32Builder:
+		return appendInt(b, value)
+	case *array.Uint64Builder:
+		return appendInt(b, value)
+	case *array.Float32Builder:
+		v, err := valueToFloat(value)
+		if err != nil {
+			return err
+		}
+		b.Append(float32(v))
+	case *array.Float64Builder:
+		v, err := valueToFloat(value)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.StringBuilder:
+		v, err := valueToString(value)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.LargeStringBuilder:
+		v, err := valueToString(value)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.BinaryBuilder:
+		switch v := value.(type) {
+		case []byte:
+			b.Append(v)
+		case string:
+			b.AppendString(v)
+		default:
+			return fmt.Errorf("cannot convert %T to binary", value)
+		}
+	case *array.Date32Builder:
+		v, err := valueToTime(value)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Date32FromTime(v))
+	case *array.TimestampBuilder:
+		v, err := valueToTime(value)
+		if err != nil {
+			return err
+		}
+		ts, err := arrow.TimestampFromTime(v, b.Type().(*arrow.TimestampType).Unit)
+		if err != nil {
+			return err
+		}
+		b.Append(ts)
+	default:
+		return fmt.Errorf("unsupported Arrow type %s", b.Type())
+	}
+	return nil
+}
+
+func appendInt[T int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64](b interface{ Append(T) }, value any) error {
+	var (
+		v   T
+		err error
+	)
+	switch value := value.(type) {
+	case int64:
+		v = T(value)
+		if (value < 0) != (v < 0) || int64(v) != value {
+			err = fmt.Errorf("value %d out of range for %T", value, v)
+		}
+	case bool:
+		if value {
+			v = 1
+		}
+	default:
+		var s string
+		if s, err = valueToString(value); err != nil {
+			break
+		}
+		var zero T
+		if ^zero < 0 {
+			var parsed int64
+			parsed, err = strconv.ParseInt(s, 10, int(reflect.TypeOf(zero).Size())*8)
+			v = T(parsed)
+		} else {
+			var parsed uint64
+			parsed, err = strconv.ParseUint(s, 10, int(reflect.TypeOf(zero).Size())*8)
+			v = T(parsed)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	b.Append(v)
+	return nil
+}
+
+func valueToFloat(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	}
+	s, err := valueToString(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func valueToString(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return "", fmt.Errorf("cannot convert %T to string", value)
+}
+
+// timeLayouts are the layouts of times returned as text, as by backends
+// without a native time type.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+func valueToTime(value any) (time.Time, error) {
+	if v, ok := value.(time.Time); ok {
+		return v, nil
+	}
+	s, err := valueToString(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+}
@@ -19,6 +19,7 @@ package driverbase
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/apache/arrow-adbc/go/adbc"
@@ -35,6 +36,8 @@ const (
 type StatementImpl interface {
 	adbc.Statement
 	adbc.StatementExecuteSchema
+	adbc.StatementCancel
+	adbc.StatementSubmitQuery
 	adbc.GetSetOptions
 	adbc.OTelTracing
 	Base() *StatementImplBase
@@ -43,13 +46,22 @@ type StatementImpl interface {
 type StatementImplBase struct {
 	ErrorHelper ErrorHelper
 	Tracer      trace.Tracer
+	// Progress backs the progress options of the statement.
+	Progress *ProgressReporter
+	// State tracks the lifecycle of the statement and owns its bound
+	// parameters.
+	State *StatementState
 
 	cnxn        *ConnectionImplBase
 	traceParent string
+	cancels     *cancelSet
 }
 
 type Statement interface {
 	adbc.Statement
+	adbc.StatementCancel
+	adbc.StatementProgress
+	adbc.StatementSubmitQuery
 	adbc.GetSetOptions
 }
 
@@ -61,7 +73,10 @@ func NewStatementImplBase(cnxn *ConnectionImplBase, errorHelper ErrorHelper) Sta
 	return StatementImplBase{
 		ErrorHelper: errorHelper,
 		Tracer:      cnxn.Tracer,
+		Progress:    NewProgressReporter(),
+		State:       NewStatementState(errorHelper),
 		cnxn:        cnxn,
+		cancels:     newCancelSet(),
 	}
 }
 
@@ -71,6 +86,13 @@ func NewStatement(impl StatementImpl) Statement {
 	}
 }
 
+// ValidateOption checks a statement option against the options registered
+// by the driver. Drivers whose statements are not wrapped with NewStatement
+// should call it at the start of SetOption.
+func (st *StatementImplBase) ValidateOption(key, value string) error {
+	return st.cnxn.DriverInfo.ValidateOption(OptionLevelStatement, key, &value)
+}
+
 func (st *StatementImplBase) SetOption(key, value string) error {
 	switch strings.ToLower(key) {
 	case adbc.OptionKeyTelemetryTraceParent:
@@ -80,6 +102,34 @@ func (st *StatementImplBase) SetOption(key, value string) error {
 	return st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s'", StatementMessageOptionUnknown, key)
 }
 
+func (st *statement) SetOption(key, value string) error {
+	if err := st.Base().ValidateOption(key, value); err != nil {
+		return err
+	}
+	return st.StatementImpl.SetOption(key, value)
+}
+
+func (st *statement) SetOptionBytes(key string, value []byte) error {
+	if err := st.Base().cnxn.DriverInfo.ValidateOption(OptionLevelStatement, key, nil); err != nil {
+		return err
+	}
+	return st.StatementImpl.SetOptionBytes(key, value)
+}
+
+func (st *statement) SetOptionInt(key string, value int64) error {
+	if err := st.Base().ValidateOption(key, strconv.FormatInt(value, 10)); err != nil {
+		return err
+	}
+	return st.StatementImpl.SetOptionInt(key, value)
+}
+
+func (st *statement) SetOptionDouble(key string, value float64) error {
+	if err := st.Base().ValidateOption(key, strconv.FormatFloat(value, 'g', -1, 64)); err != nil {
+		return err
+	}
+	return st.StatementImpl.SetOptionDouble(key, value)
+}
+
 func (st *StatementImplBase) SetOptionBytes(key string, value []byte) error {
 	return st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s'", StatementMessageOptionUnknown, key)
 }
@@ -109,9 +159,44 @@ func (st *StatementImplBase) GetOptionInt(key string) (int64, error) {
 }
 
 func (st *StatementImplBase) GetOptionDouble(key string) (float64, error) {
+	if value, ok := st.Progress.GetOptionDouble(key); ok {
+		return value, nil
+	}
 	return 0, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "%s '%s'", StatementMessageOptionUnknown, key)
 }
 
+func (st *StatementImplBase) SubmitQuery(ctx context.Context) ([]byte, error) {
+	return nil, st.ErrorHelper.Errorf(adbc.StatusNotImplemented, "SubmitQuery")
+}
+
+// SetProgressCallback implements adbc.StatementProgress.
+func (st *StatementImplBase) SetProgressCallback(callback func(adbc.QueryProgress)) {
+	st.Progress.SetProgressCallback(callback)
+}
+
+// SetProgressCallback implements adbc.StatementProgress.
+func (st *statement) SetProgressCallback(callback func(adbc.QueryProgress)) {
+	st.Base().SetProgressCallback(callback)
+}
+
+// WithCancel returns a copy of ctx that will be cancelled when Cancel is
+// called on the statement, until the returned function is called once the
+// operation is done. Drivers should use it for all work started by
+// ExecuteQuery, ExecuteUpdate, ExecutePartitions and bulk ingestion, and
+// CancelOnRelease for the readers they return, including any goroutines
+// that feed them.
+func (st *StatementImplBase) WithCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	return st.cancels.withCancel(ctx)
+}
+
+// Cancel cancels every in-progress operation started with a context from
+// WithCancel. Drivers that can also cancel work on the server should
+// override this and call the base implementation first.
+func (st *StatementImplBase) Cancel() error {
+	st.cancels.cancelAll()
+	return nil
+}
+
 func (st *StatementImplBase) GetTraceParent() string {
 	return st.traceParent
 }
@@ -0,0 +1,320 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// StatementPhase is a step in the lifecycle of a statement.
+type StatementPhase int
+
+const (
+	// StatementPhaseNew is a statement without a query.
+	StatementPhaseNew StatementPhase = iota
+	// StatementPhaseQuerySet is a statement with a query, or a target
+	// table for bulk ingestion.
+	StatementPhaseQuerySet
+	// StatementPhasePrepared is a statement whose query was prepared.
+	StatementPhasePrepared
+	// StatementPhaseBound is a statement with bound parameters.
+	StatementPhaseBound
+	// StatementPhaseExecuting is a statement running one of the Execute
+	// methods.
+	StatementPhaseExecuting
+	// StatementPhaseClosed is a closed statement.
+	StatementPhaseClosed
+)
+
+func (p StatementPhase) String() string {
+	switch p {
+	case StatementPhaseNew:
+		return "new"
+	case StatementPhaseQuerySet:
+		return "query set"
+	case StatementPhasePrepared:
+		return "prepared"
+	case StatementPhaseBound:
+		return "bound"
+	case StatementPhaseExecuting:
+		return "executing"
+	case StatementPhaseClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// StatementState tracks the lifecycle of a statement, so that drivers
+// reject the same calls the ADBC spec makes illegal with the same
+// StatusInvalidState errors. It owns the bound parameters, and makes the
+// readers returned by a previous execution fail once the statement is
+// executed again or closed.
+//
+// Drivers call it at the start of the corresponding statement methods:
+// SetQuery from SetSqlQuery, SetSubstraitPlan and when the target table
+// for bulk ingestion is set, Prepare from Prepare, Bind and BindStream
+// from the methods of the same name, BeginExecute from the Execute
+// methods and Close from Close.
+type StatementState struct {
+	errorHelper ErrorHelper
+
+	mu sync.Mutex
+	// phase is New, QuerySet, Prepared or Closed; Bound and Executing
+	// are derived from the fields below
+	phase     StatementPhase
+	executing bool
+	bound     arrow.Record
+	stream    array.RecordReader
+
+	// generation counts the executions, so that readers from previous
+	// ones are invalidated
+	generation atomic.Uint64
+}
+
+// NewStatementState returns the state of a new statement.
+func NewStatementState(errorHelper ErrorHelper) *StatementState {
+	return &StatementState{errorHelper: errorHelper}
+}
+
+// Phase returns the current phase of the statement. A statement with
+// bound parameters is reported as bound even if it was prepared.
+func (s *StatementState) Phase() StatementPhase {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.phase == StatementPhaseClosed:
+		return StatementPhaseClosed
+	case s.executing:
+		return StatementPhaseExecuting
+	case s.bound != nil || s.stream != nil:
+		return StatementPhaseBound
+	}
+	return s.phase
+}
+
+// Prepared reports whether the query of the statement was prepared.
+func (s *StatementState) Prepared() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.phase == StatementPhasePrepared
+}
+
+// checkUsable returns an error if the statement is closed or executing.
+// It must be called with mu held.
+func (s *StatementState) checkUsable(op string) error {
+	if s.phase == StatementPhaseClosed {
+		return s.errorHelper.Errorf(adbc.StatusInvalidState, "cannot %s: statement is closed", op)
+	}
+	if s.executing {
+		return s.errorHelper.Errorf(adbc.StatusInvalidState, "cannot %s: statement is executing", op)
+	}
+	return nil
+}
+
+// SetQuery records that the statement has a new query, which needs to be
+// prepared again.
+func (s *StatementState) SetQuery() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable("set query"); err != nil {
+		return err
+	}
+	s.phase = StatementPhaseQuerySet
+	return nil
+}
+
+// Prepare records that the query of the statement was prepared. It
+// returns an error if there is no query.
+func (s *StatementState) Prepare() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable("prepare"); err != nil {
+		return err
+	}
+	if s.phase == StatementPhaseNew {
+		return s.errorHelper.Errorf(adbc.StatusInvalidState, "cannot prepare without a query")
+	}
+	s.phase = StatementPhasePrepared
+	return nil
+}
+
+// RequirePrepared returns an error unless the query of the statement was
+// prepared, for op which needs a prepared statement.
+func (s *StatementState) RequirePrepared(op string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable(op); err != nil {
+		return err
+	}
+	if s.phase != StatementPhasePrepared {
+		return s.errorHelper.Errorf(adbc.StatusInvalidState, "must call Prepare before %s", op)
+	}
+	return nil
+}
+
+// Bind replaces the bound parameters with values, which is retained until
+// it is replaced, taken or the statement is closed. A nil values clears
+// the bound parameters.
+func (s *StatementState) Bind(values arrow.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable("bind"); err != nil {
+		return err
+	}
+	s.clearBound()
+	if values != nil {
+		values.Retain()
+	}
+	s.bound = values
+	return nil
+}
+
+// BindStream is Bind for a stream of parameters.
+func (s *StatementState) BindStream(stream array.RecordReader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable("bind"); err != nil {
+		return err
+	}
+	s.clearBound()
+	if stream != nil {
+		stream.Retain()
+	}
+	s.stream = stream
+	return nil
+}
+
+// Bound returns the bound parameters, at most one of which is not nil.
+// They stay owned by the statement.
+func (s *StatementState) Bound() (arrow.Record, array.RecordReader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bound, s.stream
+}
+
+// HasBound reports whether parameters are bound.
+func (s *StatementState) HasBound() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bound != nil || s.stream != nil
+}
+
+// TakeBound returns the bound parameters, like Bound, and unbinds them.
+// The caller must release the one that is not nil.
+func (s *StatementState) TakeBound() (arrow.Record, array.RecordReader) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bound, stream := s.bound, s.stream
+	s.bound, s.stream = nil, nil
+	return bound, stream
+}
+
+// ClearBound releases the bound parameters.
+func (s *StatementState) ClearBound() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clearBound()
+}
+
+func (s *StatementState) clearBound() {
+	if s.bound != nil {
+		s.bound.Release()
+		s.bound = nil
+	}
+	if s.stream != nil {
+		s.stream.Release()
+		s.stream = nil
+	}
+}
+
+// BeginExecute checks that the statement can be executed by op, and
+// invalidates the readers of previous executions. The returned function
+// must be called once op returns.
+func (s *StatementState) BeginExecute(op string) (done func(), err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkUsable(op); err != nil {
+		return nil, err
+	}
+	if s.phase == StatementPhaseNew {
+		return nil, s.errorHelper.Errorf(adbc.StatusInvalidState, "cannot execute without a query")
+	}
+	s.executing = true
+	s.generation.Add(1)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.executing = false
+	}, nil
+}
+
+// TrackReader returns rdr as a reader that fails with StatusInvalidState
+// once the statement is executed again or closed. Releasing it still
+// releases rdr.
+func (s *StatementState) TrackReader(rdr array.RecordReader) array.RecordReader {
+	if rdr == nil {
+		return nil
+	}
+	return &trackedReader{RecordReader: rdr, state: s, generation: s.generation.Load()}
+}
+
+// Close releases the bound parameters and invalidates the readers of the
+// statement. It returns an error if the statement is already closed.
+func (s *StatementState) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.phase == StatementPhaseClosed {
+		return s.errorHelper.Errorf(adbc.StatusInvalidState, "statement already closed")
+	}
+	s.clearBound()
+	s.phase = StatementPhaseClosed
+	s.generation.Add(1)
+	return nil
+}
+
+// trackedReader is a reader returned by an execution of a statement.
+type trackedReader struct {
+	array.RecordReader
+	state      *StatementState
+	generation uint64
+	err        error
+}
+
+func (r *trackedReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.state.generation.Load() != r.generation {
+		r.err = r.state.errorHelper.Errorf(adbc.StatusInvalidState,
+			"reader is no longer valid: the statement was executed again or closed")
+		return false
+	}
+	return r.RecordReader.Next()
+}
+
+func (r *trackedReader) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.RecordReader.Err()
+}
@@ -0,0 +1,152 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireInvalidState(t *testing.T, err error) {
+	t.Helper()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+	assert.True(t, strings.HasPrefix(adbcErr.Msg, "[Test] "), adbcErr.Msg)
+}
+
+func TestStatementStateTransitions(t *testing.T) {
+	s := driverbase.NewStatementState(driverbase.ErrorHelper{DriverName: "Test"})
+	assert.Equal(t, driverbase.StatementPhaseNew, s.Phase())
+
+	_, err := s.BeginExecute("ExecuteQuery")
+	requireInvalidState(t, err)
+	requireInvalidState(t, s.Prepare())
+	requireInvalidState(t, s.RequirePrepared("GetParameterSchema"))
+
+	require.NoError(t, s.SetQuery())
+	assert.Equal(t, driverbase.StatementPhaseQuerySet, s.Phase())
+	require.NoError(t, s.Prepare())
+	assert.Equal(t, driverbase.StatementPhasePrepared, s.Phase())
+	assert.True(t, s.Prepared())
+	require.NoError(t, s.RequirePrepared("GetParameterSchema"))
+
+	// a new query must be prepared again
+	require.NoError(t, s.SetQuery())
+	assert.False(t, s.Prepared())
+	requireInvalidState(t, s.RequirePrepared("GetParameterSchema"))
+
+	done, err := s.BeginExecute("ExecuteQuery")
+	require.NoError(t, err)
+	assert.Equal(t, driverbase.StatementPhaseExecuting, s.Phase())
+	_, err = s.BeginExecute("ExecuteUpdate")
+	requireInvalidState(t, err)
+	requireInvalidState(t, s.SetQuery())
+	requireInvalidState(t, s.Bind(nil))
+	done()
+	assert.Equal(t, driverbase.StatementPhaseQuerySet, s.Phase())
+
+	require.NoError(t, s.Close())
+	assert.Equal(t, driverbase.StatementPhaseClosed, s.Phase())
+	requireInvalidState(t, s.Close())
+	requireInvalidState(t, s.SetQuery())
+	_, err = s.BeginExecute("ExecuteQuery")
+	requireInvalidState(t, err)
+}
+
+func TestStatementStateBound(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"a": 1}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+	stream, err := array.NewRecordReader(schema, []arrow.Record{rec})
+	require.NoError(t, err)
+	defer stream.Release()
+
+	s := driverbase.NewStatementState(driverbase.ErrorHelper{DriverName: "Test"})
+	require.NoError(t, s.SetQuery())
+	require.NoError(t, s.Bind(rec))
+	assert.Equal(t, driverbase.StatementPhaseBound, s.Phase())
+	bound, boundStream := s.Bound()
+	assert.Same(t, rec, bound)
+	assert.Nil(t, boundStream)
+
+	// binding again releases the previous parameters
+	require.NoError(t, s.BindStream(stream))
+	bound, boundStream = s.Bound()
+	assert.Nil(t, bound)
+	assert.Equal(t, stream, boundStream)
+
+	bound, boundStream = s.TakeBound()
+	assert.Nil(t, bound)
+	assert.False(t, s.HasBound())
+	boundStream.Release()
+
+	// closing releases the bound parameters
+	require.NoError(t, s.Bind(rec))
+	require.NoError(t, s.Close())
+}
+
+func TestStatementStateTrackReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "a", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	rec, _, err := array.RecordFromJSON(mem, schema, strings.NewReader(`[{"a": 1}]`))
+	require.NoError(t, err)
+	defer rec.Release()
+
+	s := driverbase.NewStatementState(driverbase.ErrorHelper{DriverName: "Test"})
+	require.NoError(t, s.SetQuery())
+	newReader := func() array.RecordReader {
+		done, err := s.BeginExecute("ExecuteQuery")
+		require.NoError(t, err)
+		defer done()
+		rdr, err := array.NewRecordReader(schema, []arrow.Record{rec, rec})
+		require.NoError(t, err)
+		return s.TrackReader(rdr)
+	}
+
+	first := newReader()
+	defer first.Release()
+	require.True(t, first.Next())
+
+	// executing again invalidates the first reader
+	second := newReader()
+	defer second.Release()
+	assert.False(t, first.Next())
+	requireInvalidState(t, first.Err())
+	assert.True(t, second.Next())
+	assert.NoError(t, second.Err())
+
+	// and so does closing the statement
+	require.NoError(t, s.Close())
+	assert.False(t, second.Next())
+	requireInvalidState(t, second.Err())
+}
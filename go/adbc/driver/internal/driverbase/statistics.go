@@ -0,0 +1,302 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Type codes of the members of the statistic_value union.
+const (
+	statisticValueInt64Code   arrow.UnionTypeCode = 0
+	statisticValueUint64Code  arrow.UnionTypeCode = 1
+	statisticValueFloat64Code arrow.UnionTypeCode = 2
+	statisticValueBinaryCode  arrow.UnionTypeCode = 3
+)
+
+// Statistic is a structured representation of adbc.StatisticsSchema.
+//
+// Value must be an int64, uint64, float64 or []byte.
+type Statistic struct {
+	TableName     string
+	ColumnName    *string
+	Key           int16
+	Value         any
+	IsApproximate bool
+}
+
+// DBSchemaStatistics is a structured representation of
+// adbc.StatisticsDBSchemaSchema
+type DBSchemaStatistics struct {
+	DbSchemaName       *string
+	DbSchemaStatistics []Statistic
+}
+
+// GetStatisticsInfo is a structured representation of
+// adbc.GetStatisticsSchema
+type GetStatisticsInfo struct {
+	CatalogName      *string
+	CatalogDbSchemas []DBSchemaStatistics
+}
+
+// StatisticName is a structured representation of
+// adbc.GetStatisticNamesSchema
+type StatisticName struct {
+	Name string
+	Key  int16
+}
+
+// StatisticsEntry is a single statistic returned by a StatisticsEnumerator.
+//
+// Name is either one of the standard statistic names, such as
+// adbc.StatisticRowCountName, or one of the custom names returned by
+// StatisticsEnumerator.StatisticNames. ColumnName is nil for statistics
+// of a whole table. Value must be an integer, float, string or []byte.
+type StatisticsEntry struct {
+	CatalogName   string
+	DbSchemaName  string
+	TableName     string
+	ColumnName    *string
+	Name          string
+	Value         any
+	IsApproximate bool
+}
+
+// standardStatisticKeys maps the names of the statistics defined by ADBC
+// to their keys.
+var standardStatisticKeys = map[string]int16{
+	adbc.StatisticAverageByteWidthName: adbc.StatisticAverageByteWidthKey,
+	adbc.StatisticDistinctCountName:    adbc.StatisticDistinctCountKey,
+	adbc.StatisticMaxByteWidthName:     adbc.StatisticMaxByteWidthKey,
+	adbc.StatisticMaxValueName:         adbc.StatisticMaxValueKey,
+	adbc.StatisticMinValueName:         adbc.StatisticMinValueKey,
+	adbc.StatisticNullCountName:        adbc.StatisticNullCountKey,
+	adbc.StatisticRowCountName:         adbc.StatisticRowCountKey,
+}
+
+// statisticsFilter holds the search patterns of a GetStatistics call.
+type statisticsFilter struct {
+	catalog, dbSchema, tableName *regexp.Regexp
+}
+
+func newStatisticsFilter(catalog, dbSchema, tableName *string) (*statisticsFilter, error) {
+	var (
+		filter statisticsFilter
+		err    error
+	)
+	for _, p := range []struct {
+		pattern *string
+		dest    **regexp.Regexp
+	}{{catalog, &filter.catalog}, {dbSchema, &filter.dbSchema}, {tableName, &filter.tableName}} {
+		if *p.dest, err = internal.PatternToRegexp(p.pattern); err != nil {
+			return nil, err
+		}
+		if *p.dest == nil {
+			*p.dest = internal.AcceptAll
+		}
+	}
+	return &filter, nil
+}
+
+// group drops the entries not matching the filter and groups the rest by
+// catalog and schema, in the order they first appear.
+func (f *statisticsFilter) group(entries []StatisticsEntry, custom []StatisticName) ([]GetStatisticsInfo, error) {
+	keys := make(map[string]int16, len(standardStatisticKeys)+len(custom))
+	for name, key := range standardStatisticKeys {
+		keys[name] = key
+	}
+	for _, name := range custom {
+		if name.Key < 1024 {
+			return nil, fmt.Errorf("custom statistic %q has reserved key %d", name.Name, name.Key)
+		}
+		keys[name.Name] = name.Key
+	}
+
+	catalogs := make([]GetStatisticsInfo, 0)
+	catalogIndex := make(map[string]int)
+	dbSchemaIndex := make(map[[2]string]int)
+	for _, entry := range entries {
+		if !f.catalog.MatchString(entry.CatalogName) ||
+			!f.dbSchema.MatchString(entry.DbSchemaName) ||
+			!f.tableName.MatchString(entry.TableName) {
+			continue
+		}
+
+		key, ok := keys[entry.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown statistic %q", entry.Name)
+		}
+		value, err := statisticValue(entry.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		i, ok := catalogIndex[entry.CatalogName]
+		if !ok {
+			i = len(catalogs)
+			catalogIndex[entry.CatalogName] = i
+			catalogs = append(catalogs, GetStatisticsInfo{CatalogName: Nullable(entry.CatalogName)})
+		}
+		catalog := &catalogs[i]
+
+		j, ok := dbSchemaIndex[[2]string{entry.CatalogName, entry.DbSchemaName}]
+		if !ok {
+			j = len(catalog.CatalogDbSchemas)
+			dbSchemaIndex[[2]string{entry.CatalogName, entry.DbSchemaName}] = j
+			catalog.CatalogDbSchemas = append(catalog.CatalogDbSchemas, DBSchemaStatistics{DbSchemaName: Nullable(entry.DbSchemaName)})
+		}
+		dbSchema := &catalog.CatalogDbSchemas[j]
+
+		dbSchema.DbSchemaStatistics = append(dbSchema.DbSchemaStatistics, Statistic{
+			TableName:     entry.TableName,
+			ColumnName:    entry.ColumnName,
+			Key:           key,
+			Value:         value,
+			IsApproximate: entry.IsApproximate,
+		})
+	}
+	return catalogs, nil
+}
+
+// statisticValue converts a value to one of the types of the
+// statistic_value union.
+func statisticValue(v any) (any, error) {
+	switch v := v.(type) {
+	case int64, uint64, float64, []byte:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case float32:
+		return float64(v), nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("no defined type code for statistic_value of type %T", v)
+	}
+}
+
+// BuildGetStatisticsRecordReader constructs a RecordReader for the
+// GetStatistics ADBC method.
+func BuildGetStatisticsRecordReader(mem memory.Allocator, catalogs []GetStatisticsInfo) (array.RecordReader, error) {
+	bldr := array.NewRecordBuilder(mem, adbc.GetStatisticsSchema)
+	defer bldr.Release()
+
+	catalogNameBldr := bldr.Field(0).(*array.StringBuilder)
+	dbSchemasBldr := bldr.Field(1).(*array.ListBuilder)
+	dbSchemaBldr := dbSchemasBldr.ValueBuilder().(*array.StructBuilder)
+	dbSchemaNameBldr := dbSchemaBldr.FieldBuilder(0).(*array.StringBuilder)
+	statisticsBldr := dbSchemaBldr.FieldBuilder(1).(*array.ListBuilder)
+	statisticBldr := statisticsBldr.ValueBuilder().(*array.StructBuilder)
+	tableNameBldr := statisticBldr.FieldBuilder(0).(*array.StringBuilder)
+	columnNameBldr := statisticBldr.FieldBuilder(1).(*array.StringBuilder)
+	keyBldr := statisticBldr.FieldBuilder(2).(*array.Int16Builder)
+	valueBldr := statisticBldr.FieldBuilder(3).(*array.DenseUnionBuilder)
+	isApproximateBldr := statisticBldr.FieldBuilder(4).(*array.BooleanBuilder)
+
+	int64Bldr := valueBldr.Child(int(statisticValueInt64Code)).(*array.Int64Builder)
+	uint64Bldr := valueBldr.Child(int(statisticValueUint64Code)).(*array.Uint64Builder)
+	float64Bldr := valueBldr.Child(int(statisticValueFloat64Code)).(*array.Float64Builder)
+	binaryBldr := valueBldr.Child(int(statisticValueBinaryCode)).(*array.BinaryBuilder)
+
+	for _, catalog := range catalogs {
+		appendNullable(catalogNameBldr, catalog.CatalogName)
+		dbSchemasBldr.Append(true)
+		for _, dbSchema := range catalog.CatalogDbSchemas {
+			dbSchemaBldr.Append(true)
+			appendNullable(dbSchemaNameBldr, dbSchema.DbSchemaName)
+			statisticsBldr.Append(true)
+			for _, stat := range dbSchema.DbSchemaStatistics {
+				statisticBldr.Append(true)
+				tableNameBldr.Append(stat.TableName)
+				appendNullable(columnNameBldr, stat.ColumnName)
+				keyBldr.Append(stat.Key)
+				isApproximateBldr.Append(stat.IsApproximate)
+
+				switch v := stat.Value.(type) {
+				case int64:
+					valueBldr.Append(statisticValueInt64Code)
+					int64Bldr.Append(v)
+				case uint64:
+					valueBldr.Append(statisticValueUint64Code)
+					uint64Bldr.Append(v)
+				case float64:
+					valueBldr.Append(statisticValueFloat64Code)
+					float64Bldr.Append(v)
+				case []byte:
+					valueBldr.Append(statisticValueBinaryCode)
+					binaryBldr.Append(v)
+				default:
+					return nil, fmt.Errorf("no defined type code for statistic_value of type %T", v)
+				}
+			}
+		}
+	}
+
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	return array.NewRecordReader(adbc.GetStatisticsSchema, []arrow.Record{rec})
+}
+
+// BuildGetStatisticNamesRecordReader constructs a RecordReader for the
+// GetStatisticNames ADBC method.
+func BuildGetStatisticNamesRecordReader(mem memory.Allocator, names []StatisticName) (array.RecordReader, error) {
+	bldr := array.NewRecordBuilder(mem, adbc.GetStatisticNamesSchema)
+	defer bldr.Release()
+
+	nameBldr := bldr.Field(0).(*array.StringBuilder)
+	keyBldr := bldr.Field(1).(*array.Int16Builder)
+	for _, name := range names {
+		nameBldr.Append(name.Name)
+		keyBldr.Append(name.Key)
+	}
+
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	return array.NewRecordReader(adbc.GetStatisticNamesSchema, []arrow.Record{rec})
+}
+
+func appendNullable(bldr *array.StringBuilder, val *string) {
+	if val == nil {
+		bldr.AppendNull()
+	} else {
+		bldr.Append(*val)
+	}
+}
@@ -0,0 +1,205 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package driverbase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGetStatisticsRecordReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	rdr, err := driverbase.BuildGetStatisticsRecordReader(mem, []driverbase.GetStatisticsInfo{
+		{
+			CatalogName: driverbase.Nullable("catalog"),
+			CatalogDbSchemas: []driverbase.DBSchemaStatistics{
+				{
+					DbSchemaName: driverbase.Nullable("schema"),
+					DbSchemaStatistics: []driverbase.Statistic{
+						{TableName: "t", Key: adbc.StatisticRowCountKey, Value: int64(42)},
+						{TableName: "t", ColumnName: driverbase.Nullable("c"), Key: adbc.StatisticDistinctCountKey, Value: float64(7.5), IsApproximate: true},
+						{TableName: "t", Key: 1024, Value: uint64(1)},
+						{TableName: "t", ColumnName: driverbase.Nullable("c"), Key: adbc.StatisticMaxValueKey, Value: []byte("z")},
+					},
+				},
+				{DbSchemaName: driverbase.Nullable("empty")},
+			},
+		},
+		{CatalogName: nil},
+	})
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	assert.True(t, adbc.GetStatisticsSchema.Equal(rdr.Schema()))
+	require.True(t, rdr.Next())
+	rec := rdr.Record()
+	require.EqualValues(t, 2, rec.NumRows())
+
+	catalogs := rec.Column(0).(*array.String)
+	assert.Equal(t, "catalog", catalogs.Value(0))
+	assert.True(t, catalogs.IsNull(1))
+
+	dbSchemas := rec.Column(1).(*array.List)
+	start, end := dbSchemas.ValueOffsets(0)
+	assert.EqualValues(t, 2, end-start)
+	start, end = dbSchemas.ValueOffsets(1)
+	assert.EqualValues(t, 0, end-start)
+
+	dbSchema := dbSchemas.ListValues().(*array.Struct)
+	assert.Equal(t, `["schema" "empty"]`, dbSchema.Field(0).String())
+
+	statistics := dbSchema.Field(1).(*array.List).ListValues().(*array.Struct)
+	require.Equal(t, 4, statistics.Len())
+	assert.Equal(t, `["t" "t" "t" "t"]`, statistics.Field(0).String())
+	assert.Equal(t, `[(null) "c" (null) "c"]`, statistics.Field(1).String())
+	assert.Equal(t, `[6 1 1024 3]`, statistics.Field(2).String())
+	assert.Equal(t, `[false true false false]`, statistics.Field(4).String())
+
+	values := statistics.Field(3).(*array.DenseUnion)
+	assert.EqualValues(t, 42, values.Field(0).(*array.Int64).Value(int(values.ValueOffset(0))))
+	assert.EqualValues(t, 7.5, values.Field(2).(*array.Float64).Value(int(values.ValueOffset(1))))
+	assert.EqualValues(t, 1, values.Field(1).(*array.Uint64).Value(int(values.ValueOffset(2))))
+	assert.Equal(t, []byte("z"), values.Field(3).(*array.Binary).Value(int(values.ValueOffset(3))))
+
+	assert.False(t, rdr.Next())
+}
+
+func TestBuildGetStatisticsRecordReaderInvalidValue(t *testing.T) {
+	_, err := driverbase.BuildGetStatisticsRecordReader(memory.DefaultAllocator, []driverbase.GetStatisticsInfo{
+		{CatalogDbSchemas: []driverbase.DBSchemaStatistics{
+			{DbSchemaStatistics: []driverbase.Statistic{{TableName: "t", Value: "not a number"}}},
+		}},
+	})
+	assert.ErrorContains(t, err, "statistic_value of type string")
+}
+
+func TestBuildGetStatisticNamesRecordReader(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	rdr, err := driverbase.BuildGetStatisticNamesRecordReader(mem, []driverbase.StatisticName{
+		{Name: "vendor.bytes", Key: 1024},
+	})
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	require.True(t, rdr.Next())
+	rec := rdr.Record()
+	assert.Equal(t, `["vendor.bytes"]`, rec.Column(0).String())
+	assert.Equal(t, `[1024]`, rec.Column(1).String())
+}
+
+type statisticsEnumerator struct {
+	entries []driverbase.StatisticsEntry
+	names   []driverbase.StatisticName
+}
+
+func (e *statisticsEnumerator) EnumerateStatistics(ctx context.Context, catalogFilter, dbSchemaFilter, tableFilter *string, approximate bool) ([]driverbase.StatisticsEntry, error) {
+	return e.entries, nil
+}
+
+func (e *statisticsEnumerator) StatisticNames() []driverbase.StatisticName {
+	return e.names
+}
+
+func newStatisticsConnection(t *testing.T, mem memory.Allocator, helper driverbase.StatisticsEnumerator) driverbase.Connection {
+	drvBase := driverbase.NewDriverImplBase(driverbase.DefaultDriverInfo("MockDriver"), mem)
+	dbBase, err := driverbase.NewDatabaseImplBase(context.Background(), &drvBase)
+	require.NoError(t, err)
+	cnxn := &connectionImpl{ConnectionImplBase: driverbase.NewConnectionImplBase(&dbBase)}
+	return driverbase.NewConnectionBuilder(cnxn).WithStatisticsEnumerator(helper).Connection()
+}
+
+func TestStatisticsEnumerator(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	helper := &statisticsEnumerator{
+		entries: []driverbase.StatisticsEntry{
+			{CatalogName: "a", DbSchemaName: "s", TableName: "t1", Name: adbc.StatisticRowCountName, Value: 10},
+			{CatalogName: "b", DbSchemaName: "s", TableName: "t1", Name: adbc.StatisticRowCountName, Value: 20},
+			{CatalogName: "a", DbSchemaName: "s", TableName: "t1", ColumnName: driverbase.Nullable("c"), Name: adbc.StatisticNullCountName, Value: int32(1), IsApproximate: true},
+			{CatalogName: "a", DbSchemaName: "other", TableName: "t2", Name: "vendor.bytes", Value: uint32(512)},
+			{CatalogName: "a", DbSchemaName: "s", TableName: "skipped", Name: adbc.StatisticRowCountName, Value: 30},
+		},
+		names: []driverbase.StatisticName{{Name: "vendor.bytes", Key: 1024}},
+	}
+	cnxn := newStatisticsConnection(t, mem, helper)
+
+	rdr, err := cnxn.GetStatistics(context.Background(), nil, nil, driverbase.Nullable("T_"), true)
+	require.NoError(t, err)
+	defer rdr.Release()
+
+	require.True(t, rdr.Next())
+	rec := rdr.Record()
+	assert.Equal(t, `["a" "b"]`, rec.Column(0).String())
+
+	dbSchemas := rec.Column(1).(*array.List)
+	start, end := dbSchemas.ValueOffsets(0)
+	assert.EqualValues(t, 2, end-start)
+	dbSchema := dbSchemas.ListValues().(*array.Struct)
+	assert.Equal(t, `["s" "other" "s"]`, dbSchema.Field(0).String())
+
+	statistics := dbSchema.Field(1).(*array.List).ListValues().(*array.Struct)
+	assert.Equal(t, `["t1" "t1" "t2" "t1"]`, statistics.Field(0).String())
+	assert.Equal(t, `[(null) "c" (null) (null)]`, statistics.Field(1).String())
+	assert.Equal(t, `[6 5 1024 6]`, statistics.Field(2).String())
+	assert.Equal(t, `[false true false false]`, statistics.Field(4).String())
+
+	values := statistics.Field(3).(*array.DenseUnion)
+	ints := values.Field(0).(*array.Int64)
+	assert.EqualValues(t, 10, ints.Value(int(values.ValueOffset(0))))
+	assert.EqualValues(t, 1, ints.Value(int(values.ValueOffset(1))))
+	assert.EqualValues(t, 512, values.Field(1).(*array.Uint64).Value(int(values.ValueOffset(2))))
+	assert.EqualValues(t, 20, ints.Value(int(values.ValueOffset(3))))
+	assert.False(t, rdr.Next())
+
+	names, err := cnxn.GetStatisticNames(context.Background())
+	require.NoError(t, err)
+	defer names.Release()
+	require.True(t, names.Next())
+	assert.Equal(t, `["vendor.bytes"]`, names.Record().Column(0).String())
+}
+
+func TestStatisticsEnumeratorErrors(t *testing.T) {
+	var adbcErr adbc.Error
+
+	cnxn := newStatisticsConnection(t, memory.DefaultAllocator, &statisticsEnumerator{
+		entries: []driverbase.StatisticsEntry{{TableName: "t", Name: "vendor.unknown", Value: 1}},
+	})
+	_, err := cnxn.GetStatistics(context.Background(), nil, nil, nil, true)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInternal, adbcErr.Code)
+	assert.Contains(t, adbcErr.Msg, `unknown statistic "vendor.unknown"`)
+
+	cnxn = newStatisticsConnection(t, memory.DefaultAllocator, &statisticsEnumerator{
+		names: []driverbase.StatisticName{{Name: "vendor.reserved", Key: 7}},
+	})
+	_, err = cnxn.GetStatistics(context.Background(), nil, nil, nil, true)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Contains(t, adbcErr.Msg, "reserved key 7")
+}
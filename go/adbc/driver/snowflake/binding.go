@@ -18,73 +18,26 @@
 package snowflake
 
 import (
-	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"io"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 )
 
 func convertArrowToNamedValue(batch arrow.Record, index int) ([]driver.NamedValue, error) {
-	// see goTypeToSnowflake in gosnowflake
+	// see goTypeToSnowflake in gosnowflake, which would bind binary values
+	// as arrays and has no unsigned types.
 	// technically, snowflake can bind an array of values at once, but
 	// only for INSERT, so we can't take advantage of that without
 	// analyzing the query ourselves
-	params := make([]driver.NamedValue, batch.NumCols())
-	for i, field := range batch.Schema().Fields() {
-		rawColumn := batch.Column(i)
-		params[i].Ordinal = i + 1
-		switch column := rawColumn.(type) {
-		case *array.Boolean:
-			params[i].Value = sql.NullBool{
-				Bool:  column.Value(index),
-				Valid: column.IsValid(index),
-			}
-		case *array.Float32:
-			// Snowflake only recognizes float64
-			params[i].Value = sql.NullFloat64{
-				Float64: float64(column.Value(index)),
-				Valid:   column.IsValid(index),
-			}
-		case *array.Float64:
-			params[i].Value = sql.NullFloat64{
-				Float64: column.Value(index),
-				Valid:   column.IsValid(index),
-			}
-		case *array.Int8:
-			// Snowflake only recognizes int64
-			params[i].Value = sql.NullInt64{
-				Int64: int64(column.Value(index)),
-				Valid: column.IsValid(index),
-			}
-		case *array.Int16:
-			params[i].Value = sql.NullInt64{
-				Int64: int64(column.Value(index)),
-				Valid: column.IsValid(index),
-			}
-		case *array.Int32:
-			params[i].Value = sql.NullInt64{
-				Int64: int64(column.Value(index)),
-				Valid: column.IsValid(index),
-			}
-		case *array.Int64:
-			params[i].Value = sql.NullInt64{
-				Int64: column.Value(index),
-				Valid: column.IsValid(index),
-			}
-		case *array.String:
-			params[i].Value = sql.NullString{
-				String: column.Value(index),
-				Valid:  column.IsValid(index),
-			}
-		case *array.LargeString:
-			params[i].Value = sql.NullString{
-				String: column.Value(index),
-				Valid:  column.IsValid(index),
-			}
+	for _, field := range batch.Schema().Fields() {
+		switch field.Type.ID() {
+		case arrow.BOOL, arrow.FLOAT32, arrow.FLOAT64, arrow.INT8, arrow.INT16,
+			arrow.INT32, arrow.INT64, arrow.STRING, arrow.LARGE_STRING:
 		default:
 			return nil, adbc.Error{
 				Code: adbc.StatusNotImplemented,
@@ -92,7 +45,7 @@ func convertArrowToNamedValue(batch arrow.Record, index int) ([]driver.NamedValu
 			}
 		}
 	}
-	return params, nil
+	return driverbase.ArrowToNamedValues(batch, index)
 }
 
 type snowflakeBindReader struct {
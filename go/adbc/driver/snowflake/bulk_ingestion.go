@@ -36,6 +36,7 @@ import (
 	"time"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/memory"
@@ -126,14 +127,9 @@ func DefaultIngestOptions() *ingestOptions {
 // ingestRecord performs bulk ingestion of a single Record and returns the
 // number of rows affected.
 //
-// The Record must already be bound by calling stmt.Bind(), and will be released
-// and reset upon completion.
-func (st *statement) ingestRecord(ctx context.Context) (nrows int64, err error) {
-	defer func() {
-		// Record already released by writeParquet()
-		st.bound = nil
-	}()
-
+// The Record is taken from the parameters bound by calling stmt.Bind(),
+// and will be released upon completion.
+func (st *statement) ingestRecord(ctx context.Context, rec arrow.Record) (nrows int64, err error) {
 	var (
 		initialRows int64
 		target      = quoteTblName(st.targetTable)
@@ -142,7 +138,7 @@ func (st *statement) ingestRecord(ctx context.Context) (nrows int64, err error)
 	// Check final row count of target table to get definitive rows affected
 	initialRows, err = countRowsInTable(ctx, st.cnxn.cn, target)
 	if err != nil {
-		st.bound.Release()
+		rec.Release()
 		return
 	}
 
@@ -151,11 +147,12 @@ func (st *statement) ingestRecord(ctx context.Context) (nrows int64, err error)
 
 	// writeParquet takes a channel of Records, but we only have one Record to write
 	recordCh := make(chan arrow.Record, 1)
-	recordCh <- st.bound
+	// the Record is released by writeParquet()
+	recordCh <- rec
 	close(recordCh)
 
 	// Read the Record from the channel and write it into the provided writer
-	schema := st.bound.Schema()
+	schema := rec.Schema()
 	r, w := io.Pipe()
 	bw := bufio.NewWriter(w)
 	g.Go(func() (err error) {
@@ -204,13 +201,10 @@ func (st *statement) ingestRecord(ctx context.Context) (nrows int64, err error)
 // ingestStream performs bulk ingestion of a RecordReader and returns the
 // number of rows affected.
 //
-// The RecordReader must already be bound by calling stmt.BindStream(), and will
-// be released and reset upon completion.
-func (st *statement) ingestStream(ctx context.Context) (nrows int64, err error) {
-	defer func() {
-		st.streamBind.Release()
-		st.streamBind = nil
-	}()
+// The RecordReader is taken from the parameters bound by calling
+// stmt.BindStream(), and will be released upon completion.
+func (st *statement) ingestStream(ctx context.Context, stream array.RecordReader) (nrows int64, err error) {
+	defer stream.Release()
 
 	var (
 		initialRows int64
@@ -245,11 +239,11 @@ func (st *statement) ingestStream(ctx context.Context) (nrows int64, err error)
 	// Read records into channel
 	records := make(chan arrow.Record, st.ingestOptions.writerConcurrency)
 	g.Go(func() error {
-		return readRecords(gCtx, st.streamBind, records)
+		return readRecords(gCtx, stream, records)
 	})
 
 	// Read records from channel and write Parquet files in parallel to buffer pool
-	schema := st.streamBind.Schema()
+	schema := stream.Schema()
 	pool := newBufferPool(int(st.ingestOptions.targetFileSize))
 	buffers := make(chan *bytes.Buffer, st.ingestOptions.writerConcurrency)
 	g.Go(func() error {
@@ -277,7 +271,7 @@ func (st *statement) ingestStream(ctx context.Context) (nrows int64, err error)
 
 	// Read Parquet files from buffer pool and upload to Snowflake stage in parallel
 	g.Go(func() error {
-		return uploadAllStreams(gCtx, st.cnxn.cn, buffers, int(st.ingestOptions.uploadConcurrency), pool, fileReady)
+		return uploadAllStreams(gCtx, st.cnxn.cn, buffers, int(st.ingestOptions.uploadConcurrency), pool, fileReady, st.cnxn.RetryPolicy)
 	})
 
 	// Wait until either all files have been uploaded to Snowflake or the pipeline has failed / been canceled
@@ -478,6 +472,7 @@ func uploadAllStreams(
 	concurrency int,
 	buffers *bufferPool,
 	uploadCallback func(string),
+	retry driverbase.RetryPolicy,
 ) error {
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(concurrency)
@@ -500,7 +495,12 @@ func uploadAllStreams(
 			defer buffers.PutBuffer(buf)
 			defer uploadCallback(fileName)
 
-			return uploadStream(ctx, cn, buf, fileName)
+			// the PUT overwrites the staged file, so a failed upload can be
+			// retried from the start of the buffer
+			return driverbase.Retry(ctx, retry, true, func(ctx context.Context) error {
+				err := uploadStream(ctx, cn, bytes.NewReader(buf.Bytes()), fileName)
+				return errToAdbcErr(adbc.StatusIO, err)
+			})
 		})
 		i++
 	}
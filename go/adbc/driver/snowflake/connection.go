@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"path"
 	"runtime"
 	"strconv"
@@ -70,6 +71,8 @@ type connectionImpl struct {
 	cn   snowflakeConn
 	db   *databaseImpl
 	ctor driver.Connector
+	// transport downloads the result chunks of partitions
+	transport http.RoundTripper
 
 	activeTransaction     bool
 	useHighPrecision      bool
@@ -768,17 +771,6 @@ func (c *connectionImpl) Close() (err error) {
 	return c.cn.Close()
 }
 
-// ReadPartition constructs a statement for a partition of a query. The
-// results can then be read independently using the returned RecordReader.
-//
-// A partition can be retrieved by using ExecutePartitions on a statement.
-func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
-	return nil, adbc.Error{
-		Code: adbc.StatusNotImplemented,
-		Msg:  "ReadPartition not yet implemented for snowflake driver",
-	}
-}
-
 func (c *connectionImpl) SetOption(key, value string) error {
 	switch key {
 	case OptionUseHighPrecision:
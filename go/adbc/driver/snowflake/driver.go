@@ -155,6 +155,16 @@ func init() {
 	_ = gosnowflake.GetLogger().SetLogLevel("warn")
 }
 
+// snowflakeErrorStatuses are the statuses of Snowflake errors whose
+// SQLSTATE is too generic.
+var snowflakeErrorStatuses = map[int]adbc.Status{
+	604:    adbc.StatusCancelled,       // SQL execution canceled
+	630:    adbc.StatusTimeout,         // statement reached its timeout
+	390100: adbc.StatusUnauthenticated, // incorrect username or password
+	390144: adbc.StatusUnauthenticated, // JWT token is invalid
+	390318: adbc.StatusUnauthenticated, // OAuth access token expired
+}
+
 func errToAdbcErr(code adbc.Status, err error) error {
 	if err == nil {
 		return nil
@@ -168,19 +178,31 @@ func errToAdbcErr(code adbc.Status, err error) error {
 
 	var sferr *gosnowflake.SnowflakeError
 	if errors.As(err, &sferr) {
-		var sqlstate [5]byte
-		copy(sqlstate[:], []byte(sferr.SQLState))
-
-		if sferr.SQLState == "42S02" {
-			code = adbc.StatusNotFound
+		code = driverbase.StatusForSQLState(sferr.SQLState, code)
+		if status, ok := snowflakeErrorStatuses[sferr.Number]; ok {
+			code = status
+		}
+		sqlstate := driverbase.SQLState(sferr.SQLState)
+		if sqlstate[0] == 0 {
+			sqlstate = driverbase.SQLStateForStatus(code)
 		}
 
-		return adbc.Error{
+		e = adbc.Error{
 			Code:       code,
 			Msg:        sferr.Error(),
 			VendorCode: int32(sferr.Number),
 			SqlState:   sqlstate,
 		}
+		if sferr.QueryID != "" {
+			e.Details = append(e.Details, &adbc.QueryIDErrorDetail{QueryID: sferr.QueryID})
+		}
+		return e
+	}
+
+	if errors.Is(err, context.Canceled) {
+		code = adbc.StatusCancelled
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		code = adbc.StatusTimeout
 	}
 
 	return adbc.Error{
@@ -238,6 +260,13 @@ func NewDriver(alloc memory.Allocator) Driver {
 			panic(err)
 		}
 	}
+	info.RegisterOptionNamespace(optionNamespace)
+	if err := info.RegisterOptions(optionSpecs...); err != nil {
+		panic(err)
+	}
+	if err := info.RegisterRetryOptions(); err != nil {
+		panic(err)
+	}
 	return &driverImpl{DriverImplBase: driverbase.NewDriverImplBase(info, alloc)}
 }
 
@@ -277,6 +306,12 @@ func (d *driverImpl) NewDatabaseWithOptionsContext(
 		defaultAppName:        defaultAppName,
 		maxTimestampPrecision: Nanoseconds,
 	}
+	if err := db.ValidateOptions(opts); err != nil {
+		return nil, err
+	}
+	if err := db.SetRetryOptions(opts); err != nil {
+		return nil, err
+	}
 	if err := db.SetOptions(opts); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/snowflakedb/gosnowflake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrToAdbcErr(t *testing.T) {
+	var adbcErr adbc.Error
+
+	// the SQLSTATE determines the status
+	err := errToAdbcErr(adbc.StatusInternal, fmt.Errorf("query: %w", &gosnowflake.SnowflakeError{
+		Number:   2003,
+		SQLState: "42S02",
+		Message:  "Object 'T' does not exist or not authorized.",
+		QueryID:  "01b2c3d4",
+	}))
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotFound, adbcErr.Code)
+	assert.EqualValues(t, 2003, adbcErr.VendorCode)
+	assert.Equal(t, "42S02", string(adbcErr.SqlState[:]))
+	var queryID *adbc.QueryIDErrorDetail
+	require.True(t, errors.As(err, &queryID))
+	assert.Equal(t, "01b2c3d4", queryID.QueryID)
+
+	// unless the error number is more specific
+	err = errToAdbcErr(adbc.StatusInternal, &gosnowflake.SnowflakeError{Number: 630, SQLState: "57014"})
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+	assert.Equal(t, "57014", string(adbcErr.SqlState[:]))
+	assert.False(t, errors.As(err, &queryID))
+
+	// errors without a SQLSTATE get the one of their status
+	err = errToAdbcErr(adbc.StatusIO, &gosnowflake.SnowflakeError{Number: 261000})
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusIO, adbcErr.Code)
+	assert.Equal(t, "08000", string(adbcErr.SqlState[:]))
+
+	err = errToAdbcErr(adbc.StatusIO, context.DeadlineExceeded)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+}
@@ -216,10 +216,10 @@ func (s *SnowflakeQuirks) SupportsConcurrentStatements() bool          { return
 func (s *SnowflakeQuirks) SupportsCurrentCatalogSchema() bool          { return true }
 func (s *SnowflakeQuirks) SupportsExecuteSchema() bool                 { return true }
 func (s *SnowflakeQuirks) SupportsGetSetOptions() bool                 { return true }
-func (s *SnowflakeQuirks) SupportsPartitionedData() bool               { return false }
-func (s *SnowflakeQuirks) SupportsStatistics() bool                    { return false }
+func (s *SnowflakeQuirks) SupportsPartitionedData() bool               { return true }
+func (s *SnowflakeQuirks) SupportsStatistics() bool                    { return true }
 func (s *SnowflakeQuirks) SupportsTransactions() bool                  { return true }
-func (s *SnowflakeQuirks) SupportsGetParameterSchema() bool            { return false }
+func (s *SnowflakeQuirks) SupportsGetParameterSchema() bool            { return true }
 func (s *SnowflakeQuirks) SupportsDynamicParameterBinding() bool       { return false }
 func (s *SnowflakeQuirks) SupportsErrorIngestIncompatibleSchema() bool { return false }
 func (s *SnowflakeQuirks) Catalog() string                             { return s.catalogName }
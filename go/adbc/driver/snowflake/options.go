@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"strconv"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+// optionNamespace is the prefix of the driver-specific options. Keys with
+// this prefix that aren't listed in optionSpecs are rejected; other
+// unknown database options are passed to Snowflake as session parameters.
+const optionNamespace = "adbc.snowflake."
+
+const (
+	levelDatabase  = driverbase.OptionLevelDatabase
+	levelConn      = driverbase.OptionLevelConnection
+	levelStatement = driverbase.OptionLevelStatement
+)
+
+var ingestModes = []string{
+	adbc.OptionValueIngestModeCreate,
+	adbc.OptionValueIngestModeAppend,
+	adbc.OptionValueIngestModeReplace,
+	adbc.OptionValueIngestModeCreateAppend,
+}
+
+var optionSpecs = []driverbase.OptionSpec{
+	{Name: adbc.OptionKeyURI, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "Snowflake DSN, parsed by gosnowflake. May contain credentials."},
+	{Name: adbc.OptionKeyUsername, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyPassword, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true},
+	{Name: adbc.OptionKeyTelemetryTraceParent, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeString,
+		Description: "W3C trace parent to attach to OpenTelemetry traces."},
+	{Name: adbc.OptionKeyAutoCommit, Levels: levelConn, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueEnabled)},
+	{Name: adbc.OptionKeyCurrentCatalog, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyCurrentDbSchema, Levels: levelConn, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestTargetTable, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: adbc.OptionKeyIngestMode, Levels: levelStatement, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(adbc.OptionValueIngestModeCreate), AllowedValues: ingestModes},
+	{Name: adbc.OptionKeyIncremental, Levels: levelStatement, Type: driverbase.OptionTypeBool,
+		Default:     driverbase.OptionDefault(adbc.OptionValueDisabled),
+		Description: "Run the query of ExecutePartitions in the background, waiting for it across calls."},
+
+	{Name: OptionDatabase, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionSchema, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionWarehouse, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionRole, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionRegion, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionAccount, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionProtocol, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		AllowedValues: []string{"http", "https"}},
+	{Name: OptionPort, Levels: levelDatabase, Type: driverbase.OptionTypeInt},
+	{Name: OptionHost, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionAuthType, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(OptionValueAuthSnowflake),
+		AllowedValues: []string{
+			OptionValueAuthSnowflake,
+			OptionValueAuthOAuth,
+			OptionValueAuthExternalBrowser,
+			OptionValueAuthOkta,
+			OptionValueAuthJwt,
+			OptionValueAuthUserPassMFA,
+		}},
+	{Name: OptionLoginTimeout, Levels: levelDatabase, Type: driverbase.OptionTypeDuration,
+		Description: "Login retry timeout, excluding network round trips."},
+	{Name: OptionRequestTimeout, Levels: levelDatabase, Type: driverbase.OptionTypeDuration,
+		Description: "Request retry timeout, excluding network round trips."},
+	{Name: OptionJwtExpireTimeout, Levels: levelDatabase, Type: driverbase.OptionTypeDuration,
+		Description: "Expiration of the JWT used for authentication."},
+	{Name: OptionClientTimeout, Levels: levelDatabase, Type: driverbase.OptionTypeDuration,
+		Description: "Timeout for a network round trip and reading the response."},
+	{Name: OptionUseHighPrecision, Levels: driverbase.OptionLevelAll, Type: driverbase.OptionTypeBool,
+		Default:     driverbase.OptionDefault(adbc.OptionValueEnabled),
+		Description: "Return fixed-point NUMBER columns as Decimal128 instead of Int64 or Float64."},
+	{Name: OptionMaxTimestampPrecision, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault(OptionValueNanoseconds),
+		AllowedValues: []string{
+			OptionValueNanoseconds,
+			OptionValueNanosecondsNoOverflow,
+			OptionValueMicroseconds,
+		}},
+	{Name: OptionApplicationName, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionSSLSkipVerify, Levels: levelDatabase, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: OptionOCSPFailOpenMode, Levels: levelDatabase, Type: driverbase.OptionTypeBool},
+	{Name: OptionAuthToken, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "Token for OAuth and other token-based authentication."},
+	{Name: OptionAuthOktaUrl, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionKeepSessionAlive, Levels: levelDatabase, Type: driverbase.OptionTypeBool,
+		Default: driverbase.OptionDefault(adbc.OptionValueDisabled)},
+	{Name: OptionJwtPrivateKey, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Description: "Path to the private key used to sign the JWT."},
+	{Name: OptionJwtPrivateKeyPkcs8Value, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "PKCS #8 private key used to sign the JWT."},
+	{Name: OptionJwtPrivateKeyPkcs8Password, Levels: levelDatabase, Type: driverbase.OptionTypeString, Secret: true,
+		Description: "Passcode of an encrypted PKCS #8 private key."},
+	{Name: OptionDisableTelemetry, Levels: levelDatabase, Type: driverbase.OptionTypeBool},
+	{Name: OptionLogTracing, Levels: levelDatabase, Type: driverbase.OptionTypeString,
+		Default: driverbase.OptionDefault("fatal"), Description: "Log level of gosnowflake."},
+	{Name: OptionClientConfigFile, Levels: levelDatabase, Type: driverbase.OptionTypeString},
+	{Name: OptionClientRequestMFAToken, Levels: levelDatabase, Type: driverbase.OptionTypeBool},
+	{Name: OptionClientStoreTempCred, Levels: levelDatabase, Type: driverbase.OptionTypeBool},
+
+	{Name: OptionStatementQueryTag, Levels: levelStatement, Type: driverbase.OptionTypeString},
+	{Name: OptionStatementQueueSize, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default:     driverbase.OptionDefault(strconv.Itoa(defaultStatementQueueSize)),
+		Description: "Number of record batches to buffer per result stream."},
+	{Name: OptionStatementPrefetchConcurrency, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default: driverbase.OptionDefault(strconv.Itoa(defaultPrefetchConcurrency))},
+	{Name: OptionStatementIngestWriterConcurrency, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Description: "Number of Parquet files written in parallel. Defaults to the number of CPUs."},
+	{Name: OptionStatementIngestUploadConcurrency, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default: driverbase.OptionDefault(strconv.FormatUint(uint64(defaultUploadConcurrency), 10))},
+	{Name: OptionStatementIngestCopyConcurrency, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default: driverbase.OptionDefault(strconv.FormatUint(uint64(defaultCopyConcurrency), 10))},
+	{Name: OptionStatementIngestTargetFileSize, Levels: levelStatement, Type: driverbase.OptionTypeInt,
+		Default:     driverbase.OptionDefault(strconv.FormatUint(uint64(defaultTargetFileSize), 10)),
+		Description: "Approximate size in bytes of the Parquet files uploaded for ingestion."},
+}
@@ -0,0 +1,104 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+type bindCountKey struct{}
+
+// withBindCount returns a context whose describe-only query responses
+// record the number of binds the server found in the query into the
+// returned counter. The counter is left at -1 if no response reported it.
+func withBindCount(ctx context.Context) (context.Context, *int) {
+	n := -1
+	return context.WithValue(ctx, bindCountKey{}, &n), &n
+}
+
+// bindCountTransport reads the number of binds out of query responses.
+// Snowflake reports it for describe-only queries, but gosnowflake does
+// not expose it.
+type bindCountTransport struct {
+	base http.RoundTripper
+}
+
+// newBindCountTransport returns the transport to use for cfg. gosnowflake
+// only applies OCSP fail-closed mode when no transport is configured, so
+// the transport is left alone in that case and bind counts are not
+// reported.
+func newBindCountTransport(cfg *gosnowflake.Config) http.RoundTripper {
+	base := cfg.Transporter
+	if base == nil {
+		switch {
+		case cfg.DisableOCSPChecks || cfg.InsecureMode:
+			// the same as gosnowflake's transport without OCSP checks
+			transport := gosnowflake.SnowflakeTransport.Clone()
+			transport.TLSClientConfig = nil
+			base = transport
+		case cfg.OCSPFailOpen == gosnowflake.OCSPFailOpenFalse:
+			return nil
+		default:
+			base = gosnowflake.SnowflakeTransport
+		}
+	}
+	return &bindCountTransport{base: base}
+}
+
+func (t *bindCountTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	count, ok := req.Context().Value(bindCountKey{}).(*int)
+	if err != nil || !ok || req.URL.Path != "/queries/v1/query-request" {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var result struct {
+		Data struct {
+			NumberOfBinds int `json:"numberOfBinds"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(body, &result) == nil {
+		*count = result.Data.NumberOfBinds
+	}
+	return resp, nil
+}
+
+// buildParameterSchema returns the parameter schema for n bind positions.
+// Snowflake binds are unnamed and their types are not reported, so every
+// field is an unnamed NA.
+func buildParameterSchema(n int) *arrow.Schema {
+	fields := make([]arrow.Field, n)
+	for i := range fields {
+		fields[i] = arrow.Field{Type: arrow.Null, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil)
+}
@@ -0,0 +1,62 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareParameterSchema(t *testing.T) {
+	srv := &fakeSnowflakeServer{}
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+
+	// the binds are counted by the server in a describe-only query
+	require.NoError(t, st.SetSqlQuery("SELECT ?, ? FROM t WHERE x = ?"))
+	require.NoError(t, st.Prepare(context.Background()))
+	srv.mu.Lock()
+	assert.Equal(t, []string{"SELECT ?, ? FROM t WHERE x = ?"}, srv.queries)
+	srv.mu.Unlock()
+	schema, err := st.GetParameterSchema()
+	require.NoError(t, err)
+	assert.Equal(t, 3, schema.NumFields())
+	for _, f := range schema.Fields() {
+		assert.Equal(t, arrow.Null, f.Type)
+	}
+}
+
+func TestBuildParameterSchema(t *testing.T) {
+	schema := buildParameterSchema(2)
+	expected := arrow.NewSchema([]arrow.Field{
+		{Type: arrow.Null, Nullable: true},
+		{Type: arrow.Null, Nullable: true},
+	}, nil)
+	assert.Truef(t, expected.Equal(schema), "expected: %s\ngot: %s", expected, schema)
+}
@@ -0,0 +1,487 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// Partitions are the chunks of the result of a query. Snowflake stores
+// each chunk for download from a presigned URL, so a partition descriptor
+// holds that URL and the headers needed to download the chunk, and any
+// process can read it without running the query again, until the URL
+// expires. The first rows of a result are sent with the result itself, so
+// the first partition holds them instead. The chunks are in the order of
+// the result, so reading the partitions in order gives the rows in the
+// order of the query.
+//
+// gosnowflake only downloads chunks through the connection that ran the
+// query and doesn't expose where they are, so they are located through the
+// exported fields of its loader.
+
+// queryIDPattern matches Snowflake query IDs, which are UUIDs.
+var queryIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}$`)
+
+// The headers gosnowflake sends to download result chunks when Snowflake
+// doesn't give any: the chunks are encrypted with the key of the result.
+const (
+	headerSSECAlgorithm = "x-amz-server-side-encryption-customer-algorithm"
+	headerSSECKey       = "x-amz-server-side-encryption-customer-key"
+	headerSSECAES       = "AES256"
+)
+
+// partitionDescriptor is the serialized form of a partition.
+type partitionDescriptor struct {
+	// the ID of the query, whose result gives the types of the rows
+	QueryID string `json:"query_id"`
+	// the rows of the partition as an Arrow IPC stream, if they were sent
+	// with the result
+	Rows []byte `json:"rows,omitempty"`
+	// otherwise, the URL of the chunk holding the rows of the partition
+	// and the headers needed to download it. If neither is set, the
+	// partition is the whole result, read through RESULT_SCAN.
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// the number of rows in the partition
+	NumRows int64 `json:"num_rows"`
+}
+
+// resultChunk is a chunk of a result, downloaded separately from the
+// result.
+type resultChunk struct {
+	URL     string
+	NumRows int64
+}
+
+// resultChunks returns the chunks of the result loaded by ld, and the
+// headers needed to download them.
+func resultChunks(ld gosnowflake.ArrowStreamLoader) ([]resultChunk, map[string]string, error) {
+	v := reflect.Indirect(reflect.ValueOf(ld))
+	if v.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("cannot locate the chunks of a result loaded by %T", ld)
+	}
+	metas, qrmk, chunkHeader := v.FieldByName("ChunkMetas"), v.FieldByName("Qrmk"), v.FieldByName("ChunkHeader")
+	if metas.Kind() != reflect.Slice || qrmk.Kind() != reflect.String || chunkHeader.Kind() != reflect.Map {
+		return nil, nil, fmt.Errorf("cannot locate the chunks of a result loaded by %T", ld)
+	}
+
+	chunks := make([]resultChunk, metas.Len())
+	for i := range chunks {
+		meta := reflect.Indirect(metas.Index(i))
+		url, rows := meta.FieldByName("URL"), meta.FieldByName("RowCount")
+		if url.Kind() != reflect.String || !rows.CanInt() {
+			return nil, nil, fmt.Errorf("cannot locate the chunks of a result loaded by %T", ld)
+		}
+		chunks[i] = resultChunk{URL: url.String(), NumRows: rows.Int()}
+	}
+
+	headers := make(map[string]string)
+	if chunkHeader.Len() > 0 {
+		iter := chunkHeader.MapRange()
+		for iter.Next() {
+			headers[iter.Key().String()] = iter.Value().String()
+		}
+	} else {
+		headers[headerSSECAlgorithm] = headerSSECAES
+		headers[headerSSECKey] = qrmk.String()
+	}
+	return chunks, headers, nil
+}
+
+// resultPartitions returns the partitions of the result of the query with
+// the given ID, as loaded by ld.
+func resultPartitions(ctx context.Context, queryID string, ld gosnowflake.ArrowStreamLoader) ([]partitionDescriptor, error) {
+	if ld.TotalRows() == 0 {
+		return nil, nil
+	}
+	// metadata queries return their rows as JSON rather than Arrow, these
+	// are small enough to be a single partition
+	if len(ld.JSONData()) > 0 {
+		return []partitionDescriptor{{QueryID: queryID, NumRows: ld.TotalRows()}}, nil
+	}
+
+	batches, err := ld.GetBatches()
+	if err != nil {
+		return nil, err
+	}
+	chunks, headers, err := resultChunks(ld)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]partitionDescriptor, 0, len(chunks)+1)
+	if len(batches) > len(chunks) {
+		// the first batch holds the rows sent with the result
+		numRows := ld.TotalRows()
+		for _, chunk := range chunks {
+			numRows -= chunk.NumRows
+		}
+		if numRows > 0 {
+			rows, err := readBatch(ctx, batches[0])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, partitionDescriptor{QueryID: queryID, Rows: rows, NumRows: numRows})
+		}
+	}
+	for _, chunk := range chunks {
+		if chunk.NumRows > 0 {
+			out = append(out, partitionDescriptor{QueryID: queryID, URL: chunk.URL, Headers: headers, NumRows: chunk.NumRows})
+		}
+	}
+	return out, nil
+}
+
+// readBatch reads the whole Arrow IPC stream of a batch.
+func readBatch(ctx context.Context, batch gosnowflake.ArrowStreamBatch) ([]byte, error) {
+	rdr, err := batch.GetStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	return io.ReadAll(rdr)
+}
+
+func serializePartitions(descriptors []partitionDescriptor) (adbc.Partitions, error) {
+	out := adbc.Partitions{
+		NumPartitions: uint64(len(descriptors)),
+		PartitionIDs:  make([][]byte, len(descriptors)),
+	}
+	for i, descriptor := range descriptors {
+		data, err := json.Marshal(descriptor)
+		if err != nil {
+			return adbc.Partitions{}, err
+		}
+		out.PartitionIDs[i] = data
+	}
+	return out, nil
+}
+
+func deserializePartition(serializedPartition []byte) (*partitionDescriptor, error) {
+	var partition partitionDescriptor
+	if err := json.Unmarshal(serializedPartition, &partition); err != nil {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[Snowflake] invalid partition descriptor: %s", err.Error()),
+		}
+	}
+	// the query ID is interpolated into the query, so it must be checked
+	if !queryIDPattern.MatchString(partition.QueryID) {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  fmt.Sprintf("[Snowflake] invalid partition descriptor: bad query ID '%s'", partition.QueryID),
+		}
+	}
+	if partition.NumRows < 0 {
+		return nil, adbc.Error{
+			Code: adbc.StatusInvalidArgument,
+			Msg:  "[Snowflake] invalid partition descriptor: negative number of rows",
+		}
+	}
+	if partition.URL != "" {
+		if partition.Rows != nil {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  "[Snowflake] invalid partition descriptor: both rows and a chunk URL are given",
+			}
+		}
+		if u, err := url.Parse(partition.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+			return nil, adbc.Error{
+				Code: adbc.StatusInvalidArgument,
+				Msg:  fmt.Sprintf("[Snowflake] invalid partition descriptor: bad chunk URL '%s'", partition.URL),
+			}
+		}
+	}
+	return &partition, nil
+}
+
+// ExecutePartitions executes the current statement and gets the results
+// as a partitioned result set.
+//
+// It returns the Schema of the result set, the collection of partition
+// descriptors and the number of rows affected, if known. If unknown,
+// the number of rows affected will be -1.
+//
+// There is one partition per chunk of the query's result, in the order of
+// the result. A partition is downloaded directly from the storage that
+// holds the chunk, so it can be read by any connection of the same user
+// until the chunk URLs expire, a few hours after the query. Partition
+// descriptors grant access to the rows they hold, and should be kept as
+// secret as the data.
+//
+// If adbc.OptionKeyIncremental is enabled, the first call submits the
+// query and returns at once, and each later call checks its status once
+// without waiting for it. While the query runs, a call returns no schema
+// and no partitions, and its progress is reported by
+// adbc.OptionKeyProgress. Once the query is complete, its partitions are
+// returned with the schema, and the next call returns the schema and no
+// partitions to signal completion. The result of the query is then read
+// back through RESULT_SCAN, which doesn't keep the order of the rows.
+func (st *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	done, err := st.State.BeginExecute("ExecutePartitions")
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	defer done()
+
+	if st.incrementalState != nil {
+		return st.executeIncremental(ctx)
+	}
+	if err := st.checkPlainQuery("ExecutePartitions"); err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	ctx, cancel := st.setQueryContext(ctx)
+	defer cancel()
+	return st.executePartitions(ctx, st.query)
+}
+
+// checkPlainQuery returns an error if the statement isn't a query without
+// parameters, which is all that method supports.
+func (st *statement) checkPlainQuery(method string) error {
+	if st.targetTable != "" {
+		return adbc.Error{
+			Msg:  fmt.Sprintf("%s is not supported for bulk ingestion", method),
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	if st.query == "" {
+		return adbc.Error{
+			Msg:  "cannot execute without a query",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	if st.State.HasBound() {
+		return adbc.Error{
+			Msg:  fmt.Sprintf("%s with bound parameters not yet implemented for Snowflake", method),
+			Code: adbc.StatusNotImplemented,
+		}
+	}
+	return nil
+}
+
+// executePartitions runs query and returns the partitions of its result.
+func (st *statement) executePartitions(ctx context.Context, query string) (*arrow.Schema, adbc.Partitions, int64, error) {
+	queryID := make(chan string, 1)
+	loader, err := st.cnxn.cn.QueryArrowStream(gosnowflake.WithQueryIDChan(ctx, queryID), query)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, errToAdbcErr(adbc.StatusInternal, err)
+	}
+
+	schema, err := rowTypesToArrowSchema(ctx, loader, st.useHighPrecision, st.maxTimestampPrecision)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, errToAdbcErr(adbc.StatusInternal, err)
+	}
+	schema, _ = getTransformer(schema, loader, st.useHighPrecision, st.maxTimestampPrecision)
+
+	id, err := receiveQueryID(queryID)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	descriptors, err := resultPartitions(ctx, id, loader)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, errToAdbcErr(adbc.StatusInternal, err)
+	}
+	partitions, err := serializePartitions(descriptors)
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, adbc.Error{
+			Msg:  fmt.Sprintf("[Snowflake] could not serialize partition: %s", err.Error()),
+			Code: adbc.StatusInternal,
+		}
+	}
+	return schema, partitions, loader.TotalRows(), nil
+}
+
+// receiveQueryID returns the query ID gosnowflake sent to queryID, once
+// the query was accepted.
+func receiveQueryID(queryID <-chan string) (string, error) {
+	select {
+	case id := <-queryID:
+		if id != "" {
+			return id, nil
+		}
+	default:
+	}
+	return "", adbc.Error{
+		Msg:  "[Snowflake] query was submitted but no query ID was returned",
+		Code: adbc.StatusInternal,
+	}
+}
+
+// executeIncremental submits the query on the first call, and checks
+// whether it is complete on later calls. Once it is complete, the
+// partitions of its result are returned.
+func (st *statement) executeIncremental(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	state := st.incrementalState
+	if state.complete {
+		schema, rows := state.schema, state.rows
+		// Reset the statement for reuse
+		st.incrementalState = &incrementalState{}
+		return schema, adbc.Partitions{}, rows, nil
+	}
+
+	ctx, cancel := st.setQueryContext(ctx)
+	defer cancel()
+
+	if state.queryID == "" {
+		if err := st.checkPlainQuery("ExecutePartitions"); err != nil {
+			return nil, adbc.Partitions{}, -1, err
+		}
+		st.Progress.Start()
+		// the query outlives this call, only gosnowflake's wait for it
+		// ends with ctx
+		queryID, res, err := st.submit(ctx)
+		if err != nil {
+			return nil, adbc.Partitions{}, -1, err
+		}
+		if res != nil {
+			go func() { _, _ = res.RowsAffected() }()
+		}
+		state.queryID = queryID
+		st.trackProgress(ctx, queryID)
+		// the query is still running, its partitions are returned by a
+		// later call
+		return nil, adbc.Partitions{}, -1, nil
+	}
+
+	done, err := st.cnxn.queryDone(ctx, state.queryID)
+	if err != nil {
+		if ctx.Err() != nil {
+			_ = st.cnxn.abortQuery(state.queryID)
+			err = ctx.Err()
+		}
+		st.incrementalState = &incrementalState{}
+		return nil, adbc.Partitions{}, -1, errToAdbcErr(adbc.StatusIO, err)
+	} else if !done {
+		return nil, adbc.Partitions{}, -1, nil
+	}
+
+	schema, partitions, rows, err := st.executePartitions(ctx, resultScanQuery(state.queryID))
+	if err != nil {
+		st.incrementalState = &incrementalState{}
+		return nil, adbc.Partitions{}, -1, err
+	}
+	st.Progress.Finish(rows, -1)
+	state.schema, state.rows = schema, rows
+	state.complete = true
+	// Special case: returning no partitions already implies completion
+	if partitions.NumPartitions == 0 {
+		st.incrementalState = &incrementalState{}
+	}
+	return schema, partitions, rows, nil
+}
+
+// ReadPartition constructs a statement for a partition of a query. The
+// results can then be read independently using the returned RecordReader.
+//
+// A partition can be retrieved by using ExecutePartitions on a statement.
+// The partition does not have to come from this connection.
+func (c *connectionImpl) ReadPartition(ctx context.Context, serializedPartition []byte) (array.RecordReader, error) {
+	partition, err := deserializePartition(serializedPartition)
+	if err != nil {
+		return nil, err
+	}
+
+	if partition.Rows == nil && partition.URL == "" {
+		loader, err := c.cn.QueryArrowStream(ctx, resultScanQuery(partition.QueryID))
+		if err != nil {
+			return nil, errToAdbcErr(adbc.StatusInternal, err)
+		}
+		return newRecordReader(ctx, c.Alloc, loader, defaultStatementQueueSize,
+			defaultPrefetchConcurrency, c.useHighPrecision, c.maxTimestampPrecision, c.RetryPolicy)
+	}
+
+	// the rows are converted according to the types of the result, which
+	// are described without running a query
+	loader, err := c.cn.QueryArrowStream(gosnowflake.WithDescribeOnly(ctx), resultScanQuery(partition.QueryID))
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusInternal, err)
+	}
+
+	stream := func(ctx context.Context) (io.ReadCloser, error) {
+		if partition.Rows != nil {
+			return io.NopCloser(bytes.NewReader(partition.Rows)), nil
+		}
+		return driverbase.RetryValue(ctx, c.RetryPolicy, true, func(ctx context.Context) (io.ReadCloser, error) {
+			return c.downloadChunk(ctx, partition)
+		})
+	}
+	return newChunkRecordReader(ctx, c.Alloc, loader, []chunkStream{stream}, defaultStatementQueueSize,
+		defaultPrefetchConcurrency, c.useHighPrecision, c.maxTimestampPrecision)
+}
+
+// downloadChunk opens the Arrow IPC stream of the result chunk of a
+// partition, which Snowflake may compress with gzip.
+func (c *connectionImpl) downloadChunk(ctx context.Context, partition *partitionDescriptor) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, partition.URL, nil)
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusInvalidArgument, err)
+	}
+	for k, v := range partition.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := (&http.Client{Transport: c.transport}).Do(req)
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusIO, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, adbc.Error{
+			Msg:  fmt.Sprintf("[Snowflake] could not download partition, it may have expired: %s", resp.Status),
+			Code: adbc.StatusIO,
+		}
+	}
+
+	body := bufio.NewReader(resp.Body)
+	if magic, err := body.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, errToAdbcErr(adbc.StatusIO, err)
+		}
+		return &chunkReader{Reader: gz, body: resp.Body}, nil
+	}
+	return &chunkReader{Reader: body, body: resp.Body}, nil
+}
+
+// chunkReader reads a downloaded chunk, closing the response body when
+// closed.
+type chunkReader struct {
+	io.Reader
+	body io.Closer
+}
+
+func (r *chunkReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,438 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartitionDescriptors(t *testing.T) {
+	const queryID = "01b2c3d4-0000-1a2b-0000-0004c1d2e3f6"
+
+	descriptors := []partitionDescriptor{
+		{QueryID: queryID, Rows: []byte("rows"), NumRows: 100},
+		{QueryID: queryID, URL: "https://results.example.com/chunk0", Headers: map[string]string{"X-Key": "key"}, NumRows: 50},
+		{QueryID: queryID, NumRows: 7},
+	}
+	partitions, err := serializePartitions(descriptors)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, partitions.NumPartitions)
+	require.Len(t, partitions.PartitionIDs, 3)
+
+	for i, id := range partitions.PartitionIDs {
+		partition, err := deserializePartition(id)
+		require.NoError(t, err)
+		assert.Equal(t, descriptors[i], *partition)
+	}
+}
+
+func TestDeserializePartitionInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		partition string
+	}{
+		{"not json", "not a partition"},
+		{"missing query id", `{"num_rows": 1}`},
+		{"quoted query id", `{"query_id": "x')); DROP TABLE t; --", "num_rows": 1}`},
+		{"negative rows", `{"query_id": "01b2c3d4-0000-1a2b-0000-0004c1d2e3f6", "num_rows": -1}`},
+		{"rows and url", `{"query_id": "01b2c3d4-0000-1a2b-0000-0004c1d2e3f6", "rows": "cm93cw==", "url": "https://results.example.com/chunk0", "num_rows": 1}`},
+		{"bad url", `{"query_id": "01b2c3d4-0000-1a2b-0000-0004c1d2e3f6", "url": "file:///etc/passwd", "num_rows": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var adbcErr adbc.Error
+			_, err := deserializePartition([]byte(tt.partition))
+			require.ErrorAs(t, err, &adbcErr)
+			assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+		})
+	}
+}
+
+// fakeSnowflakeServer is a stand-in for the Snowflake REST API that
+// accepts any login and answers every query with the rows "a" and "b" and
+// a chunk holding the row "c", except describe-only queries which report
+// a bind for every ? in the query. Queries other than RESULT_SCANs and cancellations are reported as
+// in progress until release is closed.
+type fakeSnowflakeServer struct {
+	mu          sync.Mutex
+	queries     []string
+	statusPolls int
+	aborts      int
+	release     chan struct{}
+}
+
+const (
+	fakeQueryID           = "01b2c3d4-0000-1a2b-0000-0004c1d2e3f6"
+	fakeResultScanQueryID = "01b2c3d4-0000-1a2b-0000-0004c1d2e3f7"
+	fakeChunkPath         = "/chunks/0"
+	fakeChunkHeader       = "X-Fake-Chunk-Key"
+)
+
+func (s *fakeSnowflakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var resp any = map[string]any{"success": true}
+	switch {
+	case r.URL.Path == "/session/v1/login-request":
+		resp = map[string]any{
+			"success": true,
+			"data": map[string]any{
+				"token":                   "token",
+				"masterToken":             "master",
+				"validityInSeconds":       3600,
+				"masterValidityInSeconds": 3600,
+				"sessionId":               1,
+			},
+		}
+	case r.URL.Path == "/queries/v1/query-request":
+		var req struct {
+			SQLText      string `json:"sqlText"`
+			AsyncExec    bool   `json:"asyncExec"`
+			DescribeOnly bool   `json:"describeOnly"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.queries = append(s.queries, req.SQLText)
+		s.mu.Unlock()
+		if req.DescribeOnly {
+			resp = map[string]any{
+				"success": true,
+				"data": map[string]any{
+					"queryId":           fakeQueryID,
+					"queryResultFormat": "arrow",
+					"rowtype": []map[string]any{
+						{"name": "V", "type": "text", "nullable": true, "length": 16, "byteLength": 16},
+					},
+					"numberOfBinds": strings.Count(req.SQLText, "?"),
+				},
+			}
+			break
+		}
+		if strings.Contains(req.SQLText, "FROM TABLE(RESULT_SCAN(") || strings.Contains(req.SQLText, "SYSTEM$CANCEL_QUERY") {
+			resp = fakeResult(fakeResultScanQueryID, r.Host)
+			break
+		}
+		// the result is fetched from getResultUrl until it is ready
+		code := "333333"
+		if req.AsyncExec {
+			code = "333334"
+		}
+		resp = map[string]any{
+			"success": true,
+			"code":    code,
+			"data": map[string]any{
+				"queryId":      fakeQueryID,
+				"getResultUrl": "/queries/" + fakeQueryID + "/result",
+			},
+		}
+	case r.URL.Path == "/queries/"+fakeQueryID+"/result":
+		select {
+		case <-s.release:
+		case <-r.Context().Done():
+			return
+		}
+		resp = fakeResult(fakeQueryID, r.Host)
+	case r.URL.Path == fakeChunkPath:
+		if r.Header.Get(fakeChunkHeader) != "secret" {
+			http.Error(w, "missing chunk header", http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write(fakeChunk)
+		return
+	case r.URL.Path == "/queries/v1/abort-request":
+		s.mu.Lock()
+		s.aborts++
+		s.mu.Unlock()
+	case r.URL.Path == "/monitoring/queries/"+fakeQueryID:
+		s.mu.Lock()
+		s.statusPolls++
+		s.mu.Unlock()
+		status := "RUNNING"
+		select {
+		case <-s.release:
+			status = "SUCCESS"
+		default:
+		}
+		resp = map[string]any{
+			"success": true,
+			"data": map[string]any{
+				"queries": []map[string]any{{"status": status}},
+			},
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// fakeArrowStream returns an Arrow IPC stream of the given values of the
+// column V.
+func fakeArrowStream(values ...string) []byte {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "V", Type: arrow.BinaryTypes.String, Nullable: true}}, nil)
+	bldr := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.StringBuilder).AppendValues(values, nil)
+	rec := bldr.NewRecord()
+	defer rec.Release()
+
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := w.Write(rec); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// fakeChunk is the chunk of every result, compressed with gzip like
+// Snowflake does.
+var fakeChunk = func() []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(fakeArrowStream("c")); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}()
+
+// fakeResult is the response to a completed query, with the rows "a" and
+// "b" inline and the row "c" in a chunk served by host.
+func fakeResult(queryID, host string) map[string]any {
+	return map[string]any{
+		"success": true,
+		"data": map[string]any{
+			"queryId":           queryID,
+			"queryResultFormat": "arrow",
+			"rowtype": []map[string]any{
+				{"name": "V", "type": "text", "nullable": true, "length": 16, "byteLength": 16},
+			},
+			"total":        3,
+			"returned":     3,
+			"rowsetBase64": base64.StdEncoding.EncodeToString(fakeArrowStream("a", "b")),
+			"chunks": []map[string]any{
+				{"url": "http://" + host + fakeChunkPath, "rowCount": 1},
+			},
+			"chunkHeaders": map[string]string{fakeChunkHeader: "secret"},
+		},
+	}
+}
+
+// openFakeConnection opens a connection to srv, which is closed at the
+// end of the test.
+func openFakeConnection(t *testing.T, mem memory.Allocator, srv *fakeSnowflakeServer) adbc.Connection {
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(httpSrv.Close)
+	host, port, err := net.SplitHostPort(httpSrv.Listener.Addr().String())
+	require.NoError(t, err)
+
+	db, err := NewDriver(mem).NewDatabase(map[string]string{
+		OptionAccount:          "account",
+		OptionHost:             host,
+		OptionPort:             port,
+		OptionProtocol:         "http",
+		OptionDisableTelemetry: adbc.OptionValueEnabled,
+		adbc.OptionKeyUsername: "user",
+		adbc.OptionKeyPassword: "password",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { validation.CheckedClose(t, db) })
+
+	cnxn, err := db.Open(context.Background())
+	require.NoError(t, err)
+	t.Cleanup(func() { validation.CheckedClose(t, cnxn) })
+	return cnxn
+}
+
+// readPartitions reads the values of the column V of every partition, in
+// order.
+func readPartitions(t *testing.T, cnxn adbc.Connection, partitions adbc.Partitions) []string {
+	var values []string
+	for _, id := range partitions.PartitionIDs {
+		rdr, err := cnxn.ReadPartition(context.Background(), id)
+		require.NoError(t, err)
+		for rdr.Next() {
+			col := rdr.Record().Column(0).(*array.String)
+			for i := 0; i < col.Len(); i++ {
+				values = append(values, col.Value(i))
+			}
+		}
+		require.NoError(t, rdr.Err())
+		rdr.Release()
+	}
+	return values
+}
+
+func TestExecutePartitions(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	close(srv.release)
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+	require.NoError(t, st.SetSqlQuery("SELECT v FROM t ORDER BY v"))
+
+	schema, partitions, rows, err := st.ExecutePartitions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "V", schema.Field(0).Name)
+	assert.EqualValues(t, 3, rows)
+
+	// the rows sent with the result are a partition, and so is each chunk
+	require.EqualValues(t, 2, partitions.NumPartitions)
+	inline, err := deserializePartition(partitions.PartitionIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, fakeQueryID, inline.QueryID)
+	assert.EqualValues(t, 2, inline.NumRows)
+	assert.NotEmpty(t, inline.Rows)
+	chunk, err := deserializePartition(partitions.PartitionIDs[1])
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, chunk.NumRows)
+	assert.True(t, strings.HasSuffix(chunk.URL, fakeChunkPath))
+	assert.Equal(t, map[string]string{fakeChunkHeader: "secret"}, chunk.Headers)
+
+	// the partitions are read in the order of the result, without running
+	// the query again
+	assert.Equal(t, []string{"a", "b", "c"}, readPartitions(t, cnxn, partitions))
+	srv.mu.Lock()
+	assert.Equal(t, []string{
+		"SELECT v FROM t ORDER BY v",
+		`SELECT * FROM TABLE(RESULT_SCAN('` + fakeQueryID + `'))`,
+		`SELECT * FROM TABLE(RESULT_SCAN('` + fakeQueryID + `'))`,
+	}, srv.queries)
+	srv.mu.Unlock()
+}
+
+func TestExecutePartitionsIncremental(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	ctx := context.Background()
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+
+	require.NoError(t, st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueEnabled))
+	val, err := st.(adbc.GetSetOptions).GetOption(adbc.OptionKeyIncremental)
+	require.NoError(t, err)
+	assert.Equal(t, adbc.OptionValueEnabled, val)
+	require.NoError(t, st.SetSqlQuery("SELECT v FROM t"))
+
+	// the call returns at once, leaving the query running
+	schema, partitions, rows, err := st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+	assert.EqualValues(t, -1, rows)
+
+	// and so do the calls while it is running
+	schema, partitions, _, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+	progress, err := st.(adbc.GetSetOptions).GetOptionDouble(adbc.OptionKeyProgress)
+	require.NoError(t, err)
+	assert.Zero(t, progress)
+
+	// incremental execution can't be toggled while the query is running
+	var adbcErr adbc.Error
+	err = st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueDisabled)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidState, adbcErr.Code)
+
+	// the next call checks the same query
+	close(srv.release)
+	schema, partitions, rows, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+	assert.Equal(t, "V", schema.Field(0).Name)
+	assert.EqualValues(t, 3, rows)
+	require.EqualValues(t, 2, partitions.NumPartitions)
+	srv.mu.Lock()
+	// the query was submitted once, and its result is read back once it
+	// is complete
+	assert.Equal(t, []string{
+		"SELECT v FROM t",
+		`SELECT * FROM TABLE(RESULT_SCAN('` + fakeQueryID + `'))`,
+	}, srv.queries)
+	srv.mu.Unlock()
+	assert.Equal(t, []string{"a", "b", "c"}, readPartitions(t, cnxn, partitions))
+	progress, err = st.(adbc.GetSetOptions).GetOptionDouble(adbc.OptionKeyProgress)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, progress)
+
+	// and then the query is complete
+	schema, partitions, rows, err = st.ExecutePartitions(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, schema)
+	assert.EqualValues(t, 3, rows)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+
+	require.NoError(t, st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueDisabled))
+}
+
+func TestExecutePartitionsIncrementalClose(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	defer close(srv.release)
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	require.NoError(t, st.SetOption(adbc.OptionKeyIncremental, adbc.OptionValueEnabled))
+	require.NoError(t, st.SetSqlQuery("SELECT v FROM t"))
+
+	_, partitions, _, err := st.ExecutePartitions(context.Background())
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, partitions.NumPartitions)
+
+	// closing the statement aborts the query nothing waits for anymore
+	require.NoError(t, st.Close())
+	srv.mu.Lock()
+	assert.Equal(t, []string{"SELECT v FROM t", "SELECT SYSTEM$CANCEL_QUERY(?)"}, srv.queries)
+	srv.mu.Unlock()
+}
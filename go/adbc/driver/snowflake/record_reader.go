@@ -32,6 +32,7 @@ import (
 	"time"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/compute"
@@ -521,7 +522,19 @@ type reader struct {
 	cancelFn context.CancelFunc
 }
 
-func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake.ArrowStreamLoader, bufferSize, prefetchConcurrency int, useHighPrecision bool, maxTimestampPrecision MaxTimestampPrecision) (array.RecordReader, error) {
+// getStream opens the stream of a result chunk, retrying the download
+// according to retry.
+func getStream(ctx context.Context, batch gosnowflake.ArrowStreamBatch, retry driverbase.RetryPolicy) (io.ReadCloser, error) {
+	return driverbase.RetryValue(ctx, retry, true, func(ctx context.Context) (io.ReadCloser, error) {
+		rdr, err := batch.GetStream(ctx)
+		if err != nil {
+			return nil, errToAdbcErr(adbc.StatusIO, err)
+		}
+		return rdr, nil
+	})
+}
+
+func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake.ArrowStreamLoader, bufferSize, prefetchConcurrency int, useHighPrecision bool, maxTimestampPrecision MaxTimestampPrecision, retry driverbase.RetryPolicy) (array.RecordReader, error) {
 	batches, err := ld.GetBatches()
 	if err != nil {
 		return nil, errToAdbcErr(adbc.StatusInternal, err)
@@ -556,12 +569,9 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 
 		results := []arrow.Record{rec}
 		for _, b := range batches {
-			rdr, err := b.GetStream(ctx)
+			rdr, err := getStream(ctx, b, retry)
 			if err != nil {
-				return nil, adbc.Error{
-					Msg:  err.Error(),
-					Code: adbc.StatusInternal,
-				}
+				return nil, err
 			}
 
 			// the "JSON" data returned isn't valid JSON. Instead it is a list of
@@ -626,6 +636,22 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 		return array.NewRecordReader(schema, results)
 	}
 
+	streams := make([]chunkStream, len(batches))
+	for i, b := range batches {
+		streams[i] = func(ctx context.Context) (io.ReadCloser, error) {
+			return getStream(ctx, b, retry)
+		}
+	}
+	return newChunkRecordReader(ctx, alloc, ld, streams, bufferSize, prefetchConcurrency, useHighPrecision, maxTimestampPrecision)
+}
+
+// chunkStream opens the Arrow IPC stream of a chunk of a result.
+type chunkStream func(context.Context) (io.ReadCloser, error)
+
+// newChunkRecordReader reads the chunks of a result in order, converting
+// their records according to the row types of ld. Up to
+// prefetchConcurrency chunks are downloaded at once.
+func newChunkRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake.ArrowStreamLoader, streams []chunkStream, bufferSize, prefetchConcurrency int, useHighPrecision bool, maxTimestampPrecision MaxTimestampPrecision) (_ array.RecordReader, err error) {
 	ch := make(chan arrow.Record, bufferSize)
 	group, ctx := errgroup.WithContext(compute.WithAllocator(ctx, alloc))
 	ctx, cancelFn := context.WithCancel(ctx)
@@ -638,7 +664,7 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 		}
 	}()
 
-	chs := make([]chan arrow.Record, len(batches))
+	chs := make([]chan arrow.Record, len(streams))
 	rdr := &reader{
 		refCount: 1,
 		chs:      chs,
@@ -646,7 +672,7 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 		cancelFn: cancelFn,
 	}
 
-	if len(batches) == 0 {
+	if len(streams) == 0 {
 		schema, err := rowTypesToArrowSchema(ctx, ld, useHighPrecision, maxTimestampPrecision)
 		if err != nil {
 			return nil, err
@@ -655,9 +681,9 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 		return rdr, nil
 	}
 
-	r, err := batches[0].GetStream(ctx)
+	r, err := streams[0](ctx)
 	if err != nil {
-		return nil, errToAdbcErr(adbc.StatusIO, err)
+		return nil, err
 	}
 
 	rr, err := ipc.NewReader(r, ipc.WithAllocator(alloc))
@@ -676,7 +702,7 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 		defer func() {
 			err = errors.Join(err, r.Close())
 		}()
-		if len(batches) > 1 {
+		if len(streams) > 1 {
 			defer close(ch)
 		}
 
@@ -693,18 +719,22 @@ func newRecordReader(ctx context.Context, alloc memory.Allocator, ld gosnowflake
 
 	chs[0] = ch
 
+	// the channels are created before Next or Release can read them
+	for i := 1; i < len(chs); i++ {
+		chs[i] = make(chan arrow.Record, bufferSize)
+	}
+
 	lastChannelIndex := len(chs) - 1
 	go func() {
-		for i, b := range batches[1:] {
-			batch, batchIdx := b, i+1
-			chs[batchIdx] = make(chan arrow.Record, bufferSize)
+		for i, s := range streams[1:] {
+			stream, batchIdx := s, i+1
 			group.Go(func() (err error) {
 				// close channels (except the last) so that Next can move on to the next channel properly
 				if batchIdx != lastChannelIndex {
 					defer close(chs[batchIdx])
 				}
 
-				rdr, err := batch.GetStream(ctx)
+				rdr, err := stream(ctx)
 				if err != nil {
 					return err
 				}
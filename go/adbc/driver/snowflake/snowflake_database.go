@@ -512,7 +512,9 @@ func (d *databaseImpl) Open(ctx context.Context) (adbcConnection adbc.Connection
 	ctx, span := internal.StartSpan(ctx, "databaseImpl.Open", d)
 	defer internal.EndSpan(span, err)
 
-	connector := gosnowflake.NewConnector(drv, *d.cfg)
+	cfg := *d.cfg
+	cfg.Transporter = newBindCountTransport(&cfg)
+	connector := gosnowflake.NewConnector(drv, cfg)
 
 	ctx = gosnowflake.WithArrowAllocator(
 		gosnowflake.WithArrowBatches(ctx), d.Alloc)
@@ -524,9 +526,14 @@ func (d *databaseImpl) Open(ctx context.Context) (adbcConnection adbc.Connection
 		return nil, err
 	}
 
+	transport := cfg.Transporter
+	if transport == nil {
+		transport = gosnowflake.SnowflakeTransport
+	}
+
 	conn := &connectionImpl{
 		cn: cn.(snowflakeConn),
-		db: d, ctor: connector,
+		db: d, ctor: connector, transport: transport,
 		// default enable high precision
 		// SetOption(OptionUseHighPrecision, adbc.OptionValueDisabled) to
 		// get Int64/Float64 instead
@@ -540,6 +547,7 @@ func (d *databaseImpl) Open(ctx context.Context) (adbcConnection adbc.Connection
 		WithCurrentNamespacer(conn).
 		WithTableTypeLister(conn).
 		WithDriverInfoPreparer(conn).
+		WithStatisticsEnumerator(conn).
 		Connection()
 
 	driverbase.SetOTelDriverInfoAttributes(d.DriverInfo, span)
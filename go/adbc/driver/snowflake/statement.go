@@ -20,6 +20,7 @@ package snowflake
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -48,6 +49,17 @@ const (
 	OptionStatementIngestCompressionLevel  = "adbc.snowflake.statement.ingest_compression_level" // TODO(GH-1473): Implement option
 )
 
+// incrementalState is the query run by ExecutePartitions when
+// adbc.OptionKeyIncremental is enabled.
+type incrementalState struct {
+	// queryID is the ID of the query submitted by the first call, it is
+	// empty until the query is started
+	queryID  string
+	schema   *arrow.Schema
+	rows     int64
+	complete bool
+}
+
 type statement struct {
 	driverbase.StatementImplBase
 	cnxn                  *connectionImpl
@@ -62,21 +74,95 @@ type statement struct {
 	ingestMode    string
 	ingestOptions *ingestOptions
 	queryTag      string
+	// paramSchema is the schema of the query's parameters, found by Prepare
+	paramSchema *arrow.Schema
 
-	bound      arrow.Record
-	streamBind array.RecordReader
+	// incrementalState is nil unless incremental execution is enabled
+	incrementalState *incrementalState
 }
 
 func (st *statement) Base() *driverbase.StatementImplBase {
 	return &st.StatementImplBase
 }
 
-// setQueryContext applies the query tag if present.
-func (st *statement) setQueryContext(ctx context.Context) context.Context {
+// setQueryContext makes the context cancellable via Cancel until the
+// returned function is called, and applies the query tag if present.
+func (st *statement) setQueryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := st.Base().WithCancel(ctx)
 	if st.queryTag != "" {
 		ctx = gosnowflake.WithQueryTag(ctx, st.queryTag)
 	}
-	return ctx
+	return ctx, cancel
+}
+
+// clearIncrementalQuery returns an error if an incremental query is in
+// progress, since toggling incremental execution would abandon it.
+func (st *statement) clearIncrementalQuery() error {
+	if st.incrementalState != nil && st.incrementalState.queryID != "" && !st.incrementalState.complete {
+		return adbc.Error{
+			Msg:  "[Snowflake] Cannot disable incremental execution while a query is in progress, finish execution first",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	return nil
+}
+
+// watchProgress returns a context that makes gosnowflake send the ID of
+// the query run with it, and polls the progress of that query in the
+// background once the ID arrives. stop must be called once the query
+// returned.
+func (st *statement) watchProgress(ctx context.Context) (queryCtx context.Context, stop func()) {
+	queryID := make(chan string, 1)
+	returned, started := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(started)
+		var id string
+		select {
+		case id = <-queryID:
+		case <-returned:
+			// the ID may have been sent just before the query returned
+			select {
+			case id = <-queryID:
+			default:
+			}
+		}
+		if id != "" {
+			st.trackProgress(ctx, id)
+		}
+	}()
+	return gosnowflake.WithQueryIDChan(ctx, queryID), func() {
+		close(returned)
+		<-started
+	}
+}
+
+// trackProgress polls the status of the query with the given ID in the
+// background to report its progress. Snowflake only reports the statistics
+// of a query once it completed, so until then the query is only reported
+// as running.
+func (st *statement) trackProgress(ctx context.Context, queryID string) {
+	monitor, ok := st.cnxn.cn.(gosnowflake.SnowflakeConnection)
+	if !ok {
+		return
+	}
+	// the query completes before the caller stops waiting for it, so
+	// polling outlives ctx to report its final statistics
+	st.Progress.Poll(context.WithoutCancel(ctx), driverbase.ProgressPollInterval, func(ctx context.Context) (adbc.QueryProgress, bool, error) {
+		status, err := monitor.GetQueryStatus(ctx, queryID)
+		progress := st.Progress.Progress()
+		var sfErr *gosnowflake.SnowflakeError
+		if errors.As(err, &sfErr) && sfErr.Number == gosnowflake.ErrQueryIsRunning {
+			return progress, false, nil
+		} else if err != nil {
+			return adbc.QueryProgress{}, true, err
+		}
+		progress.Progress = progress.MaxProgress
+		if progress.Rows < 0 {
+			progress.Rows = status.ProducedRows
+		}
+		progress.BytesProcessed = status.ScanBytes
+		return progress, true, nil
+	})
 }
 
 // Close releases any relevant resources associated with this statement
@@ -87,19 +173,12 @@ func (st *statement) Close() (err error) {
 	_, span := internal.StartSpan(context.Background(), "statement.Close", st)
 	defer internal.EndSpan(span, err)
 
-	if st.cnxn == nil {
-		err = adbc.Error{
-			Msg:  "statement already closed",
-			Code: adbc.StatusInvalidState}
+	if err = st.State.Close(); err != nil {
 		return err
 	}
-
-	if st.bound != nil {
-		st.bound.Release()
-		st.bound = nil
-	} else if st.streamBind != nil {
-		st.streamBind.Release()
-		st.streamBind = nil
+	if state := st.incrementalState; state != nil && state.queryID != "" && !state.complete {
+		// nothing waits for the query anymore
+		_ = st.cnxn.abortQuery(state.queryID)
 	}
 	st.cnxn = nil
 	return err
@@ -109,6 +188,11 @@ func (st *statement) GetOption(key string) (string, error) {
 	switch key {
 	case OptionStatementQueryTag:
 		return st.queryTag, nil
+	case adbc.OptionKeyIncremental:
+		if st.incrementalState != nil {
+			return adbc.OptionValueEnabled, nil
+		}
+		return adbc.OptionValueDisabled, nil
 	default:
 		return st.Base().GetOption(key)
 	}
@@ -131,6 +215,9 @@ func (st *statement) GetOptionInt(key string) (int64, error) {
 	}
 }
 func (st *statement) GetOptionDouble(key string) (float64, error) {
+	if value, ok := st.Progress.GetOptionDouble(key); ok {
+		return value, nil
+	}
 	return 0, adbc.Error{
 		Msg:  fmt.Sprintf("[Snowflake] Unknown statement option '%s'", key),
 		Code: adbc.StatusNotFound,
@@ -141,6 +228,9 @@ func (st *statement) GetOptionDouble(key string) (float64, error) {
 func (st *statement) SetOption(key string, val string) error {
 	switch key {
 	case adbc.OptionKeyIngestTargetTable:
+		if err := st.State.SetQuery(); err != nil {
+			return err
+		}
 		st.query = ""
 		st.targetTable = val
 	case adbc.OptionKeyIngestMode:
@@ -216,6 +306,21 @@ func (st *statement) SetOption(key string, val string) error {
 	case OptionStatementQueryTag:
 		st.queryTag = val
 		return nil
+	case adbc.OptionKeyIncremental:
+		if err := st.clearIncrementalQuery(); err != nil {
+			return err
+		}
+		switch val {
+		case adbc.OptionValueEnabled:
+			st.incrementalState = &incrementalState{}
+		case adbc.OptionValueDisabled:
+			st.incrementalState = nil
+		default:
+			return adbc.Error{
+				Msg:  fmt.Sprintf("[Snowflake] invalid statement option %s=%s", key, val),
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
 	case OptionUseHighPrecision:
 		switch val {
 		case adbc.OptionValueEnabled:
@@ -327,8 +432,12 @@ func (st *statement) SetOptionDouble(key string, value float64) error {
 // For queries expected to be executed repeatedly, Prepare should be
 // called before execution.
 func (st *statement) SetSqlQuery(query string) error {
+	if err := st.State.SetQuery(); err != nil {
+		return err
+	}
 	st.query = query
 	st.targetTable = ""
+	st.paramSchema = nil
 	return nil
 }
 
@@ -381,7 +490,7 @@ func toSnowflakeType(dt arrow.DataType) string {
 	return ""
 }
 
-func (st *statement) initIngest(ctx context.Context) error {
+func (st *statement) initIngest(ctx context.Context, schema *arrow.Schema) error {
 	var (
 		createBldr strings.Builder
 	)
@@ -393,13 +502,6 @@ func (st *statement) initIngest(ctx context.Context) error {
 	createBldr.WriteString(quoteTblName(st.targetTable))
 	createBldr.WriteString(" (")
 
-	var schema *arrow.Schema
-	if st.bound != nil {
-		schema = st.bound.Schema()
-	} else {
-		schema = st.streamBind.Schema()
-	}
-
 	for i, f := range schema.Fields() {
 		if i != 0 {
 			createBldr.WriteString(", ")
@@ -451,23 +553,25 @@ func (st *statement) initIngest(ctx context.Context) error {
 }
 
 func (st *statement) executeIngest(ctx context.Context) (int64, error) {
-	if st.streamBind == nil && st.bound == nil {
-		return -1, adbc.Error{
-			Msg:  "must call Bind before bulk ingestion",
-			Code: adbc.StatusInvalidState,
+	bound, stream := st.State.TakeBound()
+	if bound != nil {
+		if err := st.initIngest(ctx, bound.Schema()); err != nil {
+			bound.Release()
+			return -1, err
 		}
+		return st.ingestRecord(ctx, bound)
 	}
-
-	err := st.initIngest(ctx)
-	if err != nil {
-		return -1, err
+	if stream != nil {
+		if err := st.initIngest(ctx, stream.Schema()); err != nil {
+			stream.Release()
+			return -1, err
+		}
+		return st.ingestStream(ctx, stream)
 	}
-
-	if st.bound != nil {
-		return st.ingestRecord(ctx)
+	return -1, adbc.Error{
+		Msg:  "must call Bind before bulk ingestion",
+		Code: adbc.StatusInvalidState,
 	}
-
-	return st.ingestStream(ctx)
 }
 
 // ExecuteQuery executes the current query or prepared statement
@@ -485,25 +589,32 @@ func (st *statement) ExecuteQuery(ctx context.Context) (reader array.RecordReade
 		internal.EndSpan(span, err)
 	}()
 
-	ctx = st.setQueryContext(ctx)
-
-	if st.targetTable != "" {
-		nRows, err = st.executeIngest(ctx)
+	done, err := st.State.BeginExecute("ExecuteQuery")
+	if err != nil {
 		return
 	}
+	defer done()
 
-	if st.query == "" {
-		err = adbc.Error{
-			Msg:  "cannot execute without a query",
-			Code: adbc.StatusInvalidState,
+	// the context is used by the reader, so it lives until the reader is
+	// released
+	ctx, cancel := st.setQueryContext(ctx)
+	defer func() {
+		if err == nil {
+			reader = driverbase.CancelOnRelease(st.State.TrackReader(reader), cancel)
+		} else {
+			cancel()
 		}
+	}()
+
+	if st.targetTable != "" {
+		nRows, err = st.executeIngest(ctx)
 		return
 	}
 
 	// for a bound stream reader we'd need to implement something to
 	// concatenate RecordReaders which doesn't exist yet. let's put
 	// that off for now.
-	if st.streamBind != nil || st.bound != nil {
+	if bound, stream := st.State.TakeBound(); bound != nil || stream != nil {
 		bind := snowflakeBindReader{
 			doQuery: func(params []driver.NamedValue) (array.RecordReader, error) {
 				var loader gosnowflake.ArrowStreamLoader
@@ -513,14 +624,12 @@ func (st *statement) ExecuteQuery(ctx context.Context) (reader array.RecordReade
 					return nil, err
 				}
 
-				reader, err = newRecordReader(ctx, st.alloc, loader, st.queueSize, st.prefetchConcurrency, st.useHighPrecision, st.maxTimestampPrecision)
+				reader, err = newRecordReader(ctx, st.alloc, loader, st.queueSize, st.prefetchConcurrency, st.useHighPrecision, st.maxTimestampPrecision, st.cnxn.RetryPolicy)
 				return reader, err
 			},
-			currentBatch: st.bound,
-			stream:       st.streamBind,
+			currentBatch: bound,
+			stream:       stream,
 		}
-		st.bound = nil
-		st.streamBind = nil
 
 		rdr := concatReader{}
 		err = rdr.Init(&bind)
@@ -531,14 +640,17 @@ func (st *statement) ExecuteQuery(ctx context.Context) (reader array.RecordReade
 		return
 	}
 
-	var loader gosnowflake.ArrowStreamLoader
-	loader, err = st.cnxn.cn.QueryArrowStream(ctx, st.query)
+	st.Progress.Start()
+	queryCtx, stop := st.watchProgress(ctx)
+	loader, err := st.cnxn.cn.QueryArrowStream(queryCtx, st.query)
+	stop()
 	if err != nil {
 		err = errToAdbcErr(adbc.StatusInternal, err)
 		return
 	}
+	st.Progress.Finish(loader.TotalRows(), -1)
 
-	reader, err = newRecordReader(ctx, st.alloc, loader, st.queueSize, st.prefetchConcurrency, st.useHighPrecision, st.maxTimestampPrecision)
+	reader, err = newRecordReader(ctx, st.alloc, loader, st.queueSize, st.prefetchConcurrency, st.useHighPrecision, st.maxTimestampPrecision, st.cnxn.RetryPolicy)
 	nRows = loader.TotalRows()
 	return
 }
@@ -552,30 +664,26 @@ func (st *statement) ExecuteUpdate(ctx context.Context) (numRows int64, err erro
 		internal.EndSpan(span, err)
 	}()
 
-	ctx = st.setQueryContext(ctx)
+	done, err := st.State.BeginExecute("ExecuteUpdate")
+	if err != nil {
+		return -1, err
+	}
+	defer done()
+
+	ctx, cancel := st.setQueryContext(ctx)
+	defer cancel()
 
 	if st.targetTable != "" {
 		numRows, err = st.executeIngest(ctx)
 		return numRows, err
 	}
 
-	if st.query == "" {
-		numRows = -1
-		err = adbc.Error{
-			Msg:  "cannot execute without a query",
-			Code: adbc.StatusInvalidState,
-		}
-		return numRows, err
-	}
-
-	if st.streamBind != nil || st.bound != nil {
+	if bound, stream := st.State.TakeBound(); bound != nil || stream != nil {
 		numRows = 0
 		bind := snowflakeBindReader{
-			currentBatch: st.bound,
-			stream:       st.streamBind,
+			currentBatch: bound,
+			stream:       stream,
 		}
-		st.bound = nil
-		st.streamBind = nil
 
 		defer bind.Release()
 		for {
@@ -603,20 +711,21 @@ func (st *statement) ExecuteUpdate(ctx context.Context) (numRows int64, err erro
 		return numRows, err
 	}
 
-	r, err := st.cnxn.cn.ExecContext(ctx, st.query, nil)
+	st.Progress.Start()
+	queryCtx, stop := st.watchProgress(ctx)
+	r, err := st.cnxn.cn.ExecContext(queryCtx, st.query, nil)
+	stop()
 	if err != nil {
-		numRows = -1
-		err = errToAdbcErr(adbc.StatusIO, err)
-		return numRows, err
+		return -1, errToAdbcErr(adbc.StatusIO, err)
 	}
 
 	numRows, err = r.RowsAffected()
 	if err != nil {
 		numRows = -1
-		err = nil
 	}
+	st.Progress.Finish(numRows, -1)
 
-	return numRows, err
+	return numRows, nil
 }
 
 // ExecuteSchema gets the schema of the result set of a query without executing it.
@@ -624,25 +733,24 @@ func (st *statement) ExecuteSchema(ctx context.Context) (schema *arrow.Schema, e
 	ctx, span := internal.StartSpan(ctx, "statement.ExecuteSchema", st)
 	defer internal.EndSpan(span, err)
 
-	ctx = st.setQueryContext(ctx)
-
-	if st.targetTable != "" {
-		err = adbc.Error{
-			Msg:  "cannot execute schema for ingestion",
-			Code: adbc.StatusInvalidState,
-		}
+	done, err := st.State.BeginExecute("ExecuteSchema")
+	if err != nil {
 		return nil, err
 	}
+	defer done()
+
+	ctx, cancel := st.setQueryContext(ctx)
+	defer cancel()
 
-	if st.query == "" {
+	if st.targetTable != "" {
 		err = adbc.Error{
-			Msg:  "cannot execute without a query",
+			Msg:  "cannot execute schema for ingestion",
 			Code: adbc.StatusInvalidState,
 		}
 		return nil, err
 	}
 
-	if st.streamBind != nil || st.bound != nil {
+	if st.State.HasBound() {
 		err = adbc.Error{
 			Msg:  "executing schema with bound params not yet implemented",
 			Code: adbc.StatusNotImplemented,
@@ -663,14 +771,27 @@ func (st *statement) ExecuteSchema(ctx context.Context) (schema *arrow.Schema, e
 
 // Prepare turns this statement into a prepared statement to be executed
 // multiple times. This invalidates any prior result sets.
-func (st *statement) Prepare(_ context.Context) error {
-	if st.query == "" {
-		return adbc.Error{
-			Code: adbc.StatusInvalidState,
-			Msg:  "cannot prepare statement with no query",
-		}
+func (st *statement) Prepare(ctx context.Context) error {
+	if err := st.State.Prepare(); err != nil {
+		return err
+	}
+	st.paramSchema = nil
+	if st.targetTable != "" {
+		return nil
+	}
+
+	// snowflake doesn't provide a "Prepare" api, but a describe-only query
+	// reports the number of binds for GetParameterSchema. Their types are
+	// not reported.
+	ctx, cancel := st.setQueryContext(ctx)
+	defer cancel()
+	ctx, binds := withBindCount(ctx)
+	if _, err := st.cnxn.cn.QueryArrowStream(gosnowflake.WithDescribeOnly(ctx), st.query); err != nil {
+		return errToAdbcErr(adbc.StatusInvalidArgument, err)
+	}
+	if *binds >= 0 {
+		st.paramSchema = buildParameterSchema(*binds)
 	}
-	// snowflake doesn't provide a "Prepare" api, this is a no-op
 	return nil
 }
 
@@ -697,19 +818,7 @@ func (st *statement) SetSubstraitPlan(plan []byte) error {
 // but it may not do this until the statement is closed or another
 // record is bound.
 func (st *statement) Bind(_ context.Context, values arrow.Record) error {
-	if st.streamBind != nil {
-		st.streamBind.Release()
-		st.streamBind = nil
-	} else if st.bound != nil {
-		st.bound.Release()
-		st.bound = nil
-	}
-
-	st.bound = values
-	if st.bound != nil {
-		st.bound.Retain()
-	}
-	return nil
+	return st.State.Bind(values)
 }
 
 // BindStream uses a record batch stream to bind parameters for this
@@ -718,19 +827,7 @@ func (st *statement) Bind(_ context.Context, values arrow.Record) error {
 // The driver will call Release on the record reader, but may not do this
 // until Close is called.
 func (st *statement) BindStream(_ context.Context, stream array.RecordReader) error {
-	if st.streamBind != nil {
-		st.streamBind.Release()
-		st.streamBind = nil
-	} else if st.bound != nil {
-		st.bound.Release()
-		st.bound = nil
-	}
-
-	st.streamBind = stream
-	if st.streamBind != nil {
-		st.streamBind.Retain()
-	}
-	return nil
+	return st.State.BindStream(stream)
 }
 
 // GetParameterSchema returns an Arrow schema representation of
@@ -750,35 +847,18 @@ func (st *statement) BindStream(_ context.Context, stream array.RecordReader) er
 //
 // This should return an error with StatusNotImplemented if the schema
 // cannot be determined.
+//
+// Snowflake does not report the types of bind parameters, so every field
+// is NA.
 func (st *statement) GetParameterSchema() (*arrow.Schema, error) {
-	// snowflake's API does not provide any way to determine the schema
-	return nil, adbc.Error{
-		Code: adbc.StatusNotImplemented,
+	if err := st.State.RequirePrepared("GetParameterSchema"); err != nil {
+		return nil, err
 	}
-}
-
-// ExecutePartitions executes the current statement and gets the results
-// as a partitioned result set.
-//
-// It returns the Schema of the result set, the collection of partition
-// descriptors and the number of rows affected, if known. If unknown,
-// the number of rows affected will be -1.
-//
-// If the driver does not support partitioned results, this will return
-// an error with a StatusNotImplemented code.
-func (st *statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
-	if st.query == "" {
-		return nil, adbc.Partitions{}, -1, adbc.Error{
-			Msg:  "cannot execute without a query",
-			Code: adbc.StatusInvalidState,
+	if st.paramSchema == nil {
+		return nil, adbc.Error{
+			Msg:  "[Snowflake] the parameters of the query could not be determined",
+			Code: adbc.StatusNotImplemented,
 		}
 	}
-
-	// snowflake partitioned results are not currently portable enough to
-	// satisfy the requirements of this function. At least not what is
-	// returned from the snowflake driver.
-	return nil, adbc.Partitions{}, -1, adbc.Error{
-		Msg:  "ExecutePartitions not implemented for Snowflake",
-		Code: adbc.StatusNotImplemented,
-	}
+	return st.paramSchema, nil
 }
@@ -0,0 +1,182 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+)
+
+const (
+	// StatisticTableBytesKey is the key of the number of bytes scanned
+	// when reading a whole table, from INFORMATION_SCHEMA.TABLES.
+	StatisticTableBytesKey = 1024
+	// StatisticTableBytesName is the name of StatisticTableBytesKey.
+	StatisticTableBytesName = "adbc.snowflake.statistic.table_bytes"
+)
+
+// EnumerateStatistics implements driverbase.StatisticsEnumerator.
+//
+// Row counts and sizes of tables come from the INFORMATION_SCHEMA.TABLES
+// view of each database. Snowflake maintains them as table metadata, so
+// they are exact and cheap to get. Column statistics are not available.
+func (c *connectionImpl) EnumerateStatistics(ctx context.Context, catalogFilter, dbSchemaFilter, tableFilter *string, approximate bool) (entries []driverbase.StatisticsEntry, err error) {
+	ctx, span := internal.StartSpan(ctx, "connectionImpl.EnumerateStatistics", c)
+	defer internal.EndSpan(span, err)
+
+	databases, err := c.getDatabases(ctx, catalogFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = make([]driverbase.StatisticsEntry, 0)
+	for _, db := range databases {
+		if entries, err = c.appendTableStatistics(ctx, entries, db, dbSchemaFilter, tableFilter); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// StatisticNames implements driverbase.StatisticsEnumerator.
+func (c *connectionImpl) StatisticNames() []driverbase.StatisticName {
+	return []driverbase.StatisticName{
+		{Name: StatisticTableBytesName, Key: StatisticTableBytesKey},
+	}
+}
+
+// getDatabases returns the names of the databases matching a pattern.
+func (c *connectionImpl) getDatabases(ctx context.Context, catalog *string) (databases []string, err error) {
+	pattern, err := internal.PatternToRegexp(catalog)
+	if err != nil {
+		return nil, adbc.Error{
+			Msg:  err.Error(),
+			Code: adbc.StatusInvalidArgument,
+		}
+	}
+	if pattern == nil {
+		pattern = internal.AcceptAll
+	}
+
+	rows, err := c.cn.QueryContext(ctx, "SHOW TERSE DATABASES", nil)
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusIO, err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	nameIdx := slices.Index(rows.Columns(), "name")
+	if nameIdx < 0 {
+		return nil, adbc.Error{
+			Msg:  fmt.Sprintf("[Snowflake] SHOW TERSE DATABASES returned unexpected columns: %s", rows.Columns()),
+			Code: adbc.StatusInternal,
+		}
+	}
+
+	dest := make([]driver.Value, len(rows.Columns()))
+	for {
+		if err = rows.Next(dest); err != nil {
+			if errors.Is(err, io.EOF) {
+				return databases, nil
+			}
+			return nil, errToAdbcErr(adbc.StatusIO, err)
+		}
+		if name, ok := dest[nameIdx].(string); ok && pattern.MatchString(name) {
+			databases = append(databases, name)
+		}
+	}
+}
+
+// appendTableStatistics appends the statistics of the tables of a database.
+func (c *connectionImpl) appendTableStatistics(ctx context.Context, entries []driverbase.StatisticsEntry, db string, dbSchema, tableName *string) (_ []driverbase.StatisticsEntry, err error) {
+	query := "SELECT TABLE_SCHEMA, TABLE_NAME, ROW_COUNT, BYTES FROM " + quoteTblName(db) +
+		".INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA ILIKE ? AND TABLE_NAME ILIKE ?" +
+		" ORDER BY TABLE_SCHEMA, TABLE_NAME"
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: driverbase.PatternToNamedArg("DB_SCHEMA", dbSchema).Value},
+		{Ordinal: 2, Value: driverbase.PatternToNamedArg("TABLE", tableName).Value},
+	}
+
+	rows, err := c.cn.QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusIO, err)
+	}
+	defer func() {
+		err = errors.Join(err, rows.Close())
+	}()
+
+	dest := make([]driver.Value, 4)
+	for {
+		if err = rows.Next(dest); err != nil {
+			if errors.Is(err, io.EOF) {
+				return entries, nil
+			}
+			return nil, errToAdbcErr(adbc.StatusIO, err)
+		}
+
+		schemaName, _ := dest[0].(string)
+		table, _ := dest[1].(string)
+		for _, stat := range []struct {
+			name  string
+			value driver.Value
+		}{{adbc.StatisticRowCountName, dest[2]}, {StatisticTableBytesName, dest[3]}} {
+			// external tables and the like don't have these
+			value, ok, err := statisticInt64(stat.value)
+			if err != nil {
+				return nil, errToAdbcErr(adbc.StatusInvalidData, err)
+			} else if !ok {
+				continue
+			}
+			entries = append(entries, driverbase.StatisticsEntry{
+				CatalogName:  db,
+				DbSchemaName: schemaName,
+				TableName:    table,
+				Name:         stat.name,
+				Value:        value,
+			})
+		}
+	}
+}
+
+// statisticInt64 converts a NUMBER value returned by gosnowflake, which
+// may be NULL.
+func statisticInt64(v driver.Value) (int64, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return v, true, nil
+	case float64:
+		return int64(v), true, nil
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		return n, err == nil, err
+	default:
+		return 0, false, fmt.Errorf("unexpected statistic value of type %T", v)
+	}
+}
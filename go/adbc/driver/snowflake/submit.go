@@ -0,0 +1,165 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/internal/driverbase"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// abortQueryTimeout bounds how long aborting a query may take, including
+// opening a session to do so.
+const abortQueryTimeout = 30 * time.Second
+
+// SubmitQuery starts the query without waiting for it, and returns its
+// query ID as the handle. Snowflake keeps running the query after the
+// connection is closed, and keeps its result for 24 hours, so the result
+// can be read by AttachQuery from any session of the same user.
+func (st *statement) SubmitQuery(ctx context.Context) ([]byte, error) {
+	done, err := st.State.BeginExecute("SubmitQuery")
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	if err := st.checkPlainQuery("SubmitQuery"); err != nil {
+		return nil, err
+	}
+
+	// the context is cancelled once the query is accepted, which stops
+	// gosnowflake from waiting for the result but not the query
+	ctx, stop := st.setQueryContext(ctx)
+	defer stop()
+
+	id, res, err := st.submit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		go func() { _, _ = res.RowsAffected() }()
+	}
+	return []byte(id), nil
+}
+
+// submit starts the query without waiting for it, and returns its query
+// ID. Unless the query completed at once, the result is also returned:
+// it is complete once RowsAffected returns, and must be read since
+// gosnowflake waits for the query in the background until it is.
+// Cancelling ctx stops that wait but not the query, see abortQuery.
+func (st *statement) submit(ctx context.Context) (string, driver.Result, error) {
+	queryID := make(chan string, 1)
+	res, err := st.cnxn.cn.ExecContext(gosnowflake.WithAsyncMode(gosnowflake.WithQueryIDChan(ctx, queryID)), st.query, nil)
+	if err != nil {
+		return "", nil, errToAdbcErr(adbc.StatusIO, err)
+	}
+	// the result is a nil pointer if the query completed at once
+	if res != nil && reflect.ValueOf(res).IsNil() {
+		res = nil
+	}
+
+	id, err := receiveQueryID(queryID)
+	if err != nil {
+		if res != nil {
+			go func() { _, _ = res.RowsAffected() }()
+		}
+		return "", nil, err
+	}
+	return id, res, nil
+}
+
+// waitForQuery polls the status of the query with the given ID until it
+// is complete, and returns its error if it failed. It returns the error of
+// ctx if ctx is done first, leaving the query running.
+func (c *connectionImpl) waitForQuery(ctx context.Context, queryID string) error {
+	return driverbase.PollIncremental(ctx, func(ctx context.Context) (bool, error) {
+		return c.queryDone(ctx, queryID)
+	})
+}
+
+// queryDone checks the status of the query with the given ID once, and
+// reports whether it is complete. The error of the query is returned if
+// it failed.
+func (c *connectionImpl) queryDone(ctx context.Context, queryID string) (bool, error) {
+	monitor, ok := c.cn.(gosnowflake.SnowflakeConnection)
+	if !ok {
+		return true, nil
+	}
+	_, err := monitor.GetQueryStatus(ctx, queryID)
+	var sfErr *gosnowflake.SnowflakeError
+	if errors.As(err, &sfErr) && sfErr.Number == gosnowflake.ErrQueryIsRunning {
+		return false, nil
+	}
+	return true, err
+}
+
+// abortQuery cancels the query with the given ID server-side. The
+// connection may still be in use, so the query is cancelled from a
+// separate session.
+func (c *connectionImpl) abortQuery(queryID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), abortQueryTimeout)
+	defer cancel()
+
+	cn, err := c.ctor.Connect(ctx)
+	if err != nil {
+		return errToAdbcErr(adbc.StatusIO, err)
+	}
+	defer cn.Close()
+
+	_, err = cn.(driver.ExecerContext).ExecContext(ctx, "SELECT SYSTEM$CANCEL_QUERY(?)", []driver.NamedValue{{Ordinal: 1, Value: queryID}})
+	return errToAdbcErr(adbc.StatusIO, err)
+}
+
+// resultScanQuery returns a query that reads the result of the query with
+// the given ID.
+func resultScanQuery(queryID string) string {
+	return fmt.Sprintf("SELECT * FROM TABLE(RESULT_SCAN('%s'))", queryID)
+}
+
+// AttachQuery waits for the query with the query ID given as handle, as
+// returned by SubmitQuery, and reads its result.
+func (c *connectionImpl) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	queryID := string(handle)
+	// the query ID is interpolated into the query, so it must be checked
+	if !queryIDPattern.MatchString(queryID) {
+		return nil, adbc.Error{
+			Msg:  fmt.Sprintf("[Snowflake] invalid query handle: bad query ID '%s'", queryID),
+			Code: adbc.StatusInvalidArgument,
+		}
+	}
+
+	if err := c.waitForQuery(ctx, queryID); err != nil {
+		return nil, errToAdbcErr(adbc.StatusIO, err)
+	}
+
+	loader, err := c.cn.QueryArrowStream(ctx, resultScanQuery(queryID))
+	if err != nil {
+		return nil, errToAdbcErr(adbc.StatusInternal, err)
+	}
+
+	return newRecordReader(ctx, c.Alloc, loader, defaultStatementQueueSize,
+		defaultPrefetchConcurrency, c.useHighPrecision, c.maxTimestampPrecision, c.RetryPolicy)
+}
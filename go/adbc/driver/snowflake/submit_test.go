@@ -0,0 +1,159 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/validation"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitAndAttachQuery(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	ctx := context.Background()
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+	require.NoError(t, st.SetSqlQuery("SELECT v FROM t"))
+
+	// the query is still running when it is submitted
+	handle, err := st.(adbc.StatementSubmitQuery).SubmitQuery(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, fakeQueryID, string(handle))
+
+	attach := cnxn.(adbc.ConnectionAttachQuery)
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = attach.AttachQuery(short, handle)
+	cancel()
+	var adbcErr adbc.Error
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusTimeout, adbcErr.Code)
+
+	close(srv.release)
+	rdr, err := attach.AttachQuery(ctx, handle)
+	require.NoError(t, err)
+	defer rdr.Release()
+	require.True(t, rdr.Next())
+	assert.Equal(t, "V", rdr.Schema().Field(0).Name)
+	col := rdr.Record().Column(0).(*array.String)
+	assert.Equal(t, []string{"a", "b"}, []string{col.Value(0), col.Value(1)})
+	require.True(t, rdr.Next())
+	assert.Equal(t, "c", rdr.Record().Column(0).(*array.String).Value(0))
+	assert.False(t, rdr.Next())
+	require.NoError(t, rdr.Err())
+
+	srv.mu.Lock()
+	assert.Equal(t, []string{"SELECT v FROM t", "SELECT * FROM TABLE(RESULT_SCAN('" + fakeQueryID + "'))"}, srv.queries)
+	srv.mu.Unlock()
+
+	_, err = attach.AttachQuery(ctx, []byte("'); DROP TABLE t; --"))
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+}
+
+func TestExecuteProgress(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+	require.NoError(t, st.SetSqlQuery("SELECT v FROM t"))
+
+	type result struct {
+		rdr array.RecordReader
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		rdr, _, err := st.ExecuteQuery(context.Background())
+		done <- result{rdr, err}
+	}()
+
+	// the query is polled while it runs
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return srv.statusPolls > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	progress, err := st.(adbc.GetSetOptions).GetOptionDouble(adbc.OptionKeyProgress)
+	require.NoError(t, err)
+	assert.Zero(t, progress)
+
+	close(srv.release)
+	res := <-done
+	require.NoError(t, res.err)
+	defer res.rdr.Release()
+	progress, err = st.(adbc.GetSetOptions).GetOptionDouble(adbc.OptionKeyProgress)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, progress)
+
+	require.True(t, res.rdr.Next())
+	assert.EqualValues(t, 2, res.rdr.Record().NumRows())
+	srv.mu.Lock()
+	assert.Equal(t, []string{"SELECT v FROM t"}, srv.queries)
+	srv.mu.Unlock()
+}
+
+func TestCancelAbortsQuery(t *testing.T) {
+	srv := &fakeSnowflakeServer{release: make(chan struct{})}
+	defer close(srv.release)
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	cnxn := openFakeConnection(t, mem, srv)
+
+	st, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer validation.CheckedClose(t, st)
+	require.NoError(t, st.SetSqlQuery("UPDATE t SET v = 1"))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := st.ExecuteUpdate(context.Background())
+		done <- err
+	}()
+
+	require.Eventually(t, func() bool {
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		return srv.statusPolls > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, st.(adbc.StatementCancel).Cancel())
+
+	var adbcErr adbc.Error
+	require.ErrorAs(t, <-done, &adbcErr)
+	assert.Equal(t, adbc.StatusCancelled, adbcErr.Code)
+
+	srv.mu.Lock()
+	assert.Equal(t, []string{"UPDATE t SET v = 1"}, srv.queries)
+	assert.Equal(t, 1, srv.aborts)
+	srv.mu.Unlock()
+}
@@ -0,0 +1,46 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseURI(t *testing.T) {
+	opts, err := profile.ParseURI("snowflake://me:pw@myorg-acct/db/schema?warehouse=wh&role=r&" +
+		"adbc.snowflake.sql.client_option.tracing=debug")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		OptionAccount:          "myorg-acct",
+		OptionDatabase:         "db",
+		OptionSchema:           "schema",
+		OptionWarehouse:        "wh",
+		OptionRole:             "r",
+		OptionLogTracing:       "debug",
+		adbc.OptionKeyUsername: "me",
+		adbc.OptionKeyPassword: "pw",
+	}, opts)
+
+	_, err = profile.ParseURI("snowflake://acct/db/schema/table")
+	assert.ErrorContains(t, err, "URI path must be /database or /database/schema")
+}
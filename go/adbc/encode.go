@@ -0,0 +1,682 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package adbc
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+const defaultEncodeBatchSize = 8192
+
+type encodeConfig struct {
+	batchSize int
+	mem       memory.Allocator
+}
+
+// EncodeOption configures RecordsFromStructs and RecordsFromChan.
+type EncodeOption func(*encodeConfig)
+
+// WithEncodeBatchSize sets the maximum number of rows in each record
+// batch. The default is 8192.
+func WithEncodeBatchSize(rows int) EncodeOption {
+	return func(cfg *encodeConfig) {
+		if rows > 0 {
+			cfg.batchSize = rows
+		}
+	}
+}
+
+// WithEncodeAllocator sets the allocator used for the record batches.
+// The default is memory.DefaultAllocator.
+func WithEncodeAllocator(mem memory.Allocator) EncodeOption {
+	return func(cfg *encodeConfig) {
+		cfg.mem = mem
+	}
+}
+
+// SchemaFromStruct infers the Arrow schema of the record batches
+// produced by RecordsFromStructs from the struct type T, see
+// StructTagKey.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+func SchemaFromStruct[T any]() (*arrow.Schema, error) {
+	enc, err := newRowEncoder(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	return enc.schema, nil
+}
+
+// RecordsFromStructs encodes a slice of structs, or of pointers to
+// structs, into record batches, for use with IngestStream or
+// Statement.BindStream. Each field of T becomes a column, with the
+// schema described by SchemaFromStruct.
+//
+//	n, err := adbc.IngestStream(ctx, cnxn, adbc.RecordsFromStructs(items),
+//		"items", adbc.OptionValueIngestModeAppend, adbc.IngestStreamOptions{})
+//
+// Errors, including a T that can't be encoded, are reported by the
+// Err method of the reader.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+func RecordsFromStructs[T any](items []T, opts ...EncodeOption) array.RecordReader {
+	next := 0
+	return newStructRecordReader(reflect.TypeFor[T](), func() (reflect.Value, bool) {
+		if next >= len(items) {
+			return reflect.Value{}, false
+		}
+		next++
+		return reflect.ValueOf(&items[next-1]).Elem(), true
+	}, opts)
+}
+
+// RecordsFromChan is like RecordsFromStructs, but reads the structs from
+// a channel until it is closed. A record batch is emitted whenever it is
+// full or the channel is closed.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+func RecordsFromChan[T any](items <-chan T, opts ...EncodeOption) array.RecordReader {
+	return newStructRecordReader(reflect.TypeFor[T](), func() (reflect.Value, bool) {
+		item, ok := <-items
+		return reflect.ValueOf(&item).Elem(), ok
+	}, opts)
+}
+
+// encodeFunc appends v to b.
+type encodeFunc func(b array.Builder, v reflect.Value) error
+
+type rowEncoder struct {
+	ptr      bool
+	schema   *arrow.Schema
+	index    [][]int
+	encoders []encodeFunc
+}
+
+func newRowEncoder(t reflect.Type) (*rowEncoder, error) {
+	enc := &rowEncoder{}
+	row := t
+	if row.Kind() == reflect.Pointer {
+		enc.ptr = true
+		row = row.Elem()
+	}
+	if !isRowStruct(row) {
+		return nil, Error{
+			Msg:  fmt.Sprintf("[adbc] RecordsFromStructs: %s is not a struct", t),
+			Code: StatusInvalidArgument,
+		}
+	}
+
+	fields, encoders, err := newFieldEncoders(row)
+	if err != nil {
+		return nil, Error{
+			Msg:  fmt.Sprintf("[adbc] RecordsFromStructs: %s", err),
+			Code: StatusInvalidArgument,
+		}
+	}
+	enc.schema = arrow.NewSchema(fields, nil)
+	enc.encoders = encoders
+	for _, f := range structFields(row) {
+		enc.index = append(enc.index, f.index)
+	}
+	return enc, nil
+}
+
+func newFieldEncoders(t reflect.Type) ([]arrow.Field, []encodeFunc, error) {
+	var (
+		fields   []arrow.Field
+		encoders []encodeFunc
+	)
+	for _, f := range structFields(t) {
+		dt, nullable, enc, err := newEncoder(f.typ, f.options)
+		if err != nil {
+			return nil, nil, fmt.Errorf("field %q: %w", f.name, err)
+		}
+		if _, ok := tagOption(f.options, "nullable"); ok {
+			nullable = true
+		}
+		fields = append(fields, arrow.Field{Name: f.name, Type: dt, Nullable: nullable})
+		encoders = append(encoders, enc)
+	}
+	return fields, encoders, nil
+}
+
+// tagOption finds an option of a struct tag, returning its arguments,
+// if any.
+func tagOption(opts []string, name string) ([]string, bool) {
+	for _, opt := range opts {
+		if opt == name {
+			return nil, true
+		}
+		if args, ok := strings.CutPrefix(opt, name+"("); ok && strings.HasSuffix(args, ")") {
+			return splitTagOptions(strings.TrimSuffix(args, ")")), true
+		}
+	}
+	return nil, false
+}
+
+func checkTagOptions(opts []string) error {
+	for _, opt := range opts {
+		name, _, _ := strings.Cut(opt, "(")
+		switch name {
+		case "nullable", "decimal", "timestamp", "date", "duration":
+		default:
+			return fmt.Errorf("unknown option %q", opt)
+		}
+	}
+	return nil
+}
+
+func parseTimeUnit(unit string) (arrow.TimeUnit, error) {
+	for _, u := range []arrow.TimeUnit{arrow.Second, arrow.Millisecond, arrow.Microsecond, arrow.Nanosecond} {
+		if u.String() == unit {
+			return u, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time unit %q", unit)
+}
+
+func parseDecimalOption(args []string) (precision, scale int32, err error) {
+	if len(args) != 2 {
+		return 0, 0, fmt.Errorf("decimal option must be decimal(precision,scale)")
+	}
+	p, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid decimal precision %q", args[0])
+	}
+	s, err := strconv.ParseInt(args[1], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid decimal scale %q", args[1])
+	}
+	return int32(p), int32(s), nil
+}
+
+// newEncoder infers the Arrow type of a Go type, and whether it can be
+// NULL, given the options of its struct tag. Options apply to the
+// elements of pointers, slices and arrays.
+func newEncoder(t reflect.Type, opts []string) (arrow.DataType, bool, encodeFunc, error) {
+	if err := checkTagOptions(opts); err != nil {
+		return nil, false, nil, err
+	}
+
+	if t.Kind() == reflect.Pointer {
+		dt, _, enc, err := newEncoder(t.Elem(), opts)
+		if err != nil {
+			return nil, false, nil, err
+		}
+		return dt, true, func(b array.Builder, v reflect.Value) error {
+			if v.IsNil() {
+				b.AppendNull()
+				return nil
+			}
+			return enc(b, v.Elem())
+		}, nil
+	}
+
+	isList := t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	if args, ok := tagOption(opts, "decimal"); ok && !isList {
+		dt, enc, err := newDecimalEncoder(t, args)
+		return dt, false, enc, err
+	}
+
+	switch t {
+	case timeType:
+		dt, enc, err := newTimeEncoder(opts)
+		return dt, false, enc, err
+	case durationType:
+		unit := arrow.Nanosecond
+		if args, ok := tagOption(opts, "duration"); ok {
+			if len(args) != 1 {
+				return nil, false, nil, fmt.Errorf("duration option must be duration(unit)")
+			}
+			var err error
+			if unit, err = parseTimeUnit(args[0]); err != nil {
+				return nil, false, nil, err
+			}
+		}
+		return &arrow.DurationType{Unit: unit}, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.DurationBuilder).Append(arrow.Duration(time.Duration(v.Int()) / unit.Multiplier()))
+			return nil
+		}, nil
+	case reflect.TypeFor[decimal.Decimal32](), reflect.TypeFor[decimal.Decimal64](),
+		reflect.TypeFor[decimal128.Num](), reflect.TypeFor[decimal256.Num]():
+		return nil, false, nil, fmt.Errorf("%s requires the decimal(precision,scale) option", t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.BooleanBuilder).Append(v.Bool())
+			return nil
+		}, nil
+	case reflect.Int8:
+		return arrow.PrimitiveTypes.Int8, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Int8Builder).Append(int8(v.Int()))
+			return nil
+		}, nil
+	case reflect.Int16:
+		return arrow.PrimitiveTypes.Int16, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Int16Builder).Append(int16(v.Int()))
+			return nil
+		}, nil
+	case reflect.Int32:
+		return arrow.PrimitiveTypes.Int32, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Int32Builder).Append(int32(v.Int()))
+			return nil
+		}, nil
+	case reflect.Int, reflect.Int64:
+		return arrow.PrimitiveTypes.Int64, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Int64Builder).Append(v.Int())
+			return nil
+		}, nil
+	case reflect.Uint8:
+		return arrow.PrimitiveTypes.Uint8, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Uint8Builder).Append(uint8(v.Uint()))
+			return nil
+		}, nil
+	case reflect.Uint16:
+		return arrow.PrimitiveTypes.Uint16, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Uint16Builder).Append(uint16(v.Uint()))
+			return nil
+		}, nil
+	case reflect.Uint32:
+		return arrow.PrimitiveTypes.Uint32, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Uint32Builder).Append(uint32(v.Uint()))
+			return nil
+		}, nil
+	case reflect.Uint, reflect.Uint64:
+		return arrow.PrimitiveTypes.Uint64, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Uint64Builder).Append(v.Uint())
+			return nil
+		}, nil
+	case reflect.Float32:
+		return arrow.PrimitiveTypes.Float32, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Float32Builder).Append(float32(v.Float()))
+			return nil
+		}, nil
+	case reflect.Float64:
+		return arrow.PrimitiveTypes.Float64, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Float64Builder).Append(v.Float())
+			return nil
+		}, nil
+	case reflect.String:
+		return arrow.BinaryTypes.String, false, func(b array.Builder, v reflect.Value) error {
+			b.(*array.StringBuilder).Append(v.String())
+			return nil
+		}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return arrow.BinaryTypes.Binary, true, func(b array.Builder, v reflect.Value) error {
+				if v.IsNil() {
+					b.AppendNull()
+					return nil
+				}
+				b.(*array.BinaryBuilder).Append(v.Bytes())
+				return nil
+			}, nil
+		}
+		return newListEncoder(t, opts)
+	case reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &arrow.FixedSizeBinaryType{ByteWidth: t.Len()}, false, func(b array.Builder, v reflect.Value) error {
+				buf := make([]byte, t.Len())
+				reflect.Copy(reflect.ValueOf(buf), v)
+				b.(*array.FixedSizeBinaryBuilder).Append(buf)
+				return nil
+			}, nil
+		}
+		return newListEncoder(t, opts)
+	case reflect.Map:
+		return newMapEncoder(t)
+	case reflect.Struct:
+		fields, encoders, err := newFieldEncoders(t)
+		if err != nil {
+			return nil, false, nil, err
+		}
+		index := make([][]int, 0, len(fields))
+		for _, f := range structFields(t) {
+			index = append(index, f.index)
+		}
+		return arrow.StructOf(fields...), false, func(b array.Builder, v reflect.Value) error {
+			sb := b.(*array.StructBuilder)
+			sb.Append(true)
+			for j, enc := range encoders {
+				if err := enc(sb.FieldBuilder(j), v.FieldByIndex(index[j])); err != nil {
+					return fmt.Errorf("field %q: %w", fields[j].Name, err)
+				}
+			}
+			return nil
+		}, nil
+	}
+	return nil, false, nil, fmt.Errorf("cannot encode %s", t)
+}
+
+func newTimeEncoder(opts []string) (arrow.DataType, encodeFunc, error) {
+	if _, ok := tagOption(opts, "date"); ok {
+		return arrow.FixedWidthTypes.Date32, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Date32Builder).Append(arrow.Date32FromTime(v.Interface().(time.Time)))
+			return nil
+		}, nil
+	}
+
+	dt := &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}
+	if args, ok := tagOption(opts, "timestamp"); ok {
+		if len(args) < 1 || len(args) > 2 {
+			return nil, nil, fmt.Errorf("timestamp option must be timestamp(unit[,zone])")
+		}
+		unit, err := parseTimeUnit(args[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		dt.Unit = unit
+		if len(args) == 2 {
+			dt.TimeZone = args[1]
+			if dt.TimeZone == "-" {
+				dt.TimeZone = ""
+			}
+		}
+		if _, err := dt.GetZone(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return dt, func(b array.Builder, v reflect.Value) error {
+		ts, err := arrow.TimestampFromTime(v.Interface().(time.Time), dt.Unit)
+		if err != nil {
+			return err
+		}
+		b.(*array.TimestampBuilder).Append(ts)
+		return nil
+	}, nil
+}
+
+func newDecimalEncoder(t reflect.Type, args []string) (arrow.DataType, encodeFunc, error) {
+	precision, scale, err := parseDecimalOption(args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch t {
+	case reflect.TypeFor[decimal.Decimal32]():
+		dt, err := arrow.NewDecimalType(arrow.DECIMAL32, precision, scale)
+		return dt, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Decimal32Builder).Append(v.Interface().(decimal.Decimal32))
+			return nil
+		}, err
+	case reflect.TypeFor[decimal.Decimal64]():
+		dt, err := arrow.NewDecimalType(arrow.DECIMAL64, precision, scale)
+		return dt, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Decimal64Builder).Append(v.Interface().(decimal.Decimal64))
+			return nil
+		}, err
+	case reflect.TypeFor[decimal128.Num]():
+		dt, err := arrow.NewDecimalType(arrow.DECIMAL128, precision, scale)
+		return dt, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Decimal128Builder).Append(v.Interface().(decimal128.Num))
+			return nil
+		}, err
+	case reflect.TypeFor[decimal256.Num]():
+		dt, err := arrow.NewDecimalType(arrow.DECIMAL256, precision, scale)
+		return dt, func(b array.Builder, v reflect.Value) error {
+			b.(*array.Decimal256Builder).Append(v.Interface().(decimal256.Num))
+			return nil
+		}, err
+	}
+
+	var parse func(v reflect.Value) (decimal128.Num, decimal256.Num, error)
+	switch t.Kind() {
+	case reflect.String:
+		parse = func(v reflect.Value) (n128 decimal128.Num, n256 decimal256.Num, err error) {
+			if precision <= 38 {
+				n128, err = decimal128.FromString(v.String(), precision, scale)
+			} else {
+				n256, err = decimal256.FromString(v.String(), precision, scale)
+			}
+			return
+		}
+	case reflect.Float32, reflect.Float64:
+		parse = func(v reflect.Value) (n128 decimal128.Num, n256 decimal256.Num, err error) {
+			if precision <= 38 {
+				n128, err = decimal128.FromFloat64(v.Float(), precision, scale)
+			} else {
+				n256, err = decimal256.FromFloat64(v.Float(), precision, scale)
+			}
+			return
+		}
+	default:
+		return nil, nil, fmt.Errorf("cannot encode %s as a decimal", t)
+	}
+
+	if precision <= 38 {
+		dt, err := arrow.NewDecimalType(arrow.DECIMAL128, precision, scale)
+		return dt, func(b array.Builder, v reflect.Value) error {
+			n, _, err := parse(v)
+			if err != nil {
+				return err
+			}
+			b.(*array.Decimal128Builder).Append(n)
+			return nil
+		}, err
+	}
+	dt, err := arrow.NewDecimalType(arrow.DECIMAL256, precision, scale)
+	return dt, func(b array.Builder, v reflect.Value) error {
+		_, n, err := parse(v)
+		if err != nil {
+			return err
+		}
+		b.(*array.Decimal256Builder).Append(n)
+		return nil
+	}, err
+}
+
+func newListEncoder(t reflect.Type, opts []string) (arrow.DataType, bool, encodeFunc, error) {
+	elemType, elemNullable, enc, err := newEncoder(t.Elem(), opts)
+	if err != nil {
+		return nil, false, nil, err
+	}
+	elem := arrow.Field{Name: "item", Type: elemType, Nullable: elemNullable}
+
+	appendValues := func(b array.Builder, v reflect.Value) error {
+		for j := 0; j < v.Len(); j++ {
+			if err := enc(b, v.Index(j)); err != nil {
+				return fmt.Errorf("element %d: %w", j, err)
+			}
+		}
+		return nil
+	}
+	if t.Kind() == reflect.Array {
+		return arrow.FixedSizeListOfField(int32(t.Len()), elem), false, func(b array.Builder, v reflect.Value) error {
+			lb := b.(*array.FixedSizeListBuilder)
+			lb.Append(true)
+			return appendValues(lb.ValueBuilder(), v)
+		}, nil
+	}
+	return arrow.ListOfField(elem), true, func(b array.Builder, v reflect.Value) error {
+		lb := b.(*array.ListBuilder)
+		if v.IsNil() {
+			lb.AppendNull()
+			return nil
+		}
+		lb.Append(true)
+		return appendValues(lb.ValueBuilder(), v)
+	}, nil
+}
+
+func newMapEncoder(t reflect.Type) (arrow.DataType, bool, encodeFunc, error) {
+	keyType, keyNullable, keyEnc, err := newEncoder(t.Key(), nil)
+	if err != nil {
+		return nil, false, nil, fmt.Errorf("key: %w", err)
+	}
+	if keyNullable {
+		return nil, false, nil, fmt.Errorf("map keys of type %s can be NULL", t.Key())
+	}
+	itemType, itemNullable, itemEnc, err := newEncoder(t.Elem(), nil)
+	if err != nil {
+		return nil, false, nil, fmt.Errorf("item: %w", err)
+	}
+
+	dt := arrow.MapOf(keyType, itemType)
+	dt.SetItemNullable(itemNullable)
+	return dt, true, func(b array.Builder, v reflect.Value) error {
+		mb := b.(*array.MapBuilder)
+		if v.IsNil() {
+			mb.AppendNull()
+			return nil
+		}
+		mb.Append(true)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := keyEnc(mb.KeyBuilder(), iter.Key()); err != nil {
+				return fmt.Errorf("key: %w", err)
+			}
+			if err := itemEnc(mb.ItemBuilder(), iter.Value()); err != nil {
+				return fmt.Errorf("item: %w", err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+// structRecordReader encodes rows pulled from a source into record
+// batches.
+type structRecordReader struct {
+	refCount  atomic.Int64
+	enc       *rowEncoder
+	bldr      *array.RecordBuilder
+	next      func() (reflect.Value, bool)
+	batchSize int
+	row       int64
+	done      bool
+
+	cur arrow.Record
+	err error
+}
+
+func newStructRecordReader(t reflect.Type, next func() (reflect.Value, bool), opts []EncodeOption) *structRecordReader {
+	cfg := encodeConfig{batchSize: defaultEncodeBatchSize, mem: memory.DefaultAllocator}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rdr := &structRecordReader{next: next, batchSize: cfg.batchSize}
+	rdr.refCount.Store(1)
+	enc, err := newRowEncoder(t)
+	if err != nil {
+		rdr.enc = &rowEncoder{schema: arrow.NewSchema(nil, nil)}
+		rdr.err = err
+		rdr.done = true
+		return rdr
+	}
+	rdr.enc = enc
+	rdr.bldr = array.NewRecordBuilder(cfg.mem, enc.schema)
+	return rdr
+}
+
+func (r *structRecordReader) Retain() {
+	r.refCount.Add(1)
+}
+
+func (r *structRecordReader) Release() {
+	if r.refCount.Add(-1) == 0 {
+		if r.cur != nil {
+			r.cur.Release()
+			r.cur = nil
+		}
+		if r.bldr != nil {
+			r.bldr.Release()
+			r.bldr = nil
+		}
+	}
+}
+
+func (r *structRecordReader) Schema() *arrow.Schema {
+	return r.enc.schema
+}
+
+func (r *structRecordReader) Record() arrow.Record {
+	return r.cur
+}
+
+func (r *structRecordReader) Err() error {
+	return r.err
+}
+
+func (r *structRecordReader) Next() bool {
+	if r.cur != nil {
+		r.cur.Release()
+		r.cur = nil
+	}
+	if r.done {
+		return false
+	}
+
+	rows := 0
+	for rows < r.batchSize {
+		v, ok := r.next()
+		if !ok {
+			r.done = true
+			break
+		}
+		if err := r.encodeRow(v); err != nil {
+			r.err = err
+			r.done = true
+			// the columns of the partial batch may not line up
+			r.bldr.Release()
+			r.bldr = nil
+			return false
+		}
+		rows++
+		r.row++
+	}
+	if rows == 0 {
+		return false
+	}
+	r.cur = r.bldr.NewRecord()
+	return true
+}
+
+func (r *structRecordReader) encodeRow(v reflect.Value) error {
+	if r.enc.ptr {
+		if v.IsNil() {
+			return Error{
+				Msg:  fmt.Sprintf("[adbc] RecordsFromStructs: row %d is nil", r.row),
+				Code: StatusInvalidArgument,
+			}
+		}
+		v = v.Elem()
+	}
+	for j, enc := range r.enc.encoders {
+		if err := enc(r.bldr.Field(j), v.FieldByIndex(r.enc.index[j])); err != nil {
+			return Error{
+				Msg:  fmt.Sprintf("[adbc] RecordsFromStructs: row %d, column %q: %s", r.row, r.enc.schema.Field(j).Name, err),
+				Code: StatusInvalidArgument,
+			}
+		}
+	}
+	return nil
+}
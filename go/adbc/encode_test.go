@@ -0,0 +1,180 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package adbc_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encodeRow struct {
+	ID      int64               `adbc:"id"`
+	Name    *string             `adbc:"name"`
+	Price   string              `adbc:"price,decimal(10,2)"`
+	Created time.Time           `adbc:"created,timestamp(ns,Europe/Paris)"`
+	Day     time.Time           `adbc:"day,date"`
+	Elapsed time.Duration       `adbc:"elapsed,duration(ms)"`
+	Tags    []string            `adbc:"tags"`
+	Attrs   map[string]int32    `adbc:"attrs"`
+	Point   struct{ X, Y int8 } `adbc:"point,nullable"`
+	Ignored bool                `adbc:"-"`
+}
+
+func TestSchemaFromStruct(t *testing.T) {
+	schema, err := adbc.SchemaFromStruct[*encodeRow]()
+	require.NoError(t, err)
+
+	attrs := arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int32)
+	attrs.SetItemNullable(false)
+	assert.Truef(t, arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "price", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}},
+		{Name: "created", Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "Europe/Paris"}},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "elapsed", Type: &arrow.DurationType{Unit: arrow.Millisecond}},
+		{Name: "tags", Type: arrow.ListOfNonNullable(arrow.BinaryTypes.String), Nullable: true},
+		{Name: "attrs", Type: attrs, Nullable: true},
+		{Name: "point", Type: arrow.StructOf(
+			arrow.Field{Name: "X", Type: arrow.PrimitiveTypes.Int8},
+			arrow.Field{Name: "Y", Type: arrow.PrimitiveTypes.Int8},
+		), Nullable: true},
+	}, nil).Equal(schema), "unexpected schema: %s", schema)
+
+	_, err = adbc.SchemaFromStruct[struct {
+		Price float64 `adbc:"price,decimal(10)"`
+	}]()
+	assert.ErrorContains(t, err, `field "price": decimal option must be decimal(precision,scale)`)
+
+	_, err = adbc.SchemaFromStruct[struct {
+		C chan int
+	}]()
+	assert.ErrorContains(t, err, `field "C": cannot encode chan int`)
+
+	_, err = adbc.SchemaFromStruct[struct {
+		ID int `adbc:"id,nulable"`
+	}]()
+	assert.ErrorContains(t, err, `unknown option "nulable"`)
+}
+
+func TestRecordsFromStructs(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	name := "a"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	items := []*encodeRow{
+		{ID: 1, Name: &name, Price: "12.34", Created: created, Day: created, Elapsed: 1500 * time.Millisecond,
+			Tags: []string{"x", "y"}, Attrs: map[string]int32{"k": 3}},
+		{ID: 2, Price: "-0.5", Created: created},
+		{ID: 3, Price: "0", Created: created},
+	}
+
+	rdr := adbc.RecordsFromStructs(items, adbc.WithEncodeBatchSize(2), adbc.WithEncodeAllocator(mem))
+	defer rdr.Release()
+
+	var sizes []int64
+	for rdr.Next() {
+		sizes = append(sizes, rdr.Record().NumRows())
+	}
+	require.NoError(t, rdr.Err())
+	assert.Equal(t, []int64{2, 1}, sizes)
+
+	// round trip through ScanRows
+	type scanRow struct {
+		ID      int64            `adbc:"id"`
+		Name    *string          `adbc:"name"`
+		Price   string           `adbc:"price"`
+		Created time.Time        `adbc:"created"`
+		Day     time.Time        `adbc:"day"`
+		Elapsed time.Duration    `adbc:"elapsed"`
+		Tags    []string         `adbc:"tags"`
+		Attrs   map[string]int32 `adbc:"attrs"`
+	}
+	rdr = adbc.RecordsFromStructs(items, adbc.WithEncodeAllocator(mem))
+	defer rdr.Release()
+	rows, err := adbc.ScanRows[scanRow](rdr)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	assert.Equal(t, scanRow{
+		ID:      1,
+		Name:    &name,
+		Price:   "12.34",
+		Created: created.In(paris),
+		Day:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Elapsed: 1500 * time.Millisecond,
+		Tags:    []string{"x", "y"},
+		Attrs:   map[string]int32{"k": 3},
+	}, rows[0])
+	assert.Nil(t, rows[1].Name)
+	assert.Nil(t, rows[1].Tags)
+	assert.Nil(t, rows[1].Attrs)
+	assert.Equal(t, "-0.50", rows[1].Price)
+}
+
+func TestRecordsFromChan(t *testing.T) {
+	type row struct {
+		ID int32 `adbc:"id"`
+	}
+	ch := make(chan row)
+	go func() {
+		defer close(ch)
+		for i := range 5 {
+			ch <- row{ID: int32(i)}
+		}
+	}()
+
+	rdr := adbc.RecordsFromChan(ch, adbc.WithEncodeBatchSize(3))
+	defer rdr.Release()
+	rows, err := adbc.ScanRows[row](rdr)
+	require.NoError(t, err)
+	assert.Equal(t, []row{{0}, {1}, {2}, {3}, {4}}, rows)
+}
+
+func TestRecordsFromStructsErrors(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	type row struct {
+		Price string `adbc:"price,decimal(4,2)"`
+	}
+	rdr := adbc.RecordsFromStructs([]*row{{Price: "1.5"}, {Price: "100"}, nil}, adbc.WithEncodeAllocator(mem))
+	defer rdr.Release()
+	assert.False(t, rdr.Next())
+	assert.ErrorContains(t, rdr.Err(), `row 1, column "price"`)
+
+	rdr = adbc.RecordsFromStructs([]*row{{Price: "1.5"}, nil}, adbc.WithEncodeAllocator(mem))
+	defer rdr.Release()
+	assert.False(t, rdr.Next())
+	assert.ErrorContains(t, rdr.Err(), "row 1 is nil")
+
+	rdr = adbc.RecordsFromStructs([]int{1})
+	defer rdr.Release()
+	assert.False(t, rdr.Next())
+	assert.ErrorContains(t, rdr.Err(), "int is not a struct")
+	assert.Zero(t, rdr.Schema().NumFields())
+}
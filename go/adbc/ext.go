@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -82,6 +83,201 @@ type OTelTracing interface {
 	GetInitialSpanAttributes() []attribute.KeyValue
 }
 
+// OptionCatalogSchema is the schema of the result of
+// OptionCatalog.GetOptionCatalog, with one row per option:
+//
+//   - option_name is the option key, or a prefix of the keys if
+//     is_prefix is true.
+//   - option_levels lists the objects the option can be set on, among
+//     "database", "connection" and "statement".
+//   - value_type is one of "string", "bool", "int", "double",
+//     "duration" (as parsed by time.ParseDuration) or "bytes".
+//   - default_value is the value used when the option isn't set, if any.
+//   - allowed_values lists the accepted values, or is null if any value
+//     of the type is accepted.
+//   - is_secret is true for credentials, which should not be logged or
+//     displayed.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+var OptionCatalogSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "option_name", Type: arrow.BinaryTypes.String},
+	{Name: "is_prefix", Type: arrow.FixedWidthTypes.Boolean},
+	{Name: "option_levels", Type: arrow.ListOfNonNullable(arrow.BinaryTypes.String)},
+	{Name: "value_type", Type: arrow.BinaryTypes.String},
+	{Name: "default_value", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "allowed_values", Type: arrow.ListOfNonNullable(arrow.BinaryTypes.String), Nullable: true},
+	{Name: "is_secret", Type: arrow.FixedWidthTypes.Boolean},
+	{Name: "description", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+// OptionCatalog is a Driver or Database that can describe the options it
+// accepts, for instance to generate configuration forms or to check
+// options before connecting.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type OptionCatalog interface {
+	// GetOptionCatalog returns the options accepted by the driver, with
+	// the schema OptionCatalogSchema.
+	GetOptionCatalog(ctx context.Context) (array.RecordReader, error)
+}
+
+// Options of the retry policy applied by the Go drivers to idempotent
+// operations, such as metadata calls and result downloads, that fail with
+// a transient error. They can be set on a database or a connection; a
+// connection starts with the policy of its database.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+const (
+	// The maximum number of attempts of an operation, including the
+	// first one. The default is 1, i.e. operations aren't retried.
+	OptionKeyRetryMaxAttempts = "adbc.retry.max_attempts"
+	// The delay before the first retry, as a duration such as "100ms".
+	OptionKeyRetryInitialBackoff = "adbc.retry.initial_backoff"
+	// The maximum delay between two attempts.
+	OptionKeyRetryMaxBackoff = "adbc.retry.max_backoff"
+	// The factor applied to the delay after each retry.
+	OptionKeyRetryBackoffMultiplier = "adbc.retry.backoff_multiplier"
+	// The fraction, between 0 and 1, of each delay that is randomized.
+	OptionKeyRetryJitter = "adbc.retry.jitter"
+	// The comma-separated error codes that are retried, named like the
+	// Status values in snake case, e.g. "io,timeout".
+	OptionKeyRetryCodes = "adbc.retry.codes"
+	// Whether operations that may not be idempotent, such as updates,
+	// are retried too. Disabled by default.
+	OptionKeyRetryNonIdempotent = "adbc.retry.non_idempotent"
+)
+
+// QueryProgress is the progress of the query of a statement, as reported
+// to the callback of StatementProgress.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type QueryProgress struct {
+	// Progress and MaxProgress are the values of the OptionKeyProgress
+	// and OptionKeyMaxProgress options of the statement. Progress reaches
+	// MaxProgress when the query completes.
+	Progress    float64
+	MaxProgress float64
+	// Rows is the number of rows produced or affected by the query, or
+	// -1 if unknown.
+	Rows int64
+	// BytesProcessed is the number of bytes processed by the query so far,
+	// or -1 if unknown.
+	BytesProcessed int64
+}
+
+// StatementProgress is a Statement that can report the progress of its
+// queries to a callback, as an alternative to polling OptionKeyProgress.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type StatementProgress interface {
+	// SetProgressCallback sets a function that is called whenever the
+	// progress of a query of the statement changes, or removes it if
+	// callback is nil. The callback may be called from other goroutines
+	// and should not block.
+	SetProgressCallback(callback func(QueryProgress))
+}
+
+// StatementSubmitQuery is a Statement that can start its query on the
+// backend without waiting for it, so that the results can be fetched
+// later with ConnectionAttachQuery, possibly from another process.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type StatementSubmitQuery interface {
+	// SubmitQuery starts the query of the statement and returns once the
+	// backend has accepted it, with an opaque handle to the query. The
+	// query keeps running if the statement, connection or process goes
+	// away. Bound parameters and bulk ingestion are not supported.
+	//
+	// The handle is specific to the driver: e.g. a Snowflake query ID, a
+	// BigQuery job reference, or a serialized Flight SQL FlightInfo. It
+	// stays valid as long as the backend keeps the results of the query.
+	SubmitQuery(ctx context.Context) ([]byte, error)
+}
+
+// ConnectionAttachQuery is a Connection that can read the results of a
+// query started by StatementSubmitQuery.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type ConnectionAttachQuery interface {
+	// AttachQuery waits for the query identified by handle to complete
+	// and returns a reader of its results. The connection need not be
+	// the one that submitted the query, but it must be to the same
+	// backend, with a user allowed to read the results.
+	AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error)
+}
+
+// Keys of the typed error details that the Go drivers attach to an Error
+// to identify the work that failed on the backend.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+const (
+	ErrorDetailKeyQueryID      = "adbc.error.query_id"
+	ErrorDetailKeyJobID        = "adbc.error.job_id"
+	ErrorDetailKeyFlightTicket = "adbc.error.flight_ticket"
+)
+
+// QueryIDErrorDetail is the ID the backend assigned to a failed query,
+// e.g. to look it up in the query history of Snowflake. It implements
+// error so that it can be retrieved with errors.As:
+//
+//	var queryID *adbc.QueryIDErrorDetail
+//	if errors.As(err, &queryID) {
+//		log.Printf("query %s failed", queryID.QueryID)
+//	}
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type QueryIDErrorDetail struct {
+	QueryID string
+}
+
+func (d *QueryIDErrorDetail) Key() string                { return ErrorDetailKeyQueryID }
+func (d *QueryIDErrorDetail) Serialize() ([]byte, error) { return []byte(d.QueryID), nil }
+func (d *QueryIDErrorDetail) Error() string              { return "query " + d.QueryID }
+
+// JobIDErrorDetail identifies a failed job, such as a BigQuery query job.
+// Location is empty if the backend has no notion of it. Like
+// QueryIDErrorDetail, it can be retrieved with errors.As.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type JobIDErrorDetail struct {
+	ProjectID string
+	Location  string
+	JobID     string
+}
+
+func (d *JobIDErrorDetail) Key() string { return ErrorDetailKeyJobID }
+
+// Serialize formats the job as project:location.job, the notation of the
+// bq command-line tool.
+func (d *JobIDErrorDetail) Serialize() ([]byte, error) { return []byte(d.String()), nil }
+func (d *JobIDErrorDetail) Error() string              { return "job " + d.String() }
+
+func (d *JobIDErrorDetail) String() string {
+	id := d.JobID
+	if d.Location != "" {
+		id = d.Location + "." + id
+	}
+	if d.ProjectID != "" {
+		id = d.ProjectID + ":" + id
+	}
+	return id
+}
+
+// FlightTicketErrorDetail is the ticket of a Flight endpoint that could
+// not be read. Like QueryIDErrorDetail, it can be retrieved with
+// errors.As.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+type FlightTicketErrorDetail struct {
+	Ticket []byte
+}
+
+func (d *FlightTicketErrorDetail) Key() string                { return ErrorDetailKeyFlightTicket }
+func (d *FlightTicketErrorDetail) Serialize() ([]byte, error) { return d.Ticket, nil }
+func (d *FlightTicketErrorDetail) Error() string {
+	return fmt.Sprintf("flight ticket %q", d.Ticket)
+}
+
 // IngestStreamOption bundles the IngestStream options.
 // Driver specific options can go into Extra.
 type IngestStreamOptions struct {
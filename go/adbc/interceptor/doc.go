@@ -0,0 +1,50 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package interceptor wraps any adbc.Database, adbc.Connection or
+// adbc.Statement with a chain of interceptors, in the manner of gRPC
+// interceptors, to add auditing, query rewriting, rate limiting or
+// metrics around a driver without modifying it.
+//
+//	audit := func(ctx context.Context, call *interceptor.Call, invoker interceptor.Invoker) error {
+//		start := time.Now()
+//		err := invoker(ctx, call)
+//		logger.InfoContext(ctx, "adbc call", "method", call.Method,
+//			"query", call.Query, "duration", time.Since(start), "error", err)
+//		return err
+//	}
+//
+//	db := interceptor.WrapDatabase(driverDB, audit, limiter)
+//	cnxn, err := db.Open(ctx)
+//
+// Interceptors run around Database.Open, Connection.GetObjects, Commit
+// and Rollback, and Statement.SetSqlQuery, Bind, BindStream and the
+// Execute methods. They see the arguments and, once the invoker returns,
+// the results of the call in its Call, and may change either. The options
+// of the object the method is called on are available through
+// Call.Options.
+//
+// Connections opened from a wrapped database and statements created from
+// a wrapped connection are wrapped with the same interceptors. The
+// wrappers implement the optional adbc.GetSetOptions,
+// adbc.ConnectionGetStatistics, adbc.StatementExecuteSchema,
+// adbc.OTelTracing and cancellation interfaces by forwarding them to the
+// driver, which reports StatusNotImplemented when it doesn't support them.
+// Unwrap returns the underlying object, for other extensions.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+package interceptor
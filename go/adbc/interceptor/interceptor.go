@@ -0,0 +1,226 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package interceptor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// Method identifies an intercepted method.
+type Method string
+
+const (
+	MethodOpen              Method = "Database.Open"
+	MethodGetObjects        Method = "Connection.GetObjects"
+	MethodCommit            Method = "Connection.Commit"
+	MethodRollback          Method = "Connection.Rollback"
+	MethodSetSqlQuery       Method = "Statement.SetSqlQuery"
+	MethodBind              Method = "Statement.Bind"
+	MethodBindStream        Method = "Statement.BindStream"
+	MethodExecuteQuery      Method = "Statement.ExecuteQuery"
+	MethodExecuteUpdate     Method = "Statement.ExecuteUpdate"
+	MethodExecutePartitions Method = "Statement.ExecutePartitions"
+	MethodExecuteSchema     Method = "Statement.ExecuteSchema"
+)
+
+// Call is an intercepted call. Only the fields relevant to its method are
+// used: interceptors may change the arguments before calling the invoker,
+// and the results once it returns.
+type Call struct {
+	Method Method
+	// Options are the options of the database, connection or statement
+	// the method is called on.
+	Options adbc.GetSetOptions
+
+	// Query is the argument of SetSqlQuery, and the current query of the
+	// statement for the Execute methods and binds. It is empty for
+	// Substrait plans and bulk ingestion.
+	Query string
+	// Values and Stream are the arguments of Bind and BindStream.
+	Values arrow.Record
+	Stream array.RecordReader
+	// Depth, Catalog, DbSchema, TableName, ColumnName and TableTypes are
+	// the arguments of GetObjects.
+	Depth      adbc.ObjectDepth
+	Catalog    *string
+	DbSchema   *string
+	TableName  *string
+	ColumnName *string
+	TableTypes []string
+
+	// Connection is the result of Open. It is wrapped once the chain
+	// returns.
+	Connection adbc.Connection
+	// Reader is the result of GetObjects and ExecuteQuery. An interceptor
+	// replacing it must release the previous one.
+	Reader       array.RecordReader
+	RowsAffected int64
+	// Schema and Partitions are the results of ExecutePartitions, and
+	// Schema of ExecuteSchema.
+	Schema     *arrow.Schema
+	Partitions adbc.Partitions
+}
+
+// Invoker performs a call, by calling the next interceptor of the chain
+// or the driver.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor intercepts a call. It must call invoker to carry on with
+// the call, or return an error to fail it without calling the driver.
+// The results the invoker stores in call are released or closed by the
+// wrapper if the interceptor returns an error.
+type Interceptor func(ctx context.Context, call *Call, invoker Invoker) error
+
+// Chain returns an interceptor running interceptors in order, the first
+// one being the outermost.
+func Chain(interceptors ...Interceptor) Interceptor {
+	interceptors = slices.DeleteFunc(slices.Clone(interceptors), func(i Interceptor) bool {
+		return i == nil
+	})
+	switch len(interceptors) {
+	case 0:
+		return func(ctx context.Context, call *Call, invoker Invoker) error {
+			return invoker(ctx, call)
+		}
+	case 1:
+		return interceptors[0]
+	}
+	return func(ctx context.Context, call *Call, invoker Invoker) error {
+		return interceptors[0](ctx, call, chained(interceptors[1:], invoker))
+	}
+}
+
+func chained(interceptors []Interceptor, invoker Invoker) Invoker {
+	if len(interceptors) == 0 {
+		return invoker
+	}
+	return func(ctx context.Context, call *Call) error {
+		return interceptors[0](ctx, call, chained(interceptors[1:], invoker))
+	}
+}
+
+// run makes call through intercept, releasing its results on failure.
+func run(ctx context.Context, intercept Interceptor, call *Call, invoker Invoker) error {
+	err := intercept(ctx, call, invoker)
+	if err != nil {
+		if call.Reader != nil {
+			call.Reader.Release()
+			call.Reader = nil
+		}
+		if call.Connection != nil {
+			_ = call.Connection.Close()
+			call.Connection = nil
+		}
+	}
+	return err
+}
+
+// options implements adbc.GetSetOptions by forwarding to target.
+type options struct {
+	target any
+}
+
+func (o options) get() (adbc.GetSetOptions, error) {
+	if opts, ok := o.target.(adbc.GetSetOptions); ok {
+		return opts, nil
+	}
+	return nil, errNotSupported("GetSetOptions")
+}
+
+// SetOption implements adbc.PostInitOptions.
+func (o options) SetOption(key, value string) error {
+	if opts, ok := o.target.(adbc.PostInitOptions); ok {
+		return opts.SetOption(key, value)
+	}
+	return errNotSupported("SetOption")
+}
+
+// SetOptionBytes implements adbc.GetSetOptions.
+func (o options) SetOptionBytes(key string, value []byte) error {
+	opts, err := o.get()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionBytes(key, value)
+}
+
+// SetOptionInt implements adbc.GetSetOptions.
+func (o options) SetOptionInt(key string, value int64) error {
+	opts, err := o.get()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionInt(key, value)
+}
+
+// SetOptionDouble implements adbc.GetSetOptions.
+func (o options) SetOptionDouble(key string, value float64) error {
+	opts, err := o.get()
+	if err != nil {
+		return err
+	}
+	return opts.SetOptionDouble(key, value)
+}
+
+// GetOption implements adbc.GetSetOptions.
+func (o options) GetOption(key string) (string, error) {
+	opts, err := o.get()
+	if err != nil {
+		return "", err
+	}
+	return opts.GetOption(key)
+}
+
+// GetOptionBytes implements adbc.GetSetOptions.
+func (o options) GetOptionBytes(key string) ([]byte, error) {
+	opts, err := o.get()
+	if err != nil {
+		return nil, err
+	}
+	return opts.GetOptionBytes(key)
+}
+
+// GetOptionInt implements adbc.GetSetOptions.
+func (o options) GetOptionInt(key string) (int64, error) {
+	opts, err := o.get()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionInt(key)
+}
+
+// GetOptionDouble implements adbc.GetSetOptions.
+func (o options) GetOptionDouble(key string) (float64, error) {
+	opts, err := o.get()
+	if err != nil {
+		return 0, err
+	}
+	return opts.GetOptionDouble(key)
+}
+
+func errNotSupported(what string) error {
+	return adbc.Error{
+		Msg:  "[interceptor] the driver doesn't support " + what,
+		Code: adbc.StatusNotImplemented,
+	}
+}
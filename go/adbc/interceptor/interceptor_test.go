@@ -0,0 +1,367 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDatabase supports logging but not the option catalog or tracing.
+type mockDatabase struct {
+	adbc.Database
+
+	mem     memory.Allocator
+	options map[string]string
+	opened  []*mockConnection
+	logger  *slog.Logger
+}
+
+func (db *mockDatabase) Open(context.Context) (adbc.Connection, error) {
+	cnxn := &mockConnection{mem: db.mem}
+	db.opened = append(db.opened, cnxn)
+	return cnxn, nil
+}
+
+func (db *mockDatabase) SetOption(key, value string) error {
+	db.options[key] = value
+	return nil
+}
+
+func (db *mockDatabase) SetLogger(logger *slog.Logger) {
+	db.logger = logger
+}
+
+// mockConnection supports GetStatistics but not GetSetOptions.
+type mockConnection struct {
+	adbc.Connection
+	adbc.ConnectionGetStatistics
+
+	mem       memory.Allocator
+	closed    bool
+	commits   int
+	catalog   *string
+	statistic bool
+}
+
+func (c *mockConnection) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *mockConnection) Commit(context.Context) error {
+	c.commits++
+	return nil
+}
+
+func (c *mockConnection) GetObjects(_ context.Context, _ adbc.ObjectDepth, catalog, _, _, _ *string, _ []string) (array.RecordReader, error) {
+	c.catalog = catalog
+	return newReader(c.mem, "objects")
+}
+
+func (c *mockConnection) GetStatisticNames(context.Context) (array.RecordReader, error) {
+	c.statistic = true
+	return newReader(c.mem, "statistic")
+}
+
+func (c *mockConnection) NewStatement() (adbc.Statement, error) {
+	return &mockStatement{cnxn: c}, nil
+}
+
+// mockStatement returns its query as a row, and implements neither
+// GetSetOptions nor ExecuteSchema.
+type mockStatement struct {
+	adbc.Statement
+
+	cnxn     *mockConnection
+	query    string
+	bound    arrow.Record
+	executed int
+}
+
+func (s *mockStatement) SetSqlQuery(query string) error {
+	s.query = query
+	return nil
+}
+
+func (s *mockStatement) Close() error {
+	return nil
+}
+
+func (s *mockStatement) Bind(_ context.Context, values arrow.Record) error {
+	s.bound = values
+	return nil
+}
+
+func (s *mockStatement) ExecuteQuery(context.Context) (array.RecordReader, int64, error) {
+	s.executed++
+	rdr, err := newReader(s.cnxn.mem, s.query)
+	return rdr, 1, err
+}
+
+func (s *mockStatement) ExecuteUpdate(context.Context) (int64, error) {
+	s.executed++
+	return 3, nil
+}
+
+var resultSchema = arrow.NewSchema([]arrow.Field{{Name: "value", Type: arrow.BinaryTypes.String}}, nil)
+
+func newReader(mem memory.Allocator, value string) (array.RecordReader, error) {
+	bldr := array.NewRecordBuilder(mem, resultSchema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.StringBuilder).Append(value)
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	return array.NewRecordReader(resultSchema, []arrow.Record{rec})
+}
+
+func readAll(t *testing.T, rdr array.RecordReader) []string {
+	defer rdr.Release()
+	var values []string
+	for rdr.Next() {
+		col := rdr.Record().Column(0).(*array.String)
+		for i := range col.Len() {
+			values = append(values, col.Value(i))
+		}
+	}
+	require.NoError(t, rdr.Err())
+	return values
+}
+
+func newTestDatabase(t *testing.T) (*mockDatabase, *memory.CheckedAllocator) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	t.Cleanup(func() { mem.AssertSize(t, 0) })
+	return &mockDatabase{mem: mem, options: make(map[string]string)}, mem
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, invoker Invoker) error {
+			calls = append(calls, name+" before "+string(call.Method))
+			err := invoker(ctx, call)
+			calls = append(calls, name+" after "+string(call.Method))
+			return err
+		}
+	}
+
+	mock, _ := newTestDatabase(t)
+	db := WrapDatabase(mock, record("outer"), nil, record("inner"))
+	ctx := context.Background()
+
+	cnxn, err := db.Open(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &Connection{}, cnxn)
+	require.NoError(t, cnxn.Commit(ctx))
+	assert.Equal(t, 1, cnxn.(*Connection).Unwrap().(*mockConnection).commits)
+
+	assert.Equal(t, []string{
+		"outer before Database.Open",
+		"inner before Database.Open",
+		"inner after Database.Open",
+		"outer after Database.Open",
+		"outer before Connection.Commit",
+		"inner before Connection.Commit",
+		"inner after Connection.Commit",
+		"outer after Connection.Commit",
+	}, calls)
+}
+
+func TestStatement(t *testing.T) {
+	var (
+		executed []string
+		rows     int64
+	)
+	rewrite := func(ctx context.Context, call *Call, invoker Invoker) error {
+		if call.Method == MethodSetSqlQuery {
+			call.Query = "/* audited */ " + call.Query
+		}
+		return invoker(ctx, call)
+	}
+	audit := func(ctx context.Context, call *Call, invoker Invoker) error {
+		err := invoker(ctx, call)
+		if call.Method == MethodExecuteQuery || call.Method == MethodExecuteUpdate {
+			executed = append(executed, call.Query)
+			rows += call.RowsAffected
+		}
+		return err
+	}
+
+	mock, _ := newTestDatabase(t)
+	cnxn := WrapConnection(&mockConnection{mem: mock.mem}, rewrite, audit)
+	ctx := context.Background()
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	defer stmt.Close()
+	require.NoError(t, stmt.SetSqlQuery("SELECT 1"))
+
+	rdr, n, err := stmt.ExecuteQuery(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+	assert.Equal(t, []string{"/* audited */ SELECT 1"}, readAll(t, rdr))
+
+	n, err = stmt.ExecuteUpdate(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+
+	assert.Equal(t, []string{"/* audited */ SELECT 1", "/* audited */ SELECT 1"}, executed)
+	assert.EqualValues(t, 4, rows)
+}
+
+func TestArguments(t *testing.T) {
+	catalog, other := "main", "other"
+	replace := func(ctx context.Context, call *Call, invoker Invoker) error {
+		switch call.Method {
+		case MethodGetObjects:
+			assert.Equal(t, &catalog, call.Catalog)
+			call.Catalog = &other
+		case MethodBind:
+			call.Values = nil
+		}
+		return invoker(ctx, call)
+	}
+
+	mock, mem := newTestDatabase(t)
+	driverCnxn := &mockConnection{mem: mem}
+	cnxn := WrapConnection(driverCnxn, replace)
+	ctx := context.Background()
+
+	rdr, err := cnxn.GetObjects(ctx, adbc.ObjectDepthAll, &catalog, nil, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"objects"}, readAll(t, rdr))
+	assert.Equal(t, &other, driverCnxn.catalog)
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	bldr := array.NewRecordBuilder(mock.mem, resultSchema)
+	defer bldr.Release()
+	bldr.Field(0).(*array.StringBuilder).Append("param")
+	rec := bldr.NewRecord()
+	defer rec.Release()
+	require.NoError(t, stmt.Bind(ctx, rec))
+	assert.Nil(t, stmt.(*Statement).Unwrap().(*mockStatement).bound)
+}
+
+func TestReject(t *testing.T) {
+	errLimited := errors.New("rate limited")
+	calls := 0
+	limit := func(ctx context.Context, call *Call, invoker Invoker) error {
+		calls++
+		if calls > 2 {
+			return errLimited
+		}
+		return invoker(ctx, call)
+	}
+
+	mock, _ := newTestDatabase(t)
+	cnxn := WrapConnection(&mockConnection{mem: mock.mem}, limit)
+	ctx := context.Background()
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	require.NoError(t, stmt.SetSqlQuery("SELECT 1"))
+	_, err = stmt.ExecuteUpdate(ctx)
+	require.NoError(t, err)
+
+	n, err := stmt.ExecuteUpdate(ctx)
+	assert.ErrorIs(t, err, errLimited)
+	assert.EqualValues(t, -1, n)
+	assert.Equal(t, 1, stmt.(*Statement).Unwrap().(*mockStatement).executed)
+}
+
+func TestReleaseOnError(t *testing.T) {
+	errAudit := errors.New("audit failed")
+	fail := func(ctx context.Context, call *Call, invoker Invoker) error {
+		if err := invoker(ctx, call); err != nil {
+			return err
+		}
+		if call.Method == MethodExecuteQuery || call.Method == MethodOpen {
+			return errAudit
+		}
+		return nil
+	}
+
+	// the checked allocator asserts that the reader is released
+	mock, _ := newTestDatabase(t)
+	cnxn := WrapConnection(&mockConnection{mem: mock.mem}, fail)
+	ctx := context.Background()
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	require.NoError(t, stmt.SetSqlQuery("SELECT 1"))
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	assert.ErrorIs(t, err, errAudit)
+	assert.Nil(t, rdr)
+
+	db := WrapDatabase(mock, fail)
+	_, err = db.Open(ctx)
+	assert.ErrorIs(t, err, errAudit)
+	require.Len(t, mock.opened, 1)
+	assert.True(t, mock.opened[0].closed)
+}
+
+func TestExtensions(t *testing.T) {
+	mock, _ := newTestDatabase(t)
+	db := WrapDatabase(mock)
+	ctx := context.Background()
+
+	require.NoError(t, db.SetOption("key", "value"))
+	assert.Equal(t, "value", mock.options["key"])
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	db.SetLogger(logger)
+	assert.Same(t, logger, mock.logger)
+	var adbcErr adbc.Error
+	_, err := db.GetOptionCatalog(ctx)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+	err = db.InitTracing(ctx, "mock", "0.0.0")
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+
+	cnxn, err := db.Open(ctx)
+	require.NoError(t, err)
+	_, err = cnxn.(adbc.GetSetOptions).GetOption(adbc.OptionKeyAutoCommit)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+
+	rdr, err := cnxn.(adbc.ConnectionGetStatistics).GetStatisticNames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"statistic"}, readAll(t, rdr))
+
+	tracing := cnxn.(adbc.OTelTracing)
+	tracing.SetTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	assert.Empty(t, tracing.GetTraceParent())
+
+	stmt, err := cnxn.NewStatement()
+	require.NoError(t, err)
+	_, err = stmt.(adbc.StatementExecuteSchema).ExecuteSchema(ctx)
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusNotImplemented, adbcErr.Code)
+}
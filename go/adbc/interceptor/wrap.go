@@ -0,0 +1,408 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Database is a database whose calls go through interceptors.
+type Database struct {
+	adbc.Database
+	options
+
+	intercept Interceptor
+}
+
+// WrapDatabase returns db with its calls, and those of the connections
+// it opens, going through interceptors in order.
+func WrapDatabase(db adbc.Database, interceptors ...Interceptor) *Database {
+	return &Database{Database: db, options: options{db}, intercept: Chain(interceptors...)}
+}
+
+// Unwrap returns the underlying database, to reach the extensions of the
+// driver.
+func (db *Database) Unwrap() adbc.Database {
+	return db.Database
+}
+
+func (db *Database) Open(ctx context.Context) (adbc.Connection, error) {
+	call := &Call{Method: MethodOpen, Options: db}
+	err := run(ctx, db.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		call.Connection, err = db.Database.Open(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wrapConnection(call.Connection, db.intercept), nil
+}
+
+// GetOptionCatalog implements adbc.OptionCatalog.
+func (db *Database) GetOptionCatalog(ctx context.Context) (array.RecordReader, error) {
+	if catalog, ok := db.Database.(adbc.OptionCatalog); ok {
+		return catalog.GetOptionCatalog(ctx)
+	}
+	return nil, errNotSupported("GetOptionCatalog")
+}
+
+// SetLogger implements adbc.DatabaseLogging. It does nothing if the
+// driver doesn't log.
+func (db *Database) SetLogger(logger *slog.Logger) {
+	if logging, ok := db.Database.(adbc.DatabaseLogging); ok {
+		logging.SetLogger(logger)
+	}
+}
+
+// InitTracing implements adbc.OTelTracingInit.
+func (db *Database) InitTracing(ctx context.Context, driverName string, driverVersion string) error {
+	if tracing, ok := db.Database.(adbc.OTelTracingInit); ok {
+		return tracing.InitTracing(ctx, driverName, driverVersion)
+	}
+	return errNotSupported("InitTracing")
+}
+
+// Connection is a connection whose calls go through interceptors.
+type Connection struct {
+	adbc.Connection
+	options
+
+	intercept Interceptor
+}
+
+// WrapConnection returns cnxn with its calls, and those of the statements
+// it creates, going through interceptors in order.
+func WrapConnection(cnxn adbc.Connection, interceptors ...Interceptor) *Connection {
+	return wrapConnection(cnxn, Chain(interceptors...))
+}
+
+func wrapConnection(cnxn adbc.Connection, intercept Interceptor) *Connection {
+	return &Connection{Connection: cnxn, options: options{cnxn}, intercept: intercept}
+}
+
+// Unwrap returns the underlying connection, to reach the extensions of
+// the driver.
+func (c *Connection) Unwrap() adbc.Connection {
+	return c.Connection
+}
+
+func (c *Connection) GetObjects(ctx context.Context, depth adbc.ObjectDepth, catalog, dbSchema, tableName, columnName *string, tableType []string) (array.RecordReader, error) {
+	call := &Call{
+		Method:     MethodGetObjects,
+		Options:    c,
+		Depth:      depth,
+		Catalog:    catalog,
+		DbSchema:   dbSchema,
+		TableName:  tableName,
+		ColumnName: columnName,
+		TableTypes: tableType,
+	}
+	err := run(ctx, c.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		call.Reader, err = c.Connection.GetObjects(ctx, call.Depth, call.Catalog, call.DbSchema,
+			call.TableName, call.ColumnName, call.TableTypes)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return call.Reader, nil
+}
+
+func (c *Connection) Commit(ctx context.Context) error {
+	return run(ctx, c.intercept, &Call{Method: MethodCommit, Options: c}, func(ctx context.Context, _ *Call) error {
+		return c.Connection.Commit(ctx)
+	})
+}
+
+func (c *Connection) Rollback(ctx context.Context) error {
+	return run(ctx, c.intercept, &Call{Method: MethodRollback, Options: c}, func(ctx context.Context, _ *Call) error {
+		return c.Connection.Rollback(ctx)
+	})
+}
+
+func (c *Connection) NewStatement() (adbc.Statement, error) {
+	stmt, err := c.Connection.NewStatement()
+	if err != nil {
+		return nil, err
+	}
+	return &Statement{Statement: stmt, options: options{stmt}, cnxn: c}, nil
+}
+
+// GetStatistics implements adbc.ConnectionGetStatistics.
+func (c *Connection) GetStatistics(ctx context.Context, catalog, dbSchema, tableName *string, approximate bool) (array.RecordReader, error) {
+	if stats, ok := c.Connection.(adbc.ConnectionGetStatistics); ok {
+		return stats.GetStatistics(ctx, catalog, dbSchema, tableName, approximate)
+	}
+	return nil, errNotSupported("GetStatistics")
+}
+
+// GetStatisticNames implements adbc.ConnectionGetStatistics.
+func (c *Connection) GetStatisticNames(ctx context.Context) (array.RecordReader, error) {
+	if stats, ok := c.Connection.(adbc.ConnectionGetStatistics); ok {
+		return stats.GetStatisticNames(ctx)
+	}
+	return nil, errNotSupported("GetStatisticNames")
+}
+
+// Cancel implements adbc.ConnectionCancel.
+func (c *Connection) Cancel() error {
+	if cancel, ok := c.Connection.(adbc.ConnectionCancel); ok {
+		return cancel.Cancel()
+	}
+	return errNotSupported("Cancel")
+}
+
+// AttachQuery implements adbc.ConnectionAttachQuery.
+func (c *Connection) AttachQuery(ctx context.Context, handle []byte) (array.RecordReader, error) {
+	if attach, ok := c.Connection.(adbc.ConnectionAttachQuery); ok {
+		return attach.AttachQuery(ctx, handle)
+	}
+	return nil, errNotSupported("AttachQuery")
+}
+
+// SetTraceParent implements adbc.OTelTracing.
+func (c *Connection) SetTraceParent(traceParent string) {
+	setTraceParent(c.Connection, traceParent)
+}
+
+// GetTraceParent implements adbc.OTelTracing.
+func (c *Connection) GetTraceParent() string {
+	return getTraceParent(c.Connection)
+}
+
+// StartSpan implements adbc.OTelTracing.
+func (c *Connection) StartSpan(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return startSpan(ctx, c.Connection, spanName, opts...)
+}
+
+// GetInitialSpanAttributes implements adbc.OTelTracing.
+func (c *Connection) GetInitialSpanAttributes() []attribute.KeyValue {
+	return getInitialSpanAttributes(c.Connection)
+}
+
+// Statement is a statement whose calls go through interceptors.
+type Statement struct {
+	adbc.Statement
+	options
+
+	cnxn *Connection
+	// query is the query set with SetSqlQuery, after interception
+	query string
+}
+
+// Unwrap returns the underlying statement, to reach the extensions of the
+// driver.
+func (s *Statement) Unwrap() adbc.Statement {
+	return s.Statement
+}
+
+func (s *Statement) SetOption(key, value string) error {
+	if err := s.Statement.SetOption(key, value); err != nil {
+		return err
+	}
+	if key == adbc.OptionKeyIngestTargetTable && value != "" {
+		s.query = ""
+	}
+	return nil
+}
+
+func (s *Statement) SetSqlQuery(query string) error {
+	// SetSqlQuery has no context, but interceptors may still need one
+	call := &Call{Method: MethodSetSqlQuery, Options: s, Query: query}
+	err := run(context.Background(), s.cnxn.intercept, call, func(_ context.Context, call *Call) error {
+		return s.Statement.SetSqlQuery(call.Query)
+	})
+	if err != nil {
+		return err
+	}
+	s.query = call.Query
+	return nil
+}
+
+func (s *Statement) SetSubstraitPlan(plan []byte) error {
+	if err := s.Statement.SetSubstraitPlan(plan); err != nil {
+		return err
+	}
+	s.query = ""
+	return nil
+}
+
+func (s *Statement) Bind(ctx context.Context, values arrow.Record) error {
+	call := &Call{Method: MethodBind, Options: s, Query: s.query, Values: values}
+	return run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) error {
+		return s.Statement.Bind(ctx, call.Values)
+	})
+}
+
+func (s *Statement) BindStream(ctx context.Context, stream array.RecordReader) error {
+	call := &Call{Method: MethodBindStream, Options: s, Query: s.query, Stream: stream}
+	return run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) error {
+		return s.Statement.BindStream(ctx, call.Stream)
+	})
+}
+
+func (s *Statement) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	call := &Call{Method: MethodExecuteQuery, Options: s, Query: s.query}
+	err := run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		call.Reader, call.RowsAffected, err = s.Statement.ExecuteQuery(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, -1, err
+	}
+	return call.Reader, call.RowsAffected, nil
+}
+
+func (s *Statement) ExecuteUpdate(ctx context.Context) (int64, error) {
+	call := &Call{Method: MethodExecuteUpdate, Options: s, Query: s.query}
+	err := run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		call.RowsAffected, err = s.Statement.ExecuteUpdate(ctx)
+		return err
+	})
+	if err != nil {
+		return -1, err
+	}
+	return call.RowsAffected, nil
+}
+
+func (s *Statement) ExecutePartitions(ctx context.Context) (*arrow.Schema, adbc.Partitions, int64, error) {
+	call := &Call{Method: MethodExecutePartitions, Options: s, Query: s.query}
+	err := run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		call.Schema, call.Partitions, call.RowsAffected, err = s.Statement.ExecutePartitions(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, adbc.Partitions{}, -1, err
+	}
+	return call.Schema, call.Partitions, call.RowsAffected, nil
+}
+
+// ExecuteSchema implements adbc.StatementExecuteSchema.
+func (s *Statement) ExecuteSchema(ctx context.Context) (*arrow.Schema, error) {
+	call := &Call{Method: MethodExecuteSchema, Options: s, Query: s.query}
+	err := run(ctx, s.cnxn.intercept, call, func(ctx context.Context, call *Call) (err error) {
+		if es, ok := s.Statement.(adbc.StatementExecuteSchema); ok {
+			call.Schema, err = es.ExecuteSchema(ctx)
+			return err
+		}
+		return errNotSupported("ExecuteSchema")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return call.Schema, nil
+}
+
+// Cancel implements adbc.StatementCancel.
+func (s *Statement) Cancel() error {
+	if cancel, ok := s.Statement.(adbc.StatementCancel); ok {
+		return cancel.Cancel()
+	}
+	return errNotSupported("Cancel")
+}
+
+// SetProgressCallback implements adbc.StatementProgress. It does nothing
+// if the driver doesn't report progress.
+func (s *Statement) SetProgressCallback(callback func(adbc.QueryProgress)) {
+	if progress, ok := s.Statement.(adbc.StatementProgress); ok {
+		progress.SetProgressCallback(callback)
+	}
+}
+
+// SubmitQuery implements adbc.StatementSubmitQuery.
+func (s *Statement) SubmitQuery(ctx context.Context) ([]byte, error) {
+	if submit, ok := s.Statement.(adbc.StatementSubmitQuery); ok {
+		return submit.SubmitQuery(ctx)
+	}
+	return nil, errNotSupported("SubmitQuery")
+}
+
+// SetTraceParent implements adbc.OTelTracing.
+func (s *Statement) SetTraceParent(traceParent string) {
+	setTraceParent(s.Statement, traceParent)
+}
+
+// GetTraceParent implements adbc.OTelTracing.
+func (s *Statement) GetTraceParent() string {
+	return getTraceParent(s.Statement)
+}
+
+// StartSpan implements adbc.OTelTracing.
+func (s *Statement) StartSpan(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return startSpan(ctx, s.Statement, spanName, opts...)
+}
+
+// GetInitialSpanAttributes implements adbc.OTelTracing.
+func (s *Statement) GetInitialSpanAttributes() []attribute.KeyValue {
+	return getInitialSpanAttributes(s.Statement)
+}
+
+// The adbc.OTelTracing methods can't fail, so without support from the
+// driver there is no trace parent and spans are those of the context.
+
+func setTraceParent(target any, traceParent string) {
+	if tracing, ok := target.(adbc.OTelTracing); ok {
+		tracing.SetTraceParent(traceParent)
+	}
+}
+
+func getTraceParent(target any) string {
+	if tracing, ok := target.(adbc.OTelTracing); ok {
+		return tracing.GetTraceParent()
+	}
+	return ""
+}
+
+func startSpan(ctx context.Context, target any, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if tracing, ok := target.(adbc.OTelTracing); ok {
+		return tracing.StartSpan(ctx, spanName, opts...)
+	}
+	return ctx, trace.SpanFromContext(ctx)
+}
+
+func getInitialSpanAttributes(target any) []attribute.KeyValue {
+	if tracing, ok := target.(adbc.OTelTracing); ok {
+		return tracing.GetInitialSpanAttributes()
+	}
+	return nil
+}
+
+var (
+	_ adbc.GetSetOptions           = (*Database)(nil)
+	_ adbc.OptionCatalog           = (*Database)(nil)
+	_ adbc.DatabaseLogging         = (*Database)(nil)
+	_ adbc.OTelTracingInit         = (*Database)(nil)
+	_ adbc.GetSetOptions           = (*Connection)(nil)
+	_ adbc.ConnectionGetStatistics = (*Connection)(nil)
+	_ adbc.ConnectionCancel        = (*Connection)(nil)
+	_ adbc.ConnectionAttachQuery   = (*Connection)(nil)
+	_ adbc.OTelTracing             = (*Connection)(nil)
+	_ adbc.GetSetOptions           = (*Statement)(nil)
+	_ adbc.StatementExecuteSchema  = (*Statement)(nil)
+	_ adbc.StatementCancel         = (*Statement)(nil)
+	_ adbc.StatementProgress       = (*Statement)(nil)
+	_ adbc.StatementSubmitQuery    = (*Statement)(nil)
+	_ adbc.OTelTracing             = (*Statement)(nil)
+)
@@ -1061,6 +1061,9 @@ func SnowflakeConnectionCancel(cnxn *C.struct_AdbcConnection, err *C.struct_Adbc
 	}
 
 	conn.cancelContext()
+	if c, ok := conn.cnxn.(adbc.ConnectionCancel); ok {
+		return C.AdbcStatusCode(errToAdbcErr(err, c.Cancel()))
+	}
 	return C.ADBC_STATUS_OK
 }
 
@@ -1491,6 +1494,9 @@ func SnowflakeStatementCancel(stmt *C.struct_AdbcStatement, err *C.struct_AdbcEr
 	}
 
 	st.cancelContext()
+	if c, ok := st.stmt.(adbc.StatementCancel); ok {
+		return C.AdbcStatusCode(errToAdbcErr(err, c.Cancel()))
+	}
 	return C.ADBC_STATUS_OK
 }
 
@@ -0,0 +1,41 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package pool provides a bounded pool of connections on top of any
+// adbc.Database, so that applications opening many short-lived
+// connections don't pay the cost of connecting to the database every
+// time.
+//
+//	p := pool.New(db, pool.Options{MaxOpen: 16, MaxIdleTime: time.Minute})
+//	defer p.Close()
+//
+//	cnxn, err := p.Get(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	// Close returns the connection to the pool
+//	defer cnxn.Close()
+//
+// Idle connections are health checked before being handed out again,
+// and are closed once they exceed the configured idle time or lifetime.
+// When a connection is returned, the autocommit, current catalog, current
+// schema and isolation level options are restored to the values it was
+// opened with, rolling back any open transaction. Connections whose state
+// cannot be restored are closed instead of being reused.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+package pool
@@ -0,0 +1,507 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// minCleanerInterval bounds how often idle connections are checked for
+// eviction in the background.
+const minCleanerInterval = time.Second
+
+// resetOptions are the connection options restored when a connection is
+// returned to the pool, in the order they are restored.
+var resetOptions = []string{
+	adbc.OptionKeyAutoCommit,
+	adbc.OptionKeyIsolationLevel,
+	adbc.OptionKeyCurrentCatalog,
+	adbc.OptionKeyCurrentDbSchema,
+}
+
+// Options configures a Pool.
+type Options struct {
+	// MaxOpen is the maximum number of open connections, in use or
+	// idle. Get blocks while this many connections are in use. Zero
+	// means no limit.
+	MaxOpen int
+	// MaxIdle is the maximum number of idle connections kept for reuse.
+	// Zero means the same as MaxOpen.
+	MaxIdle int
+	// MaxIdleTime is how long a connection may stay idle before it is
+	// closed. Zero means no limit.
+	MaxIdleTime time.Duration
+	// MaxLifetime is how long a connection may be reused after it was
+	// opened. Zero means no limit.
+	MaxLifetime time.Duration
+	// HealthCheck is called before an idle connection is handed out
+	// again. Connections failing it are closed. If nil, the connection
+	// must be able to answer GetInfo for the vendor name.
+	HealthCheck func(ctx context.Context, cnxn adbc.Connection) error
+}
+
+// Stats are metrics about the connections of a Pool.
+type Stats struct {
+	// MaxOpen is the maximum number of open connections, or zero if
+	// unlimited.
+	MaxOpen int
+	// Open is the number of open connections, in use or idle.
+	Open int
+	// InUse is the number of connections handed out by Get and not
+	// yet returned.
+	InUse int
+	// Idle is the number of connections waiting to be reused.
+	Idle int
+
+	// WaitCount is the number of calls to Get that had to wait for a
+	// connection to be returned.
+	WaitCount int64
+	// WaitDuration is the total time spent waiting in Get.
+	WaitDuration time.Duration
+	// Reused is the number of calls to Get served by an idle
+	// connection rather than a new one.
+	Reused int64
+
+	// MaxIdleClosed is the number of connections closed because there
+	// were already MaxIdle idle connections.
+	MaxIdleClosed int64
+	// MaxIdleTimeClosed is the number of connections closed because
+	// they were idle for MaxIdleTime.
+	MaxIdleTimeClosed int64
+	// MaxLifetimeClosed is the number of connections closed because
+	// they were open for MaxLifetime.
+	MaxLifetimeClosed int64
+	// HealthCheckFailed is the number of idle connections closed
+	// because they failed the health check.
+	HealthCheckFailed int64
+	// ResetFailed is the number of returned connections closed because
+	// their state could not be restored.
+	ResetFailed int64
+}
+
+// Pool is a bounded pool of connections to an adbc.Database. It is safe
+// for concurrent use.
+type Pool struct {
+	db      adbc.Database
+	opts    Options
+	maxIdle int
+	now     func() time.Time
+
+	// sem holds a token for every connection in use, or is nil if the
+	// number of open connections is unlimited.
+	sem  chan struct{}
+	done chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn // least recently returned first
+	open   int
+	closed bool
+	stats  Stats
+}
+
+// conn is a connection owned by a pool.
+type conn struct {
+	adbc.Connection
+
+	createdAt  time.Time
+	returnedAt time.Time
+	// initial holds the values of resetOptions when the connection was
+	// opened, for the options the driver could report.
+	initial map[string]string
+}
+
+// New creates a pool of connections opened from db. The pool doesn't
+// take ownership of db, which must outlive it.
+func New(db adbc.Database, opts Options) *Pool {
+	p := &Pool{
+		db:      db,
+		opts:    opts,
+		maxIdle: opts.MaxIdle,
+		now:     time.Now,
+		done:    make(chan struct{}),
+	}
+	if p.maxIdle <= 0 {
+		p.maxIdle = opts.MaxOpen
+	}
+	if opts.MaxOpen > 0 {
+		p.sem = make(chan struct{}, opts.MaxOpen)
+	}
+	if p.opts.HealthCheck == nil {
+		p.opts.HealthCheck = defaultHealthCheck
+	}
+
+	if interval := cleanerInterval(opts); interval > 0 {
+		go p.cleaner(interval)
+	}
+	return p
+}
+
+// Get returns an idle connection from the pool, or opens a new one. If
+// MaxOpen connections are already in use, it waits until one is returned
+// or ctx is done.
+func (p *Pool) Get(ctx context.Context) (*Conn, error) {
+	if err := p.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	for {
+		c, err := p.takeIdle()
+		if err != nil {
+			p.release()
+			return nil, err
+		}
+		if c == nil {
+			break
+		}
+
+		if err := p.opts.HealthCheck(ctx, c.Connection); err != nil {
+			p.discard(c, &p.stats.HealthCheckFailed)
+			continue
+		}
+		p.mu.Lock()
+		p.stats.Reused++
+		p.mu.Unlock()
+		return &Conn{Connection: c.Connection, pool: p, c: c}, nil
+	}
+
+	p.mu.Lock()
+	p.open++
+	p.mu.Unlock()
+
+	cnxn, err := p.db.Open(ctx)
+	if err != nil {
+		p.mu.Lock()
+		p.open--
+		p.mu.Unlock()
+		p.release()
+		return nil, err
+	}
+
+	c := &conn{Connection: cnxn, createdAt: p.now(), initial: snapshot(cnxn)}
+	return &Conn{Connection: cnxn, pool: p, c: c}, nil
+}
+
+// Stats returns the current metrics of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.MaxOpen = p.opts.MaxOpen
+	stats.Open = p.open
+	stats.Idle = len(p.idle)
+	stats.InUse = p.open - len(p.idle)
+	return stats
+}
+
+// Close closes the idle connections and stops handing out connections.
+// Connections in use are closed when they are returned.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return adbc.Error{
+			Msg:  "[pool] pool is already closed",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	p.closed = true
+	close(p.done)
+	idle := p.idle
+	p.idle = nil
+	p.open -= len(idle)
+	p.mu.Unlock()
+
+	var err error
+	for _, c := range idle {
+		err = errors.Join(err, c.Close())
+	}
+	return err
+}
+
+func (p *Pool) acquire(ctx context.Context) error {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return errPoolClosed()
+	}
+	if p.sem == nil {
+		return nil
+	}
+
+	select {
+	case p.sem <- struct{}{}:
+		return nil
+	default:
+	}
+
+	start := time.Now()
+	select {
+	case p.sem <- struct{}{}:
+		p.mu.Lock()
+		p.stats.WaitCount++
+		p.stats.WaitDuration += time.Since(start)
+		p.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return errPoolClosed()
+	}
+}
+
+func (p *Pool) release() {
+	if p.sem != nil {
+		<-p.sem
+	}
+}
+
+// takeIdle pops the most recently returned idle connection, closing any
+// expired ones it finds first. It returns nil if there is none.
+func (p *Pool) takeIdle() (*conn, error) {
+	var expired []*conn
+	defer func() {
+		for _, c := range expired {
+			_ = c.Close()
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil, errPoolClosed()
+	}
+
+	now := p.now()
+	for len(p.idle) > 0 {
+		c := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		if counter := p.expired(c, now); counter != nil {
+			*counter++
+			p.open--
+			expired = append(expired, c)
+			continue
+		}
+		return c, nil
+	}
+	return nil, nil
+}
+
+// put returns a connection to the pool, or closes it if it can't be
+// reused.
+func (p *Pool) put(c *conn) error {
+	defer p.release()
+
+	resetErr := c.reset(context.Background())
+
+	p.mu.Lock()
+	now := p.now()
+	var counter *int64
+	switch {
+	case resetErr != nil:
+		counter = &p.stats.ResetFailed
+	case p.closed:
+	case p.opts.MaxLifetime > 0 && now.Sub(c.createdAt) >= p.opts.MaxLifetime:
+		counter = &p.stats.MaxLifetimeClosed
+	case p.maxIdle > 0 && len(p.idle) >= p.maxIdle:
+		counter = &p.stats.MaxIdleClosed
+	default:
+		c.returnedAt = now
+		p.idle = append(p.idle, c)
+		p.mu.Unlock()
+		return nil
+	}
+	if counter != nil {
+		*counter++
+	}
+	p.open--
+	p.mu.Unlock()
+
+	return c.Close()
+}
+
+// discard closes a connection taken from the idle list.
+func (p *Pool) discard(c *conn, counter *int64) {
+	p.mu.Lock()
+	*counter++
+	p.open--
+	p.mu.Unlock()
+	_ = c.Close()
+}
+
+// expired returns the counter of the limit an idle connection exceeds,
+// or nil if it may be reused. p.mu must be held.
+func (p *Pool) expired(c *conn, now time.Time) *int64 {
+	switch {
+	case p.opts.MaxLifetime > 0 && now.Sub(c.createdAt) >= p.opts.MaxLifetime:
+		return &p.stats.MaxLifetimeClosed
+	case p.opts.MaxIdleTime > 0 && now.Sub(c.returnedAt) >= p.opts.MaxIdleTime:
+		return &p.stats.MaxIdleTimeClosed
+	}
+	return nil
+}
+
+// evict closes the idle connections that exceeded MaxIdleTime or
+// MaxLifetime.
+func (p *Pool) evict() {
+	var expired []*conn
+
+	p.mu.Lock()
+	now := p.now()
+	idle := p.idle[:0]
+	for _, c := range p.idle {
+		if counter := p.expired(c, now); counter != nil {
+			*counter++
+			p.open--
+			expired = append(expired, c)
+		} else {
+			idle = append(idle, c)
+		}
+	}
+	clear(p.idle[len(idle):])
+	p.idle = idle
+	p.mu.Unlock()
+
+	for _, c := range expired {
+		_ = c.Close()
+	}
+}
+
+func (p *Pool) cleaner(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.evict()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func cleanerInterval(opts Options) time.Duration {
+	interval := opts.MaxIdleTime
+	if interval <= 0 || (opts.MaxLifetime > 0 && opts.MaxLifetime < interval) {
+		interval = opts.MaxLifetime
+	}
+	if interval > 0 && interval < minCleanerInterval {
+		interval = minCleanerInterval
+	}
+	return interval
+}
+
+// snapshot reads the options restored when a connection is returned.
+func snapshot(cnxn adbc.Connection) map[string]string {
+	opts, ok := cnxn.(adbc.GetSetOptions)
+	if !ok {
+		return nil
+	}
+
+	initial := make(map[string]string, len(resetOptions))
+	for _, key := range resetOptions {
+		if val, err := opts.GetOption(key); err == nil {
+			initial[key] = val
+		}
+	}
+	return initial
+}
+
+// reset rolls back any open transaction and restores the options the
+// connection was opened with.
+func (c *conn) reset(ctx context.Context) error {
+	if len(c.initial) == 0 {
+		return nil
+	}
+	opts := c.Connection.(adbc.GetSetOptions)
+
+	if autocommit, err := opts.GetOption(adbc.OptionKeyAutoCommit); err == nil && autocommit == adbc.OptionValueDisabled {
+		if err := c.Rollback(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range resetOptions {
+		initial, ok := c.initial[key]
+		if !ok {
+			continue
+		}
+		if current, err := opts.GetOption(key); err == nil && current == initial {
+			continue
+		}
+		if err := opts.SetOption(key, initial); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func defaultHealthCheck(ctx context.Context, cnxn adbc.Connection) error {
+	rdr, err := cnxn.GetInfo(ctx, []adbc.InfoCode{adbc.InfoVendorName})
+	if err != nil {
+		return err
+	}
+	defer rdr.Release()
+
+	for rdr.Next() {
+	}
+	return rdr.Err()
+}
+
+func errPoolClosed() error {
+	return adbc.Error{
+		Msg:  "[pool] pool is closed",
+		Code: adbc.StatusInvalidState,
+	}
+}
+
+// Conn is a connection borrowed from a Pool. Closing it returns it to the
+// pool instead of closing the underlying connection.
+type Conn struct {
+	adbc.Connection
+
+	pool     *Pool
+	c        *conn
+	returned atomic.Bool
+}
+
+// Close returns the connection to the pool. The connection must not be
+// used afterwards.
+func (c *Conn) Close() error {
+	if !c.returned.CompareAndSwap(false, true) {
+		return adbc.Error{
+			Msg:  "[pool] connection was already returned to the pool",
+			Code: adbc.StatusInvalidState,
+		}
+	}
+	return c.pool.put(c.c)
+}
+
+// Unwrap returns the underlying connection, for example to check
+// whether it implements optional interfaces like adbc.GetSetOptions. It
+// must not be closed directly.
+func (c *Conn) Unwrap() adbc.Connection {
+	return c.Connection
+}
@@ -0,0 +1,279 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDatabase struct {
+	adbc.Database
+
+	mu     sync.Mutex
+	opened []*mockConnection
+}
+
+func (db *mockDatabase) Open(ctx context.Context) (adbc.Connection, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	cnxn := &mockConnection{options: map[string]string{
+		adbc.OptionKeyAutoCommit:      adbc.OptionValueEnabled,
+		adbc.OptionKeyCurrentCatalog:  "catalog",
+		adbc.OptionKeyCurrentDbSchema: "schema",
+	}}
+	db.opened = append(db.opened, cnxn)
+	return cnxn, nil
+}
+
+type mockConnection struct {
+	adbc.Connection
+	adbc.GetSetOptions
+
+	options   map[string]string
+	closed    bool
+	rollbacks int
+	failSet   bool
+	unhealthy bool
+}
+
+func (c *mockConnection) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *mockConnection) Rollback(context.Context) error {
+	c.rollbacks++
+	return nil
+}
+
+func (c *mockConnection) GetOption(key string) (string, error) {
+	if val, ok := c.options[key]; ok {
+		return val, nil
+	}
+	return "", adbc.Error{Code: adbc.StatusNotFound}
+}
+
+func (c *mockConnection) SetOption(key, value string) error {
+	if c.failSet {
+		return adbc.Error{Code: adbc.StatusIO}
+	}
+	c.options[key] = value
+	return nil
+}
+
+func mockHealthCheck(ctx context.Context, cnxn adbc.Connection) error {
+	if cnxn.(*mockConnection).unhealthy {
+		return errors.New("unhealthy")
+	}
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestPool(t *testing.T, opts Options) (*Pool, *mockDatabase, *fakeClock) {
+	db := &mockDatabase{}
+	if opts.HealthCheck == nil {
+		opts.HealthCheck = mockHealthCheck
+	}
+	p := New(db, opts)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p.now = clock.Now
+	t.Cleanup(func() { _ = p.Close() })
+	return p, db, clock
+}
+
+func TestReuse(t *testing.T) {
+	ctx := context.Background()
+	p, db, _ := newTestPool(t, Options{MaxOpen: 2})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	c2, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{MaxOpen: 2, Open: 2, InUse: 2}, p.Stats())
+
+	require.NoError(t, c1.Close())
+	assert.Error(t, c1.Close())
+	c3, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Same(t, db.opened[0], c3.Unwrap())
+
+	require.NoError(t, c2.Close())
+	require.NoError(t, c3.Close())
+	assert.Len(t, db.opened, 2)
+	assert.Equal(t, Stats{MaxOpen: 2, Open: 2, Idle: 2, Reused: 1}, p.Stats())
+}
+
+func TestMaxOpen(t *testing.T) {
+	p, _, _ := newTestPool(t, Options{MaxOpen: 1})
+
+	c1, err := p.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Get(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = c1.Close()
+	}()
+	c2, err := p.Get(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, c1.Unwrap(), c2.Unwrap())
+	require.NoError(t, c2.Close())
+
+	stats := p.Stats()
+	assert.EqualValues(t, 1, stats.WaitCount)
+	assert.Positive(t, stats.WaitDuration)
+}
+
+func TestMaxIdle(t *testing.T) {
+	ctx := context.Background()
+	p, db, _ := newTestPool(t, Options{MaxOpen: 2, MaxIdle: 1})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	c2, err := p.Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c1.Close())
+	require.NoError(t, c2.Close())
+
+	assert.False(t, db.opened[0].closed)
+	assert.True(t, db.opened[1].closed)
+	assert.Equal(t, Stats{MaxOpen: 2, Open: 1, Idle: 1, MaxIdleClosed: 1}, p.Stats())
+}
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	p, db, _ := newTestPool(t, Options{})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c1.Close())
+	db.opened[0].unhealthy = true
+
+	c2, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Same(t, db.opened[1], c2.Unwrap())
+	assert.True(t, db.opened[0].closed)
+	assert.EqualValues(t, 1, p.Stats().HealthCheckFailed)
+	require.NoError(t, c2.Close())
+}
+
+func TestEviction(t *testing.T) {
+	ctx := context.Background()
+	p, db, clock := newTestPool(t, Options{MaxIdleTime: time.Minute, MaxLifetime: time.Hour})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	c2, err := p.Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, c1.Close())
+
+	// idle for too long
+	clock.now = clock.now.Add(2 * time.Minute)
+	require.NoError(t, c2.Close())
+	p.evict()
+	assert.True(t, db.opened[0].closed)
+	assert.False(t, db.opened[1].closed)
+
+	// open for too long
+	c2, err = p.Get(ctx)
+	require.NoError(t, err)
+	clock.now = clock.now.Add(time.Hour)
+	require.NoError(t, c2.Close())
+	assert.True(t, db.opened[1].closed)
+
+	stats := p.Stats()
+	assert.Zero(t, stats.Open)
+	assert.EqualValues(t, 1, stats.MaxIdleTimeClosed)
+	assert.EqualValues(t, 1, stats.MaxLifetimeClosed)
+}
+
+func TestReset(t *testing.T) {
+	ctx := context.Background()
+	p, db, _ := newTestPool(t, Options{})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+	opts := c1.Unwrap().(adbc.GetSetOptions)
+	require.NoError(t, opts.SetOption(adbc.OptionKeyAutoCommit, adbc.OptionValueDisabled))
+	require.NoError(t, opts.SetOption(adbc.OptionKeyCurrentCatalog, "other"))
+	// not reported when the connection was opened, so not restored
+	require.NoError(t, opts.SetOption(adbc.OptionKeyIsolationLevel, string(adbc.LevelSerializable)))
+	require.NoError(t, c1.Close())
+
+	cnxn := db.opened[0]
+	assert.Equal(t, 1, cnxn.rollbacks)
+	assert.Equal(t, map[string]string{
+		adbc.OptionKeyAutoCommit:      adbc.OptionValueEnabled,
+		adbc.OptionKeyCurrentCatalog:  "catalog",
+		adbc.OptionKeyCurrentDbSchema: "schema",
+		adbc.OptionKeyIsolationLevel:  string(adbc.LevelSerializable),
+	}, cnxn.options)
+
+	// state that can't be restored isn't handed out again
+	c1, err = p.Get(ctx)
+	require.NoError(t, err)
+	require.NoError(t, opts.SetOption(adbc.OptionKeyCurrentDbSchema, "other"))
+	cnxn.failSet = true
+	require.NoError(t, c1.Close())
+	assert.True(t, cnxn.closed)
+	assert.EqualValues(t, 1, p.Stats().ResetFailed)
+}
+
+func TestClose(t *testing.T) {
+	ctx := context.Background()
+	db := &mockDatabase{}
+	p := New(db, Options{MaxOpen: 1, HealthCheck: mockHealthCheck})
+
+	c1, err := p.Get(ctx)
+	require.NoError(t, err)
+
+	waiting := make(chan error)
+	go func() {
+		_, err := p.Get(ctx)
+		waiting <- err
+	}()
+
+	require.NoError(t, p.Close())
+	assert.ErrorContains(t, <-waiting, "pool is closed")
+	_, err = p.Get(ctx)
+	assert.ErrorContains(t, err, "pool is closed")
+	assert.Error(t, p.Close())
+
+	// connections in use are closed when returned
+	assert.False(t, db.opened[0].closed)
+	require.NoError(t, c1.Close())
+	assert.True(t, db.opened[0].closed)
+	assert.Zero(t, p.Stats().Open)
+}
@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package profile builds the options passed to adbc.Driver.NewDatabase
+// from connection profiles and URIs, so that applications don't need to
+// spell out the option keys of each driver.
+//
+// A URI names the driver by its scheme, and each driver registers a
+// parser for its schemes with RegisterURIParser when its package is
+// imported:
+//
+//	opts, err := profile.ParseURI("snowflake://user@account/db/schema?warehouse=wh")
+//
+// A profile is a TOML or YAML file with an optional URI and explicit
+// options, which take precedence over the ones derived from the URI.
+// Dotted option keys may be written as nested tables. A profile may
+// extend another one, to share the options common to several
+// environments:
+//
+//	# prod.toml
+//	extends = "common.toml"
+//	uri = "snowflake://svc_user@myaccount/analytics/public"
+//
+//	[options]
+//	password = "${env:SNOWFLAKE_PASSWORD}"
+//	adbc.snowflake.sql.warehouse = "PROD_WH"
+//
+// Values of the URI and of the options may reference secrets instead of
+// containing them: ${env:NAME} is replaced by the environment variable
+// NAME, and ${file:PATH} by the contents of the file, without trailing
+// newlines. Relative paths are resolved from the directory of the
+// profile file that sets the value, which may be a profile it extends.
+// Write $$ for a literal $.
+//
+//	prof, err := profile.Load("prod.toml")
+//	if err != nil {
+//		return err
+//	}
+//	opts, err := prof.DatabaseOptions()
+//	if err != nil {
+//		return err
+//	}
+//	db, err := drv.NewDatabase(opts)
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+package profile
@@ -0,0 +1,289 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/apache/arrow-adbc/go/adbc"
+	"gopkg.in/yaml.v3"
+)
+
+// Profile is a connection profile, as loaded by Load.
+type Profile struct {
+	// Driver optionally names the driver the profile is meant for.
+	Driver string
+	// URI is converted into options by ParseURI.
+	URI string
+	// Options are added to the ones derived from URI, replacing them.
+	Options map[string]string
+
+	// uriDir and optionDirs are the directories of the files that set the
+	// URI and each option, which relative file references in them are
+	// resolved from.
+	uriDir     string
+	optionDirs map[string]string
+}
+
+// file is the layout of a profile file.
+type file struct {
+	Driver  string         `toml:"driver" yaml:"driver"`
+	URI     string         `toml:"uri" yaml:"uri"`
+	Extends string         `toml:"extends" yaml:"extends"`
+	Options map[string]any `toml:"options" yaml:"options"`
+}
+
+// Load reads a profile from a TOML (.toml) or YAML (.yaml, .yml) file.
+// If the profile extends another one, given by a path relative to the
+// file, the other profile is loaded first and this one overrides its
+// driver, its URI and any of its options.
+//
+// References to secrets are kept as is; they are expanded by
+// DatabaseOptions.
+func Load(path string) (*Profile, error) {
+	return load(path, nil)
+}
+
+func load(path string, seen []string) (*Profile, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, errorf(adbc.StatusInvalidArgument, "%s", err)
+	}
+	for _, p := range seen {
+		if p == path {
+			return nil, errorf(adbc.StatusInvalidArgument, "profile %s extends itself, directly or indirectly", path)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errorf(adbc.StatusIO, "%s", err)
+	}
+	f, err := decode(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	prof := &Profile{Options: map[string]string{}, optionDirs: map[string]string{}}
+	if f.Extends != "" {
+		base := f.Extends
+		if !filepath.IsAbs(base) {
+			base = filepath.Join(filepath.Dir(path), base)
+		}
+		if prof, err = load(base, append(seen, path)); err != nil {
+			return nil, err
+		}
+	}
+
+	dir := filepath.Dir(path)
+	if f.Driver != "" {
+		prof.Driver = f.Driver
+	}
+	if f.URI != "" {
+		prof.URI = f.URI
+		prof.uriDir = dir
+	}
+	options := map[string]string{}
+	if err := flattenOptions(options, "", f.Options); err != nil {
+		return nil, errorf(adbc.StatusInvalidArgument, "%s: %s", path, err)
+	}
+	for key, value := range options {
+		prof.Options[key] = value
+		prof.optionDirs[key] = dir
+	}
+	return prof, nil
+}
+
+func decode(path string, data []byte) (*file, error) {
+	var f file
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".toml":
+		md, err := toml.Decode(string(data), &f)
+		if err != nil {
+			return nil, errorf(adbc.StatusInvalidArgument, "%s: %s", path, err)
+		}
+		// options are decoded into a map[string]any, but the decoder
+		// may still report some of them as undecoded
+		for _, key := range md.Undecoded() {
+			if key[0] != "options" {
+				return nil, errorf(adbc.StatusInvalidArgument, "%s: unknown key '%s'", path, key)
+			}
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil {
+			return nil, errorf(adbc.StatusInvalidArgument, "%s: %s", path, err)
+		}
+	default:
+		return nil, errorf(adbc.StatusInvalidArgument, "%s: unknown profile format '%s', expected .toml, .yaml or .yml", path, ext)
+	}
+	return &f, nil
+}
+
+// flattenOptions adds options to dst, joining the keys of nested tables
+// with dots and formatting scalar values as option strings.
+func flattenOptions(dst map[string]string, prefix string, options map[string]any) error {
+	for key, value := range options {
+		key = prefix + key
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flattenOptions(dst, key+".", value); err != nil {
+				return err
+			}
+		case string:
+			dst[key] = value
+		case bool:
+			dst[key] = strconv.FormatBool(value)
+		case int:
+			dst[key] = strconv.Itoa(value)
+		case int64:
+			dst[key] = strconv.FormatInt(value, 10)
+		case float64:
+			dst[key] = strconv.FormatFloat(value, 'g', -1, 64)
+		case nil:
+			dst[key] = ""
+		default:
+			return fmt.Errorf("option '%s': unsupported value of type %T", key, value)
+		}
+	}
+	return nil
+}
+
+// DatabaseOptions returns the options to pass to NewDatabase: the options
+// parsed from the URI, if any, overridden by the explicit options, with
+// the references to secrets expanded.
+func (p *Profile) DatabaseOptions() (map[string]string, error) {
+	opts := map[string]string{}
+	if p.URI != "" {
+		uri, err := expand(p.URI, p.uriDir, escapeURIComponent)
+		if err != nil {
+			return nil, errorf(adbc.StatusInvalidArgument, "uri: %s", err)
+		}
+		if opts, err = ParseURI(uri); err != nil {
+			return nil, err
+		}
+		opts = maps.Clone(opts)
+	}
+
+	for key, value := range p.Options {
+		value, err := expand(value, p.optionDirs[key], nil)
+		if err != nil {
+			return nil, errorf(adbc.StatusInvalidArgument, "option '%s': %s", key, err)
+		}
+		opts[key] = value
+	}
+	return opts, nil
+}
+
+// expand replaces the ${env:NAME} and ${file:PATH} references in s. If
+// escape is not nil, the values are escaped with it.
+func expand(s, dir string, escape func(string) string) (string, error) {
+	var buf strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 || i == len(s)-1 {
+			buf.WriteString(s)
+			return buf.String(), nil
+		}
+		buf.WriteString(s[:i])
+		s = s[i:]
+
+		switch s[1] {
+		case '$':
+			buf.WriteByte('$')
+			s = s[2:]
+			continue
+		case '{':
+		default:
+			buf.WriteByte('$')
+			s = s[1:]
+			continue
+		}
+
+		ref, rest, ok := strings.Cut(s[2:], "}")
+		if !ok {
+			return "", fmt.Errorf("unterminated reference '%s'", s)
+		}
+		value, err := resolve(ref, dir)
+		if err != nil {
+			return "", err
+		}
+		if escape != nil {
+			value = escape(value)
+		}
+		buf.WriteString(value)
+		s = rest
+	}
+}
+
+func resolve(ref, dir string) (string, error) {
+	kind, name, ok := strings.Cut(ref, ":")
+	if !ok || name == "" {
+		kind = ""
+	}
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable '%s' is not set", name)
+		}
+		return value, nil
+	case "file":
+		if !filepath.IsAbs(name) && dir != "" {
+			name = filepath.Join(dir, name)
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+	return "", fmt.Errorf("unknown reference '${%s}', expected ${env:NAME} or ${file:PATH}", ref)
+}
+
+// escapeURIComponent percent-encodes every byte of s except the
+// unreserved characters of RFC 3986, so that secrets can be substituted
+// anywhere in a URI.
+func escapeURIComponent(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			buf.WriteByte(c)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+func errorf(code adbc.Status, format string, args ...any) error {
+	return adbc.Error{
+		Msg:  "[profile] " + fmt.Sprintf(format, args...),
+		Code: code,
+	}
+}
@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package profile
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+)
+
+// URIParser converts a URI into the options of a database. The URI has
+// already been parsed, and its scheme is one the parser was registered
+// for.
+type URIParser func(uri *url.URL) (map[string]string, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]URIParser{}
+)
+
+// RegisterURIParser makes a parser available for URIs with the given
+// scheme. Schemes are case-insensitive. Drivers call it from init; it
+// panics if the scheme is already registered or parser is nil.
+func RegisterURIParser(scheme string, parser URIParser) {
+	if parser == nil {
+		panic("profile: RegisterURIParser parser is nil")
+	}
+	scheme = strings.ToLower(scheme)
+
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	if _, dup := parsers[scheme]; dup {
+		panic("profile: RegisterURIParser called twice for scheme " + scheme)
+	}
+	parsers[scheme] = parser
+}
+
+// Schemes returns the sorted list of schemes with a registered parser.
+func Schemes() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	schemes := make([]string, 0, len(parsers))
+	for scheme := range parsers {
+		schemes = append(schemes, scheme)
+	}
+	slices.Sort(schemes)
+	return schemes
+}
+
+// HasURIParser reports whether a parser is registered for the scheme.
+func HasURIParser(scheme string) bool {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	_, ok := parsers[strings.ToLower(scheme)]
+	return ok
+}
+
+// ParseURI converts a URI into database options, with the parser
+// registered for its scheme. The package of the driver must be imported
+// for its parser to be registered.
+func ParseURI(uri string) (map[string]string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, adbc.Error{
+			Msg:  fmt.Sprintf("[profile] invalid URI: %s", err),
+			Code: adbc.StatusInvalidArgument,
+		}
+	}
+	if u.Scheme == "" {
+		return nil, adbc.Error{
+			Msg:  "[profile] URI has no scheme",
+			Code: adbc.StatusInvalidArgument,
+		}
+	}
+
+	parsersMu.RLock()
+	parser, ok := parsers[strings.ToLower(u.Scheme)]
+	parsersMu.RUnlock()
+	if !ok {
+		return nil, adbc.Error{
+			Msg:  fmt.Sprintf("[profile] no URI parser registered for scheme '%s' (is the driver imported?)", u.Scheme),
+			Code: adbc.StatusNotFound,
+		}
+	}
+	return parser(u)
+}
+
+// QueryOptions adds the parameters of the query of a URI to opts, for use
+// by URIParser implementations. Parameters named in aliases are renamed,
+// and the others are renamed by rename, if not nil. A parameter given
+// more than once is an error.
+func QueryOptions(opts map[string]string, query url.Values, aliases map[string]string, rename func(string) string) error {
+	for key, values := range query {
+		if len(values) != 1 {
+			return adbc.Error{
+				Msg:  fmt.Sprintf("[profile] URI parameter '%s' given %d times", key, len(values)),
+				Code: adbc.StatusInvalidArgument,
+			}
+		}
+		name, ok := aliases[key]
+		if !ok {
+			name = key
+			if rename != nil {
+				name = rename(key)
+			}
+		}
+		opts[name] = values[0]
+	}
+	return nil
+}
+
+// UserOptions adds the user and password of a URI, if any, to opts as
+// adbc.OptionKeyUsername and adbc.OptionKeyPassword, for use by URIParser
+// implementations.
+func UserOptions(opts map[string]string, user *url.Userinfo) {
+	if user == nil {
+		return
+	}
+	if name := user.Username(); name != "" {
+		opts[adbc.OptionKeyUsername] = name
+	}
+	if password, ok := user.Password(); ok {
+		opts[adbc.OptionKeyPassword] = password
+	}
+}
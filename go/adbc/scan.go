@@ -0,0 +1,783 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package adbc
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal"
+)
+
+// ScanRows reads every row of rdr into a value of type T. It does not
+// release rdr.
+//
+// If T is a struct, or a pointer to a struct, the columns of rdr are
+// mapped onto its fields by name, see StructTagKey. Names are matched
+// exactly first, then case-insensitively. Every field must match a
+// column, but columns without a field are ignored. Otherwise, rdr must
+// have a single column, which is scanned into T.
+//
+// Values are converted as follows:
+//
+//   - booleans, integers and floats into Go booleans, integers and
+//     floats, failing if the value would overflow
+//   - strings and binaries into string or []byte
+//   - decimals into the decimal.DecimalXX type of the same width, a
+//     string, or a float
+//   - timestamps and dates into time.Time, in the time zone of the
+//     timestamp type, or UTC
+//   - times of day and durations into time.Duration
+//   - lists into slices, or arrays of the same length
+//   - structs into structs, or maps with string keys
+//   - maps into maps
+//   - dictionaries and extension types as their values and storage
+//   - unions as the value of their active member
+//
+// Any Arrow value can also be scanned into an empty interface, which
+// receives the natural Go type from the list above, with decimals as
+// strings, lists as []any, structs as map[string]any and maps as
+// map[any]any.
+//
+// NULL can only be scanned into pointers, interfaces, slices and maps,
+// which are set to nil.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+func ScanRows[T any](rdr array.RecordReader) ([]T, error) {
+	sc, err := newRowScanner(reflect.TypeFor[T](), rdr.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		rows   []T
+		offset int64
+	)
+	for rdr.Next() {
+		rec := rdr.Record()
+		if rows, err = scanRecord(sc, rec, offset, rows); err != nil {
+			return nil, err
+		}
+		offset += rec.NumRows()
+	}
+	if err := rdr.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ScanRecord appends the rows of rec to dst, as described by ScanRows.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+func ScanRecord[T any](rec arrow.Record, dst []T) ([]T, error) {
+	sc, err := newRowScanner(reflect.TypeFor[T](), rec.Schema())
+	if err != nil {
+		return nil, err
+	}
+	return scanRecord(sc, rec, 0, dst)
+}
+
+func scanRecord[T any](sc *rowScanner, rec arrow.Record, offset int64, dst []T) ([]T, error) {
+	n := int(rec.NumRows())
+	start := len(dst)
+	dst = append(dst, make([]T, n)...)
+	for i := 0; i < n; i++ {
+		if err := sc.scan(rec, i, reflect.ValueOf(&dst[start+i]).Elem()); err != nil {
+			err.Row = offset + int64(i)
+			return nil, err.adbcError()
+		}
+	}
+	return dst, nil
+}
+
+// decodeFunc stores the value at index i of arr into v.
+type decodeFunc func(arr arrow.Array, i int, v reflect.Value) error
+
+type columnDecoder struct {
+	column int
+	name   string
+	// index of the struct field, or nil to decode into the row itself
+	index []int
+	dec   decodeFunc
+}
+
+type rowScanner struct {
+	ptr     bool
+	columns []columnDecoder
+}
+
+// scanError locates an error in the result set.
+type scanError struct {
+	Row    int64
+	Column string
+	Err    error
+}
+
+func (e *scanError) adbcError() error {
+	msg := fmt.Sprintf("[adbc] ScanRows: column %q: %s", e.Column, e.Err)
+	if e.Row >= 0 {
+		msg = fmt.Sprintf("[adbc] ScanRows: row %d, column %q: %s", e.Row, e.Column, e.Err)
+	}
+	return Error{Msg: msg, Code: StatusInvalidArgument}
+}
+
+func newRowScanner(t reflect.Type, schema *arrow.Schema) (*rowScanner, error) {
+	sc := &rowScanner{}
+	row := t
+	if row.Kind() == reflect.Pointer {
+		sc.ptr = true
+		row = row.Elem()
+	}
+
+	if !isRowStruct(row) {
+		if schema.NumFields() != 1 {
+			return nil, Error{
+				Msg:  fmt.Sprintf("[adbc] ScanRows: cannot scan %d columns into %s", schema.NumFields(), t),
+				Code: StatusInvalidArgument,
+			}
+		}
+		dec, err := newDecoder(schema.Field(0).Type, t)
+		if err != nil {
+			return nil, (&scanError{Row: -1, Column: schema.Field(0).Name, Err: err}).adbcError()
+		}
+		sc.ptr = false
+		sc.columns = []columnDecoder{{column: 0, name: schema.Field(0).Name, dec: dec}}
+		return sc, nil
+	}
+
+	columns, err := newFieldDecoders(row, schema.Fields())
+	if err != nil {
+		return nil, err.adbcError()
+	}
+	sc.columns = columns
+	return sc, nil
+}
+
+func (sc *rowScanner) scan(rec arrow.Record, i int, v reflect.Value) *scanError {
+	if sc.ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	for _, col := range sc.columns {
+		dest := v
+		if col.index != nil {
+			dest = v.FieldByIndex(col.index)
+		}
+		if err := col.dec(rec.Column(col.column), i, dest); err != nil {
+			return &scanError{Column: col.name, Err: err}
+		}
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeFor[time.Time]()
+	durationType = reflect.TypeFor[time.Duration]()
+	anyType      = reflect.TypeFor[any]()
+)
+
+// isRowStruct reports whether t is a struct whose fields hold the
+// columns of a row, rather than a single value like time.Time.
+func isRowStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	switch t {
+	case reflect.TypeFor[decimal.Decimal128](), reflect.TypeFor[decimal.Decimal256]():
+		return false
+	}
+	return true
+}
+
+// newFieldDecoders maps the fields of a struct onto Arrow fields.
+func newFieldDecoders(t reflect.Type, fields []arrow.Field) ([]columnDecoder, *scanError) {
+	var columns []columnDecoder
+	for _, f := range structFields(t) {
+		col := -1
+		for j, field := range fields {
+			if field.Name == f.name {
+				col = j
+				break
+			}
+		}
+		if col < 0 {
+			for j, field := range fields {
+				if strings.EqualFold(field.Name, f.name) {
+					col = j
+					break
+				}
+			}
+		}
+		if col < 0 {
+			return nil, &scanError{Row: -1, Column: f.name, Err: fmt.Errorf("no column for field %s of %s", f.name, t)}
+		}
+
+		dec, err := newDecoder(fields[col].Type, f.typ)
+		if err != nil {
+			return nil, &scanError{Row: -1, Column: fields[col].Name, Err: err}
+		}
+		columns = append(columns, columnDecoder{column: col, name: fields[col].Name, index: f.index, dec: dec})
+	}
+	return columns, nil
+}
+
+// scanNull stores NULL into v, if its type can represent it.
+func scanNull(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		v.SetZero()
+		return nil
+	}
+	return fmt.Errorf("cannot scan NULL into %s", v.Type())
+}
+
+func newDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	switch dt := dt.(type) {
+	case arrow.ExtensionType:
+		dec, err := newDecoder(dt.StorageType(), t)
+		if err != nil {
+			return nil, err
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			return dec(arr.(array.ExtensionArray).Storage(), i, v)
+		}, nil
+	case *arrow.DictionaryType:
+		dec, err := newDecoder(dt.ValueType, t)
+		if err != nil {
+			return nil, err
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			dict := arr.(*array.Dictionary)
+			if dict.IsNull(i) {
+				return scanNull(v)
+			}
+			return dec(dict.Dictionary(), dict.GetValueIndex(i), v)
+		}, nil
+	case *arrow.NullType:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			return scanNull(v)
+		}, nil
+	case arrow.UnionType:
+		return newUnionDecoder(dt, t), nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		dec, err := newDecoder(dt, t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			if arr.IsNull(i) {
+				v.SetZero()
+				return nil
+			}
+			if v.IsNil() {
+				v.Set(reflect.New(t.Elem()))
+			}
+			return dec(arr, i, v.Elem())
+		}, nil
+	}
+
+	dec, err := newValueDecoder(dt, t)
+	if err != nil {
+		return nil, err
+	}
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		if arr.IsNull(i) {
+			return scanNull(v)
+		}
+		return dec(arr, i, v)
+	}, nil
+}
+
+// newUnionDecoder decodes the active member of a union. Members that
+// can't be scanned into t only fail when they are encountered.
+func newUnionDecoder(dt arrow.UnionType, t reflect.Type) decodeFunc {
+	decs := make([]decodeFunc, len(dt.Fields()))
+	errs := make([]error, len(dt.Fields()))
+	for j, field := range dt.Fields() {
+		decs[j], errs[j] = newDecoder(field.Type, t)
+	}
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		union := arr.(array.Union)
+		child := union.ChildID(i)
+		if errs[child] != nil {
+			return errs[child]
+		}
+		j := i
+		if dense, ok := union.(*array.DenseUnion); ok {
+			j = int(dense.ValueOffset(i))
+		}
+		return decs[child](union.Field(child), j, v)
+	}
+}
+
+func unsupported(dt arrow.DataType, t reflect.Type) error {
+	return fmt.Errorf("cannot scan %s into %s", dt, t)
+}
+
+// newValueDecoder decodes non-null values into a non-pointer type.
+func newValueDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	if t.Kind() == reflect.Interface {
+		if t.NumMethod() != 0 {
+			return nil, unsupported(dt, t)
+		}
+		natural, err := naturalType(dt)
+		if err != nil {
+			return nil, err
+		}
+		dec, err := newValueDecoder(dt, natural)
+		if err != nil {
+			return nil, err
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			tmp := reflect.New(natural).Elem()
+			if err := dec(arr, i, tmp); err != nil {
+				return err
+			}
+			v.Set(tmp)
+			return nil
+		}, nil
+	}
+
+	switch dt.ID() {
+	case arrow.BOOL:
+		if t.Kind() != reflect.Bool {
+			return nil, unsupported(dt, t)
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetBool(arr.(*array.Boolean).Value(i))
+			return nil
+		}, nil
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64:
+		return newIntDecoder(dt, t)
+	case arrow.UINT8, arrow.UINT16, arrow.UINT32, arrow.UINT64:
+		return newUintDecoder(dt, t)
+	case arrow.FLOAT16, arrow.FLOAT32, arrow.FLOAT64:
+		return newFloatDecoder(dt, t)
+	case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW:
+		return newStringDecoder(dt, t, func(arr arrow.Array, i int) string {
+			return arr.(interface{ Value(int) string }).Value(i)
+		})
+	case arrow.BINARY, arrow.LARGE_BINARY, arrow.BINARY_VIEW, arrow.FIXED_SIZE_BINARY:
+		return newBinaryDecoder(dt, t)
+	case arrow.DECIMAL32, arrow.DECIMAL64, arrow.DECIMAL128, arrow.DECIMAL256:
+		return newDecimalDecoder(dt.(arrow.DecimalType), t)
+	case arrow.TIMESTAMP:
+		if t != timeType {
+			return nil, unsupported(dt, t)
+		}
+		toTime, err := dt.(*arrow.TimestampType).GetToTimeFunc()
+		if err != nil {
+			return nil, err
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.Set(reflect.ValueOf(toTime(arr.(*array.Timestamp).Value(i))))
+			return nil
+		}, nil
+	case arrow.DATE32, arrow.DATE64:
+		if t != timeType {
+			return nil, unsupported(dt, t)
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			var val time.Time
+			switch arr := arr.(type) {
+			case *array.Date32:
+				val = arr.Value(i).ToTime()
+			case *array.Date64:
+				val = arr.Value(i).ToTime()
+			}
+			v.Set(reflect.ValueOf(val))
+			return nil
+		}, nil
+	case arrow.TIME32, arrow.TIME64, arrow.DURATION:
+		if t != durationType {
+			return nil, unsupported(dt, t)
+		}
+		unit := dt.(arrow.TemporalWithUnit).TimeUnit().Multiplier()
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			var val int64
+			switch arr := arr.(type) {
+			case *array.Time32:
+				val = int64(arr.Value(i))
+			case *array.Time64:
+				val = int64(arr.Value(i))
+			case *array.Duration:
+				val = int64(arr.Value(i))
+			}
+			v.SetInt(val * int64(unit))
+			return nil
+		}, nil
+	case arrow.LIST, arrow.LARGE_LIST, arrow.FIXED_SIZE_LIST, arrow.LIST_VIEW, arrow.LARGE_LIST_VIEW:
+		return newListDecoder(dt.(arrow.ListLikeType), t)
+	case arrow.STRUCT:
+		return newStructDecoder(dt.(*arrow.StructType), t)
+	case arrow.MAP:
+		return newMapDecoder(dt.(*arrow.MapType), t)
+	}
+	return nil, unsupported(dt, t)
+}
+
+// naturalType is the Go type that values of dt are scanned into when
+// the destination is an empty interface.
+func naturalType(dt arrow.DataType) (reflect.Type, error) {
+	switch dt.ID() {
+	case arrow.BOOL:
+		return reflect.TypeFor[bool](), nil
+	case arrow.INT8:
+		return reflect.TypeFor[int8](), nil
+	case arrow.INT16:
+		return reflect.TypeFor[int16](), nil
+	case arrow.INT32:
+		return reflect.TypeFor[int32](), nil
+	case arrow.INT64:
+		return reflect.TypeFor[int64](), nil
+	case arrow.UINT8:
+		return reflect.TypeFor[uint8](), nil
+	case arrow.UINT16:
+		return reflect.TypeFor[uint16](), nil
+	case arrow.UINT32:
+		return reflect.TypeFor[uint32](), nil
+	case arrow.UINT64:
+		return reflect.TypeFor[uint64](), nil
+	case arrow.FLOAT16, arrow.FLOAT32:
+		return reflect.TypeFor[float32](), nil
+	case arrow.FLOAT64:
+		return reflect.TypeFor[float64](), nil
+	case arrow.STRING, arrow.LARGE_STRING, arrow.STRING_VIEW,
+		arrow.DECIMAL32, arrow.DECIMAL64, arrow.DECIMAL128, arrow.DECIMAL256:
+		return reflect.TypeFor[string](), nil
+	case arrow.BINARY, arrow.LARGE_BINARY, arrow.BINARY_VIEW, arrow.FIXED_SIZE_BINARY:
+		return reflect.TypeFor[[]byte](), nil
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return timeType, nil
+	case arrow.TIME32, arrow.TIME64, arrow.DURATION:
+		return durationType, nil
+	case arrow.LIST, arrow.LARGE_LIST, arrow.FIXED_SIZE_LIST, arrow.LIST_VIEW, arrow.LARGE_LIST_VIEW:
+		return reflect.TypeFor[[]any](), nil
+	case arrow.STRUCT:
+		return reflect.TypeFor[map[string]any](), nil
+	case arrow.MAP:
+		return reflect.TypeFor[map[any]any](), nil
+	}
+	return nil, unsupported(dt, anyType)
+}
+
+func newIntDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	get := func(arr arrow.Array, i int) int64 {
+		switch arr := arr.(type) {
+		case *array.Int8:
+			return int64(arr.Value(i))
+		case *array.Int16:
+			return int64(arr.Value(i))
+		case *array.Int32:
+			return int64(arr.Value(i))
+		default:
+			return arr.(*array.Int64).Value(i)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			val := get(arr, i)
+			if v.OverflowInt(val) {
+				return fmt.Errorf("value %d overflows %s", val, t)
+			}
+			v.SetInt(val)
+			return nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			val := get(arr, i)
+			if val < 0 || v.OverflowUint(uint64(val)) {
+				return fmt.Errorf("value %d overflows %s", val, t)
+			}
+			v.SetUint(uint64(val))
+			return nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetFloat(float64(get(arr, i)))
+			return nil
+		}, nil
+	}
+	return nil, unsupported(dt, t)
+}
+
+func newUintDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	get := func(arr arrow.Array, i int) uint64 {
+		switch arr := arr.(type) {
+		case *array.Uint8:
+			return uint64(arr.Value(i))
+		case *array.Uint16:
+			return uint64(arr.Value(i))
+		case *array.Uint32:
+			return uint64(arr.Value(i))
+		default:
+			return arr.(*array.Uint64).Value(i)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			val := get(arr, i)
+			if val > math.MaxInt64 || v.OverflowInt(int64(val)) {
+				return fmt.Errorf("value %d overflows %s", val, t)
+			}
+			v.SetInt(int64(val))
+			return nil
+		}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			val := get(arr, i)
+			if v.OverflowUint(val) {
+				return fmt.Errorf("value %d overflows %s", val, t)
+			}
+			v.SetUint(val)
+			return nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetFloat(float64(get(arr, i)))
+			return nil
+		}, nil
+	}
+	return nil, unsupported(dt, t)
+}
+
+func newFloatDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	if t.Kind() != reflect.Float32 && t.Kind() != reflect.Float64 {
+		return nil, unsupported(dt, t)
+	}
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		switch arr := arr.(type) {
+		case *array.Float16:
+			v.SetFloat(float64(arr.Value(i).Float32()))
+		case *array.Float32:
+			v.SetFloat(float64(arr.Value(i)))
+		case *array.Float64:
+			v.SetFloat(arr.Value(i))
+		}
+		return nil
+	}, nil
+}
+
+// newStringDecoder decodes values with a string representation into a
+// string or []byte.
+func newStringDecoder(dt arrow.DataType, t reflect.Type, get func(arrow.Array, int) string) (decodeFunc, error) {
+	switch {
+	case t.Kind() == reflect.String:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetString(get(arr, i))
+			return nil
+		}, nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetBytes([]byte(get(arr, i)))
+			return nil
+		}, nil
+	}
+	return nil, unsupported(dt, t)
+}
+
+func newBinaryDecoder(dt arrow.DataType, t reflect.Type) (decodeFunc, error) {
+	get := func(arr arrow.Array, i int) []byte {
+		return arr.(interface{ Value(int) []byte }).Value(i)
+	}
+
+	if t.Kind() == reflect.Array && t.Elem().Kind() == reflect.Uint8 {
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			val := get(arr, i)
+			if len(val) != t.Len() {
+				return fmt.Errorf("cannot scan %d bytes into %s", len(val), t)
+			}
+			reflect.Copy(v, reflect.ValueOf(val))
+			return nil
+		}, nil
+	}
+	return newStringDecoder(dt, t, func(arr arrow.Array, i int) string {
+		// copies the bytes, which belong to the array
+		return string(get(arr, i))
+	})
+}
+
+func newDecimalDecoder(dt arrow.DecimalType, t reflect.Type) (decodeFunc, error) {
+	scale := dt.GetScale()
+	get := func(arr arrow.Array, i int) interface {
+		ToFloat64(int32) float64
+		ToString(int32) string
+	} {
+		switch arr := arr.(type) {
+		case *array.Decimal32:
+			return arr.Value(i)
+		case *array.Decimal64:
+			return arr.Value(i)
+		case *array.Decimal128:
+			return arr.Value(i)
+		default:
+			return arr.(*array.Decimal256).Value(i)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetString(get(arr, i).ToString(scale))
+			return nil
+		}, nil
+	case reflect.Float32, reflect.Float64:
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			v.SetFloat(get(arr, i).ToFloat64(scale))
+			return nil
+		}, nil
+	}
+
+	var native reflect.Type
+	switch dt.ID() {
+	case arrow.DECIMAL32:
+		native = reflect.TypeFor[decimal.Decimal32]()
+	case arrow.DECIMAL64:
+		native = reflect.TypeFor[decimal.Decimal64]()
+	case arrow.DECIMAL128:
+		native = reflect.TypeFor[decimal.Decimal128]()
+	case arrow.DECIMAL256:
+		native = reflect.TypeFor[decimal.Decimal256]()
+	}
+	if t != native {
+		return nil, unsupported(dt, t)
+	}
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		v.Set(reflect.ValueOf(get(arr, i)))
+		return nil
+	}, nil
+}
+
+func newListDecoder(dt arrow.ListLikeType, t reflect.Type) (decodeFunc, error) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, unsupported(dt, t)
+	}
+	dec, err := newDecoder(dt.Elem(), t.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		list := arr.(array.ListLike)
+		start, end := list.ValueOffsets(i)
+		n := int(end - start)
+		if t.Kind() == reflect.Slice {
+			v.Set(reflect.MakeSlice(t, n, n))
+		} else if n != t.Len() {
+			return fmt.Errorf("cannot scan %d elements into %s", n, t)
+		}
+
+		values := list.ListValues()
+		for j := 0; j < n; j++ {
+			if err := dec(values, int(start)+j, v.Index(j)); err != nil {
+				return fmt.Errorf("element %d: %w", j, err)
+			}
+		}
+		return nil
+	}, nil
+}
+
+func newStructDecoder(dt *arrow.StructType, t reflect.Type) (decodeFunc, error) {
+	switch {
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		decs := make([]decodeFunc, dt.NumFields())
+		for j, field := range dt.Fields() {
+			dec, err := newDecoder(field.Type, t.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", field.Name, err)
+			}
+			decs[j] = dec
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			st := arr.(*array.Struct)
+			m := reflect.MakeMapWithSize(t, len(decs))
+			for j, dec := range decs {
+				elem := reflect.New(t.Elem()).Elem()
+				if err := dec(st.Field(j), i, elem); err != nil {
+					return fmt.Errorf("field %q: %w", dt.Field(j).Name, err)
+				}
+				m.SetMapIndex(reflect.ValueOf(dt.Field(j).Name).Convert(t.Key()), elem)
+			}
+			v.Set(m)
+			return nil
+		}, nil
+	case isRowStruct(t):
+		fields, scanErr := newFieldDecoders(t, dt.Fields())
+		if scanErr != nil {
+			return nil, fmt.Errorf("field %q: %w", scanErr.Column, scanErr.Err)
+		}
+		return func(arr arrow.Array, i int, v reflect.Value) error {
+			st := arr.(*array.Struct)
+			for _, field := range fields {
+				if err := field.dec(st.Field(field.column), i, v.FieldByIndex(field.index)); err != nil {
+					return fmt.Errorf("field %q: %w", field.name, err)
+				}
+			}
+			return nil
+		}, nil
+	}
+	return nil, unsupported(dt, t)
+}
+
+func newMapDecoder(dt *arrow.MapType, t reflect.Type) (decodeFunc, error) {
+	if t.Kind() != reflect.Map {
+		return nil, unsupported(dt, t)
+	}
+	keyDec, err := newDecoder(dt.KeyType(), t.Key())
+	if err != nil {
+		return nil, fmt.Errorf("key: %w", err)
+	}
+	itemDec, err := newDecoder(dt.ItemType(), t.Elem())
+	if err != nil {
+		return nil, fmt.Errorf("item: %w", err)
+	}
+
+	return func(arr arrow.Array, i int, v reflect.Value) error {
+		m := arr.(*array.Map)
+		start, end := m.ValueOffsets(i)
+		keys, items := m.Keys(), m.Items()
+		result := reflect.MakeMapWithSize(t, int(end-start))
+		for j := int(start); j < int(end); j++ {
+			key := reflect.New(t.Key()).Elem()
+			if err := keyDec(keys, j, key); err != nil {
+				return fmt.Errorf("key %d: %w", j-int(start), err)
+			}
+			if !key.Comparable() {
+				return fmt.Errorf("key %d: %s is not comparable", j-int(start), key.Type())
+			}
+			item := reflect.New(t.Elem()).Elem()
+			if err := itemDec(items, j, item); err != nil {
+				return fmt.Errorf("item %d: %w", j-int(start), err)
+			}
+			result.SetMapIndex(key, item)
+		}
+		v.Set(result)
+		return nil
+	}, nil
+}
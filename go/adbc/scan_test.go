@@ -0,0 +1,192 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package adbc_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordReader(t *testing.T, schema *arrow.Schema, batches ...string) array.RecordReader {
+	t.Helper()
+	var recs []arrow.Record
+	for _, batch := range batches {
+		rec, _, err := array.RecordFromJSON(memory.DefaultAllocator, schema, strings.NewReader(batch))
+		require.NoError(t, err)
+		defer rec.Release()
+		recs = append(recs, rec)
+	}
+	rdr, err := array.NewRecordReader(schema, recs)
+	require.NoError(t, err)
+	t.Cleanup(rdr.Release)
+	return rdr
+}
+
+func TestScanRows(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "Name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "price", Type: &arrow.Decimal128Type{Precision: 10, Scale: 2}},
+		{Name: "created", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "America/New_York"}},
+		{Name: "day", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: "extra", Type: arrow.BinaryTypes.String},
+	}, nil)
+
+	type Base struct {
+		ID int32 `adbc:"id"`
+	}
+	type Row struct {
+		Base
+		Name    *string
+		Price   decimal128.Num `adbc:"price"`
+		Text    string         `adbc:"price"`
+		Created time.Time      `adbc:"created"`
+		Day     time.Time      `adbc:"day"`
+		Tags    []string       `adbc:"tags"`
+		Ignored int            `adbc:"-"`
+	}
+
+	rdr := recordReader(t, schema,
+		`[{"id": 1, "Name": "a", "price": "12.34", "created": "2024-01-02T03:04:05Z", "day": "2024-01-02", "tags": ["x", "y"], "extra": ""}]`,
+		`[{"id": 2, "Name": null, "price": "-0.50", "created": "2024-07-01T00:00:00Z", "day": "2024-07-01", "tags": null, "extra": ""}]`)
+	rows, err := adbc.ScanRows[*Row](rdr)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	name := "a"
+	assert.Equal(t, &Row{
+		Base:    Base{ID: 1},
+		Name:    &name,
+		Price:   decimal128.FromI64(1234),
+		Text:    "12.34",
+		Created: time.Date(2024, 1, 1, 22, 4, 5, 0, loc),
+		Day:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Tags:    []string{"x", "y"},
+	}, rows[0])
+	assert.Nil(t, rows[1].Name)
+	assert.Nil(t, rows[1].Tags)
+	assert.Equal(t, "-0.50", rows[1].Text)
+	assert.Equal(t, loc, rows[1].Created.Location())
+}
+
+func TestScanRowsNested(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "point", Type: arrow.StructOf(
+			arrow.Field{Name: "x", Type: arrow.PrimitiveTypes.Float32},
+			arrow.Field{Name: "y", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		), Nullable: true},
+		{Name: "attrs", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int16)},
+		{Name: "elapsed", Type: &arrow.DurationType{Unit: arrow.Millisecond}},
+		{Name: "any", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "a", Type: arrow.PrimitiveTypes.Uint8},
+		))},
+	}, nil)
+
+	type Point struct {
+		X float64 `adbc:"x"`
+		Y *float64
+	}
+	type Row struct {
+		Point   *Point         `adbc:"point"`
+		Attrs   map[string]int `adbc:"attrs"`
+		Elapsed time.Duration  `adbc:"elapsed"`
+		Any     any            `adbc:"any"`
+	}
+
+	rdr := recordReader(t, schema,
+		`[{"point": {"x": 1.5, "y": null}, "attrs": [{"key": "k", "value": 3}], "elapsed": 1500, "any": [{"a": 7}, null]},
+		  {"point": null, "attrs": [], "elapsed": 0, "any": []}]`)
+	rows, err := adbc.ScanRows[Row](rdr)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, Row{
+		Point:   &Point{X: 1.5},
+		Attrs:   map[string]int{"k": 3},
+		Elapsed: 1500 * time.Millisecond,
+		Any:     []any{map[string]any{"a": uint8(7)}, nil},
+	}, rows[0])
+	assert.Nil(t, rows[1].Point)
+	assert.Equal(t, map[string]int{}, rows[1].Attrs)
+	assert.Equal(t, []any{}, rows[1].Any)
+}
+
+func TestScanRowsSingleColumn(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "n", Type: arrow.PrimitiveTypes.Uint32, Nullable: true},
+	}, nil)
+
+	values, err := adbc.ScanRows[*int64](recordReader(t, schema, `[{"n": 1}, {"n": null}]`))
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+	assert.EqualValues(t, 1, *values[0])
+	assert.Nil(t, values[1])
+}
+
+func TestScanRowsErrors(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "s", Type: arrow.StructOf(arrow.Field{Name: "v", Type: arrow.PrimitiveTypes.Int32}), Nullable: true},
+	}, nil)
+
+	var adbcErr adbc.Error
+
+	_, err := adbc.ScanRows[struct {
+		Missing int
+	}](recordReader(t, schema, `[]`))
+	require.ErrorAs(t, err, &adbcErr)
+	assert.Equal(t, adbc.StatusInvalidArgument, adbcErr.Code)
+	assert.ErrorContains(t, err, `column "Missing": no column for field Missing`)
+
+	_, err = adbc.ScanRows[struct {
+		ID string `adbc:"id"`
+	}](recordReader(t, schema, `[]`))
+	assert.ErrorContains(t, err, `column "id": cannot scan int64 into string`)
+
+	_, err = adbc.ScanRows[struct {
+		ID int8 `adbc:"id"`
+	}](recordReader(t, schema, `[{"id": 1}]`, `[{"id": 2}, {"id": 300}]`))
+	assert.ErrorContains(t, err, `row 2, column "id": value 300 overflows int8`)
+
+	_, err = adbc.ScanRows[struct {
+		ID int64 `adbc:"id"`
+	}](recordReader(t, schema, `[{"id": 1}, {"id": null}]`))
+	assert.ErrorContains(t, err, `row 1, column "id": cannot scan NULL into int64`)
+
+	_, err = adbc.ScanRows[struct {
+		S struct {
+			V uint32 `adbc:"v"`
+		} `adbc:"s"`
+	}](recordReader(t, schema, `[{"s": {"v": -1}}]`))
+	assert.ErrorContains(t, err, `row 0, column "s": field "v": value -1 overflows uint32`)
+
+	_, err = adbc.ScanRows[int64](recordReader(t, schema, `[]`))
+	assert.ErrorContains(t, err, "cannot scan 2 columns into int64")
+}
@@ -27,6 +27,12 @@
 //
 //	sql.Register("drivername", sqldriver.Driver{adbcdriver})
 //
+// The data source name passed to sql.Open is either a list of options,
+// such as "uri=grpc://localhost:8080;username=user", or a URI whose
+// scheme has a parser registered with the profile package, such as
+// "snowflake://user@account/db/schema?warehouse=wh" when the Snowflake
+// driver is imported.
+//
 // Additionally, the sqldriver/flightsql package simplifies registration
 // of the FlightSQL ADBC driver implementation, so that only a single
 // import statement is needed. See the example in that package.
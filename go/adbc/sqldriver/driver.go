@@ -31,6 +31,7 @@ import (
 	"unsafe"
 
 	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/profile"
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/decimal128"
@@ -59,6 +60,12 @@ func getIsolationlevel(lvl sql.IsolationLevel) adbc.OptionIsolationLevel {
 }
 
 func parseConnectStr(str string) (ret map[string]string, err error) {
+	// URIs with a registered scheme, e.g. snowflake://..., are converted
+	// by the parser of the driver
+	if scheme, _, ok := strings.Cut(str, "://"); ok && profile.HasURIParser(scheme) {
+		return profile.ParseURI(str)
+	}
+
 	ret = make(map[string]string)
 	for _, kv := range strings.Split(str, ";") {
 		parsed := strings.SplitN(kv, "=", 2)
@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package adbc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// StructTagKey is the struct tag used to map Go struct fields onto Arrow
+// fields by ScanRows and RecordsFromStructs.
+//
+// The tag value is the name of the Arrow field, optionally followed by
+// comma-separated options. A name of "-" skips the field, and an empty
+// name uses the Go field name. Exported fields of embedded structs are
+// treated as fields of the outer struct, as with encoding/json.
+//
+//	type Row struct {
+//		ID      int64      `adbc:"id"`
+//		Comment *string    `adbc:"comment"`
+//		Price   string     `adbc:"price,decimal(10,2)"`
+//		Created time.Time  `adbc:"created,timestamp(ns,Europe/Paris)"`
+//		Ignored string     `adbc:"-"`
+//	}
+//
+// The options only affect the schema inferred by RecordsFromStructs:
+//
+//   - nullable: declare the field nullable. Pointers, slices and maps
+//     are always nullable, and encode nil as NULL.
+//   - decimal(precision,scale): encode a string, a float or a
+//     decimal.DecimalXX value as a decimal.
+//   - timestamp(unit[,zone]): encode a time.Time as a timestamp with
+//     the given unit (s, ms, us or ns) and time zone, or no time zone if
+//     the zone is "-". The default is timestamp(us,UTC).
+//   - date: encode a time.Time as a date32.
+//   - duration(unit): encode a time.Duration as a duration with the
+//     given unit. The default is duration(ns).
+//
+// Options of pointer, slice and array fields apply to their elements.
+//
+// EXPERIMENTAL. Not formally part of the ADBC APIs.
+const StructTagKey = "adbc"
+
+// structField is a field of a Go struct mapped onto an Arrow field.
+type structField struct {
+	name    string
+	index   []int
+	typ     reflect.Type
+	options []string
+	// tagged is true if the name came from a struct tag
+	tagged bool
+}
+
+// structFields lists the fields of a struct type, in order, flattening
+// embedded structs.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(StructTagKey)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && f.Type.Kind() != reflect.Pointer {
+				for _, inner := range structFields(ft) {
+					inner.index = append([]int{i}, inner.index...)
+					fields = append(fields, inner)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		field := structField{
+			name:   name,
+			index:  []int{i},
+			typ:    f.Type,
+			tagged: hasTag && name != "",
+		}
+		if field.name == "" {
+			field.name = f.Name
+		}
+		if opts != "" {
+			field.options = splitTagOptions(opts)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// splitTagOptions splits the options of a struct tag on commas, except
+// within parentheses, so that options may take arguments like
+// "decimal(10,2)".
+func splitTagOptions(opts string) []string {
+	var (
+		result []string
+		depth  int
+		start  int
+	)
+	for i, c := range opts {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(opts[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(result, strings.TrimSpace(opts[start:]))
+}